@@ -0,0 +1,149 @@
+package beeorm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	AlterOperationCreate = "create"
+	AlterOperationAlter  = "alter"
+	AlterOperationDrop   = "drop"
+)
+
+var alterTableRegexp = regexp.MustCompile("^(CREATE|ALTER|DROP) TABLE (?:IF EXISTS )?`([^`]+)`\\.`([^`]+)`")
+
+type AltersPlan struct {
+	Items []AlterPlanItem
+}
+
+type AlterPlanItem struct {
+	Alter
+	Database      string
+	Table         string
+	Operation     string
+	Destructive   bool
+	EstimatedRows uint64
+	OnlineDDL     OnlineDDLStrategy
+}
+
+func (e *engineImplementation) GetAltersPlan() *AltersPlan {
+	plan := &AltersPlan{Items: make([]AlterPlanItem, 0)}
+	rows := make(map[string]uint64)
+	for _, alter := range getAlters(e) {
+		item := newAlterPlanItem(alter)
+		if item.Operation != AlterOperationCreate && item.Table != "" {
+			key := alter.Pool + ":" + item.Table
+			estimated, has := rows[key]
+			if !has {
+				estimated = getEstimatedTableRows(e, alter.Pool, item.Database, item.Table)
+				rows[key] = estimated
+			}
+			item.EstimatedRows = estimated
+		}
+		plan.Items = append(plan.Items, item)
+	}
+	return plan
+}
+
+func newAlterPlanItem(alter Alter) AlterPlanItem {
+	item := AlterPlanItem{Alter: alter}
+	matches := alterTableRegexp.FindStringSubmatch(alter.SQL)
+	if len(matches) == 4 {
+		item.Operation = strings.ToLower(matches[1])
+		item.Database = matches[2]
+		item.Table = matches[3]
+	}
+	switch item.Operation {
+	case AlterOperationDrop:
+		item.Destructive = true
+	case AlterOperationAlter:
+		item.Destructive = strings.Contains(alter.SQL, "DROP COLUMN") || strings.Contains(alter.SQL, "/*CHANGED FROM")
+	}
+	return item
+}
+
+func getEstimatedTableRows(engine *engineImplementation, pool, database, table string) uint64 {
+	var rows uint64
+	engine.GetMysql(pool).QueryRow(NewWhere("SELECT IFNULL(TABLE_ROWS, 0) FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?",
+		database, table), &rows)
+	return rows
+}
+
+func (p *AltersPlan) IsSafe() bool {
+	for _, item := range p.Items {
+		if !item.Safe {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *AltersPlan) HasDestructive() bool {
+	for _, item := range p.Items {
+		if item.Destructive {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *AltersPlan) Exec() {
+	for _, item := range p.Items {
+		if item.OnlineDDL == OnlineDDLNone {
+			item.Exec()
+			continue
+		}
+		checkError(item.ExecOnline(item.OnlineDDL))
+	}
+}
+
+func (p *AltersPlan) WriteSQL(writer io.Writer) error {
+	buffer := &bytes.Buffer{}
+	for i, item := range p.Items {
+		if i > 0 {
+			buffer.WriteString("\n")
+		}
+		table := item.Table
+		if item.Database != "" {
+			table = item.Database + "." + table
+		}
+		buffer.WriteString(fmt.Sprintf("-- pool: %s, table: %s, operation: %s\n", item.Pool, table, item.Operation))
+		buffer.WriteString(fmt.Sprintf("-- safe: %t, destructive: %t, estimated rows: %d\n", item.Safe, item.Destructive, item.EstimatedRows))
+		switch item.OnlineDDL {
+		case OnlineDDLGhost, OnlineDDLPtOSC:
+			command, err := item.GetOnlineCommand(item.OnlineDDL)
+			if err != nil {
+				return err
+			}
+			buffer.WriteString("-- run: " + command + "\n")
+		case OnlineDDLNone:
+			buffer.WriteString(item.SQL + "\n")
+		default:
+			sql, err := item.GetOnlineSQL(item.OnlineDDL)
+			if err != nil {
+				return err
+			}
+			buffer.WriteString(sql + "\n")
+		}
+	}
+	_, err := writer.Write(buffer.Bytes())
+	return err
+}
+
+func (p *AltersPlan) WriteSQLFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = p.WriteSQL(file)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,79 @@
+package beeorm
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type altersPlanEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestAlterPlanItem(t *testing.T) {
+	item := newAlterPlanItem(Alter{SQL: "CREATE TABLE `test`.`a` (\n  `ID` int\n);", Safe: true, Pool: "default"})
+	assert.Equal(t, AlterOperationCreate, item.Operation)
+	assert.Equal(t, "test", item.Database)
+	assert.Equal(t, "a", item.Table)
+	assert.False(t, item.Destructive)
+
+	item = newAlterPlanItem(Alter{SQL: "ALTER TABLE `test`.`a`\n    ADD COLUMN `Name` varchar(255) DEFAULT NULL AFTER `ID`;", Safe: true})
+	assert.Equal(t, AlterOperationAlter, item.Operation)
+	assert.False(t, item.Destructive)
+	item = newAlterPlanItem(Alter{SQL: "ALTER TABLE `test`.`a`\n    DROP COLUMN `Name`;"})
+	assert.True(t, item.Destructive)
+	item = newAlterPlanItem(Alter{SQL: "ALTER TABLE `test`.`a`\n    CHANGE COLUMN `Name` `Name` int;/*CHANGED FROM `Name` varchar(255)*/"})
+	assert.True(t, item.Destructive)
+	item = newAlterPlanItem(Alter{SQL: "DROP TABLE IF EXISTS `test`.`b`;"})
+	assert.Equal(t, AlterOperationDrop, item.Operation)
+	assert.Equal(t, "b", item.Table)
+	assert.True(t, item.Destructive)
+
+	plan := &AltersPlan{Items: []AlterPlanItem{
+		newAlterPlanItem(Alter{SQL: "CREATE TABLE `test`.`a` (`ID` int);", Safe: true, Pool: "default"}),
+		newAlterPlanItem(Alter{SQL: "DROP TABLE IF EXISTS `test`.`b`;", Pool: "default"}),
+	}}
+	plan.Items[1].EstimatedRows = 12
+	assert.False(t, plan.IsSafe())
+	assert.True(t, plan.HasDestructive())
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, plan.WriteSQL(buffer))
+	expected := "-- pool: default, table: test.a, operation: create\n-- safe: true, destructive: false, estimated rows: 0\n" +
+		"CREATE TABLE `test`.`a` (`ID` int);\n\n-- pool: default, table: test.b, operation: drop\n" +
+		"-- safe: false, destructive: true, estimated rows: 12\nDROP TABLE IF EXISTS `test`.`b`;\n"
+	assert.Equal(t, expected, buffer.String())
+
+	path := filepath.Join(t.TempDir(), "plan.sql")
+	assert.NoError(t, plan.WriteSQLFile(path))
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(content))
+}
+
+func TestAltersPlan(t *testing.T) {
+	var entity *altersPlanEntity
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	plan := engine.GetAltersPlan()
+	assert.Len(t, plan.Items, 0)
+	assert.True(t, plan.IsSafe())
+
+	engine.Flush(&altersPlanEntity{Name: "a"}, &altersPlanEntity{Name: "b"})
+	engine.GetMysql().Exec("ALTER TABLE `altersPlanEntity` ADD COLUMN `Old` int(11) DEFAULT NULL")
+	engine.GetMysql().Exec("ANALYZE TABLE `altersPlanEntity`")
+	plan = engine.GetAltersPlan()
+	assert.Len(t, plan.Items, 1)
+	assert.Equal(t, "altersPlanEntity", plan.Items[0].Table)
+	assert.Equal(t, AlterOperationAlter, plan.Items[0].Operation)
+	assert.True(t, plan.Items[0].Destructive)
+	assert.False(t, plan.Items[0].Safe)
+	assert.Equal(t, uint64(2), plan.Items[0].EstimatedRows)
+	assert.False(t, plan.IsSafe())
+	plan.Exec()
+	assert.Len(t, engine.GetAltersPlan().Items, 0)
+}
@@ -0,0 +1,185 @@
+package beeorm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const anonymizeBatchSize = 1000
+
+func initPIIFields(tableSchema *tableSchema, entityType reflect.Type) error {
+	for field, tags := range tableSchema.tags {
+		strategy, has := tags["pii"]
+		if !has {
+			continue
+		}
+		structField, is := entityType.FieldByName(field)
+		if !is {
+			continue
+		}
+		switch strategy {
+		case "null":
+		case "hash", "fake":
+			if structField.Type.Kind() != reflect.String || tags["enum"] != "" || tags["set"] != "" {
+				return fmt.Errorf("pii strategy %s not supported for field %s in %s", strategy, field, entityType.String())
+			}
+		default:
+			return fmt.Errorf("invalid pii strategy '%s' for field %s in %s", strategy, field, entityType.String())
+		}
+		if tableSchema.piiFields == nil {
+			tableSchema.piiFields = make(map[string]string)
+		}
+		tableSchema.piiFields[field] = strategy
+	}
+	return nil
+}
+
+func anonymizeEntities(engine *engineImplementation, entities ...Entity) {
+	serializer := newSerializer(nil)
+	for _, entity := range entities {
+		orm := initIfNeeded(engine.registry, entity)
+		schema := orm.tableSchema
+		if len(schema.piiFields) == 0 {
+			panic(fmt.Errorf("entity '%s' has no pii fields", schema.t.String()))
+		}
+		id := orm.GetID()
+		if id == 0 || !orm.inDB {
+			panic(fmt.Errorf("entity '%s' is not loaded", schema.t.String()))
+		}
+		anonymizeFields(orm, id)
+		bindBuilder, has := orm.buildDirtyBind(serializer)
+		if !has || len(bindBuilder.bind) == 0 {
+			continue
+		}
+		columns := make([]string, 0, len(bindBuilder.bind))
+		for column := range bindBuilder.bind {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		sets := make([]string, len(columns))
+		values := make([]interface{}, len(columns)+1)
+		for i, column := range columns {
+			sets[i] = "`" + schema.getColumnName(column) + "` = ?"
+			values[i] = bindBuilder.bind[column]
+		}
+		values[len(columns)] = id
+		/* #nosec */
+		schema.GetMysql(engine).Exec("UPDATE `"+schema.tableName+"` SET "+strings.Join(sets, ", ")+" WHERE `ID` = ?", values...)
+		serializer.Reset(nil)
+		orm.serialize(serializer)
+		clearByIDs(engine, entity, id)
+		anonymizeHistory(engine, schema, id, columns, bindBuilder.bind)
+	}
+}
+
+func anonymizeFields(orm *ORM, id uint64) {
+	schema := orm.tableSchema
+	for field, strategy := range schema.piiFields {
+		value := orm.elem.FieldByName(field)
+		switch strategy {
+		case "null":
+			value.Set(reflect.Zero(value.Type()))
+		case "hash":
+			if value.String() != "" {
+				hash := sha256.Sum256([]byte(value.String()))
+				value.SetString(truncatePIIValue(schema, field, hex.EncodeToString(hash[:])))
+			}
+		case "fake":
+			if value.String() != "" {
+				hash := sha256.Sum256([]byte(schema.tableName + field + strconv.FormatUint(id, 10)))
+				value.SetString(truncatePIIValue(schema, field, "anonymized_"+hex.EncodeToString(hash[:6])))
+			}
+		}
+	}
+}
+
+func anonymizeSubject(engine *engineImplementation, entity Entity, subjectID uint64) int {
+	schema := initIfNeeded(engine.registry, entity).tableSchema
+	total := 0
+	if len(schema.piiFields) > 0 {
+		subject := schema.NewEntity()
+		if engine.LoadByID(subjectID, subject) {
+			anonymizeEntities(engine, subject)
+			total++
+		}
+	}
+	for t, columns := range schema.GetUsage(engine.registry) {
+		refSchema := getTableSchema(engine.registry, t)
+		if len(refSchema.piiFields) == 0 {
+			continue
+		}
+		for _, column := range columns {
+			where := NewWhere("`"+refSchema.getColumnName(column)+"` = ?", subjectID)
+			where.ShowFakeDeleted()
+			for page := 1; ; page++ {
+				ids, _ := searchIDs(engine, where, NewPager(page, anonymizeBatchSize), false, refSchema.t)
+				if len(ids) == 0 {
+					break
+				}
+				rows := reflect.New(reflect.SliceOf(reflect.PtrTo(refSchema.t)))
+				engine.LoadByIDs(ids, rows.Interface())
+				for i := 0; i < rows.Elem().Len(); i++ {
+					row := rows.Elem().Index(i)
+					if !row.IsNil() {
+						anonymizeEntities(engine, row.Interface().(Entity))
+						total++
+					}
+				}
+				if len(ids) < anonymizeBatchSize {
+					break
+				}
+			}
+		}
+	}
+	return total
+}
+
+func anonymizeHistory(engine *engineImplementation, schema *tableSchema, id uint64, columns []string, bind Bind) {
+	replace := func(column string) (string, []interface{}) {
+		sql := "JSON_REPLACE(`" + column + "`"
+		values := make([]interface{}, 0, len(columns))
+		for _, field := range columns {
+			sql += ", '$.\"" + field + "\"', ?"
+			values = append(values, bind[field])
+		}
+		return sql + ")", values
+	}
+	rewrite := func(pool, table string, jsonColumns ...string) {
+		sets := make([]string, len(jsonColumns))
+		values := make([]interface{}, 0)
+		for i, column := range jsonColumns {
+			sql, columnValues := replace(column)
+			sets[i] = "`" + column + "` = " + sql
+			values = append(values, columnValues...)
+		}
+		values = append(values, id)
+		/* #nosec */
+		engine.GetMysql(pool).Exec("UPDATE `"+table+"` SET "+strings.Join(sets, ", ")+" WHERE `entity_id` = ?", values...)
+	}
+	if schema.hasLog {
+		rewrite(schema.logPoolName, schema.logTableName, "before", "changes")
+	}
+	if schema.hasAudit {
+		rewrite(schema.auditPoolName, schema.auditTableName, "before", "changes")
+	}
+	if schema.hasTemporal {
+		rewrite(schema.temporalPoolName, schema.temporalTableName, "data")
+	}
+}
+
+func truncatePIIValue(schema *tableSchema, field, value string) string {
+	tags := schema.tags[field]
+	length, err := strconv.Atoi(tags["length"])
+	if err != nil {
+		length, err = strconv.Atoi(tags["varbinary"])
+	}
+	if err == nil && length > 0 && len(value) > length {
+		return value[0:length]
+	}
+	return value
+}
@@ -0,0 +1,94 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type anonymizeUser struct {
+	ORM   `orm:"audit;localCache;redisCache"`
+	ID    uint
+	Name  string `orm:"pii=fake"`
+	Email string `orm:"pii=hash;length=40"`
+	Age   *uint  `orm:"pii=null"`
+	Role  string
+}
+
+type anonymizeComment struct {
+	ORM    `orm:"redisCache"`
+	ID     uint
+	User   *anonymizeUser
+	Author string `orm:"pii=null"`
+	Text   string
+}
+
+type anonymizeInvalidEntity struct {
+	ORM
+	ID  uint
+	Age uint `orm:"pii=hash"`
+}
+
+func TestAnonymize(t *testing.T) {
+	var user *anonymizeUser
+	var comment *anonymizeComment
+	registry := &Registry{}
+	plugin := NewAuditLogPlugin()
+	registry.RegisterPlugin(plugin)
+	engine := prepareTables(t, registry, 5, 6, "", user, comment)
+	engine.GetMysql().Exec("TRUNCATE TABLE `_audit_anonymizeUser`")
+
+	age := uint(30)
+	user = &anonymizeUser{Name: "John", Email: "john@example.com", Age: &age, Role: "admin"}
+	engine.Flush(user)
+	user.Email = "john@beeorm.io"
+	engine.Flush(user)
+	other := &anonymizeUser{Name: "Tom", Email: "tom@example.com"}
+	engine.Flush(other)
+	engine.Flush(&anonymizeComment{User: user, Author: "John", Text: "a"}, &anonymizeComment{User: user, Author: "John", Text: "b"},
+		&anonymizeComment{User: other, Author: "Tom", Text: "c"})
+
+	loaded := &anonymizeUser{}
+	assert.True(t, engine.LoadByID(1, loaded))
+	assert.Equal(t, 4, engine.AnonymizeSubject(user, 1))
+
+	loaded = &anonymizeUser{}
+	assert.True(t, engine.LoadByID(1, loaded))
+	assert.NotEqual(t, "John", loaded.Name)
+	assert.Contains(t, loaded.Name, "anonymized_")
+	assert.Len(t, loaded.Email, 40)
+	assert.Nil(t, loaded.Age)
+	assert.Equal(t, "admin", loaded.Role)
+	assert.False(t, loaded.IsDirty())
+
+	comment = &anonymizeComment{}
+	assert.True(t, engine.LoadByID(1, comment))
+	assert.Equal(t, "", comment.Author)
+	assert.Equal(t, "a", comment.Text)
+	comment = &anonymizeComment{}
+	assert.True(t, engine.LoadByID(3, comment))
+	assert.Equal(t, "Tom", comment.Author)
+
+	for _, entry := range plugin.GetHistory(engine, loaded, nil) {
+		for _, bind := range []Bind{entry.Before, entry.Changes} {
+			assert.NotEqual(t, "John", bind["Name"])
+			assert.NotContains(t, bind["Email"], "john")
+		}
+	}
+	assert.Len(t, plugin.GetHistory(engine, loaded, nil), 2)
+
+	engine.LoadByID(2, other)
+	engine.AnonymizeEntity(other)
+	assert.Contains(t, other.Name, "anonymized_")
+	assert.False(t, other.IsDirty())
+
+	assert.PanicsWithError(t, "entity 'beeorm.anonymizeComment' is not loaded", func() {
+		engine.AnonymizeEntity(&anonymizeComment{})
+	})
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&anonymizeInvalidEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "pii strategy hash not supported for field Age in beeorm.anonymizeInvalidEntity")
+}
@@ -0,0 +1,148 @@
+package beeorm
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const anonymizedCloneBatchSize = 1000
+
+type AnonymizedClonePlan struct {
+	Entities  []Entity
+	BatchSize int
+	Truncate  bool
+	Progress  func(schema TableSchema, copied int)
+}
+
+func cloneAnonymized(source *engineImplementation, target Engine, plan AnonymizedClonePlan) map[string]int {
+	targetEngine := target.(*engineImplementation)
+	batchSize := plan.BatchSize
+	if batchSize <= 0 {
+		batchSize = anonymizedCloneBatchSize
+	}
+	schemas := sortCloneSchemas(source.registry, plan.Entities)
+	if plan.Truncate {
+		for i := len(schemas) - 1; i >= 0; i-- {
+			targetSchema := getTableSchema(targetEngine.registry, schemas[i].t)
+			/* #nosec */
+			targetSchema.GetMysql(targetEngine).Exec("DELETE FROM `" + targetSchema.tableName + "`")
+		}
+	}
+	copied := make(map[string]int)
+	for _, schema := range schemas {
+		copied[schema.t.String()] = cloneAnonymizedTable(source, targetEngine, schema, batchSize, plan.Progress)
+	}
+	return copied
+}
+
+func cloneAnonymizedTable(source, target *engineImplementation, schema *tableSchema, batchSize int, progress func(schema TableSchema, copied int)) int {
+	targetSchema := getTableSchema(target.registry, schema.t)
+	targetFlusher := &flusher{engine: target}
+	serializer := newSerializer(nil)
+	_, hasLocalCache := targetSchema.GetLocalCache(target)
+	_, hasRedis := targetSchema.GetRedisCache(target)
+	total := 0
+	lastID := uint64(0)
+	for {
+		where := NewWhere("`ID` > ? ORDER BY `ID`", lastID)
+		where.ShowFakeDeleted()
+		rows := reflect.New(reflect.SliceOf(reflect.PtrTo(schema.t)))
+		source.Search(where, NewPager(1, batchSize), rows.Interface())
+		l := rows.Elem().Len()
+		if l == 0 {
+			break
+		}
+		var columns []string
+		values := make([]string, l)
+		ids := make([]uint64, l)
+		cacheKeys := make([]string, 0)
+		for i := 0; i < l; i++ {
+			orm := rows.Elem().Index(i).Interface().(Entity).getORM()
+			id := orm.GetID()
+			ids[i] = id
+			anonymizeFields(orm, id)
+			cloned := targetSchema.NewEntity().getORM()
+			for j := 1; j < cloned.elem.NumField(); j++ {
+				cloned.elem.Field(j).Set(orm.elem.Field(j))
+			}
+			bindBuilder, _ := cloned.buildDirtyBind(serializer)
+			bindBuilder.bind["ID"] = id
+			bindBuilder.sqlBind["ID"] = strconv.FormatUint(id, 10)
+			if columns == nil {
+				columns = make([]string, 0, len(bindBuilder.sqlBind))
+				for column := range bindBuilder.sqlBind {
+					columns = append(columns, column)
+				}
+				sort.Strings(columns)
+			}
+			row := make([]string, len(columns))
+			for j, column := range columns {
+				row[j] = bindBuilder.sqlBind[column]
+			}
+			values[i] = "(" + strings.Join(row, ",") + ")"
+			if hasLocalCache || hasRedis {
+				cacheKeys = append(cacheKeys, targetFlusher.getCacheQueriesKeys(targetSchema, bindBuilder.bind, nil, false, true)...)
+			}
+		}
+		/* #nosec */
+		targetSchema.GetMysql(target).Exec("INSERT INTO `" + targetSchema.tableName + "`(`" + strings.Join(columns, "`,`") +
+			"`) VALUES " + strings.Join(values, ","))
+		if hasLocalCache || hasRedis {
+			entity := targetSchema.NewEntity()
+			clearByIDs(target, entity, ids...)
+			if len(cacheKeys) > 0 {
+				if hasLocalCache {
+					localCache, _ := targetSchema.GetLocalCache(target)
+					localCache.Remove(cacheKeys...)
+				}
+				if hasRedis {
+					redisCache, _ := targetSchema.GetRedisCache(target)
+					redisCache.Del(cacheKeys...)
+				}
+			}
+			target.LoadByIDs(ids, reflect.New(reflect.SliceOf(reflect.PtrTo(schema.t))).Interface())
+		}
+		total += l
+		lastID = ids[l-1]
+		if progress != nil {
+			progress(targetSchema, total)
+		}
+		if l < batchSize {
+			break
+		}
+	}
+	return total
+}
+
+func sortCloneSchemas(registry *validatedRegistry, entities []Entity) []*tableSchema {
+	schemas := make([]*tableSchema, 0, len(entities))
+	requested := make(map[*tableSchema]bool)
+	for _, entity := range entities {
+		requested[initIfNeeded(registry, entity).tableSchema] = true
+	}
+	added := make(map[*tableSchema]bool)
+	var visit func(schema *tableSchema)
+	visit = func(schema *tableSchema) {
+		if added[schema] {
+			return
+		}
+		added[schema] = true
+		for _, refName := range schema.refOne {
+			refType, has := registry.entities[schema.tags[refName]["ref"]]
+			if !has {
+				continue
+			}
+			refSchema := getTableSchema(registry, refType)
+			if requested[refSchema] {
+				visit(refSchema)
+			}
+		}
+		schemas = append(schemas, schema)
+	}
+	for _, entity := range entities {
+		visit(entity.getORM().tableSchema)
+	}
+	return schemas
+}
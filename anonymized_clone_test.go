@@ -0,0 +1,79 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type anonymizedCloneUser struct {
+	ORM   `orm:"redisCache"`
+	ID    uint
+	Name  string `orm:"pii=fake"`
+	Email string `orm:"pii=hash;length=80"`
+	Age   *uint  `orm:"pii=null"`
+	Role  string
+}
+
+type anonymizedCloneOrder struct {
+	ORM   `orm:"redisCache"`
+	ID    uint
+	User  *anonymizedCloneUser
+	Note  string `orm:"pii=null"`
+	Total uint
+}
+
+func TestCloneAnonymized(t *testing.T) {
+	var user *anonymizedCloneUser
+	var order *anonymizedCloneOrder
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", user, order)
+	age := uint(30)
+	flusher := engine.NewFlusher()
+	for i := 0; i < 5; i++ {
+		user = &anonymizedCloneUser{Name: "John", Email: "john@example.com", Age: &age, Role: "admin"}
+		flusher.Track(user, &anonymizedCloneOrder{User: user, Note: "call me", Total: uint(i)})
+	}
+	flusher.Flush()
+
+	target := engine.GetRegistry().CreateTestClone("staging").CreateEngine()
+	target.GetRedis().FlushDB()
+	target.Flush(&anonymizedCloneUser{Name: "old"})
+	progress := make(map[string][]int)
+	copied := engine.CloneAnonymized(target, AnonymizedClonePlan{
+		Entities:  []Entity{order, user},
+		BatchSize: 2,
+		Truncate:  true,
+		Progress: func(schema TableSchema, copied int) {
+			progress[schema.GetTableName()] = append(progress[schema.GetTableName()], copied)
+		},
+	})
+	assert.Equal(t, map[string]int{"beeorm.anonymizedCloneUser": 5, "beeorm.anonymizedCloneOrder": 5}, copied)
+	assert.Equal(t, []int{2, 4, 5}, progress["anonymizedCloneUser"])
+	assert.Equal(t, []int{2, 4, 5}, progress["anonymizedCloneOrder"])
+
+	var users []*anonymizedCloneUser
+	target.LoadByIDs([]uint64{1, 2, 3, 4, 5}, &users)
+	assert.Len(t, users, 5)
+	for _, row := range users {
+		assert.Contains(t, row.Name, "anonymized_")
+		assert.NotEqual(t, "john@example.com", row.Email)
+		assert.Len(t, row.Email, 64)
+		assert.Nil(t, row.Age)
+		assert.Equal(t, "admin", row.Role)
+	}
+	schema := target.GetRegistry().GetTableSchemaForEntity(user).(*tableSchema)
+	assert.Equal(t, int64(1), target.GetRedis().Exists(schema.getCacheKey(target.(*engineImplementation), 3)))
+
+	var orders []*anonymizedCloneOrder
+	target.LoadByIDs([]uint64{1, 2, 3, 4, 5}, &orders, "User")
+	assert.Len(t, orders, 5)
+	assert.Equal(t, uint(2), orders[2].Total)
+	assert.Equal(t, "", orders[2].Note)
+	assert.Equal(t, users[2].Name, orders[2].User.Name)
+
+	user = &anonymizedCloneUser{}
+	assert.True(t, engine.LoadByID(1, user))
+	assert.Equal(t, "John", user.Name)
+	assert.Equal(t, "john@example.com", user.Email)
+}
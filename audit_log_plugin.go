@@ -0,0 +1,202 @@
+package beeorm
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const AuditLogPluginCode = "beeorm/audit_log"
+
+type AuditLogPlugin struct{}
+
+type AuditLogEntry struct {
+	ID       uint64
+	EntityID uint64
+	Type     FlushType
+	Date     time.Time
+	Meta     Bind
+	Before   Bind
+	Changes  Bind
+}
+
+func NewAuditLogPlugin() *AuditLogPlugin {
+	return &AuditLogPlugin{}
+}
+
+func (p *AuditLogPlugin) GetCode() string {
+	return AuditLogPluginCode
+}
+
+func (p *AuditLogPlugin) PluginInterfaceSchemaTables(engine Engine) []PluginTable {
+	tables := make([]PluginTable, 0)
+	registry := engine.(*engineImplementation).registry
+	for _, t := range registry.entities {
+		schema := getTableSchema(registry, t)
+		if !schema.hasAudit {
+			continue
+		}
+		poolConfig := engine.GetMysql(schema.auditPoolName).GetPoolConfig()
+		var createSQL string
+		if poolConfig.GetVersion() == 5 {
+			createSQL = fmt.Sprintf("CREATE TABLE `%s`.`%s` (\n  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,\n  "+
+				"`entity_id` bigint(20) unsigned NOT NULL,\n  `action` tinyint(3) unsigned NOT NULL,\n  `added_at` datetime NOT NULL,\n  "+
+				"`meta` json DEFAULT NULL,\n  `before` json DEFAULT NULL,\n  `changes` json DEFAULT NULL,\n  "+
+				"PRIMARY KEY (`id`),\n  KEY `entity_id` (`entity_id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+				poolConfig.GetDatabase(), schema.auditTableName)
+		} else {
+			createSQL = fmt.Sprintf("CREATE TABLE `%s`.`%s` (\n  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n  "+
+				"`entity_id` bigint unsigned NOT NULL,\n  `action` tinyint unsigned NOT NULL,\n  `added_at` datetime NOT NULL,\n  "+
+				"`meta` json DEFAULT NULL,\n  `before` json DEFAULT NULL,\n  `changes` json DEFAULT NULL,\n  "+
+				"PRIMARY KEY (`id`),\n  KEY `entity_id` (`entity_id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_%s;",
+				poolConfig.GetDatabase(), schema.auditTableName, registry.registry.defaultCollate)
+		}
+		tables = append(tables, PluginTable{Pool: schema.auditPoolName, Name: schema.auditTableName, CreateSQL: createSQL})
+	}
+	return tables
+}
+
+func (p *AuditLogPlugin) PluginInterfaceEntityFlushedInTransaction(engine Engine, event *EntityFlushedEvent) {
+	schema := event.TableSchema.(*tableSchema)
+	if !schema.hasAudit || event.Lazy || event.ID == 0 {
+		return
+	}
+	p.addEntry(engine, schema, event.ID, event.Type, schema.maskBind(event.Before), schema.maskBind(event.Changes))
+}
+
+func (p *AuditLogPlugin) GetHistory(engine Engine, entity Entity, pager *Pager) []AuditLogEntry {
+	orm := initIfNeeded(engine.(*engineImplementation).registry, entity)
+	schema := orm.tableSchema
+	if !schema.hasAudit {
+		panic(fmt.Errorf("entity '%s' has no audit log", schema.t.String()))
+	}
+	if pager == nil {
+		pager = NewPager(1, 1000)
+	}
+	query := "SELECT `id`, `action`, `added_at`, `meta`, `before`, `changes` FROM `" + schema.auditTableName +
+		"` WHERE `entity_id` = ? ORDER BY `id` DESC " + pager.String()
+	rows, closeF := engine.GetMysql(schema.auditPoolName).Query(query, orm.GetID())
+	defer closeF()
+	results := make([]AuditLogEntry, 0)
+	for rows.Next() {
+		entry := AuditLogEntry{EntityID: orm.GetID()}
+		var addedAt string
+		var meta, before, changes sql.NullString
+		rows.Scan(&entry.ID, &entry.Type, &addedAt, &meta, &before, &changes)
+		entry.Date, _ = time.ParseInLocation(timeFormat, addedAt, time.Local)
+		entry.Meta = decodeAuditBind(meta)
+		entry.Before = decodeAuditBind(before)
+		entry.Changes = decodeAuditBind(changes)
+		results = append(results, entry)
+	}
+	return results
+}
+
+func (p *AuditLogPlugin) Restore(engine Engine, entity Entity, auditID uint64) {
+	orm := initIfNeeded(engine.(*engineImplementation).registry, entity)
+	schema := orm.tableSchema
+	if !schema.hasAudit {
+		panic(fmt.Errorf("entity '%s' has no audit log", schema.t.String()))
+	}
+	id := orm.GetID()
+	var action FlushType
+	var before, changes sql.NullString
+	query := NewWhere("SELECT `action`, `before`, `changes` FROM `"+schema.auditTableName+"` WHERE `id` = ? AND `entity_id` = ?", auditID, id)
+	found := engine.GetMysql(schema.auditPoolName).QueryRow(query, &action, &before, &changes)
+	if !found {
+		panic(fmt.Errorf("audit log %d for entity '%s' with ID %d not found", auditID, schema.t.String(), id))
+	}
+	state := Bind{}
+	if action != FlushTypeInsert {
+		for column, value := range decodeAuditBind(before) {
+			state[column] = value
+		}
+	}
+	if action != FlushTypeDelete {
+		for column, value := range decodeAuditBind(changes) {
+			state[column] = value
+		}
+	}
+	delete(state, "ID")
+	columns := make([]string, 0, len(state))
+	for column := range state {
+		columns = append(columns, column)
+	}
+	values := make([]interface{}, 0, len(columns)+1)
+	for _, column := range columns {
+		values = append(values, state[column])
+	}
+	db := schema.GetMysql(engine)
+	var exists uint64
+	restoreType := FlushTypeUpdate
+	if db.QueryRow(NewWhere("SELECT `ID` FROM `"+schema.tableName+"` WHERE `ID` = ?", id), &exists) {
+		sets := make([]string, len(columns))
+		for i, column := range columns {
+			sets[i] = "`" + schema.getColumnName(column) + "` = ?"
+		}
+		values = append(values, id)
+		db.Exec("UPDATE `"+schema.tableName+"` SET "+strings.Join(sets, ", ")+" WHERE `ID` = ?", values...)
+	} else {
+		restoreType = FlushTypeInsert
+		values = append([]interface{}{id}, values...)
+		db.Exec("INSERT INTO `"+schema.tableName+"`(`ID`"+auditColumnsList(schema, columns)+") VALUES (?"+
+			strings.Repeat(",?", len(columns))+")", values...)
+	}
+	clearByIDs(engine.(*engineImplementation), entity, id)
+	p.addEntry(engine, schema, id, restoreType, nil, state)
+	engine.LoadByID(id, entity)
+}
+
+func (p *AuditLogPlugin) addEntry(engine Engine, schema *tableSchema, id uint64, flushType FlushType, before, changes Bind) {
+	meta := encodeAuditBind(engine.(*engineImplementation).logMetaData)
+	query := "INSERT INTO `" + schema.auditTableName + "`(`entity_id`, `action`, `added_at`, `meta`, `before`, `changes`) VALUES(?, ?, ?, ?, ?, ?)"
+	engine.GetMysql(schema.auditPoolName).Exec(query, id, int(flushType), time.Now().Format(timeFormat), meta,
+		encodeAuditBind(before), encodeAuditBind(changes))
+}
+
+func auditColumnsList(schema *tableSchema, columns []string) string {
+	result := ""
+	for _, column := range columns {
+		result += ",`" + schema.getColumnName(column) + "`"
+	}
+	return result
+}
+
+func encodeAuditBind(bind Bind) interface{} {
+	if len(bind) == 0 {
+		return nil
+	}
+	encoded, _ := json.Marshal(bind)
+	return string(encoded)
+}
+
+func decodeAuditBind(value sql.NullString) Bind {
+	if !value.Valid {
+		return nil
+	}
+	bind := Bind{}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value.String)))
+	decoder.UseNumber()
+	_ = decoder.Decode(&bind)
+	for key, val := range bind {
+		number, is := val.(json.Number)
+		if is {
+			asInt, err := strconv.ParseInt(number.String(), 10, 64)
+			if err == nil {
+				bind[key] = asInt
+				continue
+			}
+			asUint, err := strconv.ParseUint(number.String(), 10, 64)
+			if err == nil {
+				bind[key] = asUint
+				continue
+			}
+			bind[key], _ = number.Float64()
+		}
+	}
+	return bind
+}
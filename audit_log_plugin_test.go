@@ -0,0 +1,91 @@
+package beeorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type auditLogEntity struct {
+	ORM  `orm:"audit;redisCache"`
+	ID   uint
+	Name string
+	Age  uint
+}
+
+type auditLogEntityNoAudit struct {
+	ORM
+	ID uint
+}
+
+func TestAuditLogPlugin(t *testing.T) {
+	var entity *auditLogEntity
+	var entityNoAudit *auditLogEntityNoAudit
+	registry := &Registry{}
+	plugin := NewAuditLogPlugin()
+	registry.RegisterPlugin(plugin)
+	engine := prepareTables(t, registry, 5, 6, "", entity, entityNoAudit)
+	assert.Equal(t, plugin, engine.GetRegistry().GetPlugin(AuditLogPluginCode))
+	assert.Len(t, engine.GetAlters(), 0)
+	engine.GetMysql().Exec("TRUNCATE TABLE `_audit_auditLogEntity`")
+
+	engine.SetLogMetaData("user_id", 12)
+	entity = &auditLogEntity{Name: "a", Age: 10}
+	engine.Flush(entity)
+	entity.Name = "b"
+	engine.Flush(entity)
+	entity.Age = 20
+	engine.Flush(entity)
+
+	history := plugin.GetHistory(engine, entity, nil)
+	assert.Len(t, history, 3)
+	assert.Equal(t, FlushTypeUpdate, history[0].Type)
+	assert.Equal(t, uint64(1), history[0].EntityID)
+	assert.Equal(t, Bind{"Age": int64(20)}, history[0].Changes)
+	assert.Equal(t, int64(10), history[0].Before["Age"])
+	assert.Equal(t, "b", history[0].Before["Name"])
+	assert.Equal(t, Bind{"user_id": int64(12)}, history[0].Meta)
+	assert.Equal(t, FlushTypeInsert, history[2].Type)
+	assert.Nil(t, history[2].Before)
+	assert.Equal(t, "a", history[2].Changes["Name"])
+
+	plugin.Restore(engine, entity, history[2].ID)
+	assert.Equal(t, "a", entity.Name)
+	assert.Equal(t, uint(10), entity.Age)
+	entity = &auditLogEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "a", entity.Name)
+	assert.Len(t, plugin.GetHistory(engine, entity, nil), 4)
+
+	engine.Delete(entity)
+	history = plugin.GetHistory(engine, entity, NewPager(1, 1))
+	assert.Len(t, history, 1)
+	assert.Equal(t, FlushTypeDelete, history[0].Type)
+	entity = &auditLogEntity{ID: 1}
+	plugin.Restore(engine, entity, history[0].ID)
+	assert.True(t, entity.IsLoaded())
+	assert.Equal(t, "a", entity.Name)
+
+	lazyEntity := &auditLogEntity{Name: "lazy", Age: 1}
+	engine.FlushLazy(lazyEntity)
+	assert.Len(t, plugin.GetHistory(engine, &auditLogEntity{ID: 2}, nil), 0)
+	receiver := NewBackgroundConsumer(engine)
+	receiver.DisableBlockMode()
+	receiver.blockTime = time.Millisecond
+	receiver.Digest(context.Background())
+	lazyEntity = &auditLogEntity{}
+	assert.True(t, engine.LoadByID(2, lazyEntity))
+	history = plugin.GetHistory(engine, lazyEntity, nil)
+	assert.Len(t, history, 1)
+	assert.Equal(t, FlushTypeInsert, history[0].Type)
+	assert.Equal(t, "lazy", history[0].Changes["Name"])
+
+	assert.PanicsWithError(t, "audit log 100 for entity 'beeorm.auditLogEntity' with ID 1 not found", func() {
+		plugin.Restore(engine, entity, 100)
+	})
+	assert.PanicsWithError(t, "entity 'beeorm.auditLogEntityNoAudit' has no audit log", func() {
+		plugin.GetHistory(engine, &auditLogEntityNoAudit{ID: 1}, nil)
+	})
+}
@@ -3,6 +3,7 @@ package beeorm
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
 	"log"
 	"strconv"
 	"strings"
@@ -20,6 +21,8 @@ const LazyChannelName = "orm-lazy-channel"
 const LogChannelName = "orm-log-channel"
 const RedisStreamGarbageCollectorChannelName = "orm-stream-garbage-collector"
 const BackgroundConsumerGroupName = "orm-async-consumer"
+const LazyFlushDeadLetterChannelName = "orm-lazy-dead-letter-channel"
+const defaultLazyFlushMaxBackoff = time.Minute
 
 type LogQueueValue struct {
 	PoolName  string
@@ -40,6 +43,23 @@ type BackgroundConsumer struct {
 	lazyFlushModulo              uint64
 	lazyErrorLock                sync.Mutex
 	lazyFlushQueryErrorResolvers []LazyFlushQueryErrorResolver
+	lazyFlushRetryPolicy         *LazyFlushRetryPolicy
+	lazyOrderLock                sync.Mutex
+	lazyParked                   bool
+}
+
+type LazyFlushRetryPolicy struct {
+	MaxAttempts      int
+	Backoff          time.Duration
+	MaxBackoff       time.Duration
+	DeadLetterStream string
+}
+
+func (p *LazyFlushRetryPolicy) getMaxBackoff() time.Duration {
+	if p.MaxBackoff > 0 {
+		return p.MaxBackoff
+	}
+	return defaultLazyFlushMaxBackoff
 }
 
 func NewBackgroundConsumer(engine Engine) *BackgroundConsumer {
@@ -57,6 +77,10 @@ func (r *BackgroundConsumer) RegisterLazyFlushQueryErrorResolver(resolver LazyFl
 	r.lazyFlushQueryErrorResolvers = append(r.lazyFlushQueryErrorResolvers, resolver)
 }
 
+func (r *BackgroundConsumer) SetLazyFlushRetryPolicy(policy *LazyFlushRetryPolicy) {
+	r.lazyFlushRetryPolicy = policy
+}
+
 func (r *BackgroundConsumer) GetLazyFlushEventsSample(count int64) []string {
 	sample := make([]string, 0)
 	entries := r.engine.GetRedis().XRange(LazyChannelName, "-", "+", count)
@@ -94,6 +118,8 @@ func (r *BackgroundConsumer) SetBlockTime(ttl time.Duration) {
 func (r *BackgroundConsumer) Digest(ctx context.Context) bool {
 	r.consumer = r.engine.GetEventBroker().Consumer(BackgroundConsumerGroupName).(*eventsConsumer)
 	r.consumer.eventConsumerBase = r.eventConsumerBase
+	r.consumer.onIdle = r.retryParkedLazy
+	r.setLazyParked(getRedisForStream(r.engine, LazyChannelName).HLen(lazyParkedEventsKey) > 0)
 	return r.consumer.Consume(ctx, 500, func(events []Event) {
 		lazyEvents := make([]Event, 0)
 		lazyEventsData := make([]map[string]interface{}, 0)
@@ -120,6 +146,14 @@ func (r *BackgroundConsumer) Digest(ctx context.Context) bool {
 		}
 		l := len(lazyEvents)
 		if l > 0 {
+			r.engine.registry.writeFreeze.wait()
+			r.retryParkedLazy(ctx)
+			for _, data := range lazyEventsData {
+				r.addLazyQueryMasks(data)
+			}
+			defer func() {
+				r.engine.queryMasks = nil
+			}()
 			insertEvents := make(map[string][]int)
 			groupQueries := make(map[string]map[int]string)
 			groupEvents := make(map[string]map[int][]int)
@@ -127,6 +161,9 @@ func (r *BackgroundConsumer) Digest(ctx context.Context) bool {
 			for i, data := range lazyEventsData {
 				queries, has := data["q"]
 				ids, hasIDs := data["i"]
+				if has && r.lazyBlocked(lazyEvents[i], data) {
+					continue
+				}
 				if has {
 					validQueries := queries.([]interface{})
 					for k, query := range validQueries {
@@ -143,7 +180,7 @@ func (r *BackgroundConsumer) Digest(ctx context.Context) bool {
 						if hasIDs {
 							id, _ = strconv.ParseUint(fmt.Sprintf("%v", ids.([]interface{})[k]), 10, 64)
 						}
-						modulo := int(id % r.lazyFlushModulo)
+						modulo := int(uint64(fnv32(lazyEventKey(validInsert, id))) % r.lazyFlushModulo)
 						before := groupQueries[code][modulo]
 						before += sql + ";"
 						if groupQueries[code] == nil {
@@ -218,10 +255,12 @@ func (r *BackgroundConsumer) Digest(ctx context.Context) bool {
 										}
 									}
 									if !valid {
-										panic(err)
+										r.handleLazyError(lazyEvents[groupEvents[dbCode][key][0]], err)
+										return
 									}
 								}
 							} else {
+								var failed error
 								deadlock := false
 								func() {
 									defer func() {
@@ -249,7 +288,10 @@ func (r *BackgroundConsumer) Digest(ctx context.Context) bool {
 											}
 										}
 										if !valid {
-											panic(err)
+											if r.lazyFlushRetryPolicy == nil {
+												panic(err)
+											}
+											failed = err
 										}
 									} else {
 										db.Commit()
@@ -257,22 +299,30 @@ func (r *BackgroundConsumer) Digest(ctx context.Context) bool {
 								}()
 								if deadlock {
 									time.Sleep(time.Millisecond * 30)
-									log.Printf("DEADLOCK FOUND\n%s\n", updateSQL)
+									log.Printf("DEADLOCK FOUND\n%s\n", r.engine.maskQuery(updateSQL))
 									func() {
 										db := r.engine.Clone().GetMysql(dbCode)
 										db.Begin()
 										defer db.Rollback()
 										_, err := db.exec(updateSQL)
 										if err != nil {
-											// TODO report
+											failed = err
+											return
 										}
 										db.Commit()
 									}()
 								}
+								if failed != nil && r.lazyFlushRetryPolicy != nil {
+									for _, k := range groupEvents[dbCode][key] {
+										r.handleLazy(lazyEvents[k], lazyEventsData[k])
+									}
+									return
+								}
 							}
 							for _, k := range groupEvents[dbCode][key] {
 								lazyEvents[k].Ack()
 								r.handleCache(lazyEventsData[k], nil)
+								r.lazyExecuted(lazyEvents[k], lazyEventsData[k])
 							}
 						}()
 					}
@@ -341,12 +391,107 @@ func (r *BackgroundConsumer) handleLog(values map[string][]*LogQueueValue) {
 }
 
 func (r *BackgroundConsumer) handleLazy(event Event, data map[string]interface{}) {
+	if r.lazyBlocked(event, data) {
+		return
+	}
 	ids, err := r.handleQueries(r.engine, data)
 	if err != nil {
-		panic(err)
+		r.handleLazyError(event, err)
+		return
 	}
 	r.handleCache(data, ids)
 	event.Ack()
+	r.lazyExecuted(event, data)
+}
+
+// addLazyQueryMasks registers queries with masked values sent by flusher, so query logs never show masked columns
+func (r *BackgroundConsumer) addLazyQueryMasks(data map[string]interface{}) {
+	queries, has := data["q"]
+	if !has {
+		return
+	}
+	for _, query := range queries.([]interface{}) {
+		validQuery := query.([]interface{})
+		if len(validQuery) > 3 {
+			r.engine.addQueryMask(validQuery[1].(string), validQuery[3].(string))
+		}
+	}
+}
+
+func (r *BackgroundConsumer) handleLazyError(event Event, err error) {
+	if r.lazyFlushRetryPolicy == nil {
+		panic(err)
+	}
+	var data map[string]interface{}
+	event.Unserialize(&data)
+	r.requeueLazy(event, data, err)
+}
+
+func (r *BackgroundConsumer) requeueLazy(event Event, data map[string]interface{}, err error) {
+	policy := r.lazyFlushRetryPolicy
+	attempt, _ := strconv.Atoi(event.Tag("attempt"))
+	attempt++
+	meta := []string{"lazy_token", getLazyEventToken(event), "error", err.Error(), "attempt", strconv.Itoa(attempt)}
+	if attempt >= policy.MaxAttempts {
+		stream := policy.DeadLetterStream
+		if stream == "" {
+			stream = LazyFlushDeadLetterChannelName
+		}
+		r.engine.GetEventBroker().Publish(stream, data, meta...)
+		event.Ack()
+		r.lazyExecuted(event, data)
+		return
+	}
+	maxBackoff := policy.getMaxBackoff()
+	backoff := policy.Backoff
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	r.parkLazy(event, data, meta, time.Now().Add(backoff))
+}
+
+func getLazyEventToken(event Event) string {
+	token := event.Tag("lazy_token")
+	if token == "" {
+		return event.ID()
+	}
+	return token
+}
+
+func getLazyEventKeys(data map[string]interface{}) []string {
+	queries, has := data["q"]
+	if !has {
+		return nil
+	}
+	ids, _ := data["i"].([]interface{})
+	keys := make([]string, 0)
+	for k, query := range queries.([]interface{}) {
+		id := uint64(0)
+		if k < len(ids) {
+			id, _ = strconv.ParseUint(fmt.Sprintf("%v", ids[k]), 10, 64)
+		}
+		if id > 0 {
+			keys = append(keys, lazyEventKey(query.([]interface{}), id))
+		}
+	}
+	return keys
+}
+
+func fnv32(value string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(value))
+	return h.Sum32()
+}
+
+func lazyEventKey(query []interface{}, id uint64) string {
+	tableName := ""
+	if len(query) > 2 {
+		tableName, _ = query[2].(string)
+	}
+	return tableName + ":" + strconv.FormatUint(id, 10)
 }
 
 func (r *BackgroundConsumer) handleQueries(engine *engineImplementation, validMap map[string]interface{}) ([]uint64, error) {
@@ -420,6 +565,17 @@ func (r *BackgroundConsumer) handleCache(validMap map[string]interface{}, ids []
 			cache.Del(stringKeys...)
 		}
 	}
+	variants, has := validMap["cv"]
+	if has {
+		for cacheCode, allKeys := range variants.(map[interface{}]interface{}) {
+			validAllKeys := allKeys.([]interface{})
+			stringKeys := make([]string, len(validAllKeys))
+			for i, v := range validAllKeys {
+				stringKeys[i] = v.(string)
+			}
+			deleteCacheKeyVariants(r.engine.GetRedis(cacheCode.(string)), stringKeys...)
+		}
+	}
 	localCache, has := validMap["cl"]
 	if has {
 		validKeys := localCache.(map[interface{}]interface{})
@@ -453,6 +609,7 @@ func (r *BackgroundConsumer) handleCache(validMap map[string]interface{}, ids []
 			r.handleLog(map[string][]*LogQueueValue{logEvent.PoolName: {logEvent}})
 		}
 	}
+	emitLazyFlushedEvents(r.engine, validMap, ids)
 }
 
 func (r *BackgroundConsumer) handleRedisChannelGarbageCollector(event Event) {
@@ -0,0 +1,158 @@
+package beeormtest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/latolukasz/beeorm"
+)
+
+var writeQueryRegexp = regexp.MustCompile("(?i)^\\s*(?:INSERT(?:\\s+IGNORE)?\\s+INTO|REPLACE\\s+INTO|UPDATE(?:\\s+IGNORE)?|DELETE\\s+FROM)\\s+(?:`([^`]+)`\\.)?`([^`]+)`")
+
+type FakeClock struct {
+	now time.Time
+	sync.Mutex
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) Set(now time.Time) {
+	c.Lock()
+	defer c.Unlock()
+	c.now = now
+}
+
+func (c *FakeClock) Advance(duration time.Duration) time.Time {
+	c.Lock()
+	defer c.Unlock()
+	c.now = c.now.Add(duration)
+	return c.now
+}
+
+type Option func(options *options)
+
+type options struct {
+	truncate  bool
+	keepRedis bool
+	now       time.Time
+}
+
+func WithTruncate() Option {
+	return func(options *options) {
+		options.truncate = true
+	}
+}
+
+func WithKeepRedis() Option {
+	return func(options *options) {
+		options.keepRedis = true
+	}
+}
+
+func WithTime(now time.Time) Option {
+	return func(options *options) {
+		options.now = now
+	}
+}
+
+type tablesTracker struct {
+	tables map[string]map[string]bool
+	sync.Mutex
+}
+
+func (t *tablesTracker) Handle(log map[string]interface{}) {
+	query, _ := log["query"].(string)
+	matches := writeQueryRegexp.FindStringSubmatch(query)
+	if matches == nil {
+		return
+	}
+	pool, _ := log["pool"].(string)
+	t.Lock()
+	defer t.Unlock()
+	if t.tables[pool] == nil {
+		t.tables[pool] = make(map[string]bool)
+	}
+	table := "`" + matches[2] + "`"
+	if matches[1] != "" {
+		table = "`" + matches[1] + "`." + table
+	}
+	t.tables[pool][table] = true
+}
+
+// NewEngine wraps test in MySQL transactions rolled back on cleanup, use WithTruncate when test runs own transactions.
+func NewEngine(t testing.TB, registry beeorm.ValidatedRegistry, option ...Option) beeorm.Engine {
+	opts := &options{now: time.Now().Truncate(time.Second)}
+	for _, o := range option {
+		o(opts)
+	}
+	engine := registry.CreateEngine()
+	engine.SetClock(NewFakeClock(opts.now))
+	pools := make([]string, 0, len(registry.GetMySQLPools()))
+	for code := range registry.GetMySQLPools() {
+		pools = append(pools, code)
+	}
+	sort.Strings(pools)
+	tracker := &tablesTracker{tables: make(map[string]map[string]bool)}
+	if opts.truncate {
+		engine.RegisterQueryLogger(tracker, true, false, false)
+	} else {
+		for _, code := range pools {
+			engine.GetMysql(code).Begin()
+		}
+	}
+	t.Cleanup(func() {
+		if opts.truncate {
+			truncateTables(engine, tracker)
+		} else {
+			for _, code := range pools {
+				engine.GetMysql(code).Rollback()
+			}
+		}
+		for code := range registry.GetLocalCachePools() {
+			engine.GetLocalCache(code).Clear()
+		}
+		if !opts.keepRedis {
+			for code := range registry.GetRedisPools() {
+				engine.GetRedis(code).FlushDB()
+			}
+		}
+	})
+	return engine
+}
+
+func GetClock(engine beeorm.Engine) *FakeClock {
+	clock, is := engine.GetClock().(*FakeClock)
+	if !is {
+		panic(fmt.Errorf("engine is not created by beeormtest"))
+	}
+	return clock
+}
+
+func truncateTables(engine beeorm.Engine, tracker *tablesTracker) {
+	tracker.Lock()
+	touched := tracker.tables
+	tracker.tables = make(map[string]map[string]bool)
+	tracker.Unlock()
+	for pool, tables := range touched {
+		db := engine.GetMysql(pool)
+		db.Begin()
+		db.Exec("SET FOREIGN_KEY_CHECKS = 0")
+		for table := range tables {
+			db.Exec("DELETE FROM " + table)
+		}
+		db.Exec("SET FOREIGN_KEY_CHECKS = 1")
+		db.Commit()
+	}
+}
@@ -0,0 +1,93 @@
+package beeormtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/latolukasz/beeorm"
+	"github.com/stretchr/testify/assert"
+)
+
+type harnessEntity struct {
+	beeorm.ORM `orm:"localCache;redisCache"`
+	ID         uint
+	Name       string
+	CreatedAt  time.Time `orm:"createdAt;time"`
+}
+
+func TestFakeClock(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(now)
+	assert.Equal(t, now, clock.Now())
+	assert.Equal(t, now.Add(time.Minute), clock.Advance(time.Minute))
+	assert.Equal(t, now.Add(time.Minute), clock.Now())
+	clock.Set(now)
+	assert.Equal(t, now, clock.Now())
+}
+
+func TestTablesTracker(t *testing.T) {
+	tracker := &tablesTracker{tables: make(map[string]map[string]bool)}
+	tracker.Handle(map[string]interface{}{"pool": "default", "query": "INSERT INTO `test`.`a`(`ID`) VALUES (?)"})
+	tracker.Handle(map[string]interface{}{"pool": "default", "query": "UPDATE `b` SET `Name` = ? WHERE `ID` = ?"})
+	tracker.Handle(map[string]interface{}{"pool": "log", "query": "delete from `c` WHERE `ID` = ?"})
+	tracker.Handle(map[string]interface{}{"pool": "default", "query": "SELECT * FROM `d`"})
+	assert.Equal(t, map[string]map[string]bool{"default": {"`test`.`a`": true, "`b`": true}, "log": {"`c`": true}}, tracker.tables)
+}
+
+func createRegistry(t *testing.T) beeorm.ValidatedRegistry {
+	registry := &beeorm.Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterRedis("localhost:6382", "", 15)
+	registry.RegisterLocalCache(1000)
+	registry.RegisterEntity(&harnessEntity{})
+	registry.SetTimestampsLocation(time.UTC)
+	validated, err := registry.Validate()
+	assert.NoError(t, err)
+	engine := validated.CreateEngine()
+	for _, alter := range engine.GetAlters() {
+		alter.Exec()
+	}
+	validated.GetTableSchemaForEntity(&harnessEntity{}).TruncateTable(engine)
+	return validated
+}
+
+func TestNewEngineRollback(t *testing.T) {
+	registry := createRegistry(t)
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	t.Run("insert", func(t *testing.T) {
+		engine := NewEngine(t, registry, WithTime(now))
+		entity := &harnessEntity{Name: "a"}
+		engine.Flush(entity)
+		assert.Equal(t, now, entity.CreatedAt)
+		GetClock(engine).Advance(time.Hour)
+		entity = &harnessEntity{Name: "b"}
+		engine.Flush(entity)
+		assert.Equal(t, now.Add(time.Hour), entity.CreatedAt)
+		assert.True(t, engine.LoadByID(entity.GetID(), &harnessEntity{}))
+	})
+	engine := registry.CreateEngine()
+	var rows []*harnessEntity
+	engine.Search(beeorm.NewWhere("1"), nil, &rows)
+	assert.Len(t, rows, 0)
+}
+
+func TestNewEngineTruncate(t *testing.T) {
+	registry := createRegistry(t)
+	t.Run("insert", func(t *testing.T) {
+		engine := NewEngine(t, registry, WithTruncate())
+		db := engine.GetMysql()
+		db.Begin()
+		engine.Flush(&harnessEntity{Name: "a"}, &harnessEntity{Name: "b"})
+		db.Commit()
+		var rows []*harnessEntity
+		engine.Search(beeorm.NewWhere("1"), nil, &rows)
+		assert.Len(t, rows, 2)
+	})
+	engine := registry.CreateEngine()
+	var rows []*harnessEntity
+	engine.Search(beeorm.NewWhere("1"), nil, &rows)
+	assert.Len(t, rows, 0)
+	assert.Panics(t, func() {
+		GetClock(engine)
+	})
+}
@@ -27,21 +27,28 @@ type bindBuilder struct {
 }
 
 func newBindBuilder(id uint64, orm *ORM) *bindBuilder {
-	b := &bindBuilder{
-		id:       id,
-		orm:      orm,
-		buildSQL: !orm.delete,
-		bind:     Bind{},
-		index:    -1,
+	b := getBindBuilder()
+	b.id = id
+	b.orm = orm
+	b.buildSQL = !orm.delete
+	b.index = -1
+	if b.bind == nil {
+		b.bind = Bind{}
 	}
 	if orm.delete {
 		b.sqlBind = nil
-	} else {
+	} else if b.sqlBind == nil {
 		b.sqlBind = make(map[string]string)
 	}
-	if orm.delete || orm.tableSchema.hasLog || len(orm.tableSchema.cachedIndexesAll) > 0 {
+	if orm.delete || orm.tableSchema.hasLog || orm.tableSchema.hasAudit || orm.tableSchema.hasTemporal ||
+		len(orm.tableSchema.cachedIndexesAll) > 0 || orm.tableSchema.treeParentColumn != "" ||
+		len(orm.tableSchema.fileColumns) > 0 || len(orm.tableSchema.crudSubscribers) > 0 {
 		b.hasCurrent = true
-		b.current = Bind{}
+		if b.current == nil {
+			b.current = Bind{}
+		}
+	} else {
+		b.current = nil
 	}
 	return b
 }
@@ -70,6 +77,7 @@ func (b *bindBuilder) build(serializer *serializer, fields *tableFields, value r
 	b.buildDatesNullable(serializer, fields, value)
 	b.buildJSONs(serializer, fields, value)
 	b.buildRefsMany(serializer, fields, value)
+	b.buildCustoms(serializer, fields, value)
 	for k, i := range fields.structs {
 		b.build(serializer, fields.structsFields[k], value.Field(i), false)
 	}
@@ -432,7 +440,7 @@ func (b *bindBuilder) buildEnums(serializer *serializer, fields *tableFields, va
 	k := 0
 	for _, i := range fields.stringsEnums {
 		b.index++
-		val := value.Field(i).String()
+		val := getEnumFieldValue(value.Field(i))
 		enum := fields.enums[k]
 		name := b.orm.tableSchema.columnNames[b.index]
 		k++
@@ -451,6 +459,9 @@ func (b *bindBuilder) buildEnums(serializer *serializer, fields *tableFields, va
 		}
 		if val != "" {
 			if !enum.Has(val) {
+				if b.orm.tableSchema.registry.registry.strictEnums {
+					panic(b.newEnumValueError(name, val))
+				}
 				panic(errors.New("unknown enum value for " + name + " - " + val))
 			}
 			b.bind[name] = val
@@ -569,6 +580,13 @@ func (b *bindBuilder) buildSets(serializer *serializer, fields *tableFields, val
 			}
 		}
 		if l > 0 {
+			if b.orm.tableSchema.registry.registry.strictEnums {
+				for _, v := range val {
+					if !set.Has(v) {
+						panic(b.newEnumValueError(name, v))
+					}
+				}
+			}
 			valAsString := strings.Join(val, ",")
 			b.bind[name] = valAsString
 			if b.buildSQL {
@@ -805,6 +823,13 @@ func (b *bindBuilder) buildJSONs(serializer *serializer, fields *tableFields, va
 				if cmp.Equal(oldValue, newValue) {
 					continue
 				}
+				if b.buildSQL && b.orm.tableSchema.tags[name]["jsonSet"] == "true" {
+					if expression, valid := buildJSONSetExpression(name, old, v); valid {
+						b.bind[name] = asString
+						b.sqlBind[name] = expression
+						continue
+					}
+				}
 			}
 		}
 		if !isNil {
@@ -907,3 +932,13 @@ func (b *bindBuilder) buildRefsMany(serializer *serializer, fields *tableFields,
 		}
 	}
 }
+
+func getEnumFieldValue(field reflect.Value) string {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return ""
+		}
+		return field.Elem().String()
+	}
+	return field.String()
+}
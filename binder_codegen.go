@@ -0,0 +1,278 @@
+package beeorm
+
+import (
+	"fmt"
+	"go/format"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type BinarySerializer interface {
+	SerializeUInteger(v uint64)
+	SerializeInteger(v int64)
+	SerializeBool(v bool)
+	SerializeFloat(v float64)
+	SerializeString(v string)
+	SerializeBytes(val []byte)
+	DeserializeUInteger() uint64
+	DeserializeInteger() int64
+	DeserializeBool() bool
+	DeserializeFloat() float64
+	DeserializeString() string
+	DeserializeBytes() []byte
+}
+
+type EntityBinder interface {
+	BeeormBinderHash() uint64
+	BeeormSerialize(s BinarySerializer)
+	BeeormDeserialize(s BinarySerializer)
+}
+
+type binderField struct {
+	category  string
+	field     reflect.StructField
+	precision int
+}
+
+func SerializeBinderTime(s BinarySerializer, t time.Time, dateOnly, nullable bool) {
+	if !nullable && t.IsZero() {
+		s.SerializeInteger(zeroDateSeconds)
+		return
+	}
+	if dateOnly {
+		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+	unix := t.Unix()
+	if unix > 0 {
+		unix += timeStampSeconds
+	} else {
+		unix = zeroDateSeconds
+	}
+	s.SerializeInteger(unix)
+}
+
+func DeserializeBinderTime(s BinarySerializer, nullable bool) time.Time {
+	unix := s.DeserializeInteger()
+	if !nullable && unix == zeroDateSeconds {
+		return time.Time{}
+	}
+	return time.Unix(unix-timeStampSeconds, 0)
+}
+
+func getBinderLayout(schema *tableSchema) ([]binderField, error) {
+	fields := schema.fields
+	unsupported := [][]int{fields.refs, fields.stringsEnums, fields.sliceStringsSets, fields.jsons, fields.refsMany, fields.customs, fields.structs}
+	for _, indexes := range unsupported {
+		if len(indexes) > 0 {
+			return nil, fmt.Errorf("field %s in %s is not supported by binder generator", fields.fields[indexes[0]].Name, schema.t.String())
+		}
+	}
+	layout := make([]binderField, 0)
+	add := func(category string, indexes []int, precisions []int) error {
+		for k, i := range indexes {
+			field := fields.fields[i]
+			t := field.Type
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.PkgPath() != "" && t.String() != "time.Time" {
+				return fmt.Errorf("field %s in %s with named type %s is not supported by binder generator", field.Name, schema.t.String(), t.String())
+			}
+			precision := 0
+			if precisions != nil {
+				precision = precisions[k]
+			}
+			layout = append(layout, binderField{category: category, field: field, precision: precision})
+		}
+		return nil
+	}
+	groups := []struct {
+		category   string
+		indexes    []int
+		precisions []int
+	}{
+		{"uinteger", fields.uintegers, nil},
+		{"integer", fields.integers, nil},
+		{"boolean", fields.booleans, nil},
+		{"float", fields.floats, fields.floatsPrecision},
+		{"time", fields.times, nil},
+		{"date", fields.dates, nil},
+	}
+	if fields.fakeDelete > 0 {
+		groups = append(groups, struct {
+			category   string
+			indexes    []int
+			precisions []int
+		}{"boolean", []int{fields.fakeDelete}, nil})
+	}
+	groups = append(groups, []struct {
+		category   string
+		indexes    []int
+		precisions []int
+	}{
+		{"string", fields.strings, nil},
+		{"uintegerNullable", fields.uintegersNullable, nil},
+		{"integerNullable", fields.integersNullable, nil},
+		{"bytes", fields.bytes, nil},
+		{"booleanNullable", fields.booleansNullable, nil},
+		{"floatNullable", fields.floatsNullable, fields.floatsNullablePrecision},
+		{"timeNullable", fields.timesNullable, nil},
+		{"dateNullable", fields.datesNullable, nil},
+	}...)
+	for _, group := range groups {
+		err := add(group.category, group.indexes, group.precisions)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return layout, nil
+}
+
+func getBinderHash(schema *tableSchema, layout []binderField) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strconv.FormatUint(schema.structureHash, 10)))
+	for _, field := range layout {
+		_, _ = h.Write([]byte(fmt.Sprintf(";%s:%s:%s:%d", field.category, field.field.Name, field.field.Type.String(), field.precision)))
+	}
+	return h.Sum64()
+}
+
+func initBinder(tableSchema *tableSchema, entityType reflect.Type) {
+	tableSchema.hasBinder = false
+	binder, is := reflect.New(entityType).Interface().(EntityBinder)
+	if !is {
+		return
+	}
+	layout, err := getBinderLayout(tableSchema)
+	if err != nil {
+		return
+	}
+	tableSchema.hasBinder = binder.BeeormBinderHash() == getBinderHash(tableSchema, layout)
+}
+
+func GenerateBinders(registry ValidatedRegistry, packageName string, entities ...Entity) (string, error) {
+	validated := registry.(*validatedRegistry)
+	prefix := "beeorm."
+	if packageName == "beeorm" {
+		prefix = ""
+	}
+	types := make([]reflect.Type, 0, len(entities))
+	for _, entity := range entities {
+		types = append(types, reflect.TypeOf(entity).Elem())
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Name() < types[j].Name()
+	})
+	body := &strings.Builder{}
+	imports := map[string]bool{}
+	for _, t := range types {
+		schema := getTableSchema(validated, t)
+		if schema == nil {
+			return "", fmt.Errorf("entity '%s' is not registered", t.String())
+		}
+		layout, err := getBinderLayout(schema)
+		if err != nil {
+			return "", err
+		}
+		serialize := &strings.Builder{}
+		deserialize := &strings.Builder{}
+		for _, field := range layout {
+			writeBinderField(serialize, deserialize, field, prefix, imports)
+		}
+		name := t.Name()
+		body.WriteString(fmt.Sprintf("\nfunc (e *%s) BeeormBinderHash() uint64 {\nreturn %d\n}\n", name, getBinderHash(schema, layout)))
+		body.WriteString(fmt.Sprintf("\nfunc (e *%s) BeeormSerialize(s %sBinarySerializer) {\n%s}\n", name, prefix, serialize.String()))
+		body.WriteString(fmt.Sprintf("\nfunc (e *%s) BeeormDeserialize(s %sBinarySerializer) {\n%s}\n", name, prefix, deserialize.String()))
+	}
+	if prefix != "" {
+		imports["github.com/latolukasz/beeorm"] = true
+	}
+	code := &strings.Builder{}
+	code.WriteString("// Code generated by beeorm. DO NOT EDIT.\n\npackage " + packageName + "\n")
+	if len(imports) > 0 {
+		paths := make([]string, 0, len(imports))
+		for path := range imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		code.WriteString("\nimport (\n")
+		for _, path := range paths {
+			code.WriteString("\"" + path + "\"\n")
+		}
+		code.WriteString(")\n")
+	}
+	code.WriteString(body.String())
+	formatted, err := format.Source([]byte(code.String()))
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
+
+func writeBinderField(serialize, deserialize *strings.Builder, field binderField, prefix string, imports map[string]bool) {
+	name := "e." + field.field.Name
+	t := field.field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	typeName := t.String()
+	convert := func(to, value string) string {
+		if typeName == to {
+			return value
+		}
+		return to + "(" + value + ")"
+	}
+	rounded := func(value string) string {
+		imports["math"] = true
+		p := "math.Pow10(" + strconv.Itoa(field.precision) + ")"
+		return "math.Round(" + convert("float64", value) + "*" + p + ") / " + p
+	}
+	converted := func(value string) string {
+		if typeName == "uint64" || typeName == "int64" || typeName == "float64" {
+			return value
+		}
+		return typeName + "(" + value + ")"
+	}
+	nullable := func(serializeValue, deserializeValue string) {
+		serialize.WriteString("if " + name + " == nil {\ns.SerializeBool(false)\n} else {\ns.SerializeBool(true)\n" + serializeValue + "\n}\n")
+		deserialize.WriteString("if s.DeserializeBool() {\nv := " + deserializeValue + "\n" + name + " = &v\n} else {\n" + name + " = nil\n}\n")
+	}
+	switch field.category {
+	case "uinteger":
+		serialize.WriteString("s.SerializeUInteger(" + convert("uint64", name) + ")\n")
+		deserialize.WriteString(name + " = " + converted("s.DeserializeUInteger()") + "\n")
+	case "integer":
+		serialize.WriteString("s.SerializeInteger(" + convert("int64", name) + ")\n")
+		deserialize.WriteString(name + " = " + converted("s.DeserializeInteger()") + "\n")
+	case "boolean":
+		serialize.WriteString("s.SerializeBool(" + name + ")\n")
+		deserialize.WriteString(name + " = s.DeserializeBool()\n")
+	case "float":
+		serialize.WriteString("s.SerializeFloat(" + rounded(name) + ")\n")
+		deserialize.WriteString(name + " = " + converted("s.DeserializeFloat()") + "\n")
+	case "time", "date":
+		serialize.WriteString(prefix + "SerializeBinderTime(s, " + name + ", " + strconv.FormatBool(field.category == "date") + ", false)\n")
+		deserialize.WriteString(name + " = " + prefix + "DeserializeBinderTime(s, false)\n")
+	case "string":
+		serialize.WriteString("s.SerializeString(" + name + ")\n")
+		deserialize.WriteString(name + " = s.DeserializeString()\n")
+	case "bytes":
+		serialize.WriteString("s.SerializeBytes(" + name + ")\n")
+		deserialize.WriteString(name + " = s.DeserializeBytes()\n")
+	case "uintegerNullable":
+		nullable("s.SerializeUInteger("+convert("uint64", "*"+name)+")", converted("s.DeserializeUInteger()"))
+	case "integerNullable":
+		nullable("s.SerializeInteger("+convert("int64", "*"+name)+")", converted("s.DeserializeInteger()"))
+	case "booleanNullable":
+		nullable("s.SerializeBool(*"+name+")", "s.DeserializeBool()")
+	case "floatNullable":
+		nullable("s.SerializeFloat("+rounded("*"+name)+")", converted("s.DeserializeFloat()"))
+	case "timeNullable", "dateNullable":
+		nullable(prefix+"SerializeBinderTime(s, *"+name+", "+strconv.FormatBool(field.category == "dateNullable")+", true)",
+			prefix+"DeserializeBinderTime(s, true)")
+	}
+}
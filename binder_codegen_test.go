@@ -0,0 +1,215 @@
+package beeorm
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type binderEntity struct {
+	ORM         `orm:"localCache"`
+	ID          uint
+	Name        string `orm:"required"`
+	Age         uint8
+	Balance     int32
+	Active      bool
+	Price       float64 `orm:"decimal=10,2"`
+	Weight      float32
+	Born        time.Time
+	LastVisit   time.Time `orm:"time"`
+	Image       []byte
+	Score       *int
+	Rank        *uint16
+	Premium     *bool
+	Rating      *float64 `orm:"precision=3"`
+	Confirmed   *time.Time
+	ConfirmedAt *time.Time `orm:"time"`
+	FakeDelete  bool
+}
+
+type binderUnsupportedEntity struct {
+	ORM
+	ID  uint
+	Ref *binderEntity
+}
+
+func (e *binderEntity) BeeormBinderHash() uint64 {
+	return 7752975583149125371
+}
+
+func (e *binderEntity) BeeormSerialize(s BinarySerializer) {
+	s.SerializeUInteger(uint64(e.ID))
+	s.SerializeUInteger(uint64(e.Age))
+	s.SerializeInteger(int64(e.Balance))
+	s.SerializeBool(e.Active)
+	s.SerializeFloat(math.Round(e.Price*math.Pow10(2)) / math.Pow10(2))
+	s.SerializeFloat(math.Round(float64(e.Weight)*math.Pow10(4)) / math.Pow10(4))
+	SerializeBinderTime(s, e.LastVisit, false, false)
+	SerializeBinderTime(s, e.Born, true, false)
+	s.SerializeBool(e.FakeDelete)
+	s.SerializeString(e.Name)
+	if e.Rank == nil {
+		s.SerializeBool(false)
+	} else {
+		s.SerializeBool(true)
+		s.SerializeUInteger(uint64(*e.Rank))
+	}
+	if e.Score == nil {
+		s.SerializeBool(false)
+	} else {
+		s.SerializeBool(true)
+		s.SerializeInteger(int64(*e.Score))
+	}
+	s.SerializeBytes(e.Image)
+	if e.Premium == nil {
+		s.SerializeBool(false)
+	} else {
+		s.SerializeBool(true)
+		s.SerializeBool(*e.Premium)
+	}
+	if e.Rating == nil {
+		s.SerializeBool(false)
+	} else {
+		s.SerializeBool(true)
+		s.SerializeFloat(math.Round(*e.Rating*math.Pow10(3)) / math.Pow10(3))
+	}
+	if e.ConfirmedAt == nil {
+		s.SerializeBool(false)
+	} else {
+		s.SerializeBool(true)
+		SerializeBinderTime(s, *e.ConfirmedAt, false, true)
+	}
+	if e.Confirmed == nil {
+		s.SerializeBool(false)
+	} else {
+		s.SerializeBool(true)
+		SerializeBinderTime(s, *e.Confirmed, true, true)
+	}
+}
+
+func (e *binderEntity) BeeormDeserialize(s BinarySerializer) {
+	e.ID = uint(s.DeserializeUInteger())
+	e.Age = uint8(s.DeserializeUInteger())
+	e.Balance = int32(s.DeserializeInteger())
+	e.Active = s.DeserializeBool()
+	e.Price = s.DeserializeFloat()
+	e.Weight = float32(s.DeserializeFloat())
+	e.LastVisit = DeserializeBinderTime(s, false)
+	e.Born = DeserializeBinderTime(s, false)
+	e.FakeDelete = s.DeserializeBool()
+	e.Name = s.DeserializeString()
+	if s.DeserializeBool() {
+		v := uint16(s.DeserializeUInteger())
+		e.Rank = &v
+	} else {
+		e.Rank = nil
+	}
+	if s.DeserializeBool() {
+		v := int(s.DeserializeInteger())
+		e.Score = &v
+	} else {
+		e.Score = nil
+	}
+	e.Image = s.DeserializeBytes()
+	if s.DeserializeBool() {
+		v := s.DeserializeBool()
+		e.Premium = &v
+	} else {
+		e.Premium = nil
+	}
+	if s.DeserializeBool() {
+		v := s.DeserializeFloat()
+		e.Rating = &v
+	} else {
+		e.Rating = nil
+	}
+	if s.DeserializeBool() {
+		v := DeserializeBinderTime(s, true)
+		e.ConfirmedAt = &v
+	} else {
+		e.ConfirmedAt = nil
+	}
+	if s.DeserializeBool() {
+		v := DeserializeBinderTime(s, true)
+		e.Confirmed = &v
+	} else {
+		e.Confirmed = nil
+	}
+}
+
+func TestBinderTime(t *testing.T) {
+	s := newSerializer(nil)
+	SerializeBinderTime(s, time.Time{}, false, false)
+	SerializeBinderTime(s, time.Date(2022, 3, 4, 12, 30, 0, 0, time.UTC), true, false)
+	SerializeBinderTime(s, time.Time{}, false, true)
+	s.Reset(s.Read())
+	assert.True(t, DeserializeBinderTime(s, false).IsZero())
+	assert.Equal(t, time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC).Unix(), DeserializeBinderTime(s, false).Unix())
+	assert.Equal(t, int64(zeroDateSeconds-timeStampSeconds), DeserializeBinderTime(s, true).Unix())
+}
+
+func TestBinders(t *testing.T) {
+	var entity *binderEntity
+	var unsupported *binderUnsupportedEntity
+	registry := &Registry{}
+	registry.RegisterLocalCache(1000)
+	engine := prepareTables(t, registry, 5, 6, "", entity, unsupported)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema)
+	assert.True(t, schema.hasBinder)
+	assert.False(t, engine.GetRegistry().GetTableSchemaForEntity(unsupported).(*tableSchema).hasBinder)
+
+	code, err := GenerateBinders(engine.GetRegistry(), "beeorm", entity)
+	assert.NoError(t, err)
+	assert.Contains(t, code, "// Code generated by beeorm. DO NOT EDIT.\n\npackage beeorm\n\nimport (\n\t\"math\"\n)\n")
+	assert.Contains(t, code, "func (e *binderEntity) BeeormSerialize(s BinarySerializer) {\n\ts.SerializeUInteger(uint64(e.ID))\n")
+	assert.Contains(t, code, "\tSerializeBinderTime(s, e.LastVisit, false, false)\n")
+	code, err = GenerateBinders(engine.GetRegistry(), "entities", entity)
+	assert.NoError(t, err)
+	assert.Contains(t, code, "\t\"github.com/latolukasz/beeorm\"\n")
+	assert.Contains(t, code, "func (e *binderEntity) BeeormDeserialize(s beeorm.BinarySerializer) {\n")
+	assert.Contains(t, code, "\t\tv := beeorm.DeserializeBinderTime(s, true)\n")
+	_, err = GenerateBinders(engine.GetRegistry(), "beeorm", unsupported)
+	assert.EqualError(t, err, "field Ref in beeorm.binderUnsupportedEntity is not supported by binder generator")
+
+	score := -12
+	rank := uint16(3)
+	premium := true
+	rating := 4.12345
+	confirmed := time.Date(2022, 5, 6, 0, 0, 0, 0, time.UTC)
+	entity = &binderEntity{Name: "Tom", Age: 18, Balance: -20, Active: true, Price: 12.345, Weight: 1.5,
+		Born: time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC), LastVisit: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		Image: []byte("image"), Score: &score, Rank: &rank, Premium: &premium, Rating: &rating, Confirmed: &confirmed}
+	engine.Flush(entity)
+
+	orm := entity.getORM()
+	reflection := newSerializer(nil)
+	orm.serializeFields(reflection, schema.fields, orm.elem, true)
+	assert.Equal(t, reflection.Read(), orm.binary)
+
+	entity = &binderEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "Tom", entity.Name)
+	assert.Equal(t, uint8(18), entity.Age)
+	assert.Equal(t, int32(-20), entity.Balance)
+	assert.Equal(t, 12.35, entity.Price)
+	assert.Equal(t, "image", string(entity.Image))
+	assert.Equal(t, -12, *entity.Score)
+	assert.Equal(t, uint16(3), *entity.Rank)
+	assert.True(t, *entity.Premium)
+	assert.Equal(t, 4.123, *entity.Rating)
+	assert.Equal(t, confirmed.Unix(), entity.Confirmed.Unix())
+	assert.Nil(t, entity.ConfirmedAt)
+	assert.Equal(t, "2022-01-02 03:04:05", entity.LastVisit.UTC().Format(timeFormat))
+
+	entity.Age = 19
+	entity.Score = nil
+	assert.True(t, entity.IsDirty())
+	engine.Flush(entity)
+	entity = &binderEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, uint8(19), entity.Age)
+	assert.Nil(t, entity.Score)
+	assert.False(t, entity.IsDirty())
+}
@@ -0,0 +1,159 @@
+package beeorm
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"reflect"
+	"sync"
+)
+
+const cacheCompressionMarker = byte(0)
+const flateDictionaryMaxSize = 32 * 1024
+
+type CacheCompressor interface {
+	Compress(schema TableSchema, value []byte) []byte
+	Decompress(schema TableSchema, value []byte) ([]byte, error)
+}
+
+func (r *Registry) SetRedisCacheCompression(compressor CacheCompressor, minSize int) {
+	r.cacheCompressor = compressor
+	r.cacheCompressionMinSize = minSize
+}
+
+// FlateCacheCompressor uses DEFLATE from the standard library instead of zstd so the ORM does not
+// depend on a third-party compression package. Plug zstd in by implementing CacheCompressor.
+// Dictionaries are identified by versions and must be registered with the same content on every instance.
+type FlateCacheCompressor struct {
+	level        int
+	mutex        sync.RWMutex
+	dictionaries map[string]map[uint64][]byte
+	latest       map[string]uint64
+}
+
+func NewFlateCacheCompressor(level int) *FlateCacheCompressor {
+	return &FlateCacheCompressor{level: level, dictionaries: make(map[string]map[uint64][]byte), latest: make(map[string]uint64)}
+}
+
+func (c *FlateCacheCompressor) AddDictionary(schema TableSchema, version uint64, dictionary []byte) {
+	if version == 0 {
+		panic(fmt.Errorf("invalid compression dictionary version 0 for %s", schema.GetTableName()))
+	}
+	if len(dictionary) > flateDictionaryMaxSize {
+		dictionary = dictionary[len(dictionary)-flateDictionaryMaxSize:]
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	tableName := schema.GetTableName()
+	if c.dictionaries[tableName] == nil {
+		c.dictionaries[tableName] = make(map[uint64][]byte)
+	}
+	before, has := c.dictionaries[tableName][version]
+	if has && !bytes.Equal(before, dictionary) {
+		panic(fmt.Errorf("compression dictionary %d for %s already registered", version, tableName))
+	}
+	c.dictionaries[tableName][version] = dictionary
+	if version > c.latest[tableName] {
+		c.latest[tableName] = version
+	}
+}
+
+func (c *FlateCacheCompressor) GetDictionary(schema TableSchema, version uint64) (dictionary []byte, has bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	dictionary, has = c.dictionaries[schema.GetTableName()][version]
+	return dictionary, has
+}
+
+// Train builds a dictionary from the latest rows. Persist it and register it with AddDictionary on every instance.
+func (c *FlateCacheCompressor) Train(engine Engine, entity Entity, samples int) []byte {
+	schema := initIfNeeded(engine.(*engineImplementation).registry, entity).tableSchema
+	rows := reflect.New(reflect.SliceOf(reflect.PtrTo(schema.t)))
+	engine.Search(NewWhere("1 ORDER BY `ID` DESC"), NewPager(1, samples), rows.Interface())
+	dictionary := make([]byte, 0)
+	for i := rows.Elem().Len() - 1; i >= 0; i-- {
+		dictionary = append(dictionary, rows.Elem().Index(i).Interface().(Entity).getORM().binary...)
+	}
+	if len(dictionary) > flateDictionaryMaxSize {
+		dictionary = dictionary[len(dictionary)-flateDictionaryMaxSize:]
+	}
+	return dictionary
+}
+
+func (c *FlateCacheCompressor) Compress(schema TableSchema, value []byte) []byte {
+	c.mutex.RLock()
+	version := c.latest[schema.GetTableName()]
+	dictionary := c.dictionaries[schema.GetTableName()][version]
+	c.mutex.RUnlock()
+	buffer := &bytes.Buffer{}
+	header := make([]byte, binary.MaxVarintLen64+crc32.Size)
+	n := binary.PutUvarint(header, uint64(version))
+	binary.BigEndian.PutUint32(header[n:], crc32.ChecksumIEEE(value))
+	buffer.Write(header[0 : n+crc32.Size])
+	writer, err := flate.NewWriterDict(buffer, c.level, dictionary)
+	checkError(err)
+	_, _ = writer.Write(value)
+	checkError(writer.Close())
+	return buffer.Bytes()
+}
+
+func (c *FlateCacheCompressor) Decompress(schema TableSchema, value []byte) ([]byte, error) {
+	version, n := binary.Uvarint(value)
+	if n <= 0 || len(value) < n+crc32.Size {
+		return nil, fmt.Errorf("invalid compressed cache value for %s", schema.GetTableName())
+	}
+	var dictionary []byte
+	if version > 0 {
+		var has bool
+		dictionary, has = c.GetDictionary(schema, version)
+		if !has {
+			return nil, fmt.Errorf("unknown compression dictionary %d for %s", version, schema.GetTableName())
+		}
+	}
+	checksum := binary.BigEndian.Uint32(value[n:])
+	reader := flate.NewReaderDict(bytes.NewReader(value[n+crc32.Size:]), dictionary)
+	defer func() {
+		_ = reader.Close()
+	}()
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(decompressed) != checksum {
+		return nil, fmt.Errorf("invalid compressed cache value for %s", schema.GetTableName())
+	}
+	return decompressed, nil
+}
+
+func (tableSchema *tableSchema) getRedisCacheValue(value []byte) []byte {
+	source := tableSchema.registry.registry
+	if source.cacheCompressor == nil || len(value) < source.cacheCompressionMinSize {
+		return value
+	}
+	compressed := source.cacheCompressor.Compress(tableSchema, value)
+	if len(compressed)+1 >= len(value) {
+		return value
+	}
+	return append([]byte{cacheCompressionMarker}, compressed...)
+}
+
+// decodeRedisCacheValue reports false when a compressed value can't be decoded, for example when it was
+// written with a dictionary not registered in this instance. Such keys are removed and loaded again from MySQL.
+func decodeRedisCacheValue(redisCache *RedisCache, schema *tableSchema, key string, value string) ([]byte, bool) {
+	encoded := []byte(value)
+	if len(encoded) == 0 || encoded[0] != cacheCompressionMarker {
+		return encoded, true
+	}
+	compressor := schema.registry.registry.cacheCompressor
+	if compressor != nil {
+		decompressed, err := compressor.Decompress(schema, encoded[1:])
+		if err == nil {
+			return decompressed, true
+		}
+	}
+	redisCache.Del(key)
+	return nil, false
+}
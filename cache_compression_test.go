@@ -0,0 +1,108 @@
+package beeorm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheCompressionEntity struct {
+	ORM         `orm:"redisCache"`
+	ID          uint
+	Description string `orm:"length=2000"`
+}
+
+func TestCacheCompression(t *testing.T) {
+	var entity *cacheCompressionEntity
+	compressor := NewFlateCacheCompressor(9)
+	registry := &Registry{}
+	registry.SetRedisCacheCompression(compressor, 100)
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	engine.GetRedis().FlushDB()
+	schema := engine.GetRegistry().GetTableSchemaForEntity(&cacheCompressionEntity{})
+
+	description := strings.Repeat("lorem ipsum dolor sit amet ", 40)
+	flusher := engine.NewFlusher()
+	for i := 0; i < 10; i++ {
+		flusher.Track(&cacheCompressionEntity{Description: description})
+	}
+	flusher.Track(&cacheCompressionEntity{Description: "short"})
+	flusher.Flush()
+
+	entity = &cacheCompressionEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	raw, has := engine.GetRedis().Get(schema.(*tableSchema).getCacheKey(engine, 1))
+	assert.True(t, has)
+	assert.Equal(t, cacheCompressionMarker, raw[0])
+	assert.Less(t, len(raw), len(entity.getORM().binary))
+	entity = &cacheCompressionEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, description, entity.Description)
+
+	assert.True(t, engine.LoadByID(11, &cacheCompressionEntity{}))
+	raw, _ = engine.GetRedis().Get(schema.(*tableSchema).getCacheKey(engine, 11))
+	assert.NotEqual(t, cacheCompressionMarker, raw[0])
+
+	dictionary := compressor.Train(engine, entity, 5)
+	assert.NotEmpty(t, dictionary)
+	compressor.AddDictionary(schema, 1, dictionary)
+	_, has = compressor.GetDictionary(schema, 1)
+	assert.True(t, has)
+	var rows []*cacheCompressionEntity
+	engine.LoadByIDs([]uint64{2, 3, 1}, &rows)
+	assert.Len(t, rows, 3)
+	for _, row := range rows {
+		assert.Equal(t, description, row.Description)
+	}
+	engine.GetRedis().FlushDB()
+	engine.LoadByIDs([]uint64{2, 3}, &rows)
+	rows = nil
+	engine.LoadByIDs([]uint64{2, 3}, &rows)
+	assert.Equal(t, description, rows[1].Description)
+
+	engine.GetRedis().Set(schema.(*tableSchema).getCacheKey(engine, 2), append([]byte{cacheCompressionMarker}, 9, 0, 0, 0, 0, 1), 0)
+	entity = &cacheCompressionEntity{}
+	assert.True(t, engine.LoadByID(2, entity))
+	assert.Equal(t, description, entity.Description)
+	raw, _ = engine.GetRedis().Get(schema.(*tableSchema).getCacheKey(engine, 2))
+	assert.Equal(t, cacheCompressionMarker, raw[0])
+	assert.Equal(t, byte(1), raw[1])
+}
+
+func TestFlateCacheCompressor(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&cacheCompressionEntity{})
+	validated, err := registry.Validate()
+	assert.NoError(t, err)
+	schema := validated.GetTableSchemaForEntity(&cacheCompressionEntity{})
+
+	compressor := NewFlateCacheCompressor(9)
+	value := []byte(strings.Repeat("beeorm compression ", 20))
+	compressed := compressor.Compress(schema, value)
+	assert.Less(t, len(compressed), len(value))
+	decompressed, err := compressor.Decompress(schema, compressed)
+	assert.NoError(t, err)
+	assert.Equal(t, value, decompressed)
+
+	compressor.AddDictionary(schema, 1, []byte("beeorm compression "))
+	assert.PanicsWithError(t, "compression dictionary 1 for cacheCompressionEntity already registered", func() {
+		compressor.AddDictionary(schema, 1, []byte("other"))
+	})
+	withDictionary := compressor.Compress(schema, value)
+	assert.Less(t, len(withDictionary), len(compressed))
+	decompressed, err = compressor.Decompress(schema, withDictionary)
+	assert.NoError(t, err)
+	assert.Equal(t, value, decompressed)
+	decompressed, err = compressor.Decompress(schema, compressed)
+	assert.NoError(t, err)
+	assert.Equal(t, value, decompressed)
+
+	_, err = NewFlateCacheCompressor(9).Decompress(schema, withDictionary)
+	assert.EqualError(t, err, "unknown compression dictionary 1 for cacheCompressionEntity")
+	other := NewFlateCacheCompressor(9)
+	other.AddDictionary(schema, 1, []byte("other dictionary"))
+	_, err = other.Decompress(schema, withDictionary)
+	assert.Error(t, err)
+}
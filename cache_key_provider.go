@@ -0,0 +1,96 @@
+package beeorm
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+)
+
+type CacheKeyProvider interface {
+	GetCacheKey(engine Engine, schema TableSchema, key string) string
+}
+
+func (e *engineImplementation) SetCacheKeyDimension(name, value string) {
+	if e.cacheKeyDimensions == nil {
+		e.cacheKeyDimensions = make(map[string]string)
+	}
+	e.cacheKeyDimensions[name] = value
+}
+
+func (e *engineImplementation) GetCacheKeyDimension(name string) string {
+	return e.cacheKeyDimensions[name]
+}
+
+func initCacheKeyProviders(tableSchema *tableSchema, registry *Registry, entityType reflect.Type) {
+	tableSchema.cacheKeyProviders = nil
+	for _, plugin := range registry.plugins {
+		provider, is := plugin.(CacheKeyProvider)
+		if is {
+			tableSchema.cacheKeyProviders = append(tableSchema.cacheKeyProviders, provider)
+		}
+	}
+	provider, is := reflect.New(entityType).Interface().(CacheKeyProvider)
+	if is {
+		tableSchema.cacheKeyProviders = append(tableSchema.cacheKeyProviders, provider)
+	}
+}
+
+func (tableSchema *tableSchema) getCacheKey(engine *engineImplementation, id uint64) string {
+	key := tableSchema.cachePrefix + tableSchema.getCacheVersionSuffix(engine, "") + ":" + strconv.FormatUint(id, 10)
+	for _, provider := range tableSchema.cacheKeyProviders {
+		key = provider.GetCacheKey(engine, tableSchema, key)
+	}
+	return key
+}
+
+const cacheKeyVariantsSuffix = ":variants"
+
+// Every key written for an entity with cache key providers is tracked in a per entity set,
+// so invalidation removes variants of all dimensions, not only the one of the flushing engine.
+var cacheKeyVariantsDeleteScript = newRedisScript("beeorm_cache_key_variants_delete", `
+for _, key in ipairs(KEYS) do
+	local variants = redis.call('SMEMBERS', key)
+	for i = 1, #variants, 500 do
+		redis.call('DEL', unpack(variants, i, math.min(i + 499, #variants)))
+	end
+	redis.call('DEL', key)
+end
+return 1
+`)
+
+func (tableSchema *tableSchema) hasCacheKeyVariants() bool {
+	return len(tableSchema.cacheKeyProviders) > 0
+}
+
+func (tableSchema *tableSchema) getCacheKeyVariantsKey(engine *engineImplementation, id uint64) string {
+	return tableSchema.cachePrefix + tableSchema.getCacheVersionSuffix(engine, "") + ":" + strconv.FormatUint(id, 10) + cacheKeyVariantsSuffix
+}
+
+func (tableSchema *tableSchema) trackCacheKeyVariants(engine *engineImplementation, redisCache *RedisCache, ttl int, ids []uint64, keys []string) {
+	if !tableSchema.hasCacheKeyVariants() || len(ids) == 0 {
+		return
+	}
+	if nilTTL := engine.registry.runtime.getRedisCacheNilTTL(); ttl > 0 && nilTTL > ttl {
+		ttl = nilTTL
+	}
+	pipeLine := redisCache.PipeLine()
+	for i, id := range ids {
+		variantsKey := tableSchema.getCacheKeyVariantsKey(engine, id)
+		pipeLine.SAdd(variantsKey, redisCache.addNamespacePrefix(keys[i]))
+		if ttl > 0 {
+			pipeLine.Expire(variantsKey, time.Duration(ttl)*time.Second)
+		}
+	}
+	pipeLine.Exec()
+}
+
+func deleteCacheKeyVariants(redisCache *RedisCache, variantsKeys ...string) {
+	if len(variantsKeys) == 0 {
+		return
+	}
+	keys := make([]string, len(variantsKeys))
+	for i, key := range variantsKeys {
+		keys[i] = redisCache.addNamespacePrefix(key)
+	}
+	redisCache.runScript(cacheKeyVariantsDeleteScript, keys)
+}
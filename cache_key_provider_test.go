@@ -0,0 +1,77 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheKeyProviderEntity struct {
+	ORM  `orm:"redisCache"`
+	ID   uint
+	Name string
+}
+
+func (e *cacheKeyProviderEntity) GetCacheKey(engine Engine, _ TableSchema, key string) string {
+	return key + ":" + engine.GetCacheKeyDimension("locale")
+}
+
+type cacheKeyProviderPlugin struct{}
+
+func (p *cacheKeyProviderPlugin) GetCode() string {
+	return "test/cache_key"
+}
+
+func (p *cacheKeyProviderPlugin) GetCacheKey(engine Engine, _ TableSchema, key string) string {
+	tenant := engine.GetCacheKeyDimension("tenant")
+	if tenant == "" {
+		return key
+	}
+	return tenant + ":" + key
+}
+
+func TestCacheKeyProvider(t *testing.T) {
+	var entity *cacheKeyProviderEntity
+	registry := &Registry{}
+	registry.RegisterPlugin(&cacheKeyProviderPlugin{})
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	engine.GetRedis().FlushDB()
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema)
+	prefix := schema.cachePrefix + ":1"
+
+	engine.SetCacheKeyDimension("locale", "en")
+	assert.Equal(t, prefix+":en", schema.getCacheKey(engine, 1))
+	engine.SetCacheKeyDimension("tenant", "t1")
+	assert.Equal(t, "t1:"+prefix+":en", schema.getCacheKey(engine, 1))
+	assert.Equal(t, "t1", engine.Clone().GetCacheKeyDimension("tenant"))
+
+	entity = &cacheKeyProviderEntity{Name: "a"}
+	engine.Flush(entity)
+	assert.True(t, engine.LoadByID(1, &cacheKeyProviderEntity{}))
+	assert.True(t, engine.GetRedis().Exists("t1:"+prefix+":en") == 1)
+	assert.False(t, engine.GetRedis().Exists(prefix) == 1)
+
+	engine.SetCacheKeyDimension("locale", "pl")
+	assert.True(t, engine.LoadByID(1, &cacheKeyProviderEntity{}))
+	assert.True(t, engine.GetRedis().Exists("t1:"+prefix+":pl") == 1)
+	assert.Equal(t, int64(2), engine.GetRedis().SCard(prefix+cacheKeyVariantsSuffix))
+
+	entity.Name = "b"
+	engine.Flush(entity)
+	assert.True(t, engine.GetRedis().Exists("t1:"+prefix+":pl") == 0)
+	assert.True(t, engine.GetRedis().Exists("t1:"+prefix+":en") == 0)
+	assert.True(t, engine.GetRedis().Exists(prefix+cacheKeyVariantsSuffix) == 0)
+	entity = &cacheKeyProviderEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "b", entity.Name)
+
+	var rows []*cacheKeyProviderEntity
+	engine.SetCacheKeyDimension("tenant", "t2")
+	engine.LoadByIDs([]uint64{1}, &rows)
+	assert.Len(t, rows, 1)
+	assert.True(t, engine.GetRedis().Exists("t2:"+prefix+":pl") == 1)
+
+	engine.SetCacheKeyDimension("tenant", "t1")
+	engine.ClearCacheByIDs(&cacheKeyProviderEntity{}, 1)
+	assert.True(t, engine.GetRedis().Exists("t2:"+prefix+":pl") == 0)
+}
@@ -0,0 +1,160 @@
+package beeorm
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type cacheMode int
+
+const (
+	cacheModeWriteThrough cacheMode = iota
+	// cacheModeWriteBehind updates Redis right away and sends update query to lazy consumer.
+	// Inside a transaction query is executed synchronously, so it is committed or rolled back with other changes.
+	cacheModeWriteBehind
+	cacheModeAside
+	cacheModeStrict
+)
+
+var cacheModes = map[string]cacheMode{"writeThrough": cacheModeWriteThrough, "writeBehind": cacheModeWriteBehind,
+	"aside": cacheModeAside, "strict": cacheModeStrict}
+
+type cacheDeletes struct {
+	local    map[string][]string
+	redis    map[string][]string
+	variants map[string][]string
+}
+
+func initCacheMode(tableSchema *tableSchema) error {
+	mode := tableSchema.getTag("cacheMode", "", "")
+	ttl := tableSchema.getTag("cacheTTL", "", "")
+	if mode == "" && ttl == "" {
+		return nil
+	}
+	if !tableSchema.hasLocalCache && !tableSchema.hasRedisCache {
+		return fmt.Errorf("cache mode in %s requires localCache or redisCache", tableSchema.t.String())
+	}
+	if mode != "" {
+		value, has := cacheModes[mode]
+		if !has {
+			return fmt.Errorf("invalid cache mode '%s' in %s", mode, tableSchema.t.String())
+		}
+		tableSchema.cacheMode = value
+	}
+	if ttl != "" {
+		if tableSchema.cacheMode != cacheModeAside {
+			return fmt.Errorf("cache TTL in %s requires cacheMode=aside", tableSchema.t.String())
+		}
+		seconds, err := strconv.Atoi(ttl)
+		if err != nil || seconds <= 0 {
+			return fmt.Errorf("invalid cache TTL '%s' in %s", ttl, tableSchema.t.String())
+		}
+		tableSchema.cacheTTL = seconds
+	} else if tableSchema.cacheMode == cacheModeAside {
+		return fmt.Errorf("cache mode aside in %s requires cacheTTL", tableSchema.t.String())
+	}
+	return nil
+}
+
+func setEntityRedisCache(redisCache *RedisCache, ttl int, pairs ...interface{}) {
+	if ttl <= 0 {
+		redisCache.MSet(pairs...)
+		return
+	}
+	pipeLine := redisCache.PipeLine()
+	for i := 0; i < len(pairs); i += 2 {
+		pipeLine.Set(pairs[i].(string), pairs[i+1], time.Duration(ttl)*time.Second)
+	}
+	pipeLine.Exec()
+}
+
+func (f *flusher) addStrictCacheDeletes(schema *tableSchema, id uint64) {
+	if schema.cacheMode != cacheModeStrict {
+		return
+	}
+	if f.strictCacheDeletes == nil {
+		f.strictCacheDeletes = &cacheDeletes{}
+	}
+	f.strictCacheDeletes.add(f.engine, schema, id)
+}
+
+func (f *flusher) applyStrictCacheDeletes(transaction bool) {
+	for typeOf, deleteBinds := range f.deleteBinds {
+		schema := getTableSchema(f.engine.registry, typeOf)
+		for id := range deleteBinds {
+			f.addStrictCacheDeletes(schema, id)
+		}
+	}
+	if f.strictCacheDeletes == nil {
+		return
+	}
+	if transaction {
+		if f.engine.beforeCommitCacheDeletes == nil {
+			f.engine.beforeCommitCacheDeletes = &cacheDeletes{}
+		}
+		f.engine.beforeCommitCacheDeletes.merge(f.strictCacheDeletes)
+	} else {
+		f.strictCacheDeletes.execute(f.engine)
+	}
+	f.strictCacheDeletes = nil
+}
+
+func (d *cacheDeletes) add(engine *engineImplementation, schema *tableSchema, id uint64) {
+	cacheKey := schema.getCacheKey(engine, id)
+	localCacheName := schema.localCacheName
+	if !schema.hasLocalCache && engine.hasRequestCache {
+		localCacheName = requestCacheKey
+	}
+	if localCacheName != "" {
+		if d.local == nil {
+			d.local = make(map[string][]string)
+		}
+		d.local[localCacheName] = append(d.local[localCacheName], cacheKey)
+	}
+	if schema.hasRedisCache {
+		if d.redis == nil {
+			d.redis = make(map[string][]string)
+		}
+		d.redis[schema.redisCacheName] = append(d.redis[schema.redisCacheName], cacheKey)
+		if schema.hasCacheKeyVariants() {
+			if d.variants == nil {
+				d.variants = make(map[string][]string)
+			}
+			d.variants[schema.redisCacheName] = append(d.variants[schema.redisCacheName], schema.getCacheKeyVariantsKey(engine, id))
+		}
+	}
+}
+
+func (d *cacheDeletes) merge(other *cacheDeletes) {
+	for code, keys := range other.local {
+		if d.local == nil {
+			d.local = make(map[string][]string)
+		}
+		d.local[code] = append(d.local[code], keys...)
+	}
+	for code, keys := range other.redis {
+		if d.redis == nil {
+			d.redis = make(map[string][]string)
+		}
+		d.redis[code] = append(d.redis[code], keys...)
+	}
+	for code, keys := range other.variants {
+		if d.variants == nil {
+			d.variants = make(map[string][]string)
+		}
+		d.variants[code] = append(d.variants[code], keys...)
+	}
+}
+
+func (d *cacheDeletes) execute(engine *engineImplementation) {
+	for code, keys := range d.local {
+		engine.GetLocalCache(code).Remove(keys...)
+	}
+	for code, keys := range d.redis {
+		engine.GetRedis(code).Del(keys...)
+	}
+	for code, keys := range d.variants {
+		deleteCacheKeyVariants(engine.GetRedis(code), keys...)
+	}
+}
@@ -0,0 +1,149 @@
+package beeorm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheModeWriteBehindEntity struct {
+	ORM  `orm:"redisCache;cacheMode=writeBehind"`
+	ID   uint
+	Name string
+}
+
+type cacheModeAsideEntity struct {
+	ORM  `orm:"localCache;redisCache;cacheMode=aside;cacheTTL=30"`
+	ID   uint
+	Name string
+}
+
+type cacheModeAsideUUIDEntity struct {
+	ORM  `orm:"uuid;redisCache;cacheMode=aside;cacheTTL=30"`
+	ID   uint64
+	Name string
+}
+
+type cacheModeStrictEntity struct {
+	ORM  `orm:"redisCache;cacheMode=strict"`
+	ID   uint
+	Name string
+}
+
+func TestCacheModeWriteBehind(t *testing.T) {
+	var entity *cacheModeWriteBehindEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	entity = &cacheModeWriteBehindEntity{Name: "a"}
+	engine.Flush(entity)
+	assert.True(t, engine.LoadByID(1, entity))
+
+	entity.Name = "b"
+	engine.Flush(entity)
+	var name string
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Name` FROM `cacheModeWriteBehindEntity` WHERE `ID` = 1"), &name)
+	assert.Equal(t, "a", name)
+	entity = &cacheModeWriteBehindEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "b", entity.Name)
+
+	receiver := NewBackgroundConsumer(engine)
+	receiver.DisableBlockMode()
+	receiver.blockTime = time.Millisecond
+	receiver.Digest(context.Background())
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Name` FROM `cacheModeWriteBehindEntity` WHERE `ID` = 1"), &name)
+	assert.Equal(t, "b", name)
+}
+
+func TestCacheModeAside(t *testing.T) {
+	var entity *cacheModeAsideEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	schema := engine.registry.GetTableSchemaForEntity(entity).(*tableSchema)
+	entity = &cacheModeAsideEntity{Name: "a"}
+	engine.Flush(entity)
+	cacheKey := schema.getCacheKey(engine, 1)
+	_, has := engine.GetLocalCache().Get(cacheKey)
+	assert.False(t, has)
+
+	assert.True(t, engine.LoadByID(1, &cacheModeAsideEntity{}))
+	ttl := engine.GetRedis().client.TTL(context.Background(), cacheKey).Val()
+	assert.True(t, ttl > 25*time.Second && ttl <= 30*time.Second)
+
+	engine.GetRedis().Del(cacheKey)
+	var rows []*cacheModeAsideEntity
+	engine.GetLocalCache().Clear()
+	engine.LoadByIDs([]uint64{1}, &rows)
+	ttl = engine.GetRedis().client.TTL(context.Background(), cacheKey).Val()
+	assert.True(t, ttl > 25*time.Second && ttl <= 30*time.Second)
+
+	rows[0].Name = "b"
+	engine.Flush(rows[0])
+	_, has = engine.GetLocalCache().Get(cacheKey)
+	assert.False(t, has)
+	assert.Equal(t, int64(0), engine.GetRedis().Exists(cacheKey))
+}
+
+func TestCacheModeAsideUUID(t *testing.T) {
+	var entity *cacheModeAsideUUIDEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	schema := engine.registry.GetTableSchemaForEntity(entity).(*tableSchema)
+	entity = &cacheModeAsideUUIDEntity{Name: "a"}
+	engine.Flush(entity)
+	cacheKey := schema.getCacheKey(engine, entity.GetID())
+	ttl := engine.GetRedis().client.TTL(context.Background(), cacheKey).Val()
+	assert.True(t, ttl > 25*time.Second && ttl <= 30*time.Second)
+}
+
+func TestCacheModeStrict(t *testing.T) {
+	var entity *cacheModeStrictEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	schema := engine.registry.GetTableSchemaForEntity(entity).(*tableSchema)
+	entity = &cacheModeStrictEntity{Name: "a"}
+	engine.Flush(entity)
+	cacheKey := schema.getCacheKey(engine, 1)
+	entity = &cacheModeStrictEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, int64(1), engine.GetRedis().Exists(cacheKey))
+
+	db := engine.GetMysql()
+	db.Begin()
+	entity.Name = "b"
+	engine.Flush(entity)
+	assert.Equal(t, int64(1), engine.GetRedis().Exists(cacheKey))
+	assert.NotNil(t, engine.beforeCommitCacheDeletes)
+	engine.GetRedis().Set(cacheKey, "stale", 0)
+	db.Commit()
+	assert.Nil(t, engine.beforeCommitCacheDeletes)
+	assert.Equal(t, int64(0), engine.GetRedis().Exists(cacheKey))
+
+	assert.True(t, engine.LoadByID(1, entity))
+	db.Begin()
+	entity.Name = "c"
+	engine.Flush(entity)
+	db.Rollback()
+	assert.Nil(t, engine.beforeCommitCacheDeletes)
+	assert.Equal(t, int64(1), engine.GetRedis().Exists(cacheKey))
+}
+
+func TestCacheModeInvalidTags(t *testing.T) {
+	schema := func(tags map[string]string, cached bool) *tableSchema {
+		return &tableSchema{t: reflect.TypeOf(cacheModeStrictEntity{}), tags: map[string]map[string]string{"ORM": tags}, hasRedisCache: cached}
+	}
+	assert.NoError(t, initCacheMode(schema(map[string]string{}, false)))
+	valid := schema(map[string]string{"cacheMode": "aside", "cacheTTL": "60"}, true)
+	assert.NoError(t, initCacheMode(valid))
+	assert.Equal(t, cacheModeAside, valid.cacheMode)
+	assert.Equal(t, 60, valid.cacheTTL)
+	assert.EqualError(t, initCacheMode(schema(map[string]string{"cacheMode": "strict"}, false)),
+		"cache mode in beeorm.cacheModeStrictEntity requires localCache or redisCache")
+	assert.EqualError(t, initCacheMode(schema(map[string]string{"cacheMode": "invalid"}, true)),
+		"invalid cache mode 'invalid' in beeorm.cacheModeStrictEntity")
+	assert.EqualError(t, initCacheMode(schema(map[string]string{"cacheMode": "aside"}, true)),
+		"cache mode aside in beeorm.cacheModeStrictEntity requires cacheTTL")
+	assert.EqualError(t, initCacheMode(schema(map[string]string{"cacheTTL": "10"}, true)),
+		"cache TTL in beeorm.cacheModeStrictEntity requires cacheMode=aside")
+	assert.EqualError(t, initCacheMode(schema(map[string]string{"cacheMode": "aside", "cacheTTL": "x"}, true)),
+		"invalid cache TTL 'x' in beeorm.cacheModeStrictEntity")
+}
@@ -0,0 +1,90 @@
+package beeorm
+
+type EntityCacheReport struct {
+	Checked    int
+	Repaired   int
+	Mismatches []EntityCacheMismatch
+}
+
+type EntityCacheMismatch struct {
+	ID      uint64
+	Cache   string
+	InCache bool
+	InDB    bool
+	Columns map[string][2]interface{}
+}
+
+func verifyEntityCache(engine *engineImplementation, entity Entity, repair bool, ids ...uint64) *EntityCacheReport {
+	schema := initIfNeeded(engine.registry, entity).tableSchema
+	localCache, hasLocalCache := schema.GetLocalCache(engine)
+	redisCache, hasRedis := schema.GetRedisCache(engine)
+	serializer := newSerializer(nil)
+	report := &EntityCacheReport{Mismatches: make([]EntityCacheMismatch, 0)}
+	for _, id := range ids {
+		report.Checked++
+		dbEntity := schema.NewEntity()
+		inDB, _ := loadByID(serializer, engine, id, dbEntity, false)
+		cacheKey := schema.getCacheKey(engine, id)
+		hasMismatch := false
+		if hasLocalCache {
+			value, has := localCache.Get(cacheKey)
+			if has {
+				var binary []byte
+				if value != cacheNilValue {
+					binary = value.([]byte)
+				}
+				mismatch := compareEntityCache(serializer, dbEntity, inDB, binary, value != cacheNilValue)
+				if mismatch != nil {
+					mismatch.Cache = localCache.config.GetCode()
+					report.Mismatches = append(report.Mismatches, *mismatch)
+					hasMismatch = true
+				}
+			}
+		}
+		if hasRedis {
+			value, has := redisCache.Get(cacheKey)
+			if has {
+				binary, valid := decodeRedisCacheValue(redisCache, schema, cacheKey, value)
+				mismatch := compareEntityCache(serializer, dbEntity, inDB, binary, valid && value != cacheNilValue)
+				if mismatch != nil {
+					mismatch.Cache = redisCache.config.GetCode()
+					report.Mismatches = append(report.Mismatches, *mismatch)
+					hasMismatch = true
+				}
+			}
+		}
+		if hasMismatch && repair {
+			clearByIDs(engine, entity, id)
+			loadByID(serializer, engine, id, schema.NewEntity(), true)
+			report.Repaired++
+		}
+	}
+	return report
+}
+
+func compareEntityCache(serializer *serializer, dbEntity Entity, inDB bool, binary []byte, inCache bool) *EntityCacheMismatch {
+	id := dbEntity.GetID()
+	if !inDB || !inCache {
+		if inDB == inCache {
+			return nil
+		}
+		return &EntityCacheMismatch{ID: id, InCache: inCache, InDB: inDB}
+	}
+	orm := dbEntity.getORM()
+	dbBinary := orm.binary
+	orm.binary = binary
+	builder := newBindBuilder(id, orm)
+	builder.hasCurrent = true
+	builder.current = Bind{}
+	serializer.Reset(binary)
+	builder.build(serializer, orm.tableSchema.fields, orm.elem, true)
+	orm.binary = dbBinary
+	if len(builder.bind) == 0 {
+		return nil
+	}
+	columns := make(map[string][2]interface{}, len(builder.bind))
+	for column, value := range builder.bind {
+		columns[column] = [2]interface{}{builder.current[column], value}
+	}
+	return &EntityCacheMismatch{ID: id, InCache: true, InDB: true, Columns: columns}
+}
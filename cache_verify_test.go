@@ -0,0 +1,53 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheVerifyEntity struct {
+	ORM  `orm:"localCache;redisCache"`
+	ID   uint
+	Name string
+	Age  uint
+}
+
+func TestVerifyEntityCache(t *testing.T) {
+	var entity *cacheVerifyEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	engine.GetRedis().FlushDB()
+	engine.Flush(&cacheVerifyEntity{Name: "a", Age: 10}, &cacheVerifyEntity{Name: "b", Age: 20})
+	var rows []*cacheVerifyEntity
+	engine.LoadByIDs([]uint64{1, 2, 3}, &rows)
+
+	report := engine.VerifyEntityCache(entity, false, 1, 2, 3, 4)
+	assert.Equal(t, 4, report.Checked)
+	assert.Len(t, report.Mismatches, 0)
+
+	engine.GetMysql().Exec("UPDATE `cacheVerifyEntity` SET `Name` = 'c' WHERE `ID` = 1")
+	engine.GetMysql().Exec("DELETE FROM `cacheVerifyEntity` WHERE `ID` = 2")
+	engine.GetMysql().Exec("INSERT INTO `cacheVerifyEntity`(`ID`, `Name`, `Age`) VALUES(3, 'd', 30)")
+	report = engine.VerifyEntityCache(entity, false, 1, 2, 3)
+	assert.Len(t, report.Mismatches, 6)
+	assert.Equal(t, 0, report.Repaired)
+	assert.Equal(t, uint64(1), report.Mismatches[0].ID)
+	assert.Equal(t, "default", report.Mismatches[0].Cache)
+	assert.Equal(t, map[string][2]interface{}{"Name": {"a", "c"}}, report.Mismatches[0].Columns)
+	assert.Equal(t, uint64(1), report.Mismatches[1].ID)
+	assert.Equal(t, uint64(2), report.Mismatches[2].ID)
+	assert.True(t, report.Mismatches[2].InCache)
+	assert.False(t, report.Mismatches[2].InDB)
+	assert.Equal(t, uint64(3), report.Mismatches[4].ID)
+	assert.False(t, report.Mismatches[4].InCache)
+	assert.True(t, report.Mismatches[4].InDB)
+
+	report = engine.VerifyEntityCache(entity, true, 1, 2, 3)
+	assert.Equal(t, 3, report.Repaired)
+	report = engine.VerifyEntityCache(entity, false, 1, 2, 3)
+	assert.Len(t, report.Mismatches, 0)
+	entity = &cacheVerifyEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "c", entity.Name)
+	assert.False(t, engine.LoadByID(2, entity))
+}
@@ -0,0 +1,92 @@
+package beeorm
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const cacheVersionRefresh = time.Second
+
+type cacheVersion struct {
+	value    uint64
+	loadedAt int64
+}
+
+func initCacheVersions(tableSchema *tableSchema) {
+	tableSchema.cacheVersions = map[string]*cacheVersion{"": {}}
+	for indexName := range tableSchema.cachedIndexesAll {
+		tableSchema.cacheVersions[indexName] = &cacheVersion{}
+	}
+}
+
+func (e *engineImplementation) BumpCacheVersion(entity Entity) {
+	schema := initIfNeeded(e.registry, entity).tableSchema
+	bumpCacheVersion(e, schema, "")
+}
+
+func (e *engineImplementation) ClearCachedQuery(entity Entity, indexName string) {
+	schema := initIfNeeded(e.registry, entity).tableSchema
+	if _, has := schema.cachedIndexesAll[indexName]; !has {
+		panic(fmt.Errorf("index %s not found", indexName))
+	}
+	bumpCacheVersion(e, schema, indexName)
+}
+
+func bumpCacheVersion(engine *engineImplementation, schema *tableSchema, indexName string) {
+	version := schema.cacheVersions[indexName]
+	redisCache, hasRedis := schema.GetRedisCache(engine)
+	if hasRedis {
+		setCacheVersion(version, uint64(redisCache.Incr(getCacheVersionKey(schema, indexName))))
+	} else {
+		atomic.AddUint64(&version.value, 1)
+	}
+	atomic.StoreInt64(&version.loadedAt, time.Now().UnixNano())
+}
+
+func (tableSchema *tableSchema) getCacheVersionSuffix(engine *engineImplementation, indexName string) string {
+	version := tableSchema.getCacheVersion(engine, indexName)
+	if version == 0 {
+		return ""
+	}
+	return "v" + strconv.FormatUint(version, 10)
+}
+
+func (tableSchema *tableSchema) getCacheVersion(engine *engineImplementation, indexName string) uint64 {
+	version := tableSchema.cacheVersions[indexName]
+	if version == nil {
+		return 0
+	}
+	if !tableSchema.hasRedisCache {
+		return atomic.LoadUint64(&version.value)
+	}
+	now := time.Now().UnixNano()
+	if now-atomic.LoadInt64(&version.loadedAt) > int64(cacheVersionRefresh) {
+		redisCache, _ := tableSchema.GetRedisCache(engine)
+		key := redisCache.addNamespacePrefix(getCacheVersionKey(tableSchema, indexName))
+		value, err := redisCache.client.Get(context.Background(), key).Uint64()
+		if err == nil {
+			setCacheVersion(version, value)
+		}
+		atomic.StoreInt64(&version.loadedAt, now)
+	}
+	return atomic.LoadUint64(&version.value)
+}
+
+func setCacheVersion(version *cacheVersion, value uint64) {
+	for {
+		current := atomic.LoadUint64(&version.value)
+		if value <= current || atomic.CompareAndSwapUint64(&version.value, current, value) {
+			return
+		}
+	}
+}
+
+func getCacheVersionKey(schema *tableSchema, indexName string) string {
+	if indexName == "" {
+		return "_cache_version:" + schema.cachePrefix
+	}
+	return "_cache_version:" + schema.cachePrefix + ":" + indexName
+}
@@ -0,0 +1,54 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheVersionEntity struct {
+	ORM   `orm:"localCache;redisCache"`
+	ID    uint
+	Name  string       `orm:"index=Name"`
+	Index *CachedQuery `query:":Name = ?"`
+}
+
+func TestBumpCacheVersion(t *testing.T) {
+	var entity *cacheVersionEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema)
+
+	engine.Flush(&cacheVersionEntity{Name: "a"}, &cacheVersionEntity{Name: "b"})
+	loaded := &cacheVersionEntity{}
+	assert.True(t, engine.LoadByID(1, loaded))
+	var rows []*cacheVersionEntity
+	assert.Equal(t, 1, engine.CachedSearch(&rows, "Index", nil, "a"))
+	assert.Equal(t, schema.cachePrefix+":1", schema.getCacheKey(engine, 1))
+
+	engine.GetMysql().Exec("UPDATE `cacheVersionEntity` SET `Name` = 'a'")
+	assert.True(t, engine.LoadByID(2, loaded))
+	assert.Equal(t, "b", loaded.Name)
+	assert.Equal(t, 1, engine.CachedSearch(&rows, "Index", nil, "a"))
+
+	engine.ClearCachedQuery(entity, "Index")
+	assert.Equal(t, 2, engine.CachedSearch(&rows, "Index", nil, "a"))
+	assert.True(t, engine.LoadByID(2, loaded))
+	assert.Equal(t, "b", loaded.Name)
+
+	engine.BumpCacheVersion(entity)
+	assert.Equal(t, schema.cachePrefix+"v1:1", schema.getCacheKey(engine, 1))
+	assert.True(t, engine.LoadByID(2, loaded))
+	assert.Equal(t, "a", loaded.Name)
+
+	engine.GetMysql().Exec("UPDATE `cacheVersionEntity` SET `Name` = 'c' WHERE `ID` = 1")
+	assert.Equal(t, 2, engine.CachedSearch(&rows, "Index", nil, "a"))
+	engine.BumpCacheVersion(entity)
+	assert.Equal(t, 1, engine.CachedSearch(&rows, "Index", nil, "a"))
+	v, has := engine.GetRedis().Get(getCacheVersionKey(schema, ""))
+	assert.True(t, has)
+	assert.Equal(t, "2", v)
+
+	assert.PanicsWithError(t, "index Invalid not found", func() {
+		engine.ClearCachedQuery(entity, "Invalid")
+	})
+}
@@ -0,0 +1,252 @@
+package beeorm
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	googleuuid "github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+)
+
+type cachedQueryRawValue struct {
+	Tags    []string    `json:"t"`
+	Columns []string    `json:"c"`
+	Rows    [][]*string `json:"r"`
+	Created int64       `json:"a,omitempty"`
+}
+
+func (e *engineImplementation) CachedQueryRaw(key string, ttl time.Duration, tags []string, query string, args []interface{}, dest interface{}) {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Slice {
+		panic(fmt.Errorf("cached query raw destination must be a pointer to slice"))
+	}
+	r := e.GetRedis()
+	keys := make([]string, len(tags)+1)
+	keys[0] = getCachedQueryRawKey(key)
+	for i, tag := range tags {
+		keys[i+1] = getCacheTagKey(tag)
+	}
+	values := r.MGet(keys...)
+	if values[0] != nil {
+		cached := &cachedQueryRawValue{}
+		err := jsoniter.ConfigFastest.UnmarshalFromString(values[0].(string), cached)
+		if err == nil && len(cached.Tags) == len(tags) {
+			valid := true
+			for i, tag := range cached.Tags {
+				if values[i+1] == nil || values[i+1].(string) != tag {
+					valid = false
+					break
+				}
+			}
+			if valid {
+				e.refreshAheadCachedQueryRaw(cached, key, ttl, tags, values[1:], query, args)
+				fillCachedQueryRaw(cached, destValue.Elem())
+				return
+			}
+		}
+	}
+	fillCachedQueryRaw(e.loadCachedQueryRaw(key, ttl, tags, values[1:], query, args), destValue.Elem())
+}
+
+func (e *engineImplementation) SetRefreshAhead(threshold float64) {
+	e.refreshAhead = threshold
+}
+
+func (e *engineImplementation) refreshAheadCachedQueryRaw(cached *cachedQueryRawValue, key string, ttl time.Duration, tags []string,
+	tagValues []interface{}, query string, args []interface{}) {
+	if e.refreshAhead <= 0 || e.refreshAhead >= 1 || ttl < time.Second || cached.Created == 0 {
+		return
+	}
+	age := e.now().Sub(time.Unix(0, cached.Created*int64(time.Millisecond)))
+	if age < time.Duration(float64(ttl)*e.refreshAhead) {
+		return
+	}
+	r := e.GetRedis()
+	lockKey := "_refresh_ahead:" + key
+	if !r.SetNX(lockKey, "1", int(ttl.Seconds())) {
+		return
+	}
+	refresher := e.Clone().(*engineImplementation)
+	go func() {
+		defer func() {
+			_ = recover()
+			refresher.GetRedis().Del(lockKey)
+		}()
+		refresher.loadCachedQueryRaw(key, ttl, tags, tagValues, query, args)
+	}()
+}
+
+func (e *engineImplementation) loadCachedQueryRaw(key string, ttl time.Duration, tags []string, tagValues []interface{},
+	query string, args []interface{}) *cachedQueryRawValue {
+	r := e.GetRedis()
+	cached := &cachedQueryRawValue{Tags: make([]string, len(tags)), Rows: make([][]*string, 0),
+		Created: e.now().UnixNano() / int64(time.Millisecond)}
+	for i, tag := range tags {
+		if tagValues[i] != nil {
+			cached.Tags[i] = tagValues[i].(string)
+			continue
+		}
+		token := googleuuid.New().String()
+		if !r.SetNX(getCacheTagKey(tag), token, 0) {
+			token, _ = r.Get(getCacheTagKey(tag))
+		}
+		cached.Tags[i] = token
+	}
+	results, def := e.GetMysql().Query(query, args...)
+	defer def()
+	cached.Columns = results.Columns()
+	for results.Next() {
+		pointers := make([]interface{}, len(cached.Columns))
+		for i := range pointers {
+			pointers[i] = &sql.NullString{}
+		}
+		results.Scan(pointers...)
+		row := make([]*string, len(pointers))
+		for i, pointer := range pointers {
+			value := pointer.(*sql.NullString)
+			if value.Valid {
+				row[i] = &value.String
+			}
+		}
+		cached.Rows = append(cached.Rows, row)
+	}
+	encoded, err := jsoniter.ConfigFastest.MarshalToString(cached)
+	checkError(err)
+	r.Set(getCachedQueryRawKey(key), encoded, int(ttl.Seconds()))
+	return cached
+}
+
+func (e *engineImplementation) InvalidateCacheTags(tags ...string) {
+	if len(tags) == 0 {
+		return
+	}
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = getCacheTagKey(tag)
+	}
+	e.GetRedis().Del(keys...)
+}
+
+func (f *flusher) invalidateTableCacheTag(schema *tableSchema) {
+	if _, has := f.engine.registry.redisServers["default"]; has {
+		f.getRedisFlusher().Del("default", getCacheTagKey(schema.tableName))
+	}
+}
+
+func getCachedQueryRawKey(key string) string {
+	return "_cached_query_raw:" + key
+}
+
+func getCacheTagKey(tag string) string {
+	return "_cache_tag:" + tag
+}
+
+func fillCachedQueryRaw(cached *cachedQueryRawValue, slice reflect.Value) {
+	sliceType := slice.Type().Elem()
+	result := reflect.MakeSlice(slice.Type(), len(cached.Rows), len(cached.Rows))
+	if sliceType.Kind() == reflect.Map {
+		if sliceType.Key().Kind() != reflect.String || sliceType.Elem().Kind() != reflect.Interface {
+			panic(fmt.Errorf("cached query raw destination %s is not supported", slice.Type().String()))
+		}
+		for i, row := range cached.Rows {
+			values := make(map[string]interface{}, len(row))
+			for j, value := range row {
+				if value == nil {
+					values[cached.Columns[j]] = nil
+				} else {
+					values[cached.Columns[j]] = *value
+				}
+			}
+			result.Index(i).Set(reflect.ValueOf(values))
+		}
+		slice.Set(result)
+		return
+	}
+	isPointer := sliceType.Kind() == reflect.Ptr
+	structType := sliceType
+	if isPointer {
+		structType = sliceType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		panic(fmt.Errorf("cached query raw destination %s is not supported", slice.Type().String()))
+	}
+	fields := make([]int, len(cached.Columns))
+	for i, column := range cached.Columns {
+		fields[i] = -1
+		for j := 0; j < structType.NumField(); j++ {
+			if structType.Field(j).PkgPath == "" && strings.EqualFold(structType.Field(j).Name, column) {
+				fields[i] = j
+				break
+			}
+		}
+	}
+	for i, row := range cached.Rows {
+		elem := reflect.New(structType)
+		for j, value := range row {
+			if fields[j] >= 0 {
+				setCachedQueryRawField(elem.Elem().Field(fields[j]), value, cached.Columns[j])
+			}
+		}
+		if isPointer {
+			result.Index(i).Set(elem)
+		} else {
+			result.Index(i).Set(elem.Elem())
+		}
+	}
+	slice.Set(result)
+}
+
+func setCachedQueryRawField(field reflect.Value, value *string, column string) {
+	if field.Kind() == reflect.Ptr {
+		if value == nil {
+			field.Set(reflect.Zero(field.Type()))
+			return
+		}
+		pointer := reflect.New(field.Type().Elem())
+		setCachedQueryRawField(pointer.Elem(), value, column)
+		field.Set(pointer)
+		return
+	}
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return
+	}
+	var err error
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(*value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var v uint64
+		v, err = strconv.ParseUint(*value, 10, 64)
+		field.SetUint(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var v int64
+		v, err = strconv.ParseInt(*value, 10, 64)
+		field.SetInt(v)
+	case reflect.Float32, reflect.Float64:
+		var v float64
+		v, err = strconv.ParseFloat(*value, 64)
+		field.SetFloat(v)
+	case reflect.Bool:
+		field.SetBool(*value == "1" || strings.ToLower(*value) == "true")
+	default:
+		if field.Type() != timeType {
+			panic(fmt.Errorf("cached query raw column %s type %s is not supported", column, field.Type().String()))
+		}
+		var v time.Time
+		for _, layout := range []string{"2006-01-02 15:04:05.999999", timeFormat, dateformat} {
+			v, err = time.ParseInLocation(layout, *value, time.UTC)
+			if err == nil {
+				break
+			}
+		}
+		field.Set(reflect.ValueOf(v))
+	}
+	if err != nil {
+		panic(fmt.Errorf("invalid value for cached query raw column %s: %w", column, err))
+	}
+}
@@ -0,0 +1,91 @@
+package beeorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cachedQueryRawEntity struct {
+	ORM
+	ID     uint
+	Name   string
+	Amount float64
+}
+
+type cachedQueryRawReport struct {
+	Name  string
+	Total *float64
+	Rows  uint
+}
+
+func TestCachedQueryRaw(t *testing.T) {
+	var entity *cachedQueryRawEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	engine.Flush(&cachedQueryRawEntity{Name: "a", Amount: 10}, &cachedQueryRawEntity{Name: "a", Amount: 5},
+		&cachedQueryRawEntity{Name: "b", Amount: 1})
+
+	query := "SELECT `Name`, SUM(`Amount`) AS `Total`, COUNT(*) AS `Rows` FROM `cachedQueryRawEntity` GROUP BY `Name` ORDER BY `Name`"
+	tags := []string{"cachedQueryRawEntity"}
+	var report []cachedQueryRawReport
+	engine.CachedQueryRaw("report", time.Minute, tags, query, nil, &report)
+	assert.Len(t, report, 2)
+	assert.Equal(t, "a", report[0].Name)
+	assert.Equal(t, 15.0, *report[0].Total)
+	assert.Equal(t, uint(2), report[0].Rows)
+
+	testLogger := &testLogHandler{}
+	engine.RegisterQueryLogger(testLogger, true, false, false)
+	var rows []map[string]interface{}
+	engine.CachedQueryRaw("report", time.Minute, tags, query, nil, &rows)
+	assert.Len(t, testLogger.Logs, 0)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "b", rows[1]["Name"])
+	assert.Equal(t, "1", rows[1]["Rows"])
+
+	engine.Flush(&cachedQueryRawEntity{Name: "c", Amount: 2})
+	engine.CachedQueryRaw("report", time.Minute, tags, query, nil, &report)
+	assert.Len(t, testLogger.Logs, 1)
+	assert.Len(t, report, 3)
+
+	engine.GetMysql().Exec("DELETE FROM `cachedQueryRawEntity` WHERE `Name` = 'c'")
+	testLogger.clear()
+	engine.CachedQueryRaw("report", time.Minute, tags, query, nil, &report)
+	assert.Len(t, report, 3)
+	engine.InvalidateCacheTags(tags...)
+	engine.CachedQueryRaw("report", time.Minute, tags, query, nil, &report)
+	assert.Len(t, testLogger.Logs, 1)
+	assert.Len(t, report, 2)
+
+	assert.PanicsWithError(t, "cached query raw destination must be a pointer to slice", func() {
+		engine.CachedQueryRaw("report", time.Minute, tags, query, nil, report)
+	})
+}
+
+func TestCachedQueryRawRefreshAhead(t *testing.T) {
+	var entity *cachedQueryRawEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	clock := &cronTestClock{now: time.Now()}
+	engine.SetClock(clock)
+	engine.SetRefreshAhead(0.8)
+	engine.Flush(&cachedQueryRawEntity{Name: "a", Amount: 10})
+
+	query := "SELECT `Name` FROM `cachedQueryRawEntity` ORDER BY `ID`"
+	var rows []map[string]interface{}
+	engine.CachedQueryRaw("names", time.Minute, nil, query, nil, &rows)
+	assert.Len(t, rows, 1)
+	engine.GetMysql().Exec("INSERT INTO `cachedQueryRawEntity`(`Name`, `Amount`) VALUES('b', 1)")
+
+	clock.now = clock.now.Add(time.Second * 30)
+	engine.CachedQueryRaw("names", time.Minute, nil, query, nil, &rows)
+	assert.Len(t, rows, 1)
+
+	clock.now = clock.now.Add(time.Second * 20)
+	engine.CachedQueryRaw("names", time.Minute, nil, query, nil, &rows)
+	assert.Len(t, rows, 1)
+	assert.Eventually(t, func() bool {
+		engine.CachedQueryRaw("names", time.Minute, nil, query, nil, &rows)
+		return len(rows) == 2
+	}, time.Second*2, time.Millisecond*20)
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/segmentio/fasthash/fnv1a"
 )
@@ -28,8 +30,21 @@ func cachedSearch(serializer *serializer, engine *engineImplementation, entities
 		pager = NewPager(1, definition.Max)
 	}
 	start := (pager.GetCurrentPage() - 1) * pager.GetPageSize()
-	if start+pager.GetPageSize() > definition.Max {
-		panic(fmt.Errorf("max cache index page size (%d) exceeded %s", definition.Max, indexName))
+	size := pager.GetPageSize()
+	truncatedEmpty := false
+	if start+size > definition.Max {
+		switch definition.Overflow {
+		case CachedQueryOverflowSQL:
+			return cachedSearchOverflow(serializer, engine, entities, entityType, NewWhere(definition.Query, arguments...), pager, references)
+		case CachedQueryOverflowTruncate:
+			if start >= definition.Max {
+				truncatedEmpty = true
+				start = definition.Max - 1
+			}
+			size = definition.Max - start
+		default:
+			panic(fmt.Errorf("max cache index page size (%d) exceeded %s", definition.Max, indexName))
+		}
 	}
 	localCache, hasLocalCache := schema.GetLocalCache(engine)
 	if !hasLocalCache && engine.hasRequestCache {
@@ -41,15 +56,15 @@ func cachedSearch(serializer *serializer, engine *engineImplementation, entities
 		panic(fmt.Errorf("cache search not allowed for entity without cache: '%s'", entityType.String()))
 	}
 	where := NewWhere(definition.Query, arguments...)
-	cacheKey := getCacheKeySearch(schema, indexName, where.GetParameters()...)
+	cacheKey := getCacheKeySearch(engine, schema, indexName, where.GetParameters()...)
 
 	pageSize := idsOnCachePage
 	if hasLocalCache {
 		pageSize = definition.Max
 	}
-	minCachePage := float64((pager.GetCurrentPage() - 1) * pager.GetPageSize() / pageSize)
+	minCachePage := float64(start / pageSize)
 	minCachePageCeil := minCachePage
-	maxCachePage := float64((pager.GetCurrentPage()-1)*pager.GetPageSize()+pager.GetPageSize()) / float64(pageSize)
+	maxCachePage := float64(start+size) / float64(pageSize)
 	maxCachePageCeil := math.Ceil(maxCachePage)
 	pages := make([]string, int(maxCachePageCeil-minCachePageCeil))
 	j := 0
@@ -156,6 +171,7 @@ func cachedSearch(serializer *serializer, engine *engineImplementation, entities
 		}
 		if hasRedis {
 			redisCache.HSet(cacheKey, cacheFields...)
+			trackCachedQueryCardinality(engine, redisCache, schema, indexName, cacheKey)
 		}
 	}
 	nilKeysLen := len(nilsKeys)
@@ -173,13 +189,19 @@ func cachedSearch(serializer *serializer, engine *engineImplementation, entities
 	for i := minCachePageCeil; i < maxCachePageCeil; i++ {
 		resultsIDs = append(resultsIDs, filledPages[strconv.Itoa(int(i)+1)]...)
 	}
-	sliceStart := (pager.GetCurrentPage() - 1) * pager.GetPageSize()
+	if definition.Overflow == CachedQueryOverflowTruncate && totalRows > definition.Max {
+		totalRows = definition.Max
+	}
+	sliceStart := start
 	diff := int(minCachePageCeil) * pageSize
 	sliceStart -= diff
-	if sliceStart > totalRows {
+	if sliceStart > totalRows || truncatedEmpty {
+		if _, is := entities.(Entity); !is {
+			value.Elem().SetLen(0)
+		}
 		return totalRows, []uint64{}
 	}
-	sliceEnd := sliceStart + pager.GetPageSize()
+	sliceEnd := sliceStart + size
 	length := len(resultsIDs)
 	if sliceEnd > length {
 		sliceEnd = length
@@ -212,10 +234,96 @@ func cachedSearch(serializer *serializer, engine *engineImplementation, entities
 				elem.Set(newSlice)
 			}
 		}
+		if len(definition.OrderFields) > 0 {
+			sortCachedSearchResults(definition, elem)
+		}
 	}
 	return totalRows, idsToReturn
 }
 
+func cachedSearchOverflow(serializer *serializer, engine *engineImplementation, entities interface{}, entityType reflect.Type,
+	where *Where, pager *Pager, references []string) (totalRows int, ids []uint64) {
+	ids, totalRows = searchIDs(engine, where, pager, true, entityType)
+	if _, is := entities.(Entity); !is {
+		tryByIDs(serializer, engine, ids, reflect.ValueOf(entities).Elem(), references)
+	}
+	return totalRows, ids
+}
+
+func sortCachedSearchResults(definition *cachedQueryDefinition, rows reflect.Value) {
+	sort.SliceStable(rows.Interface(), func(i, j int) bool {
+		first := rows.Index(i).Elem()
+		second := rows.Index(j).Elem()
+		for k, field := range definition.OrderFields {
+			firstValue := first.FieldByName(field)
+			if !firstValue.IsValid() {
+				return false
+			}
+			compared := compareCachedSearchValues(firstValue, second.FieldByName(field))
+			if compared == 0 {
+				continue
+			}
+			if definition.OrderDesc[k] {
+				return compared > 0
+			}
+			return compared < 0
+		}
+		return false
+	})
+}
+
+func compareCachedSearchValues(first, second reflect.Value) int {
+	if first.Kind() == reflect.Ptr {
+		if first.IsNil() || second.IsNil() {
+			if first.IsNil() && second.IsNil() {
+				return 0
+			} else if first.IsNil() {
+				return -1
+			}
+			return 1
+		}
+		first = first.Elem()
+		second = second.Elem()
+	}
+	switch first.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(first.Int(), second.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return compareOrdered(first.Uint(), second.Uint())
+	case reflect.Float32, reflect.Float64:
+		return compareOrdered(first.Float(), second.Float())
+	case reflect.String:
+		return compareOrdered(first.String(), second.String())
+	case reflect.Bool:
+		return compareOrdered(boolToInt(first.Bool()), boolToInt(second.Bool()))
+	}
+	if firstTime, is := first.Interface().(time.Time); is {
+		secondTime := second.Interface().(time.Time)
+		if firstTime.Before(secondTime) {
+			return -1
+		} else if firstTime.After(secondTime) {
+			return 1
+		}
+	}
+	return 0
+}
+
+func compareOrdered[T int | int64 | uint64 | float64 | string](first, second T) int {
+	if first < second {
+		return -1
+	} else if first > second {
+		return 1
+	}
+	return 0
+}
+
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
+
 func cachedSearchOne(serializer *serializer, engine *engineImplementation, entity Entity, indexName string, fillStruct bool, arguments []interface{}, references []string) (has bool) {
 	value := reflect.ValueOf(entity)
 	entityType := value.Elem().Type()
@@ -237,7 +345,7 @@ func cachedSearchOne(serializer *serializer, engine *engineImplementation, entit
 	if !hasLocalCache && !hasRedis {
 		panic(fmt.Errorf("cache search not allowed for entity without cache: '%s'", entityType.String()))
 	}
-	cacheKey := getCacheKeySearch(schema, indexName, where.GetParameters()...)
+	cacheKey := getCacheKeySearch(engine, schema, indexName, where.GetParameters()...)
 	var fromCache map[string]interface{}
 	if hasLocalCache {
 		fromLocalCache, hasInLocalCache := localCache.Get(cacheKey)
@@ -264,6 +372,7 @@ func cachedSearchOne(serializer *serializer, engine *engineImplementation, entit
 		}
 		if hasRedis {
 			redisCache.HSet(cacheKey, "1", value)
+			trackCachedQueryCardinality(engine, redisCache, schema, indexName, cacheKey)
 		}
 	} else {
 		ids := strings.Split(fromCache["1"].(string), " ")
@@ -281,6 +390,37 @@ func cachedSearchOne(serializer *serializer, engine *engineImplementation, entit
 	return false
 }
 
-func getCacheKeySearch(tableSchema *tableSchema, indexName string, parameters ...interface{}) string {
-	return tableSchema.cachePrefix + "_" + indexName + strconv.Itoa(int(fnv1a.HashString32(fmt.Sprintf("%v", parameters))))
+func getCacheKeySearch(engine *engineImplementation, tableSchema *tableSchema, indexName string, parameters ...interface{}) string {
+	key := tableSchema.cachePrefix + tableSchema.getCacheVersionSuffix(engine, "") + "_" + indexName
+	if suffix := tableSchema.getCacheVersionSuffix(engine, indexName); suffix != "" {
+		key += suffix + ":"
+	}
+	return key + strconv.Itoa(int(fnv1a.HashString32(fmt.Sprintf("%v", parameters))))
+}
+
+func trackCachedQueryCardinality(engine *engineImplementation, redisCache *RedisCache, schema *tableSchema, indexName, cacheKey string) {
+	key := getCachedQueryCardinalityKey(schema, indexName)
+	if redisCache.PFAdd(key, cacheKey) == 0 || engine.cardinalityLimit <= 0 || engine.cardinalityHandler == nil {
+		return
+	}
+	cardinality := int(redisCache.PFCount(key))
+	if cardinality > engine.cardinalityLimit {
+		engine.cardinalityHandler(engine, schema, indexName, cardinality)
+	}
+}
+
+func getCachedQueryCardinality(engine *engineImplementation, entity Entity, indexName string) int {
+	schema := initIfNeeded(engine.registry, entity).tableSchema
+	if _, has := schema.cachedIndexes[indexName]; !has {
+		panic(fmt.Errorf("index %s not found", indexName))
+	}
+	redisCache, hasRedis := schema.GetRedisCache(engine)
+	if !hasRedis {
+		return 0
+	}
+	return int(redisCache.PFCount(getCachedQueryCardinalityKey(schema, indexName)))
+}
+
+func getCachedQueryCardinalityKey(schema *tableSchema, indexName string) string {
+	return "_cardinality:" + schema.cachePrefix + ":" + indexName
 }
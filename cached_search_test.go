@@ -347,6 +347,73 @@ func TestCachedSearchErrors(t *testing.T) {
 	})
 }
 
+type cachedSearchMaxEntity struct {
+	ORM      `orm:"redisCache"`
+	ID       uint
+	Age      uint16       `orm:"index=Age"`
+	IndexAge *CachedQuery `query:":Age = ? ORDER BY ID;max=10"`
+	IndexAll *CachedQuery `query:""`
+}
+
+type cachedSearchMaxOnlyEntity struct {
+	ORM      `orm:"redisCache"`
+	ID       uint
+	Age      uint16       `orm:"index=Age"`
+	IndexAge *CachedQuery `query:":Age = ? ORDER BY ID;max=10"`
+}
+
+func TestCachedSearchPaginationEnforcement(t *testing.T) {
+	var entity *cachedSearchMaxEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	var rows []*cachedSearchMaxEntity
+	assert.PanicsWithError(t, "max cache index page size (10) exceeded IndexAge", func() {
+		_ = engine.CachedSearch(&rows, "IndexAge", NewPager(2, 6), 10)
+	})
+
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterRedis("localhost:6382", "", 15)
+	registry.EnforcePagination()
+	registry.RegisterEntity(entity)
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "cached query 'IndexAll' in beeorm.cachedSearchMaxEntity must define max")
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterRedis("localhost:6382", "", 15)
+	registry.EnforcePagination()
+	registry.RegisterEntity(&cachedSearchMaxOnlyEntity{})
+	_, err = registry.Validate()
+	assert.NoError(t, err)
+}
+
+func TestCachedSearchCardinalityLimit(t *testing.T) {
+	var entity *cachedSearchMaxEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	engine.GetRedis().FlushDB()
+	alerts := make([]int, 0)
+	engine.SetCachedQueryCardinalityLimit(2, func(engine Engine, schema TableSchema, indexName string, cardinality int) {
+		assert.Equal(t, "cachedSearchMaxEntity", schema.GetTableName())
+		assert.Equal(t, "IndexAge", indexName)
+		alerts = append(alerts, cardinality)
+	})
+	var rows []*cachedSearchMaxEntity
+	for _, age := range []int{1, 2, 1, 2} {
+		engine.CachedSearch(&rows, "IndexAge", nil, age)
+	}
+	assert.Equal(t, 2, engine.GetCachedQueryCardinality(entity, "IndexAge"))
+	assert.Len(t, alerts, 0)
+	engine.CachedSearch(&rows, "IndexAge", nil, 3)
+	engine.GetRedis().FlushDB()
+	assert.Equal(t, []int{3}, alerts)
+	assert.Equal(t, 0, engine.GetCachedQueryCardinality(entity, "IndexAge"))
+	engine.CachedSearch(&rows, "IndexAll", nil)
+	assert.Equal(t, 1, engine.GetCachedQueryCardinality(entity, "IndexAll"))
+	assert.PanicsWithError(t, "index Invalid not found", func() {
+		engine.GetCachedQueryCardinality(entity, "Invalid")
+	})
+}
+
 func BenchmarkCachedSearch(b *testing.B) {
 	entity := &schemaEntity{}
 	ref := &schemaEntityRef{}
@@ -375,3 +442,105 @@ func BenchmarkCachedSearch(b *testing.B) {
 		_ = engine.CachedSearchCount(entity, "IndexAll")
 	}
 }
+
+type cachedSearchOrderEntity struct {
+	ORM        `orm:"localCache"`
+	ID         uint
+	Category   uint         `orm:"index=IndexCategory"`
+	Score      uint         `orm:"index=IndexCategory:2"`
+	Name       string       `orm:"index=IndexCategory:3"`
+	IndexScore *CachedQuery `query:":Category = ? ORDER BY :Score DESC, :Name DESC"`
+}
+
+func TestCachedSearchOrderDirection(t *testing.T) {
+	var entity *cachedSearchOrderEntity
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema)
+	assert.Equal(t, []string{"Score", "Name"}, schema.cachedIndexes["IndexScore"].OrderFields)
+	assert.Equal(t, []bool{true, true}, schema.cachedIndexes["IndexScore"].OrderDesc)
+
+	flusher := engine.NewFlusher()
+	for i := 1; i <= 5; i++ {
+		flusher.Track(&cachedSearchOrderEntity{Category: 1, Score: uint(i), Name: "name " + strconv.Itoa(i)})
+	}
+	flusher.Flush()
+
+	var rows []*cachedSearchOrderEntity
+	assert.Equal(t, 5, engine.CachedSearch(&rows, "IndexScore", nil, 1))
+	assert.Equal(t, uint(5), rows[0].Score)
+	assert.Equal(t, uint(1), rows[4].Score)
+
+	engine.EnableIdentityMap()
+	engine.CachedSearch(&rows, "IndexScore", nil, 1)
+	rows[4].Score = 10
+	engine.CachedSearch(&rows, "IndexScore", nil, 1)
+	assert.Equal(t, uint(10), rows[0].Score)
+	assert.Equal(t, uint(5), rows[1].Score)
+	assert.Equal(t, uint(2), rows[4].Score)
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	type cachedSearchMixedOrderEntity struct {
+		ORM        `orm:"localCache"`
+		ID         uint
+		Score      uint         `orm:"index=IndexScore"`
+		Name       string       `orm:"index=IndexScore:2"`
+		IndexScore *CachedQuery `query:"ORDER BY :Score DESC, :Name ASC"`
+	}
+	registry.RegisterEntity(&cachedSearchMixedOrderEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "cached query 'IndexScore' in beeorm.cachedSearchMixedOrderEntity can't mix ASC and DESC order")
+}
+
+type cachedSearchOverflowEntity struct {
+	ORM           `orm:"redisCache"`
+	ID            uint
+	Age           uint16       `orm:"index=Age"`
+	IndexSQL      *CachedQuery `query:":Age = ? ORDER BY ID;max=5;overflow=sql"`
+	IndexTruncate *CachedQuery `query:":Age = ? ORDER BY ID;max=5;overflow=truncate"`
+}
+
+func TestCachedSearchOverflow(t *testing.T) {
+	var entity *cachedSearchOverflowEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	flusher := engine.NewFlusher()
+	for i := 0; i < 8; i++ {
+		flusher.Track(&cachedSearchOverflowEntity{Age: 10})
+	}
+	flusher.Flush()
+
+	var rows []*cachedSearchOverflowEntity
+	total := engine.CachedSearch(&rows, "IndexSQL", NewPager(2, 4), 10)
+	assert.Equal(t, 8, total)
+	assert.Len(t, rows, 4)
+	assert.Equal(t, uint(5), rows[0].ID)
+	assert.Equal(t, uint(8), rows[3].ID)
+	total, ids := engine.CachedSearchIDs(entity, "IndexSQL", NewPager(3, 3), 10)
+	assert.Equal(t, 8, total)
+	assert.Equal(t, []uint64{7, 8}, ids)
+	total = engine.CachedSearch(&rows, "IndexSQL", NewPager(1, 5), 10)
+	assert.Equal(t, 8, total)
+	assert.Len(t, rows, 5)
+
+	total = engine.CachedSearch(&rows, "IndexTruncate", NewPager(2, 4), 10)
+	assert.Equal(t, 5, total)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, uint(5), rows[0].ID)
+	total = engine.CachedSearch(&rows, "IndexTruncate", NewPager(3, 4), 10)
+	assert.Equal(t, 5, total)
+	assert.Len(t, rows, 0)
+
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterRedis("localhost:6382", "", 15)
+	type cachedSearchInvalidOverflowEntity struct {
+		ORM      `orm:"redisCache"`
+		ID       uint
+		Age      uint16       `orm:"index=Age"`
+		IndexAge *CachedQuery `query:":Age = ? ORDER BY ID;max=5;overflow=drop"`
+	}
+	registry.RegisterEntity(&cachedSearchInvalidOverflowEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "invalid overflow 'drop' in cached query 'IndexAge' in beeorm.cachedSearchInvalidOverflowEntity")
+}
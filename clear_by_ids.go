@@ -4,7 +4,7 @@ func clearByIDs(engine *engineImplementation, entity Entity, ids ...uint64) {
 	schema := initIfNeeded(engine.registry, entity).tableSchema
 	cacheKeys := make([]string, len(ids))
 	for i, id := range ids {
-		cacheKeys[i] = schema.getCacheKey(id)
+		cacheKeys[i] = schema.getCacheKey(engine, id)
 	}
 	localCache, has := schema.GetLocalCache(engine)
 	if !has && engine.hasRequestCache {
@@ -17,5 +17,12 @@ func clearByIDs(engine *engineImplementation, entity Entity, ids ...uint64) {
 	redisCache, has := schema.GetRedisCache(engine)
 	if has {
 		redisCache.Del(cacheKeys...)
+		if schema.hasCacheKeyVariants() {
+			variantsKeys := make([]string, len(ids))
+			for i, id := range ids {
+				variantsKeys[i] = schema.getCacheKeyVariantsKey(engine, id)
+			}
+			deleteCacheKeyVariants(redisCache, variantsKeys...)
+		}
 	}
 }
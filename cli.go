@@ -0,0 +1,289 @@
+package beeorm
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+)
+
+const cliUsage = `usage: beeorm <command> [arguments]
+
+commands:
+  validate                        validate registry and list registered entities
+  alters [-apply] [-safe] [-online=strategy] [-min-rows=N] [-out=file]
+                                  preview or apply schema alters
+  consume [-block-time=duration]  run background consumer until interrupted
+  streams [stream...]             show redis streams statistics
+  clear-cache <entity> [id...]    remove cached entities, all rows when no id is provided
+`
+
+type CLI struct {
+	registryFactory func() *Registry
+	output          io.Writer
+}
+
+func NewCLI(registryFactory func() *Registry) *CLI {
+	return &CLI{registryFactory: registryFactory, output: os.Stdout}
+}
+
+func (c *CLI) SetOutput(output io.Writer) {
+	c.output = output
+}
+
+func (c *CLI) Main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	err := c.Run(ctx, os.Args[1:]...)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+func (c *CLI) Run(ctx context.Context, args ...string) (err error) {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		_, err = io.WriteString(c.output, cliUsage)
+		return err
+	}
+	defer func() {
+		if rec := recover(); rec != nil {
+			asErr, is := rec.(error)
+			if !is {
+				asErr = fmt.Errorf("%v", rec)
+			}
+			err = asErr
+		}
+	}()
+	command := args[0]
+	switch command {
+	case "validate":
+		return c.validate()
+	case "alters":
+		return c.alters(args[1:])
+	case "consume":
+		return c.consume(ctx, args[1:])
+	case "streams":
+		return c.streams(args[1:])
+	case "clear-cache":
+		return c.clearCache(args[1:])
+	}
+	_, _ = io.WriteString(c.output, cliUsage)
+	return fmt.Errorf("unknown command '%s'", command)
+}
+
+func (c *CLI) getValidatedRegistry() (ValidatedRegistry, error) {
+	if c.registryFactory == nil {
+		return nil, fmt.Errorf("missing registry factory")
+	}
+	registry := c.registryFactory()
+	if registry == nil {
+		return nil, fmt.Errorf("registry factory returned nil")
+	}
+	return registry.Validate()
+}
+
+func (c *CLI) createEngine() (Engine, error) {
+	validated, err := c.getValidatedRegistry()
+	if err != nil {
+		return nil, err
+	}
+	return validated.CreateEngine(), nil
+}
+
+func (c *CLI) newFlagSet(command string) *flag.FlagSet {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(c.output)
+	return flags
+}
+
+func (c *CLI) validate() error {
+	validated, err := c.getValidatedRegistry()
+	if err != nil {
+		return err
+	}
+	entities := validated.GetEntities()
+	names := make([]string, 0, len(entities))
+	for name := range entities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	_, _ = fmt.Fprintf(c.output, "registry is valid, %d entities registered\n", len(names))
+	for _, name := range names {
+		schema := validated.GetTableSchema(name).(*tableSchema)
+		_, _ = fmt.Fprintf(c.output, "  %s -> %s.%s\n", name, schema.mysqlPoolName, schema.tableName)
+	}
+	return nil
+}
+
+func (c *CLI) alters(args []string) error {
+	flags := c.newFlagSet("alters")
+	apply := flags.Bool("apply", false, "execute alters")
+	safe := flags.Bool("safe", false, "apply only safe alters")
+	online := flags.String("online", "", "online DDL strategy: instant, inplace, gh-ost or pt-osc")
+	minRows := flags.Uint64("min-rows", 0, "use online DDL only for tables with at least this number of rows")
+	out := flags.String("out", "", "write alters SQL to file")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	engine, err := c.createEngine()
+	if err != nil {
+		return err
+	}
+	plan := engine.GetAltersPlan()
+	if *online != "" {
+		plan.UseOnlineDDL(OnlineDDLStrategy(*online), *minRows)
+	}
+	if *safe {
+		items := make([]AlterPlanItem, 0, len(plan.Items))
+		for _, item := range plan.Items {
+			if item.Safe {
+				items = append(items, item)
+			}
+		}
+		plan.Items = items
+	}
+	if len(plan.Items) == 0 {
+		_, _ = io.WriteString(c.output, "schema is up to date\n")
+		return nil
+	}
+	if *out != "" {
+		err = plan.WriteSQLFile(*out)
+		if err != nil {
+			return err
+		}
+	}
+	if !*apply {
+		return plan.WriteSQL(c.output)
+	}
+	plan.Exec()
+	_, _ = fmt.Fprintf(c.output, "%d alters applied\n", len(plan.Items))
+	return nil
+}
+
+func (c *CLI) consume(ctx context.Context, args []string) error {
+	flags := c.newFlagSet("consume")
+	blockTime := flags.Duration("block-time", 0, "redis block time")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	engine, err := c.createEngine()
+	if err != nil {
+		return err
+	}
+	consumer := NewBackgroundConsumer(engine)
+	if *blockTime > 0 {
+		consumer.SetBlockTime(*blockTime)
+	}
+	_, _ = io.WriteString(c.output, "background consumer started\n")
+	for ctx.Err() == nil {
+		if !consumer.Digest(ctx) {
+			break
+		}
+	}
+	_, _ = io.WriteString(c.output, "background consumer stopped\n")
+	return nil
+}
+
+func (c *CLI) streams(args []string) error {
+	engine, err := c.createEngine()
+	if err != nil {
+		return err
+	}
+	stats := engine.GetEventBroker().GetStreamsStatistics(args...)
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Stream < stats[j].Stream
+	})
+	writer := tabwriter.NewWriter(c.output, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "STREAM\tPOOL\tLEN\tOLDEST\tGROUP\tLAG\tPENDING\tCONSUMERS\tRATE")
+	for _, stream := range stats {
+		prefix := fmt.Sprintf("%s\t%s\t%d\t%ds", stream.Stream, stream.RedisPool, stream.Len, stream.OldestEventSeconds)
+		if len(stream.Groups) == 0 {
+			_, _ = fmt.Fprintln(writer, prefix+"\t-\t-\t-\t-\t-")
+			continue
+		}
+		for _, group := range stream.Groups {
+			_, _ = fmt.Fprintf(writer, "%s\t%s\t%d\t%d\t%d\t%.2f/s\n", prefix, group.Group, group.Lag, group.Pending,
+				len(group.Consumers), group.DeliveryRate)
+		}
+	}
+	return writer.Flush()
+}
+
+func (c *CLI) clearCache(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing entity name")
+	}
+	engine, err := c.createEngine()
+	if err != nil {
+		return err
+	}
+	entityType, err := findRegisteredEntity(engine.GetRegistry(), args[0])
+	if err != nil {
+		return err
+	}
+	schema := getTableSchema(engine.(*engineImplementation).registry, entityType)
+	if !schema.hasLocalCache && !schema.hasRedisCache {
+		return fmt.Errorf("entity '%s' is not cached", entityType.String())
+	}
+	entity := schema.NewEntity()
+	ids := make([]uint64, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		id, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid id '%s'", arg)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) > 0 {
+		engine.ClearCacheByIDs(entity, ids...)
+		_, _ = fmt.Fprintf(c.output, "cache cleared for %d %s entities\n", len(ids), entityType.String())
+		return nil
+	}
+	where := NewWhere("1")
+	where.ShowFakeDeleted()
+	total := 0
+	for page := 1; ; page++ {
+		pageIDs, _ := searchIDs(engine.(*engineImplementation), where, NewPager(page, 10000), false, entityType)
+		if len(pageIDs) > 0 {
+			engine.ClearCacheByIDs(entity, pageIDs...)
+		}
+		total += len(pageIDs)
+		if len(pageIDs) < 10000 {
+			break
+		}
+	}
+	_, _ = fmt.Fprintf(c.output, "cache cleared for %d %s entities\n", total, entityType.String())
+	return nil
+}
+
+func findRegisteredEntity(registry ValidatedRegistry, name string) (reflect.Type, error) {
+	entities := registry.GetEntities()
+	t, has := entities[name]
+	if has {
+		return t, nil
+	}
+	var found reflect.Type
+	for fullName, entityType := range entities {
+		if strings.HasSuffix(fullName, "."+name) {
+			if found != nil {
+				return nil, fmt.Errorf("entity name '%s' is ambiguous", name)
+			}
+			found = entityType
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("entity '%s' is not registered", name)
+	}
+	return found, nil
+}
@@ -0,0 +1,87 @@
+package beeorm
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cliEntity struct {
+	ORM  `orm:"localCache;redisCache"`
+	ID   uint
+	Name string
+}
+
+func TestCLIUsage(t *testing.T) {
+	output := &bytes.Buffer{}
+	cli := NewCLI(nil)
+	cli.SetOutput(output)
+	assert.NoError(t, cli.Run(context.Background()))
+	assert.Equal(t, cliUsage, output.String())
+	output.Reset()
+	assert.EqualError(t, cli.Run(context.Background(), "invalid"), "unknown command 'invalid'")
+	assert.Equal(t, cliUsage, output.String())
+	assert.EqualError(t, cli.Run(context.Background(), "validate"), "missing registry factory")
+	cli = NewCLI(func() *Registry {
+		return nil
+	})
+	assert.EqualError(t, cli.Run(context.Background(), "validate"), "registry factory returned nil")
+}
+
+func TestCLI(t *testing.T) {
+	var entity *cliEntity
+	registry := &Registry{}
+	registry.RegisterRedisStream("test-stream", "default", []string{"test-group"})
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	output := &bytes.Buffer{}
+	cli := NewCLI(func() *Registry {
+		return registry
+	})
+	cli.SetOutput(output)
+
+	assert.NoError(t, cli.Run(context.Background(), "validate"))
+	assert.Contains(t, output.String(), "registry is valid, 1 entities registered\n  beeorm.cliEntity -> default.cliEntity\n")
+
+	output.Reset()
+	assert.NoError(t, cli.Run(context.Background(), "alters"))
+	assert.Equal(t, "schema is up to date\n", output.String())
+	engine.GetMysql().Exec("ALTER TABLE `cliEntity` DROP COLUMN `Name`")
+	output.Reset()
+	assert.NoError(t, cli.Run(context.Background(), "alters"))
+	assert.Contains(t, output.String(), "-- pool: default, table: test.cliEntity, operation: alter\n")
+	assert.Contains(t, output.String(), "ADD COLUMN `Name`")
+	output.Reset()
+	assert.NoError(t, cli.Run(context.Background(), "alters", "-apply"))
+	assert.Equal(t, "1 alters applied\n", output.String())
+	assert.Len(t, engine.GetAlters(), 0)
+
+	engine.Flush(&cliEntity{Name: "a"}, &cliEntity{Name: "b"})
+	engine.LoadByIDs([]uint64{1, 2}, &[]*cliEntity{})
+	redisCache, _ := engine.GetRegistry().GetTableSchemaForEntity(entity).GetRedisCache(engine)
+	key := engine.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema).getCacheKey(engine, 1)
+	assert.NotEmpty(t, redisCache.MGet(key)[0])
+	output.Reset()
+	assert.NoError(t, cli.Run(context.Background(), "clear-cache", "cliEntity", "1"))
+	assert.Equal(t, "cache cleared for 1 beeorm.cliEntity entities\n", output.String())
+	assert.Nil(t, redisCache.MGet(key)[0])
+	output.Reset()
+	assert.NoError(t, cli.Run(context.Background(), "clear-cache", "beeorm.cliEntity"))
+	assert.Equal(t, "cache cleared for 2 beeorm.cliEntity entities\n", output.String())
+	assert.EqualError(t, cli.Run(context.Background(), "clear-cache", "unknownEntity"), "entity 'unknownEntity' is not registered")
+	assert.EqualError(t, cli.Run(context.Background(), "clear-cache", "cliEntity", "abc"), "invalid id 'abc'")
+
+	output.Reset()
+	engine.GetEventBroker().Publish("test-stream", "a")
+	assert.NoError(t, cli.Run(context.Background(), "streams", "test-stream"))
+	assert.Contains(t, output.String(), "STREAM")
+	assert.Contains(t, output.String(), "test-stream")
+	assert.Contains(t, output.String(), "test-group")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	output.Reset()
+	assert.NoError(t, cli.Run(ctx, "consume", "-block-time=1ms"))
+	assert.Equal(t, "background consumer started\nbackground consumer stopped\n", output.String())
+}
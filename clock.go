@@ -0,0 +1,22 @@
+package beeorm
+
+import "time"
+
+type Clock interface {
+	Now() time.Time
+}
+
+func (e *engineImplementation) SetClock(clock Clock) {
+	e.clock = clock
+}
+
+func (e *engineImplementation) GetClock() Clock {
+	return e.clock
+}
+
+func (e *engineImplementation) now() time.Time {
+	if e.clock == nil {
+		return time.Now()
+	}
+	return e.clock.Now()
+}
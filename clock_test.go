@@ -0,0 +1,58 @@
+package beeorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func TestClockLocalCacheTTL(t *testing.T) {
+	engine := &engineImplementation{}
+	assert.Nil(t, engine.GetClock())
+	assert.WithinDuration(t, time.Now(), engine.now(), time.Second)
+	clock := &testClock{now: time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)}
+	engine.SetClock(clock)
+	assert.Equal(t, clock.now, engine.now())
+	assert.Equal(t, clock, engine.Clone().GetClock())
+
+	cache := &LocalCache{engine: engine, config: newLocalCacheConfig("default", 100)}
+	calls := 0
+	provider := func() interface{} {
+		calls++
+		return calls
+	}
+	assert.Equal(t, 1, cache.GetSet("key", time.Minute, provider))
+	clock.now = clock.now.Add(time.Minute)
+	assert.Equal(t, 1, cache.GetSet("key", time.Minute, provider))
+	clock.now = clock.now.Add(time.Second)
+	assert.Equal(t, 2, cache.GetSet("key", time.Minute, provider))
+}
+
+func TestClockTimestamps(t *testing.T) {
+	var entity *flushTimestampsEntity
+	registry := &Registry{}
+	registry.SetTimestampsLocation(time.UTC)
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	clock := &testClock{now: time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)}
+	engine.SetClock(clock)
+
+	entity = &flushTimestampsEntity{Name: "a"}
+	engine.Flush(entity)
+	assert.Equal(t, clock.now, entity.CreatedAt)
+	assert.Equal(t, clock.now, *entity.UpdatedAt)
+
+	clock.now = clock.now.Add(time.Hour)
+	entity.Name = "b"
+	engine.Flush(entity)
+	assert.Equal(t, time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC), entity.CreatedAt)
+	assert.Equal(t, time.Date(2022, 1, 1, 13, 0, 0, 0, time.UTC), *entity.UpdatedAt)
+}
@@ -0,0 +1,63 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+type ColumnNamingStrategy func(field string) string
+
+func SnakeCaseColumnNames(field string) string {
+	runes := []rune(field)
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				builder.WriteRune('_')
+			}
+			builder.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+func (r *Registry) SetColumnNamingStrategy(strategy ColumnNamingStrategy) {
+	r.columnNaming = strategy
+}
+
+func initColumnNames(tableSchema *tableSchema, registry *Registry, entityType reflect.Type) error {
+	tableSchema.columnNaming = registry.columnNaming
+	tableSchema.hasColumnNames = registry.columnNaming != nil
+	for field, tags := range tableSchema.tags {
+		column, has := tags["column"]
+		if !has {
+			continue
+		}
+		if column == "true" || column == "" {
+			return fmt.Errorf("missing column name for field %s in %s", field, entityType.String())
+		}
+		if field == "ID" || field == "FakeDelete" || field == "ORM" {
+			return fmt.Errorf("column name of %s in %s can't be changed", field, entityType.String())
+		}
+		tableSchema.hasColumnNames = true
+	}
+	return nil
+}
+
+func (tableSchema *tableSchema) getColumnName(field string) string {
+	if !tableSchema.hasColumnNames || field == "ID" || field == "FakeDelete" {
+		return field
+	}
+	if column, has := tableSchema.tags[field]["column"]; has {
+		return column
+	}
+	if tableSchema.columnNaming == nil {
+		return field
+	}
+	return tableSchema.columnNaming(field)
+}
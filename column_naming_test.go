@@ -0,0 +1,84 @@
+package beeorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type columnNamingEntity struct {
+	ORM        `orm:"localCache;redisCache"`
+	ID         uint
+	FirstName  string `orm:"index=FirstName"`
+	UserName   string `orm:"column=login;unique=Login"`
+	Age        uint8  `orm:"index=Age"`
+	HTTPStatus uint16
+	CreatedAt  *time.Time   `orm:"time"`
+	IndexAge   *CachedQuery `query:":Age = ? ORDER BY :FirstName"`
+}
+
+func TestSnakeCaseColumnNames(t *testing.T) {
+	assert.Equal(t, "first_name", SnakeCaseColumnNames("FirstName"))
+	assert.Equal(t, "http_status", SnakeCaseColumnNames("HTTPStatus"))
+	assert.Equal(t, "address2_street", SnakeCaseColumnNames("Address2Street"))
+	assert.Equal(t, "name", SnakeCaseColumnNames("name"))
+	assert.Equal(t, "user_id", SnakeCaseColumnNames("UserID"))
+}
+
+func TestColumnNaming(t *testing.T) {
+	var entity *columnNamingEntity
+	registry := &Registry{}
+	registry.SetColumnNamingStrategy(SnakeCaseColumnNames)
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	schema.DropTable(engine)
+	has, alters := schema.GetSchemaChanges(engine)
+	assert.True(t, has)
+	assert.Equal(t, "CREATE TABLE `test`.`columnNamingEntity` (\n  `ID` int(10) unsigned NOT NULL AUTO_INCREMENT,\n  "+
+		"`first_name` varchar(255) DEFAULT NULL,\n  `login` varchar(255) DEFAULT NULL,\n  `age` tinyint(3) unsigned NOT NULL DEFAULT '0',\n  "+
+		"`http_status` smallint(5) unsigned NOT NULL DEFAULT '0',\n  `created_at` datetime DEFAULT NULL,\n  "+
+		"INDEX `Age` (`age`),\n  INDEX `FirstName` (`first_name`),\n  UNIQUE INDEX `Login` (`login`),\n  PRIMARY KEY (`ID`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;", alters[0].SQL)
+	schema.UpdateSchema(engine)
+	assert.Len(t, engine.GetAlters(), 0)
+	assert.Equal(t, []string{"ID", "FirstName", "UserName", "Age", "HTTPStatus", "CreatedAt"}, schema.GetColumns())
+
+	now := time.Now().UTC().Truncate(time.Second)
+	entity = &columnNamingEntity{FirstName: "Tom", UserName: "tom", Age: 18, HTTPStatus: 200, CreatedAt: &now}
+	engine.Flush(entity)
+	var login string
+	assert.True(t, engine.GetMysql().QueryRow(NewWhere("SELECT `login` FROM `columnNamingEntity` WHERE `ID` = 1"), &login))
+	assert.Equal(t, "tom", login)
+
+	entity = &columnNamingEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "tom", entity.UserName)
+	assert.Equal(t, uint16(200), entity.HTTPStatus)
+	assert.Equal(t, now, entity.CreatedAt.UTC())
+
+	entity.UserName = "thomas"
+	engine.Flush(entity)
+	entity = &columnNamingEntity{}
+	assert.True(t, engine.SearchOne(NewWhere("`login` = ?", "thomas"), entity))
+	assert.Equal(t, "Tom", entity.FirstName)
+
+	var rows []*columnNamingEntity
+	total := engine.CachedSearch(&rows, "IndexAge", nil, 18)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "thomas", rows[0].UserName)
+	engine.Flush(&columnNamingEntity{FirstName: "Adam", UserName: "adam", Age: 18})
+	total = engine.CachedSearch(&rows, "IndexAge", nil, 18)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, "Adam", rows[0].FirstName)
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&columnNamingInvalidEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "column name of ID in beeorm.columnNamingInvalidEntity can't be changed")
+}
+
+type columnNamingInvalidEntity struct {
+	ORM
+	ID uint `orm:"column=id"`
+}
@@ -0,0 +1,255 @@
+package beeorm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const CronResultsStreamName = "beeorm-cron-results"
+
+const cronScheduleKey = "_beeorm_cron"
+
+var cronClaimScript = newRedisScript("beeorm_cron_claim", `
+local score = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not score then
+	redis.call('ZADD', KEYS[1], ARGV[3], ARGV[1])
+	return 0
+end
+if tonumber(score) > tonumber(ARGV[2]) then
+	return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[3], ARGV[1])
+return tonumber(score)
+`)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type CronJobHandler func(ctx context.Context, engine Engine) error
+
+type CronResult struct {
+	Job       string
+	Scheduled time.Time
+	Started   time.Time
+	Duration  time.Duration
+	Error     string
+}
+
+type cronJob struct {
+	name     string
+	schedule *cronSchedule
+	handler  CronJobHandler
+}
+
+type cronSchedule struct {
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	domStar bool
+	dowStar bool
+}
+
+type CronScheduler struct {
+	engine   *engineImplementation
+	interval time.Duration
+	lockTTL  time.Duration
+}
+
+func (r *Registry) RegisterCronJob(name, expression string, handler CronJobHandler) {
+	schedule, err := parseCronExpression(expression)
+	if err != nil {
+		panic(err)
+	}
+	if r.cronJobs == nil {
+		r.cronJobs = make(map[string]*cronJob)
+		r.RegisterRedisStream(CronResultsStreamName, "default", nil)
+	}
+	if _, has := r.cronJobs[name]; has {
+		panic(fmt.Errorf("cron job '%s' already registered", name))
+	}
+	r.cronJobs[name] = &cronJob{name: name, schedule: schedule, handler: handler}
+}
+
+func NewCronScheduler(engine Engine) *CronScheduler {
+	return &CronScheduler{engine: engine.(*engineImplementation), interval: time.Second, lockTTL: time.Hour}
+}
+
+func (s *CronScheduler) SetInterval(interval time.Duration) {
+	s.interval = interval
+}
+
+func (s *CronScheduler) SetLockTTL(ttl time.Duration) {
+	s.lockTTL = ttl
+}
+
+func (s *CronScheduler) Run(ctx context.Context) {
+	for {
+		s.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.interval):
+		}
+	}
+}
+
+func (s *CronScheduler) RunOnce(ctx context.Context) int {
+	jobs := s.engine.registry.registry.cronJobs
+	names := make([]string, 0, len(jobs))
+	for name := range jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	r := s.engine.GetRedis()
+	executed := 0
+	for _, name := range names {
+		job := jobs[name]
+		now := s.engine.now()
+		next := job.schedule.next(now)
+		if next.IsZero() {
+			continue
+		}
+		claimed := r.runScript(cronClaimScript, []string{r.addNamespacePrefix(cronScheduleKey)}, name, now.Unix(), next.Unix())
+		scheduled, _ := claimed.(int64)
+		if scheduled == 0 {
+			continue
+		}
+		lock, obtained := r.GetLocker().Obtain(ctx, cronScheduleKey+":"+name, s.lockTTL, 0)
+		if !obtained {
+			continue
+		}
+		s.execute(ctx, job, time.Unix(scheduled, 0))
+		lock.Release()
+		executed++
+	}
+	return executed
+}
+
+func (s *CronScheduler) execute(ctx context.Context, job *cronJob, scheduled time.Time) {
+	result := CronResult{Job: job.name, Scheduled: scheduled, Started: s.engine.now()}
+	func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				result.Error = fmt.Sprintf("%v", rec)
+			}
+		}()
+		err := job.handler(ctx, s.engine)
+		if err != nil {
+			result.Error = err.Error()
+		}
+	}()
+	result.Duration = s.engine.now().Sub(result.Started)
+	s.engine.GetEventBroker().Publish(CronResultsStreamName, result)
+}
+
+func parseCronExpression(expression string) (*cronSchedule, error) {
+	expression = strings.TrimSpace(expression)
+	if macro, has := cronMacros[expression]; has {
+		expression = macro
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression '%s'", expression)
+	}
+	schedule := &cronSchedule{domStar: fields[2] == "*", dowStar: fields[4] == "*"}
+	ranges := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	targets := [5]*uint64{&schedule.minute, &schedule.hour, &schedule.dom, &schedule.month, &schedule.dow}
+	for i, field := range fields {
+		bits, err := parseCronField(field, ranges[i][0], ranges[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression '%s': %w", expression, err)
+		}
+		*targets[i] = bits
+	}
+	if schedule.dow&(1<<7) > 0 {
+		schedule.dow |= 1
+	}
+	return schedule, nil
+}
+
+func parseCronField(field string, minValue, maxValue int) (uint64, error) {
+	bits := uint64(0)
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if pos := strings.Index(part, "/"); pos >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[pos+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in '%s'", part)
+			}
+			part = part[0:pos]
+		}
+		from, to := minValue, maxValue
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			from, err = strconv.Atoi(bounds[0])
+			if err != nil {
+				return 0, fmt.Errorf("invalid value '%s'", part)
+			}
+			to = from
+			if len(bounds) == 2 {
+				to, err = strconv.Atoi(bounds[1])
+				if err != nil {
+					return 0, fmt.Errorf("invalid value '%s'", part)
+				}
+			} else if step > 1 {
+				to = maxValue
+			}
+		}
+		if from < minValue || to > maxValue || from > to {
+			return 0, fmt.Errorf("value '%s' out of range %d-%d", part, minValue, maxValue)
+		}
+		for i := from; i <= to; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+func (s *cronSchedule) next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) > 0
+	dow := s.dow&(1<<uint(t.Weekday())) > 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,85 @@
+package beeorm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cronTestClock struct {
+	now time.Time
+}
+
+func (c *cronTestClock) Now() time.Time {
+	return c.now
+}
+
+func TestCronSchedule(t *testing.T) {
+	start := time.Date(2023, 1, 31, 10, 17, 30, 0, time.UTC)
+	schedule, err := parseCronExpression("*/15 * * * *")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 1, 31, 10, 30, 0, 0, time.UTC), schedule.next(start))
+
+	schedule, err = parseCronExpression("@daily")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC), schedule.next(start))
+
+	schedule, err = parseCronExpression("0 9 * * 1-5")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 2, 1, 9, 0, 0, 0, time.UTC), schedule.next(start))
+	assert.Equal(t, time.Date(2023, 2, 6, 9, 0, 0, 0, time.UTC), schedule.next(time.Date(2023, 2, 3, 9, 0, 0, 0, time.UTC)))
+
+	schedule, err = parseCronExpression("0 0 13 * 5")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 2, 3, 0, 0, 0, 0, time.UTC), schedule.next(start))
+
+	schedule, err = parseCronExpression("30 4 29 2 *")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 2, 29, 4, 30, 0, 0, time.UTC), schedule.next(start))
+
+	schedule, err = parseCronExpression("0 0 * * 7")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Sunday, schedule.next(start).Weekday())
+
+	_, err = parseCronExpression("* * *")
+	assert.EqualError(t, err, "invalid cron expression '* * *'")
+	_, err = parseCronExpression("61 * * * *")
+	assert.EqualError(t, err, "invalid cron expression '61 * * * *': value '61' out of range 0-59")
+	_, err = parseCronExpression("*/0 * * * *")
+	assert.EqualError(t, err, "invalid cron expression '*/0 * * * *': invalid step in '*/0'")
+}
+
+func TestCronScheduler(t *testing.T) {
+	runs := 0
+	registry := &Registry{}
+	registry.RegisterCronJob("purge", "*/5 * * * *", func(ctx context.Context, engine Engine) error {
+		runs++
+		if runs == 2 {
+			return fmt.Errorf("purge failed")
+		}
+		return nil
+	})
+	engine := prepareTables(t, registry, 5, 6, "")
+	clock := &cronTestClock{now: time.Date(2023, 1, 31, 10, 1, 0, 0, time.UTC)}
+	engine.SetClock(clock)
+
+	scheduler := NewCronScheduler(engine)
+	assert.Equal(t, 0, scheduler.RunOnce(context.Background()))
+	assert.Equal(t, 0, NewCronScheduler(engine).RunOnce(context.Background()))
+	clock.now = clock.now.Add(time.Minute * 4)
+	assert.Equal(t, 1, scheduler.RunOnce(context.Background()))
+	assert.Equal(t, 0, NewCronScheduler(engine).RunOnce(context.Background()))
+	assert.Equal(t, 1, runs)
+	clock.now = clock.now.Add(time.Minute * 5)
+	assert.Equal(t, 1, NewCronScheduler(engine).RunOnce(context.Background()))
+	assert.Equal(t, 2, runs)
+
+	events := engine.GetRedis().XRange(CronResultsStreamName, "-", "+", 10)
+	assert.Len(t, events, 2)
+	assert.Panics(t, func() {
+		registry.RegisterCronJob("invalid", "invalid", nil)
+	})
+}
@@ -0,0 +1,138 @@
+package beeorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+const crudStreamPrefix = "beeorm-crud-"
+
+type CrudSubscription struct {
+	Entity Entity
+	Fields []string
+	Types  []FlushType
+}
+
+type CrudEvent struct {
+	Entity  string
+	ID      uint64
+	Type    FlushType
+	Changes map[string]FieldDiff
+}
+
+type CrudEventHandler func(events []*CrudEvent)
+
+type crudSubscriber struct {
+	stream string
+	fields map[string]bool
+	types  map[FlushType]bool
+}
+
+type crudSubscriberDefinition struct {
+	stream        string
+	subscriptions []CrudSubscription
+}
+
+type CrudConsumer struct {
+	eventConsumerBase
+	stream string
+}
+
+func (r *Registry) RegisterCrudSubscriber(name, redisPool string, subscriptions ...CrudSubscription) {
+	if r.crudSubscribers == nil {
+		r.crudSubscribers = make(map[string]*crudSubscriberDefinition)
+	}
+	if _, has := r.crudSubscribers[name]; has {
+		panic(fmt.Errorf("crud subscriber '%s' already registered", name))
+	}
+	definition := &crudSubscriberDefinition{stream: crudStreamPrefix + name, subscriptions: subscriptions}
+	r.RegisterRedisStream(definition.stream, redisPool, []string{definition.stream})
+	r.crudSubscribers[name] = definition
+}
+
+func initCrudSubscribers(r *Registry, registry *validatedRegistry) error {
+	for name, definition := range r.crudSubscribers {
+		for _, subscription := range definition.subscriptions {
+			t := reflect.TypeOf(subscription.Entity)
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			schema := registry.tableSchemas[t]
+			if schema == nil {
+				return fmt.Errorf("crud subscriber '%s' entity '%s' is not registered", name, t.String())
+			}
+			subscriber := &crudSubscriber{stream: definition.stream}
+			for _, field := range subscription.Fields {
+				if _, has := schema.columnMapping[field]; !has {
+					return fmt.Errorf("crud subscriber '%s' field %s not found in %s", name, field, t.String())
+				}
+				if subscriber.fields == nil {
+					subscriber.fields = make(map[string]bool)
+				}
+				subscriber.fields[field] = true
+			}
+			for _, flushType := range subscription.Types {
+				if subscriber.types == nil {
+					subscriber.types = make(map[FlushType]bool)
+				}
+				subscriber.types[flushType] = true
+			}
+			schema.crudSubscribers = append(schema.crudSubscribers, subscriber)
+		}
+	}
+	return nil
+}
+
+func (f *flusher) addCrudEvents(flushType FlushType, schema *tableSchema, id uint64, before, changes Bind) {
+	for _, subscriber := range schema.crudSubscribers {
+		if subscriber.types != nil && !subscriber.types[flushType] {
+			continue
+		}
+		diff := make(map[string]FieldDiff)
+		if flushType == FlushTypeDelete {
+			for field, value := range before {
+				if subscriber.fields == nil || subscriber.fields[field] {
+					diff[field] = FieldDiff{Old: value}
+				}
+			}
+		} else {
+			for field, value := range changes {
+				if subscriber.fields == nil || subscriber.fields[field] {
+					diff[field] = FieldDiff{Old: before[field], New: value}
+				}
+			}
+			if len(diff) == 0 {
+				continue
+			}
+		}
+		f.getRedisFlusher().Publish(subscriber.stream, CrudEvent{Entity: schema.t.String(), ID: id, Type: flushType, Changes: diff})
+	}
+}
+
+func NewCrudConsumer(engine Engine, name string) *CrudConsumer {
+	e := engine.(*engineImplementation)
+	definition, has := e.registry.registry.crudSubscribers[name]
+	if !has {
+		panic(fmt.Errorf("crud subscriber '%s' is not registered", name))
+	}
+	c := &CrudConsumer{stream: definition.stream}
+	c.engine = e
+	c.block = true
+	c.blockTime = time.Second * 30
+	return c
+}
+
+func (c *CrudConsumer) Digest(ctx context.Context, count int, handler CrudEventHandler) bool {
+	consumer := c.engine.GetEventBroker().Consumer(c.stream).(*eventsConsumer)
+	consumer.eventConsumerBase = c.eventConsumerBase
+	return consumer.Consume(ctx, count, func(events []Event) {
+		crudEvents := make([]*CrudEvent, len(events))
+		for i, event := range events {
+			crudEvents[i] = &CrudEvent{}
+			event.Unserialize(crudEvents[i])
+		}
+		handler(crudEvents)
+	})
+}
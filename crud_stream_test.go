@@ -0,0 +1,68 @@
+package beeorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type crudStreamEntity struct {
+	ORM
+	ID     uint
+	Name   string
+	Status string
+}
+
+func TestCrudSubscriber(t *testing.T) {
+	var entity *crudStreamEntity
+	registry := &Registry{}
+	registry.RegisterCrudSubscriber("status", "default", CrudSubscription{Entity: entity, Fields: []string{"Status"}})
+	registry.RegisterCrudSubscriber("deletes", "default", CrudSubscription{Entity: entity, Types: []FlushType{FlushTypeDelete}})
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+
+	e := &crudStreamEntity{Name: "a", Status: "new"}
+	engine.Flush(e)
+	e.Name = "b"
+	engine.Flush(e)
+	e.Status = "done"
+	engine.Flush(e)
+	engine.Delete(e)
+
+	consumer := NewCrudConsumer(engine, "status")
+	consumer.DisableBlockMode()
+	consumer.blockTime = time.Millisecond
+	events := make([]*CrudEvent, 0)
+	consumer.Digest(context.Background(), 100, func(received []*CrudEvent) {
+		events = append(events, received...)
+	})
+	assert.Len(t, events, 3)
+	assert.Equal(t, FlushTypeInsert, events[0].Type)
+	assert.Equal(t, "new", events[0].Changes["Status"].New)
+	assert.Len(t, events[0].Changes, 1)
+	assert.Equal(t, FlushTypeUpdate, events[1].Type)
+	assert.Equal(t, uint64(1), events[1].ID)
+	assert.Equal(t, "beeorm.crudStreamEntity", events[1].Entity)
+	assert.Equal(t, "new", events[1].Changes["Status"].Old)
+	assert.Equal(t, "done", events[1].Changes["Status"].New)
+	assert.Equal(t, FlushTypeDelete, events[2].Type)
+	assert.Equal(t, "done", events[2].Changes["Status"].Old)
+
+	consumer = NewCrudConsumer(engine, "deletes")
+	consumer.DisableBlockMode()
+	consumer.blockTime = time.Millisecond
+	events = events[0:0]
+	consumer.Digest(context.Background(), 100, func(received []*CrudEvent) {
+		events = append(events, received...)
+	})
+	assert.Len(t, events, 1)
+	assert.Equal(t, "b", events[0].Changes["Name"].Old)
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(entity)
+	registry.RegisterCrudSubscriber("invalid", "default", CrudSubscription{Entity: entity, Fields: []string{"Invalid"}})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "crud subscriber 'invalid' field Invalid not found in beeorm.crudStreamEntity")
+}
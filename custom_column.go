@@ -0,0 +1,260 @@
+package beeorm
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	googleuuid "github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type ColumnType interface {
+	sql.Scanner
+	driver.Valuer
+	ColumnDefinition(mysqlVersion int) string
+}
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+var columnTypeType = reflect.TypeOf((*ColumnType)(nil)).Elem()
+var decimalType = reflect.TypeOf(decimal.Decimal{})
+var uuidType = reflect.TypeOf(googleuuid.UUID{})
+
+func getCustomColumnType(t reflect.Type) (nullable bool, is bool) {
+	if t.Kind() == reflect.Ptr {
+		nullable = true
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Ptr || !reflect.PtrTo(t).Implements(scannerType) || !reflect.PtrTo(t).Implements(valuerType) {
+		return false, false
+	}
+	return nullable, true
+}
+
+func customColumnDefinition(version int, field reflect.StructField, attributes map[string]string) (string, error) {
+	definition, has := attributes["columnType"]
+	if has {
+		return definition, nil
+	}
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == uuidType {
+		if attributes["uuidStorage"] == "char" {
+			return "char(36) CHARACTER SET ascii COLLATE ascii_bin", nil
+		}
+		return "binary(16)", nil
+	}
+	if t == decimalType {
+		precision, has := attributes["decimal"]
+		if !has || getDecimalScale(t, attributes) < 0 {
+			return "", fmt.Errorf("decimal field %s requires decimal=precision,scale tag", field.Name)
+		}
+		definition = "decimal(" + precision + ")"
+		if attributes["unsigned"] == "true" {
+			definition += " unsigned"
+		}
+		return definition, nil
+	}
+	if reflect.PtrTo(t).Implements(columnTypeType) {
+		return reflect.New(t).Interface().(ColumnType).ColumnDefinition(version), nil
+	}
+	return "", fmt.Errorf("custom column %s of type %s requires columnType tag", field.Name, field.Type.String())
+}
+
+type customColumnOptions struct {
+	scale       int
+	binaryUUID  bool
+	intEnum     *intEnum
+	enumStorage bool
+}
+
+func getCustomColumnOptions(t reflect.Type, attributes map[string]string) customColumnOptions {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return customColumnOptions{scale: getDecimalScale(t, attributes), binaryUUID: t == uuidType && attributes["uuidStorage"] != "char"}
+}
+
+func getDecimalScale(t reflect.Type, attributes map[string]string) int {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t != decimalType {
+		return -1
+	}
+	definition, has := attributes["decimal"]
+	if !has {
+		return -1
+	}
+	parts := strings.Split(definition, ",")
+	if len(parts) != 2 {
+		return -1
+	}
+	scale, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return -1
+	}
+	return scale
+}
+
+func getCustomColumnValue(f reflect.Value, options customColumnOptions) (string, bool) {
+	if f.Kind() == reflect.Ptr {
+		if f.IsNil() {
+			return "", false
+		}
+		f = f.Elem()
+	}
+	if options.intEnum != nil {
+		value, _ := options.intEnum.format(f, options.enumStorage)
+		return value, true
+	}
+	if f.Type() == decimalType {
+		value := f.Interface().(decimal.Decimal)
+		if options.scale >= 0 {
+			return value.StringFixed(int32(options.scale)), true
+		}
+		return value.String(), true
+	}
+	if options.binaryUUID {
+		value := f.Interface().(googleuuid.UUID)
+		return string(value[:]), true
+	}
+	var valuer driver.Valuer
+	if f.Type().Implements(valuerType) {
+		valuer = f.Interface().(driver.Valuer)
+	} else if f.CanAddr() {
+		valuer = f.Addr().Interface().(driver.Valuer)
+	} else {
+		copied := reflect.New(f.Type())
+		copied.Elem().Set(f)
+		valuer = copied.Interface().(driver.Valuer)
+	}
+	value, err := valuer.Value()
+	checkError(err)
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case []byte:
+		return string(v), true
+	case string:
+		return v, true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		if v {
+			return "1", true
+		}
+		return "0", true
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05.999999"), true
+	default:
+		return fmt.Sprintf("%v", v), true
+	}
+}
+
+func setCustomColumnValue(f reflect.Value, value []byte, valid bool, options customColumnOptions) {
+	t := f.Type()
+	nullable := t.Kind() == reflect.Ptr
+	if nullable {
+		t = t.Elem()
+	}
+	if options.intEnum != nil {
+		if !valid {
+			f.Set(reflect.Zero(f.Type()))
+			return
+		}
+		v := reflect.New(t)
+		options.intEnum.set(v.Elem(), string(value), options.enumStorage)
+		if nullable {
+			f.Set(v)
+		} else {
+			f.Set(v.Elem())
+		}
+		return
+	}
+	if !valid {
+		if nullable {
+			f.Set(reflect.Zero(f.Type()))
+			return
+		}
+		v := reflect.New(t)
+		_ = v.Interface().(sql.Scanner).Scan(nil)
+		f.Set(v.Elem())
+		return
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	v := reflect.New(t)
+	checkError(v.Interface().(sql.Scanner).Scan(value))
+	if nullable {
+		f.Set(v)
+	} else {
+		f.Set(v.Elem())
+	}
+}
+
+func normalizeCustomColumnValue(t reflect.Type, value *sql.NullString, options customColumnOptions) (string, bool) {
+	if !value.Valid {
+		return "", false
+	}
+	f := reflect.New(t).Elem()
+	setCustomColumnValue(f, []byte(value.String), true, options)
+	return getCustomColumnValue(f, options)
+}
+
+func (b *bindBuilder) buildCustoms(serializer *serializer, fields *tableFields, value reflect.Value) {
+	for k, i := range fields.customs {
+		b.index++
+		f := value.Field(i)
+		options := fields.customsOptions[k]
+		val, valid := getCustomColumnValue(f, options)
+		name := b.orm.tableSchema.columnNames[b.index]
+		if valid && options.intEnum != nil {
+			if f.Kind() == reflect.Ptr {
+				f = f.Elem()
+			}
+			if _, known := options.intEnum.format(f, options.enumStorage); !known {
+				panic(b.newEnumValueError(name, val))
+			}
+		}
+		if b.orm.inDB {
+			oldValid := serializer.DeserializeBool()
+			old := string(serializer.DeserializeBytes())
+			if b.hasCurrent {
+				if oldValid {
+					b.current[name] = old
+				} else {
+					b.current[name] = nil
+				}
+			}
+			if oldValid == valid && old == val {
+				continue
+			}
+		}
+		if valid {
+			b.bind[name] = val
+			if b.buildSQL {
+				b.sqlBind[name] = escapeSQLString(val)
+				if !utf8.ValidString(val) {
+					b.sqlBind[name] = "_binary" + b.sqlBind[name]
+				}
+			}
+		} else {
+			b.bind[name] = nil
+			if b.buildSQL {
+				b.sqlBind[name] = "NULL"
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package beeorm
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMoney struct {
+	Cents int64
+}
+
+func (m *testMoney) Scan(src interface{}) error {
+	var asString string
+	switch v := src.(type) {
+	case nil:
+		m.Cents = 0
+		return nil
+	case []byte:
+		asString = string(v)
+	case string:
+		asString = v
+	default:
+		return fmt.Errorf("invalid money value %v", src)
+	}
+	parts := strings.SplitN(asString, ".", 2)
+	units, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return err
+	}
+	cents := int64(0)
+	if len(parts) == 2 {
+		cents, err = strconv.ParseInt((parts[1] + "00")[0:2], 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+	m.Cents = units*100 + cents
+	return nil
+}
+
+func (m testMoney) Value() (driver.Value, error) {
+	return fmt.Sprintf("%d.%02d", m.Cents/100, m.Cents%100), nil
+}
+
+func (m *testMoney) ColumnDefinition(_ int) string {
+	return "decimal(12,2)"
+}
+
+type testIP struct {
+	net.IP
+}
+
+func (ip *testIP) Scan(src interface{}) error {
+	raw, _ := src.([]byte)
+	ip.IP = net.IP(append([]byte{}, raw...))
+	return nil
+}
+
+func (ip testIP) Value() (driver.Value, error) {
+	return []byte(ip.IP.To16()), nil
+}
+
+type customColumnEntity struct {
+	ORM      `orm:"localCache;redisCache"`
+	ID       uint
+	Name     string
+	Price    testMoney
+	Discount *testMoney
+	IP       testIP `orm:"columnType=varbinary(16)"`
+}
+
+type customColumnInvalidEntity struct {
+	ORM
+	ID uint
+	IP testIP
+}
+
+func TestCustomColumns(t *testing.T) {
+	var entity *customColumnEntity
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	schema.DropTable(engine)
+	has, alters := schema.GetSchemaChanges(engine)
+	assert.True(t, has)
+	assert.Equal(t, "CREATE TABLE `test`.`customColumnEntity` (\n  `ID` int(10) unsigned NOT NULL AUTO_INCREMENT,\n  "+
+		"`Name` varchar(255) DEFAULT NULL,\n  `Price` decimal(12,2) NOT NULL,\n  `Discount` decimal(12,2) DEFAULT NULL,\n  "+
+		"`IP` varbinary(16) NOT NULL,\n  PRIMARY KEY (`ID`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;", alters[0].SQL)
+	schema.UpdateSchema(engine)
+	assert.Len(t, engine.GetAlters(), 0)
+
+	entity = &customColumnEntity{Name: "a", Price: testMoney{Cents: 1250}, IP: testIP{IP: net.ParseIP("10.0.0.1")}}
+	engine.Flush(entity)
+	assert.False(t, entity.IsDirty())
+
+	entity = &customColumnEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, int64(1250), entity.Price.Cents)
+	assert.Nil(t, entity.Discount)
+	assert.Equal(t, "10.0.0.1", entity.IP.String())
+
+	entity.Discount = &testMoney{Cents: 99}
+	assert.True(t, entity.IsDirty())
+	engine.Flush(entity)
+	entity = &customColumnEntity{}
+	engine.GetLocalCache().Clear()
+	engine.GetRedis().FlushDB()
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, int64(99), entity.Discount.Cents)
+	assert.Equal(t, int64(1250), entity.Price.Cents)
+
+	var rows []*customColumnEntity
+	engine.Search(NewWhere("`Price` = ?", testMoney{Cents: 1250}), nil, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "10.0.0.1", rows[0].IP.String())
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&customColumnInvalidEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "invalid entity struct 'beeorm.customColumnInvalidEntity': custom column IP of type beeorm.testIP requires columnType tag")
+}
@@ -21,16 +21,21 @@ type MySQLPoolConfig interface {
 	getClient() *sql.DB
 	getAutoincrement() uint64
 	getMaxConnections() int
+	GetMaxAllowedPacket() int
+	GetMaxInSize() int
+	getIDsChunkSize(queryLength int) int
 }
 
 type mySQLPoolConfig struct {
-	dataSourceName string
-	code           string
-	databaseName   string
-	client         *sql.DB
-	autoincrement  uint64
-	version        int
-	maxConnections int
+	dataSourceName   string
+	code             string
+	databaseName     string
+	client           *sql.DB
+	autoincrement    uint64
+	version          int
+	maxConnections   int
+	maxAllowedPacket int
+	maxInSize        int
 }
 
 func (p *mySQLPoolConfig) GetCode() string {
@@ -61,6 +66,14 @@ func (p *mySQLPoolConfig) getMaxConnections() int {
 	return p.maxConnections
 }
 
+func (p *mySQLPoolConfig) GetMaxAllowedPacket() int {
+	return p.maxAllowedPacket
+}
+
+func (p *mySQLPoolConfig) GetMaxInSize() int {
+	return p.maxInSize
+}
+
 type ExecResult interface {
 	LastInsertId() uint64
 	RowsAffected() uint64
@@ -271,6 +284,7 @@ type DB struct {
 	client        sqlClient
 	config        MySQLPoolConfig
 	inTransaction bool
+	timeout       time.Duration
 }
 
 func (db *DB) GetPoolConfig() MySQLPoolConfig {
@@ -292,11 +306,18 @@ func (db *DB) Begin() {
 }
 
 func (db *DB) Commit() {
+	if db.engine.beforeCommitCacheDeletes != nil {
+		db.engine.beforeCommitCacheDeletes.execute(db.engine)
+		db.engine.beforeCommitCacheDeletes = nil
+	}
 	start := getNow(db.engine.hasDBLogger)
 	err := db.client.Commit()
 	if db.engine.hasDBLogger {
 		db.fillLogFields("COMMIT", "COMMIT", start, err)
 	}
+	if db.engine.flushReport != nil {
+		db.engine.flushReport.committed(db.config.GetCode(), err)
+	}
 	checkError(err)
 	db.inTransaction = false
 	if db.engine.afterCommitLocalCacheSets != nil {
@@ -311,6 +332,14 @@ func (db *DB) Commit() {
 		db.engine.afterCommitRedisFlusher.Flush()
 		db.engine.afterCommitRedisFlusher = nil
 	}
+	if db.engine.afterCommitDoubleDeletes != nil {
+		scheduleDoubleDeletes(db.engine, db.engine.afterCommitDoubleDeletes)
+		db.engine.afterCommitDoubleDeletes = nil
+	}
+	if db.engine.afterCommitFingerprints != nil {
+		db.engine.addFlushedFingerprints(db.engine.afterCommitFingerprints)
+		db.engine.afterCommitFingerprints = nil
+	}
 }
 
 func (db *DB) Rollback() {
@@ -320,15 +349,24 @@ func (db *DB) Rollback() {
 		if db.engine.hasDBLogger {
 			db.fillLogFields("ROLLBACK", "ROLLBACK", start, err)
 		}
+		if db.engine.flushReport != nil {
+			db.engine.flushReport.rolledBack(db.config.GetCode())
+		}
 	}
 	checkError(err)
 	db.engine.afterCommitLocalCacheSets = nil
 	db.engine.afterCommitRedisFlusher = nil
+	db.engine.beforeCommitCacheDeletes = nil
+	db.engine.afterCommitDoubleDeletes = nil
+	db.engine.afterCommitFingerprints = nil
 	db.inTransaction = false
 }
 
 func (db *DB) Exec(query string, args ...interface{}) ExecResult {
 	results, err := db.exec(query, args...)
+	if db.engine.flushReport != nil {
+		db.engine.flushReport.executed(db.config.GetCode(), db.inTransaction, err)
+	}
 	if err != nil {
 		panic(db.convertToError(err))
 	}
@@ -337,10 +375,11 @@ func (db *DB) Exec(query string, args ...interface{}) ExecResult {
 
 func (db *DB) exec(query string, args ...interface{}) (ExecResult, error) {
 	start := getNow(db.engine.hasDBLogger)
-	if db.engine.queryTimeLimit > 0 {
-		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(db.engine.queryTimeLimit)*time.Second)
+	limit := db.getQueryTimeout()
+	if limit != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), limit.timeout)
 		defer cancel()
-		rows, err := db.client.ExecContext(ctx, query, args...)
+		rows, err := db.client.ExecContext(ctx, limit.markQuery(query), args...)
 		if db.engine.hasDBLogger {
 			message := query
 			if len(args) > 0 {
@@ -349,9 +388,11 @@ func (db *DB) exec(query string, args ...interface{}) (ExecResult, error) {
 			db.fillLogFields("EXEC", message, start, err)
 		}
 		if err != nil {
-			_, isTimeout := ctx.Deadline()
-			if isTimeout {
-				return nil, &mysql.MySQLError{Number: 1969, Message: fmt.Sprintf("query exceeded limit of %d seconds", db.engine.queryTimeLimit)}
+			if ctx.Err() == context.DeadlineExceeded {
+				if limit.legacy {
+					return nil, &mysql.MySQLError{Number: 1969, Message: fmt.Sprintf("query exceeded limit of %d seconds", db.engine.queryTimeLimit)}
+				}
+				return nil, db.timeoutError(limit, query)
 			}
 			return nil, err
 		}
@@ -370,10 +411,11 @@ func (db *DB) exec(query string, args ...interface{}) (ExecResult, error) {
 
 func (db *DB) QueryRow(query *Where, toFill ...interface{}) (found bool) {
 	start := getNow(db.engine.hasDBLogger)
-	if db.engine.queryTimeLimit > 0 {
-		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(db.engine.queryTimeLimit)*time.Second)
+	limit := db.getQueryTimeout()
+	if limit != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), limit.timeout)
 		defer cancel()
-		row := db.client.QueryRowContext(ctx, query.String(), query.GetParameters()...)
+		row := db.client.QueryRowContext(ctx, limit.markQuery(query.String()), query.GetParameters()...)
 		err := row.Scan(toFill...)
 		message := ""
 		if db.engine.hasDBLogger {
@@ -383,9 +425,11 @@ func (db *DB) QueryRow(query *Where, toFill ...interface{}) (found bool) {
 			}
 		}
 		if err != nil {
-			_, isTimeout := ctx.Deadline()
-			if isTimeout {
-				panic(errors.Errorf("query exceeded limit of %d seconds", db.engine.queryTimeLimit))
+			if ctx.Err() == context.DeadlineExceeded {
+				if limit.legacy {
+					panic(errors.Errorf("query exceeded limit of %d seconds", db.engine.queryTimeLimit))
+				}
+				panic(db.timeoutError(limit, query.String()))
 			}
 			if err.Error() == "sql: no rows in result set" {
 				if db.engine.hasDBLogger {
@@ -432,10 +476,10 @@ func (db *DB) QueryRow(query *Where, toFill ...interface{}) (found bool) {
 
 func (db *DB) Query(query string, args ...interface{}) (rows Rows, close func()) {
 	start := getNow(db.engine.hasDBLogger)
-	if db.engine.queryTimeLimit > 0 {
-		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(db.engine.queryTimeLimit)*time.Second)
-		defer cancel()
-		result, err := db.client.QueryContext(ctx, query, args...)
+	limit := db.getQueryTimeout()
+	if limit != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), limit.timeout)
+		result, err := db.client.QueryContext(ctx, limit.markQuery(query), args...)
 		if db.engine.hasDBLogger {
 			message := query
 			if len(args) > 0 {
@@ -444,15 +488,22 @@ func (db *DB) Query(query string, args ...interface{}) (rows Rows, close func())
 			db.fillLogFields("SELECT", message, start, err)
 		}
 		if err != nil {
-			_, isTimeout := ctx.Deadline()
-			if isTimeout {
-				panic(errors.Errorf("query exceeded limit of %d seconds", db.engine.queryTimeLimit))
+			cancel()
+			if ctx.Err() == context.DeadlineExceeded {
+				if limit.legacy {
+					panic(errors.Errorf("query exceeded limit of %d seconds", db.engine.queryTimeLimit))
+				}
+				panic(db.timeoutError(limit, query))
 			}
 		}
 		checkError(err)
 		return &rowsStruct{result}, func() {
+			defer cancel()
 			if result != nil {
 				err := result.Err()
+				if err != nil && ctx.Err() == context.DeadlineExceeded && !limit.legacy {
+					err = db.timeoutError(limit, query)
+				}
 				checkError(err)
 				err = result.Close()
 				checkError(err)
@@ -479,7 +530,10 @@ func (db *DB) Query(query string, args ...interface{}) (rows Rows, close func())
 }
 
 func (db *DB) fillLogFields(operation, query string, start *time.Time, err error) {
-	query = strings.ReplaceAll(query, "\n", " ")
+	if !db.engine.registry.runtime.sampleLog(err) {
+		return
+	}
+	query = db.engine.maskQuery(strings.ReplaceAll(query, "\n", " "))
 	fillLogFields(db.engine.queryLoggersDB, db.GetPoolConfig().GetCode(), sourceMySQL, operation, query, start, false, err)
 }
 
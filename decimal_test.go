@@ -0,0 +1,76 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type decimalEntity struct {
+	ORM      `orm:"localCache;redisCache"`
+	ID       uint
+	Price    decimal.Decimal  `orm:"decimal=12,2"`
+	Discount *decimal.Decimal `orm:"decimal=5,4;unsigned"`
+}
+
+type decimalInvalidEntity struct {
+	ORM
+	ID    uint
+	Price decimal.Decimal
+}
+
+func TestDecimal(t *testing.T) {
+	var entity *decimalEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	schema.DropTable(engine)
+	_, alters := schema.GetSchemaChanges(engine)
+	assert.Equal(t, "CREATE TABLE `test`.`decimalEntity` (\n  `ID` int(10) unsigned NOT NULL AUTO_INCREMENT,\n  "+
+		"`Price` decimal(12,2) NOT NULL,\n  `Discount` decimal(5,4) unsigned DEFAULT NULL,\n  PRIMARY KEY (`ID`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;", alters[0].SQL)
+	schema.UpdateSchema(engine)
+	assert.Len(t, engine.GetAlters(), 0)
+
+	entity = &decimalEntity{Price: decimal.RequireFromString("1234567890.10")}
+	engine.Flush(entity)
+	assert.False(t, entity.IsDirty())
+
+	entity = &decimalEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "1234567890.10", entity.Price.StringFixed(2))
+	assert.Nil(t, entity.Discount)
+	assert.False(t, entity.IsDirty())
+
+	engine.GetLocalCache().Clear()
+	engine.GetRedis().FlushDB()
+	entity = &decimalEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.True(t, entity.Price.Equal(decimal.RequireFromString("1234567890.1")))
+	assert.False(t, entity.IsDirty())
+
+	entity.Price = decimal.RequireFromString("0.1").Add(decimal.RequireFromString("0.2"))
+	discount := decimal.RequireFromString("0.12345")
+	entity.Discount = &discount
+	engine.Flush(entity)
+	engine.GetLocalCache().Clear()
+	entity = &decimalEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "0.30", entity.Price.StringFixed(2))
+	assert.Equal(t, "0.1235", entity.Discount.String())
+	engine.GetRedis().FlushDB()
+	engine.GetLocalCache().Clear()
+	entity = &decimalEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "0.1235", entity.Discount.String())
+
+	var rows []*decimalEntity
+	engine.Search(NewWhere("`Price` = ?", decimal.RequireFromString("0.3")), nil, &rows)
+	assert.Len(t, rows, 1)
+
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&decimalInvalidEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "invalid entity struct 'beeorm.decimalInvalidEntity': decimal field Price requires decimal=precision,scale tag")
+}
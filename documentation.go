@@ -0,0 +1,193 @@
+package beeorm
+
+import (
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type entityDocumentation struct {
+	Name          string
+	Table         string
+	MySQLPool     string
+	LocalCache    string
+	RedisCache    string
+	Columns       [][2]string
+	Indexes       []entityDocumentationIndex
+	References    [][3]string
+	CachedQueries []entityDocumentationQuery
+}
+
+type entityDocumentationIndex struct {
+	Name    string
+	Unique  bool
+	Columns []string
+}
+
+type entityDocumentationQuery struct {
+	Name          string
+	Query         string
+	One           bool
+	Max           int
+	Overflow      string
+	TrackedFields []string
+}
+
+func GenerateEntitiesMarkdown(registry ValidatedRegistry) string {
+	b := &strings.Builder{}
+	b.WriteString("# Entities\n")
+	for _, doc := range buildEntitiesDocumentation(registry) {
+		b.WriteString("\n## " + doc.Name + "\n\n")
+		b.WriteString("| Table | MySQL pool | Local cache | Redis cache |\n|---|---|---|---|\n")
+		b.WriteString("| " + markdownCell(doc.Table) + " | " + markdownCell(doc.MySQLPool) + " | " + markdownCell(doc.LocalCache) +
+			" | " + markdownCell(doc.RedisCache) + " |\n")
+		b.WriteString("\n### Columns\n\n| Column | Type |\n|---|---|\n")
+		for _, column := range doc.Columns {
+			b.WriteString("| " + markdownCell(column[0]) + " | " + markdownCell(column[1]) + " |\n")
+		}
+		if len(doc.Indexes) > 0 {
+			b.WriteString("\n### Indexes\n\n| Index | Unique | Columns |\n|---|---|---|\n")
+			for _, index := range doc.Indexes {
+				b.WriteString("| " + markdownCell(index.Name) + " | " + strconv.FormatBool(index.Unique) + " | " +
+					markdownCell(strings.Join(index.Columns, ", ")) + " |\n")
+			}
+		}
+		if len(doc.References) > 0 {
+			b.WriteString("\n### References\n\n| Field | Entity | Type |\n|---|---|---|\n")
+			for _, reference := range doc.References {
+				b.WriteString("| " + markdownCell(reference[0]) + " | " + markdownCell(reference[1]) + " | " + reference[2] + " |\n")
+			}
+		}
+		if len(doc.CachedQueries) > 0 {
+			b.WriteString("\n### Cached queries\n\n| Name | Query | One | Max | Overflow | Tracked fields |\n|---|---|---|---|---|---|\n")
+			for _, query := range doc.CachedQueries {
+				b.WriteString("| " + markdownCell(query.Name) + " | " + markdownCell(query.Query) + " | " + strconv.FormatBool(query.One) +
+					" | " + strconv.Itoa(query.Max) + " | " + query.Overflow + " | " + markdownCell(strings.Join(query.TrackedFields, ", ")) + " |\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+func GenerateEntitiesHTML(registry ValidatedRegistry) string {
+	b := &strings.Builder{}
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>Entities</title></head>\n<body>\n<h1>Entities</h1>\n")
+	for _, doc := range buildEntitiesDocumentation(registry) {
+		b.WriteString("<h2 id=\"" + html.EscapeString(doc.Name) + "\">" + html.EscapeString(doc.Name) + "</h2>\n")
+		writeHTMLTable(b, []string{"Table", "MySQL pool", "Local cache", "Redis cache"},
+			[][]string{{doc.Table, doc.MySQLPool, doc.LocalCache, doc.RedisCache}})
+		b.WriteString("<h3>Columns</h3>\n")
+		rows := make([][]string, len(doc.Columns))
+		for i, column := range doc.Columns {
+			rows[i] = []string{column[0], column[1]}
+		}
+		writeHTMLTable(b, []string{"Column", "Type"}, rows)
+		if len(doc.Indexes) > 0 {
+			b.WriteString("<h3>Indexes</h3>\n")
+			rows = make([][]string, len(doc.Indexes))
+			for i, index := range doc.Indexes {
+				rows[i] = []string{index.Name, strconv.FormatBool(index.Unique), strings.Join(index.Columns, ", ")}
+			}
+			writeHTMLTable(b, []string{"Index", "Unique", "Columns"}, rows)
+		}
+		if len(doc.References) > 0 {
+			b.WriteString("<h3>References</h3>\n")
+			rows = make([][]string, len(doc.References))
+			for i, reference := range doc.References {
+				rows[i] = []string{reference[0], reference[1], reference[2]}
+			}
+			writeHTMLTable(b, []string{"Field", "Entity", "Type"}, rows)
+		}
+		if len(doc.CachedQueries) > 0 {
+			b.WriteString("<h3>Cached queries</h3>\n")
+			rows = make([][]string, len(doc.CachedQueries))
+			for i, query := range doc.CachedQueries {
+				rows[i] = []string{query.Name, query.Query, strconv.FormatBool(query.One), strconv.Itoa(query.Max),
+					query.Overflow, strings.Join(query.TrackedFields, ", ")}
+			}
+			writeHTMLTable(b, []string{"Name", "Query", "One", "Max", "Overflow", "Tracked fields"}, rows)
+		}
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+func buildEntitiesDocumentation(registry ValidatedRegistry) []*entityDocumentation {
+	engine := registry.CreateEngine().(*engineImplementation)
+	names := make([]string, 0)
+	for name := range registry.GetEntities() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	docs := make([]*entityDocumentation, len(names))
+	for i, name := range names {
+		schema := registry.GetTableSchema(name).(*tableSchema)
+		doc := &entityDocumentation{Name: name, Table: schema.tableName, MySQLPool: schema.mysqlPoolName,
+			LocalCache: schema.localCacheName, RedisCache: schema.redisCacheName}
+		indexes := make(map[string]*index)
+		columns, err := checkStruct(schema, engine, schema.t, indexes, make(map[string]*foreignIndex), nil, "")
+		checkError(err)
+		for _, column := range columns {
+			definition := strings.TrimPrefix(column[1], "`"+column[0]+"` ")
+			doc.Columns = append(doc.Columns, [2]string{column[0], definition})
+		}
+		for indexName, definition := range indexes {
+			documentationIndex := entityDocumentationIndex{Name: indexName, Unique: definition.Unique}
+			for j := 1; j <= len(definition.Columns); j++ {
+				documentationIndex.Columns = append(documentationIndex.Columns, definition.Columns[j])
+			}
+			doc.Indexes = append(doc.Indexes, documentationIndex)
+		}
+		sort.Slice(doc.Indexes, func(a, b int) bool {
+			return doc.Indexes[a].Name < doc.Indexes[b].Name
+		})
+		for field, tags := range schema.tags {
+			ref, has := tags["ref"]
+			if has {
+				doc.References = append(doc.References, [3]string{field, ref, "one"})
+			}
+			ref, has = tags["refs"]
+			if has {
+				doc.References = append(doc.References, [3]string{field, ref, "many"})
+			}
+		}
+		sort.Slice(doc.References, func(a, b int) bool {
+			return doc.References[a][0] < doc.References[b][0]
+		})
+		for queryName, definition := range schema.cachedIndexesAll {
+			_, one := schema.cachedIndexesOne[queryName]
+			overflow := string(definition.Overflow)
+			if overflow == "" {
+				overflow = "error"
+			}
+			doc.CachedQueries = append(doc.CachedQueries, entityDocumentationQuery{Name: queryName, Query: definition.Query,
+				One: one, Max: definition.Max, Overflow: overflow, TrackedFields: definition.TrackedFields})
+		}
+		sort.Slice(doc.CachedQueries, func(a, b int) bool {
+			return doc.CachedQueries[a].Name < doc.CachedQueries[b].Name
+		})
+		docs[i] = doc
+	}
+	return docs
+}
+
+func markdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", "\\|")
+}
+
+func writeHTMLTable(b *strings.Builder, header []string, rows [][]string) {
+	b.WriteString("<table>\n<tr>")
+	for _, name := range header {
+		b.WriteString("<th>" + html.EscapeString(name) + "</th>")
+	}
+	b.WriteString("</tr>\n")
+	for _, row := range rows {
+		b.WriteString("<tr>")
+		for _, value := range row {
+			b.WriteString("<td>" + html.EscapeString(value) + "</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</table>\n")
+}
@@ -0,0 +1,47 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type documentationEntity struct {
+	ORM       `orm:"localCache;redisCache"`
+	ID        uint
+	Name      string `orm:"unique=Name;required"`
+	Age       uint8  `orm:"index=Age"`
+	Reference *documentationEntityReference
+	ByAge     *CachedQuery `query:":Age = ?"`
+	ByName    *CachedQuery `queryOne:":Name = ?"`
+}
+
+type documentationEntityReference struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestGenerateEntitiesDocumentation(t *testing.T) {
+	var entity *documentationEntity
+	var reference *documentationEntityReference
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", entity, reference)
+
+	markdown := GenerateEntitiesMarkdown(engine.GetRegistry())
+	assert.Contains(t, markdown, "## beeorm.documentationEntity\n")
+	assert.Contains(t, markdown, "| documentationEntity | default | default | default |\n")
+	assert.Contains(t, markdown, "| Name | varchar(255) NOT NULL DEFAULT '' |\n")
+	assert.Contains(t, markdown, "| Age | tinyint(3) unsigned NOT NULL DEFAULT '0' |\n")
+	assert.Contains(t, markdown, "| Name | true | Name |\n")
+	assert.Contains(t, markdown, "| Age | false | Age |\n")
+	assert.Contains(t, markdown, "| Reference | beeorm.documentationEntityReference | one |\n")
+	assert.Contains(t, markdown, "| ByAge | :Age = ? | false | 50000 | Age |\n")
+	assert.Contains(t, markdown, "| ByName | :Name = ? | true | 1 | Name |\n")
+	assert.Contains(t, markdown, "## beeorm.documentationEntityReference\n")
+
+	html := GenerateEntitiesHTML(engine.GetRegistry())
+	assert.Contains(t, html, "<h2 id=\"beeorm.documentationEntity\">beeorm.documentationEntity</h2>\n")
+	assert.Contains(t, html, "<tr><td>Name</td><td>varchar(255) NOT NULL DEFAULT &#39;&#39;</td></tr>\n")
+	assert.Contains(t, html, "<tr><td>ByAge</td><td>:Age = ?</td><td>false</td><td>50000</td><td>Age</td></tr>\n")
+}
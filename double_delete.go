@@ -0,0 +1,111 @@
+package beeorm
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+func (r *Registry) SetCacheDoubleDeleteDelay(delay time.Duration) {
+	r.cacheDoubleDeleteDelay = delay
+}
+
+// SetCacheDoubleDeleteErrorHandler sets handler of errors in delayed cache deletes, by default they are logged
+func (r *Registry) SetCacheDoubleDeleteErrorHandler(handler func(err error)) {
+	r.cacheDoubleDeleteError = handler
+}
+
+func initDoubleDelete(tableSchema *tableSchema, registry *Registry) error {
+	if !tableSchema.hasLocalCache && !tableSchema.hasRedisCache {
+		return nil
+	}
+	tableSchema.doubleDeleteDelay = registry.cacheDoubleDeleteDelay
+	tag := tableSchema.getTag("doubleDelete", "", "")
+	if tag == "" {
+		return nil
+	}
+	if tag == "false" {
+		tableSchema.doubleDeleteDelay = 0
+		return nil
+	}
+	delay, err := time.ParseDuration(tag)
+	if err != nil || delay < 0 {
+		return fmt.Errorf("invalid double delete delay '%s' in %s", tag, tableSchema.t.String())
+	}
+	tableSchema.doubleDeleteDelay = delay
+	return nil
+}
+
+func (f *flusher) addDoubleDelete(schema *tableSchema, id uint64) {
+	if schema.doubleDeleteDelay <= 0 {
+		return
+	}
+	if f.doubleDeletes == nil {
+		f.doubleDeletes = make(map[time.Duration]*cacheDeletes)
+	}
+	deletes, has := f.doubleDeletes[schema.doubleDeleteDelay]
+	if !has {
+		deletes = &cacheDeletes{}
+		f.doubleDeletes[schema.doubleDeleteDelay] = deletes
+	}
+	deletes.add(f.engine, schema, id)
+}
+
+func (f *flusher) applyDoubleDeletes(transaction bool) {
+	if f.doubleDeletes == nil {
+		return
+	}
+	if transaction {
+		if f.engine.afterCommitDoubleDeletes == nil {
+			f.engine.afterCommitDoubleDeletes = make(map[time.Duration]*cacheDeletes)
+		}
+		for delay, deletes := range f.doubleDeletes {
+			existing, has := f.engine.afterCommitDoubleDeletes[delay]
+			if !has {
+				existing = &cacheDeletes{}
+				f.engine.afterCommitDoubleDeletes[delay] = existing
+			}
+			existing.merge(deletes)
+		}
+	} else {
+		scheduleDoubleDeletes(f.engine, f.doubleDeletes)
+	}
+	f.doubleDeletes = nil
+}
+
+// scheduleDoubleDeletes creates worker engine when timer fires, so engine that scheduled deletes can be released.
+func scheduleDoubleDeletes(engine *engineImplementation, deletes map[time.Duration]*cacheDeletes) {
+	registry := engine.registry
+	loggersRedis := engine.queryLoggersRedis
+	loggersLocalCache := engine.queryLoggersLocalCache
+	for delay, keys := range deletes {
+		delete(keys.local, requestCacheKey)
+		keys := keys
+		time.AfterFunc(delay, func() {
+			worker := registry.CreateEngine().(*engineImplementation)
+			worker.queryLoggersRedis = loggersRedis
+			worker.hasRedisLogger = len(loggersRedis) > 0
+			worker.queryLoggersLocalCache = loggersLocalCache
+			worker.hasLocalCacheLogger = len(loggersLocalCache) > 0
+			defer func() {
+				if rec := recover(); rec != nil {
+					asErr, is := rec.(error)
+					if !is {
+						asErr = fmt.Errorf("%v", rec)
+					}
+					reportDoubleDeleteError(registry, fmt.Errorf("delayed cache delete failed: %w", asErr))
+				}
+				worker.Release()
+			}()
+			keys.execute(worker)
+		})
+	}
+}
+
+func reportDoubleDeleteError(registry *validatedRegistry, err error) {
+	if registry.registry.cacheDoubleDeleteError != nil {
+		registry.registry.cacheDoubleDeleteError(err)
+		return
+	}
+	log.Printf("%s\n", err.Error())
+}
@@ -0,0 +1,108 @@
+package beeorm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type doubleDeleteEntity struct {
+	ORM  `orm:"localCache;redisCache"`
+	ID   uint
+	Name string
+}
+
+type doubleDeleteDisabledEntity struct {
+	ORM  `orm:"redisCache;doubleDelete=false"`
+	ID   uint
+	Name string
+}
+
+func TestCacheDoubleDelete(t *testing.T) {
+	var entity *doubleDeleteEntity
+	var disabled *doubleDeleteDisabledEntity
+	registry := &Registry{}
+	registry.SetCacheDoubleDeleteDelay(50 * time.Millisecond)
+	engine := prepareTables(t, registry, 5, 6, "", entity, disabled)
+	schema := engine.registry.GetTableSchemaForEntity(entity).(*tableSchema)
+	schemaDisabled := engine.registry.GetTableSchemaForEntity(disabled).(*tableSchema)
+	assert.Equal(t, 50*time.Millisecond, schema.doubleDeleteDelay)
+	assert.Equal(t, time.Duration(0), schemaDisabled.doubleDeleteDelay)
+
+	entity = &doubleDeleteEntity{Name: "a"}
+	disabled = &doubleDeleteDisabledEntity{Name: "a"}
+	engine.Flush(entity, disabled)
+	cacheKey := schema.getCacheKey(engine, 1)
+	cacheKeyDisabled := schemaDisabled.getCacheKey(engine, 1)
+
+	entity.Name = "b"
+	disabled.Name = "b"
+	engine.Flush(entity, disabled)
+	engine.GetRedis().Set(cacheKey, "stale", 0)
+	engine.GetLocalCache().Set(cacheKey, "stale")
+	engine.GetRedis().Set(cacheKeyDisabled, "stale", 0)
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, int64(0), engine.GetRedis().Exists(cacheKey))
+	_, has := engine.GetLocalCache().Get(cacheKey)
+	assert.False(t, has)
+	assert.Equal(t, int64(1), engine.GetRedis().Exists(cacheKeyDisabled))
+
+	db := engine.GetMysql()
+	db.Begin()
+	entity.Name = "c"
+	engine.Flush(entity)
+	assert.Len(t, engine.afterCommitDoubleDeletes, 1)
+	db.Commit()
+	assert.Nil(t, engine.afterCommitDoubleDeletes)
+	engine.GetRedis().Set(cacheKey, "stale", 0)
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, int64(0), engine.GetRedis().Exists(cacheKey))
+
+	engine.Delete(entity)
+	engine.GetRedis().Set(cacheKey, "stale", 0)
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, int64(0), engine.GetRedis().Exists(cacheKey))
+}
+
+func TestCacheDoubleDeleteTag(t *testing.T) {
+	registry := &Registry{}
+	registry.SetCacheDoubleDeleteDelay(time.Second)
+	schema := &tableSchema{t: reflect.TypeOf(doubleDeleteEntity{}), hasRedisCache: true,
+		tags: map[string]map[string]string{"ORM": {"doubleDelete": "250ms"}}}
+	assert.NoError(t, initDoubleDelete(schema, registry))
+	assert.Equal(t, 250*time.Millisecond, schema.doubleDeleteDelay)
+
+	schema.tags["ORM"]["doubleDelete"] = "soon"
+	assert.EqualError(t, initDoubleDelete(schema, registry), "invalid double delete delay 'soon' in beeorm.doubleDeleteEntity")
+
+	delete(schema.tags["ORM"], "doubleDelete")
+	assert.NoError(t, initDoubleDelete(schema, registry))
+	assert.Equal(t, time.Second, schema.doubleDeleteDelay)
+
+	schema.hasRedisCache = false
+	schema.doubleDeleteDelay = 0
+	assert.NoError(t, initDoubleDelete(schema, registry))
+	assert.Equal(t, time.Duration(0), schema.doubleDeleteDelay)
+}
+
+func TestCacheDoubleDeleteErrorHandler(t *testing.T) {
+	registry := &Registry{}
+	reported := make(chan error, 1)
+	registry.SetCacheDoubleDeleteErrorHandler(func(err error) {
+		reported <- err
+	})
+	validatedRegistry, err := registry.Validate()
+	assert.NoError(t, err)
+	engine := validatedRegistry.CreateEngine().(*engineImplementation)
+	deletes := &cacheDeletes{local: map[string][]string{"missing": {"key"}}}
+	scheduleDoubleDeletes(engine, map[time.Duration]*cacheDeletes{time.Millisecond: deletes})
+	engine.Release()
+	select {
+	case err := <-reported:
+		assert.Contains(t, err.Error(), "delayed cache delete failed")
+	case <-time.After(time.Second):
+		assert.Fail(t, "error handler not called")
+	}
+}
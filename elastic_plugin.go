@@ -0,0 +1,249 @@
+package beeorm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const ElasticPluginCode = "beeorm/elastic"
+
+type ElasticQuery map[string]interface{}
+
+type ElasticPlugin struct {
+	url    string
+	direct bool
+	client *http.Client
+	fields map[*tableSchema][]string
+	mutex  sync.Mutex
+}
+
+type elasticSearchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			ID string `json:"_id"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+func NewElasticPlugin(url string, direct bool) *ElasticPlugin {
+	return &ElasticPlugin{url: strings.TrimRight(url, "/"), direct: direct, client: &http.Client{Timeout: time.Second * 30},
+		fields: make(map[*tableSchema][]string)}
+}
+
+func (p *ElasticPlugin) GetCode() string {
+	return ElasticPluginCode
+}
+
+func (p *ElasticPlugin) SetHTTPClient(client *http.Client) {
+	p.client = client
+}
+
+func (p *ElasticPlugin) PluginInterfaceEntityFlushed(engine Engine, event *EntityFlushedEvent) {
+	schema := event.TableSchema.(*tableSchema)
+	if !p.direct || event.ID == 0 || len(p.getFields(schema)) == 0 {
+		return
+	}
+	checkError(p.sync(engine, schema, []uint64{event.ID}))
+}
+
+func (p *ElasticPlugin) IndexerHandler() IndexerHandler {
+	return func(engine Engine, schema TableSchema, ids []uint64) error {
+		return p.sync(engine, schema.(*tableSchema), ids)
+	}
+}
+
+func (p *ElasticPlugin) CreateIndex(engine Engine, entity Entity) {
+	schema := initIfNeeded(engine.(*engineImplementation).registry, entity).tableSchema
+	properties := make(map[string]interface{})
+	for _, field := range p.getFields(schema) {
+		properties[field] = map[string]string{"type": getElasticFieldType(schema, field)}
+	}
+	body, err := jsoniter.ConfigFastest.Marshal(map[string]interface{}{"mappings": map[string]interface{}{"properties": properties}})
+	checkError(err)
+	_, err = p.request(http.MethodPut, "/"+getElasticIndexName(schema), "application/json", body)
+	checkError(err)
+}
+
+func (p *ElasticPlugin) Search(engine Engine, entity Entity, query ElasticQuery, pager *Pager) (ids []uint64, total int) {
+	schema := initIfNeeded(engine.(*engineImplementation).registry, entity).tableSchema
+	if pager == nil {
+		pager = NewPager(1, 100)
+	}
+	request := map[string]interface{}{"from": (pager.CurrentPage - 1) * pager.PageSize, "size": pager.PageSize, "_source": false,
+		"track_total_hits": true}
+	if query != nil {
+		request["query"] = query
+	}
+	body, err := jsoniter.ConfigFastest.Marshal(request)
+	checkError(err)
+	response, err := p.request(http.MethodPost, "/"+getElasticIndexName(schema)+"/_search", "application/json", body)
+	checkError(err)
+	result := &elasticSearchResponse{}
+	checkError(jsoniter.ConfigFastest.Unmarshal(response, result))
+	ids = make([]uint64, len(result.Hits.Hits))
+	for i, hit := range result.Hits.Hits {
+		ids[i], err = strconv.ParseUint(hit.ID, 10, 64)
+		checkError(err)
+	}
+	return ids, result.Hits.Total.Value
+}
+
+func (p *ElasticPlugin) SearchEntities(engine Engine, query ElasticQuery, pager *Pager, entities interface{}, references ...string) int {
+	entityType, _, _ := getEntityTypeForSlice(engine.(*engineImplementation).registry, reflect.TypeOf(entities), true)
+	schema := getTableSchema(engine.(*engineImplementation).registry, entityType)
+	ids, total := p.Search(engine, schema.NewEntity(), query, pager)
+	engine.LoadByIDs(ids, entities, references...)
+	return total
+}
+
+func (p *ElasticPlugin) sync(engine Engine, schema *tableSchema, ids []uint64) error {
+	fields := p.getFields(schema)
+	if len(fields) == 0 {
+		return nil
+	}
+	index := getElasticIndexName(schema)
+	buffer := &bytes.Buffer{}
+	for _, id := range ids {
+		entity := schema.NewEntity()
+		meta := map[string]map[string]string{"index": {"_index": index, "_id": strconv.FormatUint(id, 10)}}
+		var document map[string]interface{}
+		if engine.LoadByID(id, entity) {
+			document = getElasticDocument(entity, fields)
+		} else {
+			meta = map[string]map[string]string{"delete": meta["index"]}
+		}
+		line, err := jsoniter.ConfigFastest.Marshal(meta)
+		if err != nil {
+			return err
+		}
+		buffer.Write(append(line, '\n'))
+		if document != nil {
+			line, err = jsoniter.ConfigFastest.Marshal(document)
+			if err != nil {
+				return err
+			}
+			buffer.Write(append(line, '\n'))
+		}
+	}
+	response, err := p.request(http.MethodPost, "/_bulk", "application/x-ndjson", buffer.Bytes())
+	if err != nil {
+		return err
+	}
+	result := struct {
+		Errors bool `json:"errors"`
+	}{}
+	err = jsoniter.ConfigFastest.Unmarshal(response, &result)
+	if err != nil {
+		return err
+	}
+	if result.Errors {
+		return fmt.Errorf("elasticsearch bulk request for %s failed: %s", index, string(response))
+	}
+	return nil
+}
+
+func (p *ElasticPlugin) request(method, path, contentType string, body []byte) ([]byte, error) {
+	request, err := http.NewRequest(method, p.url+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", contentType)
+	response, err := p.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode >= 300 {
+		return nil, fmt.Errorf("elasticsearch request %s %s failed with status %d: %s", method, path, response.StatusCode, string(responseBody))
+	}
+	return responseBody, nil
+}
+
+func (p *ElasticPlugin) getFields(schema *tableSchema) []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	fields, has := p.fields[schema]
+	if has {
+		return fields
+	}
+	fields = make([]string, 0)
+	for _, column := range schema.columnNames {
+		if _, has := schema.t.FieldByName(column); has && schema.tags[column]["elastic"] == "true" {
+			fields = append(fields, column)
+		}
+	}
+	p.fields[schema] = fields
+	return fields
+}
+
+func getElasticIndexName(schema *tableSchema) string {
+	return strings.ToLower(schema.tableName)
+}
+
+func getElasticDocument(entity Entity, fields []string) map[string]interface{} {
+	elem := entity.getORM().elem
+	document := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		value := elem.FieldByName(field)
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				document[field] = nil
+				continue
+			}
+			if asEntity, is := value.Interface().(Entity); is {
+				document[field] = asEntity.GetID()
+				continue
+			}
+			value = value.Elem()
+		}
+		if value.Type() == timeType {
+			document[field] = value.Interface().(time.Time).Format(time.RFC3339)
+			continue
+		}
+		document[field] = value.Interface()
+	}
+	return document
+}
+
+func getElasticFieldType(schema *tableSchema, field string) string {
+	structField, _ := schema.t.FieldByName(field)
+	t := structField.Type
+	if t.Kind() == reflect.Ptr {
+		if t.Implements(entityInterfaceType) {
+			return "long"
+		}
+		t = t.Elem()
+	}
+	if t == timeType {
+		return "date"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "long"
+	case reflect.Float32, reflect.Float64:
+		return "double"
+	}
+	if schema.tags[field]["enum"] != "" {
+		return "keyword"
+	}
+	return "text"
+}
@@ -0,0 +1,107 @@
+package beeorm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type elasticEntity struct {
+	ORM
+	ID     uint
+	Name   string `orm:"elastic"`
+	Age    int    `orm:"elastic"`
+	Secret string
+}
+
+type elasticRequest struct {
+	method string
+	path   string
+	body   string
+}
+
+func newElasticTestServer() (*httptest.Server, *[]elasticRequest) {
+	requests := make([]elasticRequest, 0)
+	mutex := sync.Mutex{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mutex.Lock()
+		requests = append(requests, elasticRequest{method: r.Method, path: r.URL.Path, body: string(body)})
+		mutex.Unlock()
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_search"):
+			_, _ = w.Write([]byte(`{"hits":{"total":{"value":7},"hits":[{"_id":"2"},{"_id":"1"}]}}`))
+		case r.URL.Path == "/_bulk":
+			_, _ = w.Write([]byte(`{"errors":false}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	return server, &requests
+}
+
+func TestElasticPluginDirect(t *testing.T) {
+	server, requests := newElasticTestServer()
+	defer server.Close()
+	plugin := NewElasticPlugin(server.URL+"/", true)
+	var entity *elasticEntity
+	registry := &Registry{}
+	registry.RegisterPlugin(plugin)
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+
+	plugin.CreateIndex(engine, entity)
+	assert.Equal(t, "/elasticentity", (*requests)[0].path)
+	assert.Equal(t, http.MethodPut, (*requests)[0].method)
+	assert.Contains(t, (*requests)[0].body, `"Age":{"type":"long"}`)
+	assert.Contains(t, (*requests)[0].body, `"Name":{"type":"text"}`)
+
+	e := &elasticEntity{Name: "a", Age: 10, Secret: "hidden"}
+	engine.Flush(e)
+	assert.Len(t, *requests, 2)
+	assert.Equal(t, "/_bulk", (*requests)[1].path)
+	assert.Contains(t, (*requests)[1].body, `"_id":"1"`)
+	assert.Contains(t, (*requests)[1].body, `"Name":"a"`)
+	assert.NotContains(t, (*requests)[1].body, "hidden")
+
+	engine.Flush(&elasticEntity{Name: "b"})
+	engine.Delete(e)
+	assert.Len(t, *requests, 4)
+	assert.Contains(t, (*requests)[3].body, `{"delete":{`)
+	assert.Contains(t, (*requests)[3].body, `"_id":"1"`)
+
+	ids, total := plugin.Search(engine, entity, ElasticQuery{"match": map[string]interface{}{"Name": "a"}}, NewPager(2, 10))
+	assert.Equal(t, []uint64{2, 1}, ids)
+	assert.Equal(t, 7, total)
+	assert.Contains(t, (*requests)[4].body, `"from":10`)
+	var rows []*elasticEntity
+	assert.Equal(t, 7, plugin.SearchEntities(engine, nil, nil, &rows))
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "b", rows[0].Name)
+}
+
+func TestElasticPluginIndexer(t *testing.T) {
+	server, requests := newElasticTestServer()
+	defer server.Close()
+	plugin := NewElasticPlugin(server.URL, false)
+	var entity *elasticEntity
+	registry := &Registry{}
+	registry.RegisterPlugin(plugin)
+	registry.RegisterIndexer("elastic", "default", plugin.IndexerHandler(), entity)
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+
+	engine.Flush(&elasticEntity{Name: "a"}, &elasticEntity{Name: "b"})
+	assert.Len(t, *requests, 0)
+	consumer := NewIndexerConsumer(engine, "elastic")
+	consumer.DisableBlockMode()
+	consumer.blockTime = time.Millisecond
+	consumer.Digest(context.Background())
+	assert.Len(t, *requests, 1)
+	assert.Equal(t, 4, strings.Count((*requests)[0].body, "\n"))
+}
@@ -1,18 +1,53 @@
 package beeorm
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"reflect"
 	"sync"
+	"time"
 )
 
 type Engine interface {
 	Clone() Engine
 	EnableRequestCache()
 	SetQueryTimeLimit(seconds int)
+	SetQueryResultLimit(rows int, handler ...QueryResultLimitHandler)
+	SetCachedQueryCardinalityLimit(limit int, handler CachedQueryCardinalityHandler)
+	EnablePagerEnforcement()
+	EnableOwnershipEnforcement(owner string)
+	EnableFlushDeduplication()
+	EnqueueJob(queue string, payload interface{}, options ...*JobOptions) *JobEntity
+	CancelJob(job *JobEntity)
+	GetJobQueueStatistics(queue string) map[string]int
+	DisableFlushDeduplication()
+	EnableIdentityMap()
+	DisableIdentityMap()
+	ClearIdentityMap()
+	EnableReferenceBatching(window time.Duration)
+	DisableReferenceBatching()
+	ClearReferenceBatch()
+	EnableUnitOfWork()
+	DisableUnitOfWork()
+	Track(entity ...Entity)
+	Commit()
+	SetWriteFreeze(until time.Time, mode WriteFreezeMode)
+	GetWriteFreeze() (until time.Time, mode WriteFreezeMode, active bool)
+	SetClock(clock Clock)
+	GetClock() Clock
+	SetCacheKeyDimension(name, value string)
+	GetCacheKeyDimension(name string) string
+	SetLocale(locale string)
+	GetLocale() string
+	OnClose(handler EngineCloseHandler)
+	Close()
+	IsClosed() bool
+	Release()
 	GetMysql(code ...string) *DB
 	GetLocalCache(code ...string) *LocalCache
 	GetRedis(code ...string) *RedisCache
+	GetRateLimiter(code ...string) *RateLimiter
 	SetLogMetaData(key string, value interface{})
 	NewFlusher() Flusher
 	Flush(entity ...Entity)
@@ -22,6 +57,8 @@ type Engine interface {
 	Delete(entity ...Entity)
 	DeleteLazy(entity ...Entity)
 	ForceDelete(entity ...Entity)
+	Restore(entity ...Entity)
+	PurgeSoftDeleted(entity Entity, retention time.Duration) int
 	GetRegistry() ValidatedRegistry
 	SearchWithCount(where *Where, pager *Pager, entities interface{}, references ...string) (totalRows int)
 	Search(where *Where, pager *Pager, entities interface{}, references ...string)
@@ -33,12 +70,39 @@ type Engine interface {
 	CachedSearch(entities interface{}, indexName string, pager *Pager, arguments ...interface{}) (totalRows int)
 	CachedSearchIDs(entity Entity, indexName string, pager *Pager, arguments ...interface{}) (totalRows int, ids []uint64)
 	CachedSearchCount(entity Entity, indexName string, arguments ...interface{}) int
+	GetCachedQueryCardinality(entity Entity, indexName string) int
 	CachedSearchWithReferences(entities interface{}, indexName string, pager *Pager, arguments []interface{}, references []string) (totalRows int)
 	ClearCacheByIDs(entity Entity, ids ...uint64)
+	BumpCacheVersion(entity Entity)
+	ClearCachedQuery(entity Entity, indexName string)
+	CachedQueryRaw(key string, ttl time.Duration, tags []string, query string, args []interface{}, dest interface{})
+	InvalidateCacheTags(tags ...string)
+	SetRefreshAhead(threshold float64)
+	GetWebhookDeliveries(webhook string, pager *Pager) []*WebhookDeliveryEntity
+	VerifyEntityCache(entity Entity, repair bool, ids ...uint64) *EntityCacheReport
+	RewriteReferences(entity Entity, fromID, toID uint64) int
+	AnonymizeEntity(entity ...Entity)
+	AnonymizeSubject(entity Entity, subjectID uint64) int
+	CloneAnonymized(target Engine, plan AnonymizedClonePlan) map[string]int
+	GetTreeAncestors(entity Entity, entities interface{})
+	GetTreeDescendants(entity Entity, entities interface{}, pager *Pager)
+	MoveTreeNode(entity Entity, parent Entity)
+	AttachFile(entity Entity, field, name, contentType string, content io.Reader) *File
+	OpenFile(file *File) io.ReadCloser
+	SignFileURL(file *File, expires time.Duration) string
 	LoadByID(id uint64, entity Entity, references ...string) (found bool)
 	Load(entity Entity, references ...string) (found bool)
 	LoadByIDs(ids []uint64, entities interface{}, references ...string) (found bool)
+	LoadByUniqueKey(entity Entity, indexName string, values ...interface{}) (found bool)
+	LoadByIDAsOf(id uint64, asOf time.Time, entity Entity, references ...string) (found bool)
+	WarmUp(plan WarmUpPlan)
+	WarmUpCachedQuery(entity Entity, indexName string, arguments ...interface{}) int
+	WarmUpAllCachedQueries(entity Entity) int
 	GetAlters() (alters []Alter)
+	GetAltersPlan() *AltersPlan
+	CheckSchemaDrift() *SchemaDriftReport
+	ExportEntities(entity Entity, where *Where, writer io.Writer, format ExportFormat) (rows int, err error)
+	ImportEntities(entity Entity, reader io.Reader, format ExportFormat, options *ImportOptions) (rows int, err error)
 	GetEventBroker() EventBroker
 	RegisterQueryLogger(handler LogHandler, mysql, redis, local bool)
 	EnableQueryDebug()
@@ -60,24 +124,72 @@ type engineImplementation struct {
 	hasLocalCacheLogger       bool
 	afterCommitLocalCacheSets map[string][]interface{}
 	afterCommitRedisFlusher   *redisFlusher
+	beforeCommitCacheDeletes  *cacheDeletes
+	afterCommitDoubleDeletes  map[time.Duration]*cacheDeletes
+	afterCommitFingerprints   map[Entity]string
+	flushReport               *FlushReport
 	eventBroker               *eventBroker
 	queryTimeLimit            uint16
+	queryResultLimit          int
+	queryResultLimitHandler   QueryResultLimitHandler
+	cardinalityLimit          int
+	cardinalityHandler        CachedQueryCardinalityHandler
+	pagerRequired             bool
+	owner                     string
+	queryMasks                []string
+	cacheKeyDimensions        map[string]string
+	refreshAhead              float64
+	locale                    string
+	closeHandlers             []EngineCloseHandler
+	closeContext              context.Context
+	closeCancel               context.CancelFunc
+	closed                    bool
+	flushDeduplication        bool
+	flushedFingerprints       map[Entity]string
+	identityMap               map[*tableSchema]map[uint64]Entity
+	referenceBatch            map[*tableSchema]map[string]*referenceBatchEntry
+	referenceBatchWindow      time.Duration
+	unitOfWork                bool
+	unitOfWorkEntities        []Entity
+	unitOfWorkTracked         map[Entity]bool
+	clock                     Clock
 	sync.Mutex
 }
 
+type QueryResultLimitHandler func(engine Engine, schema TableSchema, rows int)
+
+type CachedQueryCardinalityHandler func(engine Engine, schema TableSchema, indexName string, cardinality int)
+
 func (e *engineImplementation) Clone() Engine {
-	return &engineImplementation{
-		registry:               e.registry,
-		queryTimeLimit:         e.queryTimeLimit,
-		logMetaData:            e.logMetaData,
-		hasRequestCache:        e.hasRequestCache,
-		queryLoggersDB:         e.queryLoggersDB,
-		queryLoggersRedis:      e.queryLoggersRedis,
-		queryLoggersLocalCache: e.queryLoggersLocalCache,
-		hasRedisLogger:         e.hasRedisLogger,
-		hasDBLogger:            e.hasDBLogger,
-		hasLocalCacheLogger:    e.hasLocalCacheLogger,
+	clone := getPooledEngine()
+	clone.registry = e.registry
+	clone.queryTimeLimit = e.queryTimeLimit
+	clone.queryResultLimit = e.queryResultLimit
+	clone.queryResultLimitHandler = e.queryResultLimitHandler
+	clone.cardinalityLimit = e.cardinalityLimit
+	clone.cardinalityHandler = e.cardinalityHandler
+	clone.pagerRequired = e.pagerRequired
+	clone.owner = e.owner
+	clone.cacheKeyDimensions = e.cacheKeyDimensions
+	clone.locale = e.locale
+	clone.logMetaData = e.logMetaData
+	clone.hasRequestCache = e.hasRequestCache
+	clone.queryLoggersDB = e.queryLoggersDB
+	clone.queryLoggersRedis = e.queryLoggersRedis
+	clone.queryLoggersLocalCache = e.queryLoggersLocalCache
+	clone.hasRedisLogger = e.hasRedisLogger
+	clone.hasDBLogger = e.hasDBLogger
+	clone.hasLocalCacheLogger = e.hasLocalCacheLogger
+	clone.flushDeduplication = e.flushDeduplication
+	clone.clock = e.clock
+	clone.refreshAhead = e.refreshAhead
+	if len(e.queryMasks) > 0 {
+		clone.queryMasks = append([]string(nil), e.queryMasks...)
+	}
+	if e.referenceBatchWindow > 0 {
+		clone.EnableReferenceBatching(e.referenceBatchWindow)
 	}
+	return clone
 }
 
 func (e *engineImplementation) EnableRequestCache() {
@@ -88,6 +200,47 @@ func (e *engineImplementation) SetQueryTimeLimit(seconds int) {
 	e.queryTimeLimit = uint16(seconds)
 }
 
+func (e *engineImplementation) SetQueryResultLimit(rows int, handler ...QueryResultLimitHandler) {
+	e.queryResultLimit = rows
+	e.queryResultLimitHandler = nil
+	if len(handler) > 0 {
+		e.queryResultLimitHandler = handler[0]
+	}
+}
+
+func (e *engineImplementation) SetCachedQueryCardinalityLimit(limit int, handler CachedQueryCardinalityHandler) {
+	e.cardinalityLimit = limit
+	e.cardinalityHandler = handler
+}
+
+func (e *engineImplementation) EnablePagerEnforcement() {
+	e.pagerRequired = true
+}
+
+func (e *engineImplementation) EnableOwnershipEnforcement(owner string) {
+	e.owner = owner
+}
+
+func (e *engineImplementation) SetWriteFreeze(until time.Time, mode WriteFreezeMode) {
+	e.registry.writeFreeze.set(until, mode)
+}
+
+func (e *engineImplementation) GetWriteFreeze() (until time.Time, mode WriteFreezeMode, active bool) {
+	return e.registry.writeFreeze.get()
+}
+
+func (e *engineImplementation) checkQueryResultLimit(schema *tableSchema, rows int, done bool) {
+	if rows <= e.queryResultLimit {
+		return
+	}
+	if e.queryResultLimitHandler == nil {
+		panic(fmt.Errorf("query result for '%s' exceeded limit of %d rows", schema.t.String(), e.queryResultLimit))
+	}
+	if done {
+		e.queryResultLimitHandler(e, schema, rows)
+	}
+}
+
 func (e *engineImplementation) GetMysql(code ...string) *DB {
 	dbCode := "default"
 	if len(code) > 0 {
@@ -111,6 +264,13 @@ func (e *engineImplementation) GetMysql(code ...string) *DB {
 	return db
 }
 
+func (e *engineImplementation) inTransaction(code string) bool {
+	e.Mutex.Lock()
+	defer e.Mutex.Unlock()
+	db, has := e.dbs[code]
+	return has && db.inTransaction
+}
+
 func (e *engineImplementation) GetLocalCache(code ...string) *LocalCache {
 	dbCode := "default"
 	if len(code) > 0 {
@@ -176,23 +336,35 @@ func (e *engineImplementation) SetLogMetaData(key string, value interface{}) {
 }
 
 func (e *engineImplementation) NewFlusher() Flusher {
-	return &flusher{engine: e}
+	f := flusherPool.Get().(*flusher)
+	f.engine = e
+	return f
 }
 
 func (e *engineImplementation) Flush(entity ...Entity) {
-	e.NewFlusher().Track(entity...).Flush()
+	f := e.NewFlusher()
+	f.Track(entity...).Flush()
+	f.Release()
 }
 
 func (e *engineImplementation) FlushLazy(entity ...Entity) {
-	e.NewFlusher().Track(entity...).FlushLazy()
+	f := e.NewFlusher()
+	f.Track(entity...).FlushLazy()
+	f.Release()
 }
 
 func (e *engineImplementation) FlushWithCheck(entity ...Entity) error {
-	return e.NewFlusher().Track(entity...).FlushWithCheck()
+	f := e.NewFlusher()
+	err := f.Track(entity...).FlushWithCheck()
+	f.Release()
+	return err
 }
 
 func (e *engineImplementation) FlushWithFullCheck(entity ...Entity) error {
-	return e.NewFlusher().Track(entity...).FlushWithFullCheck()
+	f := e.NewFlusher()
+	err := f.Track(entity...).FlushWithFullCheck()
+	f.Release()
+	return err
 }
 
 func (e *engineImplementation) Delete(entity ...Entity) {
@@ -216,16 +388,31 @@ func (e *engineImplementation) ForceDelete(entity ...Entity) {
 	e.Flush(entity...)
 }
 
+func (e *engineImplementation) Restore(entity ...Entity) {
+	restoreDeleted(e, entity...)
+}
+
+func (e *engineImplementation) PurgeSoftDeleted(entity Entity, retention time.Duration) int {
+	return purgeSoftDeleted(e, entity, retention)
+}
+
 func (e *engineImplementation) GetRegistry() ValidatedRegistry {
 	return e.registry
 }
 
 func (e *engineImplementation) SearchWithCount(where *Where, pager *Pager, entities interface{}, references ...string) (totalRows int) {
-	return search(newSerializer(nil), e, where, pager, true, true, reflect.ValueOf(entities).Elem(), references...)
+	serializer := getSerializer()
+	defer putSerializer(serializer)
+	totalRows = search(serializer, e, where, pager, true, true, reflect.ValueOf(entities).Elem(), references...)
+	e.translate(entities)
+	return totalRows
 }
 
 func (e *engineImplementation) Search(where *Where, pager *Pager, entities interface{}, references ...string) {
-	search(newSerializer(nil), e, where, pager, false, true, reflect.ValueOf(entities).Elem(), references...)
+	serializer := getSerializer()
+	defer putSerializer(serializer)
+	search(serializer, e, where, pager, false, true, reflect.ValueOf(entities).Elem(), references...)
+	e.translate(entities)
 }
 
 func (e *engineImplementation) SearchIDsWithCount(where *Where, pager *Pager, entity Entity) (results []uint64, totalRows int) {
@@ -238,56 +425,163 @@ func (e *engineImplementation) SearchIDs(where *Where, pager *Pager, entity Enti
 }
 
 func (e *engineImplementation) SearchOne(where *Where, entity Entity, references ...string) (found bool) {
-	found, _, _ = searchOne(newSerializer(nil), e, where, entity, references)
+	serializer := getSerializer()
+	defer putSerializer(serializer)
+	found, _, _ = searchOne(serializer, e, where, entity, references)
+	if found {
+		e.translate(entity)
+	}
 	return found
 }
 
 func (e *engineImplementation) CachedSearchOne(entity Entity, indexName string, arguments ...interface{}) (found bool) {
-	return cachedSearchOne(newSerializer(nil), e, entity, indexName, true, arguments, nil)
+	serializer := getSerializer()
+	defer putSerializer(serializer)
+	found = cachedSearchOne(serializer, e, entity, indexName, true, arguments, nil)
+	if found {
+		e.translate(entity)
+	}
+	return found
 }
 
 func (e *engineImplementation) CachedSearchOneWithReferences(entity Entity, indexName string, arguments []interface{}, references []string) (found bool) {
-	return cachedSearchOne(newSerializer(nil), e, entity, indexName, true, arguments, references)
+	serializer := getSerializer()
+	defer putSerializer(serializer)
+	found = cachedSearchOne(serializer, e, entity, indexName, true, arguments, references)
+	if found {
+		e.translate(entity)
+	}
+	return found
 }
 
 func (e *engineImplementation) CachedSearch(entities interface{}, indexName string, pager *Pager, arguments ...interface{}) (totalRows int) {
-	total, _ := cachedSearch(newSerializer(nil), e, entities, indexName, pager, arguments, true, nil)
+	serializer := getSerializer()
+	defer putSerializer(serializer)
+	total, _ := cachedSearch(serializer, e, entities, indexName, pager, arguments, true, nil)
+	e.translate(entities)
 	return total
 }
 
 func (e *engineImplementation) CachedSearchIDs(entity Entity, indexName string, pager *Pager, arguments ...interface{}) (totalRows int, ids []uint64) {
-	return cachedSearch(newSerializer(nil), e, entity, indexName, pager, arguments, false, nil)
+	serializer := getSerializer()
+	defer putSerializer(serializer)
+	return cachedSearch(serializer, e, entity, indexName, pager, arguments, false, nil)
 }
 
 func (e *engineImplementation) CachedSearchCount(entity Entity, indexName string, arguments ...interface{}) int {
-	total, _ := cachedSearch(newSerializer(nil), e, entity, indexName, NewPager(1, 1), arguments, false, nil)
+	serializer := getSerializer()
+	defer putSerializer(serializer)
+	total, _ := cachedSearch(serializer, e, entity, indexName, NewPager(1, 1), arguments, false, nil)
 	return total
 }
 
 func (e *engineImplementation) CachedSearchWithReferences(entities interface{}, indexName string, pager *Pager,
 	arguments []interface{}, references []string) (totalRows int) {
-	total, _ := cachedSearch(newSerializer(nil), e, entities, indexName, pager, arguments, true, references)
+	serializer := getSerializer()
+	defer putSerializer(serializer)
+	total, _ := cachedSearch(serializer, e, entities, indexName, pager, arguments, true, references)
+	e.translate(entities)
 	return total
 }
 
+func (e *engineImplementation) GetCachedQueryCardinality(entity Entity, indexName string) int {
+	return getCachedQueryCardinality(e, entity, indexName)
+}
+
+func (e *engineImplementation) VerifyEntityCache(entity Entity, repair bool, ids ...uint64) *EntityCacheReport {
+	return verifyEntityCache(e, entity, repair, ids...)
+}
+
+func (e *engineImplementation) RewriteReferences(entity Entity, fromID, toID uint64) int {
+	return rewriteReferences(e, entity, fromID, toID)
+}
+
+func (e *engineImplementation) AnonymizeEntity(entity ...Entity) {
+	anonymizeEntities(e, entity...)
+}
+
+func (e *engineImplementation) AnonymizeSubject(entity Entity, subjectID uint64) int {
+	return anonymizeSubject(e, entity, subjectID)
+}
+
+func (e *engineImplementation) CloneAnonymized(target Engine, plan AnonymizedClonePlan) map[string]int {
+	return cloneAnonymized(e, target, plan)
+}
+
+func (e *engineImplementation) GetTreeAncestors(entity Entity, entities interface{}) {
+	getTreeAncestors(e, entity, entities)
+}
+
+func (e *engineImplementation) GetTreeDescendants(entity Entity, entities interface{}, pager *Pager) {
+	getTreeDescendants(e, entity, entities, pager)
+}
+
+func (e *engineImplementation) MoveTreeNode(entity Entity, parent Entity) {
+	moveTreeNode(e, entity, parent)
+}
+
+func (e *engineImplementation) AttachFile(entity Entity, field, name, contentType string, content io.Reader) *File {
+	return attachFile(e, entity, field, name, contentType, content)
+}
+
+func (e *engineImplementation) OpenFile(file *File) io.ReadCloser {
+	return openFile(e, file)
+}
+
+func (e *engineImplementation) SignFileURL(file *File, expires time.Duration) string {
+	return signFileURL(e, file, expires)
+}
+
 func (e *engineImplementation) ClearCacheByIDs(entity Entity, ids ...uint64) {
 	clearByIDs(e, entity, ids...)
 }
 
 func (e *engineImplementation) LoadByID(id uint64, entity Entity, references ...string) (found bool) {
-	found, _ = loadByID(newSerializer(nil), e, id, entity, true, references...)
+	serializer := getSerializer()
+	defer putSerializer(serializer)
+	found, _ = loadByID(serializer, e, id, entity, true, references...)
+	if found {
+		e.translate(entity)
+	}
 	return found
 }
 
 func (e *engineImplementation) Load(entity Entity, references ...string) (found bool) {
-	return e.load(newSerializer(nil), entity, references...)
+	serializer := getSerializer()
+	defer putSerializer(serializer)
+	found = e.load(serializer, entity, references...)
+	if found {
+		e.translate(entity)
+	}
+	return found
+}
+
+func (e *engineImplementation) LoadByIDAsOf(id uint64, asOf time.Time, entity Entity, references ...string) (found bool) {
+	serializer := getSerializer()
+	defer putSerializer(serializer)
+	return loadByIDAsOf(serializer, e, id, asOf, entity, references...)
 }
 
 func (e *engineImplementation) LoadByIDs(ids []uint64, entities interface{}, references ...string) (found bool) {
-	_, hasMissing := tryByIDs(newSerializer(nil), e, ids, reflect.ValueOf(entities).Elem(), references)
+	serializer := getSerializer()
+	defer putSerializer(serializer)
+	_, hasMissing := tryByIDs(serializer, e, ids, reflect.ValueOf(entities).Elem(), references)
+	e.translate(entities)
 	return !hasMissing
 }
 
+func (e *engineImplementation) WarmUp(plan WarmUpPlan) {
+	warmUp(e, plan)
+}
+
+func (e *engineImplementation) WarmUpCachedQuery(entity Entity, indexName string, arguments ...interface{}) int {
+	return warmUpCachedQuery(e, entity, indexName, arguments...)
+}
+
+func (e *engineImplementation) WarmUpAllCachedQueries(entity Entity) int {
+	return warmUpAllCachedQueries(e, entity)
+}
+
 func (e *engineImplementation) GetAlters() (alters []Alter) {
 	return getAlters(e)
 }
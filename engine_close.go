@@ -0,0 +1,83 @@
+package beeorm
+
+import (
+	"context"
+)
+
+type EngineCloseHandler func(engine Engine)
+
+type FlushableLogHandler interface {
+	LogHandler
+	Flush()
+}
+
+func (e *engineImplementation) OnClose(handler EngineCloseHandler) {
+	e.Mutex.Lock()
+	defer e.Mutex.Unlock()
+	e.closeHandlers = append(e.closeHandlers, handler)
+}
+
+func (e *engineImplementation) Close() {
+	e.Mutex.Lock()
+	if e.closed {
+		e.Mutex.Unlock()
+		return
+	}
+	e.closed = true
+	handlers := e.closeHandlers
+	e.closeHandlers = nil
+	if e.closeCancel != nil {
+		e.closeCancel()
+	}
+	delete(e.localCache, requestCacheKey)
+	e.hasRequestCache = false
+	e.identityMap = nil
+	e.referenceBatch = nil
+	e.unitOfWorkEntities = nil
+	e.unitOfWorkTracked = nil
+	e.afterCommitLocalCacheSets = nil
+	e.afterCommitRedisFlusher = nil
+	e.beforeCommitCacheDeletes = nil
+	e.afterCommitDoubleDeletes = nil
+	e.Mutex.Unlock()
+	for i := len(handlers) - 1; i >= 0; i-- {
+		handlers[i](e)
+	}
+	flushed := make(map[LogHandler]bool)
+	for _, loggers := range [][]LogHandler{e.queryLoggersDB, e.queryLoggersRedis, e.queryLoggersLocalCache} {
+		for _, logger := range loggers {
+			flushable, is := logger.(FlushableLogHandler)
+			if is && !flushed[logger] {
+				flushed[logger] = true
+				flushable.Flush()
+			}
+		}
+	}
+}
+
+func (e *engineImplementation) IsClosed() bool {
+	e.Mutex.Lock()
+	defer e.Mutex.Unlock()
+	return e.closed
+}
+
+func (e *engineImplementation) withCloseContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	e.Mutex.Lock()
+	defer e.Mutex.Unlock()
+	if e.closeContext == nil {
+		e.closeContext, e.closeCancel = context.WithCancel(context.Background())
+		if e.closed {
+			e.closeCancel()
+		}
+	}
+	closeContext := e.closeContext
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-closeContext.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
@@ -0,0 +1,74 @@
+package beeorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type engineCloseEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+type flushableTestLogHandler struct {
+	testLogHandler
+	flushed int
+}
+
+func (h *flushableTestLogHandler) Flush() {
+	h.flushed++
+}
+
+func TestEngineClose(t *testing.T) {
+	var entity *engineCloseEntity
+	registry := &Registry{}
+	registry.RegisterRedisStream("test-close-stream", "default", []string{"test-close-group"})
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	engine.GetRedis().FlushDB()
+	engine.EnableRequestCache()
+	engine.Flush(&engineCloseEntity{Name: "a"})
+	assert.True(t, engine.LoadByID(1, &engineCloseEntity{}))
+	_, has := engine.localCache[requestCacheKey]
+	assert.True(t, has)
+
+	logger := &flushableTestLogHandler{}
+	engine.RegisterQueryLogger(logger, true, true, false)
+	calls := make([]int, 0)
+	engine.OnClose(func(closed Engine) {
+		assert.Equal(t, engine, closed)
+		calls = append(calls, 1)
+	})
+	engine.OnClose(func(_ Engine) {
+		calls = append(calls, 2)
+	})
+
+	consumer := engine.GetEventBroker().Consumer("test-close-group")
+	consumer.SetBlockTime(time.Second * 30)
+	done := make(chan bool)
+	go func() {
+		done <- consumer.Consume(context.Background(), 10, func(events []Event) {})
+	}()
+	time.Sleep(time.Millisecond * 100)
+
+	assert.False(t, engine.IsClosed())
+	engine.Close()
+	assert.True(t, engine.IsClosed())
+	assert.Equal(t, []int{2, 1}, calls)
+	assert.Equal(t, 1, logger.flushed)
+	_, has = engine.localCache[requestCacheKey]
+	assert.False(t, has)
+	select {
+	case finished := <-done:
+		assert.True(t, finished)
+	case <-time.After(time.Second * 5):
+		assert.Fail(t, "consumer not stopped")
+	}
+
+	engine.Close()
+	assert.Equal(t, []int{2, 1}, calls)
+	assert.Equal(t, 1, logger.flushed)
+}
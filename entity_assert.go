@@ -0,0 +1,85 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+func AssertEntityEqual(t TestingT, expected, actual Entity, ignoreColumns ...string) bool {
+	t.Helper()
+	diff, err := diffEntities(expected, actual, ignoreColumns...)
+	if err != nil {
+		t.Errorf("%s", err.Error())
+		return false
+	}
+	if len(diff) > 0 {
+		t.Errorf("entities are not equal:\n%s", strings.Join(diff, "\n"))
+		return false
+	}
+	return true
+}
+
+func diffEntities(expected, actual Entity, ignoreColumns ...string) ([]string, error) {
+	if expected == nil || actual == nil {
+		if expected == nil && actual == nil {
+			return nil, nil
+		}
+		return []string{fmt.Sprintf("expected %v, got %v", expected, actual)}, nil
+	}
+	schema := actual.getORM().tableSchema
+	if schema == nil {
+		schema = expected.getORM().tableSchema
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("entity '%T' is not initialised", actual)
+	}
+	if reflect.TypeOf(expected) != reflect.TypeOf(actual) {
+		return nil, fmt.Errorf("can't compare %T with %T", expected, actual)
+	}
+	expectedBind := getEntityColumnValues(schema, expected)
+	actualBind := getEntityColumnValues(schema, actual)
+	ignored := make(map[string]bool, len(ignoreColumns))
+	for _, column := range ignoreColumns {
+		ignored[column] = true
+	}
+	diff := make([]string, 0)
+	for _, column := range schema.columnNames {
+		if ignored[column] {
+			continue
+		}
+		expectedValue := formatEntityColumnValue(expectedBind[column])
+		actualValue := formatEntityColumnValue(actualBind[column])
+		if expectedValue != actualValue {
+			diff = append(diff, fmt.Sprintf("  %s: expected %s, got %s", column, expectedValue, actualValue))
+		}
+	}
+	return diff, nil
+}
+
+func getEntityColumnValues(schema *tableSchema, entity Entity) Bind {
+	cloned := schema.NewEntity().getORM()
+	elem := reflect.ValueOf(entity).Elem()
+	for i := 1; i < elem.NumField(); i++ {
+		cloned.elem.Field(i).Set(elem.Field(i))
+	}
+	bindBuilder, _ := cloned.buildDirtyBind(newSerializer(nil))
+	bindBuilder.bind["ID"] = elem.Field(1).Uint()
+	return bindBuilder.bind
+}
+
+func formatEntityColumnValue(value interface{}) string {
+	if value == nil {
+		return "NULL"
+	}
+	asString, is := value.(string)
+	if is {
+		return "'" + asString + "'"
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,68 @@
+package beeorm
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type entityAssertEntity struct {
+	ORM
+	ID        uint
+	Name      string
+	Age       *uint8
+	Born      time.Time
+	CreatedAt time.Time `orm:"time"`
+	Parent    *entityAssertEntity
+}
+
+type entityAssertTestingT struct {
+	errors []string
+}
+
+func (t *entityAssertTestingT) Helper() {}
+
+func (t *entityAssertTestingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertEntityEqual(t *testing.T) {
+	var entity *entityAssertEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+
+	now := time.Date(2022, 4, 10, 14, 30, 15, 200, time.UTC)
+	parent := &entityAssertEntity{Name: "parent", Born: now, CreatedAt: now}
+	engine.Flush(parent)
+	age := uint8(18)
+	entity = &entityAssertEntity{Name: "child", Age: &age, Born: now, CreatedAt: now, Parent: parent}
+	engine.Flush(entity)
+
+	loaded := &entityAssertEntity{}
+	engine.LoadByID(2, loaded)
+	mock := &entityAssertTestingT{}
+	expectedAge := uint8(18)
+	expected := &entityAssertEntity{ID: 2, Name: "child", Age: &expectedAge, Born: now.Add(time.Hour),
+		CreatedAt: now.Add(time.Millisecond), Parent: &entityAssertEntity{ID: 1}}
+	assert.True(t, AssertEntityEqual(mock, expected, loaded))
+	assert.Len(t, mock.errors, 0)
+
+	expected.Name = "other"
+	expected.Age = nil
+	expected.CreatedAt = now.Add(time.Minute)
+	assert.False(t, AssertEntityEqual(mock, expected, loaded))
+	assert.Len(t, mock.errors, 1)
+	assert.Equal(t, "entities are not equal:\n  CreatedAt: expected '2022-04-10 14:31:15', got '2022-04-10 14:30:15'\n"+
+		"  Name: expected 'other', got 'child'\n  Age: expected NULL, got 18", mock.errors[0])
+
+	mock = &entityAssertTestingT{}
+	assert.True(t, AssertEntityEqual(mock, expected, loaded, "Name", "Age", "CreatedAt"))
+	expected.Parent = nil
+	assert.False(t, AssertEntityEqual(mock, expected, loaded, "Name", "Age", "CreatedAt"))
+	assert.Equal(t, "entities are not equal:\n  Parent: expected NULL, got 1", mock.errors[0])
+
+	mock = &entityAssertTestingT{}
+	assert.False(t, AssertEntityEqual(mock, &flushEntity{}, loaded))
+	assert.Equal(t, "can't compare *beeorm.flushEntity with *beeorm.entityAssertEntity", mock.errors[0])
+}
@@ -0,0 +1,74 @@
+package beeorm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+type FieldDiff struct {
+	Old interface{}
+	New interface{}
+}
+
+func (orm *ORM) Snapshot() Bind {
+	if !orm.elem.IsValid() {
+		panic(errors.New("entity is not loaded"))
+	}
+	snapshot := *orm
+	snapshot.inDB = false
+	snapshot.delete = false
+	snapshot.fakeDelete = false
+	serializer := newSerializer(nil)
+	serializer.Reset(nil)
+	bindBuilder := newBindBuilder(orm.GetID(), &snapshot)
+	bindBuilder.build(serializer, orm.tableSchema.fields, orm.elem, true)
+	bindBuilder.bind["ID"] = orm.GetID()
+	return bindBuilder.bind
+}
+
+func Diff(a, b Bind) map[string]FieldDiff {
+	diff := make(map[string]FieldDiff)
+	for field, old := range a {
+		value, has := b[field]
+		if !has || !reflect.DeepEqual(old, value) {
+			diff[field] = FieldDiff{Old: old, New: value}
+		}
+	}
+	for field, value := range b {
+		if _, has := a[field]; !has {
+			diff[field] = FieldDiff{New: value}
+		}
+	}
+	return diff
+}
+
+func Merge(dst, src Entity, fields ...string) {
+	dstORM := dst.getORM()
+	srcORM := src.getORM()
+	if !dstORM.elem.IsValid() || !srcORM.elem.IsValid() {
+		panic(errors.New("entity is not loaded"))
+	}
+	if dstORM.tableSchema != srcORM.tableSchema {
+		panic(fmt.Errorf("can't merge %s into %s", srcORM.tableSchema.t.String(), dstORM.tableSchema.t.String()))
+	}
+	t := dstORM.tableSchema.t
+	if len(fields) == 0 {
+		for i := 2; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath == "" {
+				dstORM.elem.Field(i).Set(srcORM.elem.Field(i))
+			}
+		}
+		return
+	}
+	for _, field := range fields {
+		structField, has := t.FieldByName(field)
+		if !has || len(structField.Index) != 1 || structField.Index[0] < 2 {
+			panic(fmt.Errorf("field %s not found", field))
+		}
+		if structField.PkgPath != "" {
+			panic(fmt.Errorf("field %s is not public", field))
+		}
+		dstORM.elem.Field(structField.Index[0]).Set(srcORM.elem.Field(structField.Index[0]))
+	}
+}
@@ -0,0 +1,68 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type snapshotEntityAddress struct {
+	City   string
+	Street string
+}
+
+type snapshotEntity struct {
+	ORM     `orm:"localCache"`
+	ID      uint
+	Name    string `orm:"required"`
+	Age     int
+	Score   *float64
+	Address snapshotEntityAddress
+}
+
+func TestEntitySnapshot(t *testing.T) {
+	var entity *snapshotEntity
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	entity = &snapshotEntity{Name: "Tom", Age: 18}
+	entity.Address.City = "Berlin"
+	engine.Flush(entity)
+
+	before := entity.Snapshot()
+	assert.Equal(t, Bind{"ID": uint64(1), "Name": "Tom", "Age": int64(18), "Score": nil, "AddressCity": "Berlin",
+		"AddressStreet": nil}, before)
+	score := 4.5
+	entity.Age = 19
+	entity.Score = &score
+	entity.Address.City = "Paris"
+	after := entity.Snapshot()
+	assert.Equal(t, map[string]FieldDiff{
+		"Age":         {Old: int64(18), New: int64(19)},
+		"Score":       {Old: nil, New: 4.5},
+		"AddressCity": {Old: "Berlin", New: "Paris"},
+	}, Diff(before, after))
+	assert.Len(t, Diff(after, entity.Snapshot()), 0)
+	assert.Equal(t, map[string]FieldDiff{"Other": {New: 1}}, Diff(Bind{}, Bind{"Other": 1}))
+
+	loaded := &snapshotEntity{}
+	engine.LoadByID(1, loaded)
+	Merge(loaded, entity, "Age", "Address")
+	assert.Equal(t, 19, loaded.Age)
+	assert.Equal(t, "Paris", loaded.Address.City)
+	assert.Nil(t, loaded.Score)
+	assert.Equal(t, uint(1), loaded.ID)
+	Merge(loaded, entity)
+	assert.Equal(t, 4.5, *loaded.Score)
+	engine.Flush(loaded)
+	engine.GetLocalCache().Clear()
+	loaded = &snapshotEntity{}
+	engine.LoadByID(1, loaded)
+	assert.Len(t, Diff(entity.Snapshot(), loaded.Snapshot()), 0)
+
+	assert.PanicsWithError(t, "field Invalid not found", func() {
+		Merge(loaded, entity, "Invalid")
+	})
+	assert.PanicsWithError(t, "entity is not loaded", func() {
+		(&snapshotEntity{}).Snapshot()
+	})
+}
@@ -7,8 +7,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/shamaton/msgpack"
-
 	"github.com/go-redis/redis/v9"
 )
 
@@ -63,7 +61,7 @@ func (ev *event) Tag(key string) (value string) {
 
 func (ev *event) Unserialize(value interface{}) {
 	val := ev.message.Values["s"]
-	err := msgpack.Unmarshal([]byte(val.(string)), &value)
+	err := ev.consumer.engine.registry.decodePayload([]byte(val.(string)), value)
 	checkError(err)
 }
 
@@ -74,6 +72,8 @@ type EventBroker interface {
 	GetStreamsStatistics(stream ...string) []*RedisStreamStatistics
 	GetStreamStatistics(stream string) *RedisStreamStatistics
 	GetStreamGroupStatistics(stream, group string) *RedisStreamGroupStatistics
+	GetGroupCheckpoint(stream, group string) *EventGroupCheckpoint
+	SetGroupCheckpoint(stream, group, id string)
 }
 
 type EventFlusher interface {
@@ -90,12 +90,11 @@ type eventBroker struct {
 	engine *engineImplementation
 }
 
-func createEventSlice(body interface{}, meta []string) []string {
+func createEventSlice(engine *engineImplementation, stream string, body interface{}, meta []string) []string {
 	if body == nil {
 		return meta
 	}
-	asString, err := msgpack.Marshal(body)
-	checkError(err)
+	asString := engine.registry.encodePayload(body, stream)
 	values := make([]string, len(meta)+2)
 	values[0] = "s"
 	values[1] = string(asString)
@@ -106,12 +105,19 @@ func createEventSlice(body interface{}, meta []string) []string {
 }
 
 func (ef *eventFlusher) Publish(stream string, body interface{}, meta ...string) {
-	ef.events[stream] = append(ef.events[stream], createEventSlice(body, meta))
+	ef.events[stream] = append(ef.events[stream], createEventSlice(ef.eb.engine, stream, body, meta))
 }
 
 func (ef *eventFlusher) Flush() {
 	grouped := make(map[*RedisCache]map[string][][]string)
 	for stream, events := range ef.events {
+		jetStream, isJetStream := getJetStreamForStream(ef.eb.engine, stream)
+		if isJetStream {
+			for _, e := range events {
+				jetStreamPublish(jetStream, stream, e)
+			}
+			continue
+		}
 		r := getRedisForStream(ef.eb.engine, stream)
 		if grouped[r] == nil {
 			grouped[r] = make(map[string][][]string)
@@ -144,7 +150,11 @@ func (eb *eventBroker) NewFlusher() EventFlusher {
 }
 
 func (eb *eventBroker) Publish(stream string, body interface{}, meta ...string) (id string) {
-	return getRedisForStream(eb.engine, stream).xAdd(stream, createEventSlice(body, meta))
+	jetStream, isJetStream := getJetStreamForStream(eb.engine, stream)
+	if isJetStream {
+		return jetStreamPublish(jetStream, stream, createEventSlice(eb.engine, stream, body, meta))
+	}
+	return getRedisForStream(eb.engine, stream).xAdd(stream, createEventSlice(eb.engine, stream, body, meta))
 }
 
 func getRedisForStream(engine *engineImplementation, stream string) *RedisCache {
@@ -167,6 +177,13 @@ type EventsConsumer interface {
 
 func (eb *eventBroker) Consumer(group string) EventsConsumer {
 	streams := eb.engine.registry.getRedisStreamsForGroup(group)
+	jetStreams := eb.engine.registry.getJetStreamStreamsForGroup(group)
+	if len(jetStreams) > 0 {
+		if len(streams) > 0 {
+			panic(fmt.Errorf("group %s is registered in both redis and jet stream streams", group))
+		}
+		return eb.jetStreamConsumer(group, jetStreams)
+	}
 	if len(streams) == 0 {
 		panic(fmt.Errorf("unregistered streams for group %s", group))
 	}
@@ -194,6 +211,7 @@ type eventsConsumer struct {
 	lockTTL         time.Duration
 	lockTick        time.Duration
 	garbageLastTick int64
+	onIdle          func(ctx context.Context) (wait time.Duration)
 }
 
 func (b *eventConsumerBase) DisableBlockMode() {
@@ -213,6 +231,8 @@ func (r *eventsConsumer) ConsumeMany(ctx context.Context, nr, count int, handler
 }
 
 func (r *eventsConsumer) consume(ctx context.Context, name string, count int, handler EventConsumerHandler) (finished bool) {
+	ctx, cancel := r.engine.withCloseContext(ctx)
+	defer cancel()
 	lockKey := r.redis.config.GetNamespace() + r.group + "_" + name
 	locker := r.redis.GetLocker()
 	lock, has := locker.Obtain(ctx, lockKey, r.lockTTL, 0)
@@ -294,7 +314,34 @@ func (r *eventsConsumer) digestKeys(ctx context.Context, attributes *consumeAttr
 	}
 	a := &redis.XReadGroupArgs{Consumer: attributes.Name, Group: r.group, Streams: attributes.Streams,
 		Count: int64(attributes.Count), Block: attributes.BlockTime}
-	results := r.redis.XReadGroup(ctx, a)
+	failover := r.redis.config.getFailoverChannel()
+	readCtx := ctx
+	if failover != nil && attributes.BlockTime > 0 {
+		var cancel context.CancelFunc
+		readCtx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			select {
+			case <-failover:
+				cancel()
+			case <-readCtx.Done():
+			}
+		}()
+	}
+	results := r.redis.XReadGroup(readCtx, a)
+	if failover != nil {
+		select {
+		case <-failover:
+			for _, stream := range r.streams {
+				r.redis.XGroupCreateMkStream(stream, r.group, "0")
+				attributes.LastIDs[stream] = "0"
+			}
+			attributes.Pending = true
+			attributes.BlockTime = -1
+			return false
+		default:
+		}
+	}
 	totalMessages := 0
 	for _, row := range results {
 		l := len(row.Messages)
@@ -313,6 +360,23 @@ func (r *eventsConsumer) digestKeys(ctx context.Context, attributes *consumeAttr
 			}
 			return false
 		}
+		if r.onIdle != nil {
+			wait := r.onIdle(ctx)
+			if r.block {
+				attributes.BlockTime = r.blockTime
+				if wait > 0 && wait < r.blockTime {
+					attributes.BlockTime = wait
+				}
+			} else if wait > 0 {
+				timer := time.NewTimer(wait)
+				defer timer.Stop()
+				select {
+				case <-ctx.Done():
+				case <-timer.C:
+				}
+				return false
+			}
+		}
 		return true
 	}
 	events := make([]Event, totalMessages)
@@ -0,0 +1,323 @@
+package beeorm
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type JetStreamClient interface {
+	Publish(ctx context.Context, stream string, data []byte, headers map[string]string) (sequence uint64, err error)
+	AddConsumer(ctx context.Context, stream, consumer string) error
+	Fetch(ctx context.Context, stream, consumer string, batch int, wait time.Duration) ([]JetStreamMessage, error)
+	StreamInfo(ctx context.Context, stream string) (*JetStreamStreamInfo, error)
+	ConsumerInfo(ctx context.Context, stream, consumer string) (*JetStreamConsumerInfo, error)
+}
+
+type JetStreamMessage interface {
+	Sequence() uint64
+	Data() []byte
+	Headers() map[string]string
+	Ack() error
+}
+
+type JetStreamStreamInfo struct {
+	Messages      uint64
+	FirstSequence uint64
+	FirstTime     time.Time
+}
+
+type JetStreamConsumerInfo struct {
+	NumPending        uint64
+	NumAckPending     uint64
+	DeliveredSequence uint64
+	DeliveredTime     time.Time
+	AckFloorSequence  uint64
+	AckFloorTime      time.Time
+}
+
+type JetStreamPoolConfig interface {
+	GetCode() string
+	GetClient() JetStreamClient
+}
+
+type jetStreamPoolConfig struct {
+	code   string
+	client JetStreamClient
+}
+
+func (p *jetStreamPoolConfig) GetCode() string {
+	return p.code
+}
+
+func (p *jetStreamPoolConfig) GetClient() JetStreamClient {
+	return p.client
+}
+
+func (r *Registry) RegisterJetStream(client JetStreamClient, code ...string) {
+	dbCode := "default"
+	if len(code) > 0 {
+		dbCode = code[0]
+	}
+	if r.jetStreamPools == nil {
+		r.jetStreamPools = make(map[string]JetStreamPoolConfig)
+	}
+	r.jetStreamPools[dbCode] = &jetStreamPoolConfig{code: dbCode, client: client}
+}
+
+func (r *Registry) RegisterJetStreamStream(name string, jetStreamPool string, groups []string) {
+	if r.jetStreamGroups == nil {
+		r.jetStreamGroups = make(map[string]map[string]map[string]bool)
+		r.jetStreamStreamPools = make(map[string]string)
+	}
+	_, has := r.jetStreamStreamPools[name]
+	if !has {
+		_, has = r.redisStreamPools[name]
+	}
+	if has {
+		panic(fmt.Errorf("stream with name %s already exists", name))
+	}
+	r.jetStreamStreamPools[name] = jetStreamPool
+	if r.jetStreamGroups[jetStreamPool] == nil {
+		r.jetStreamGroups[jetStreamPool] = make(map[string]map[string]bool)
+	}
+	groupsMap := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		groupsMap[group] = true
+	}
+	r.jetStreamGroups[jetStreamPool][name] = groupsMap
+}
+
+func (r *validatedRegistry) GetJetStreamPools() map[string]JetStreamPoolConfig {
+	return r.jetStreamServers
+}
+
+func (r *validatedRegistry) getJetStreamStreamsForGroup(group string) []string {
+	streams := make([]string, 0)
+	for _, row := range r.jetStreamGroups {
+		for stream, groups := range row {
+			_, has := groups[group]
+			if has {
+				streams = append(streams, stream)
+			}
+		}
+	}
+	return streams
+}
+
+func getJetStreamForStream(engine *engineImplementation, stream string) (JetStreamClient, bool) {
+	pool, has := engine.registry.jetStreamStreamPools[stream]
+	if !has {
+		return nil, false
+	}
+	config, has := engine.registry.jetStreamServers[pool]
+	if !has {
+		panic(fmt.Errorf("unregistered jet stream pool '%s'", pool))
+	}
+	return config.GetClient(), true
+}
+
+func jetStreamPublish(client JetStreamClient, stream string, values []string) string {
+	var data []byte
+	headers := make(map[string]string, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		if values[i] == "s" {
+			data = []byte(values[i+1])
+			continue
+		}
+		headers[values[i]] = values[i+1]
+	}
+	sequence, err := client.Publish(context.Background(), stream, data, headers)
+	checkError(err)
+	return strconv.FormatUint(sequence, 10)
+}
+
+type jetStreamEvent struct {
+	stream   string
+	message  JetStreamMessage
+	ack      bool
+	registry *validatedRegistry
+}
+
+func (ev *jetStreamEvent) Ack() {
+	checkError(ev.message.Ack())
+	ev.ack = true
+}
+
+func (ev *jetStreamEvent) delete() {
+	ev.Ack()
+}
+
+func (ev *jetStreamEvent) ID() string {
+	return strconv.FormatUint(ev.message.Sequence(), 10)
+}
+
+func (ev *jetStreamEvent) Stream() string {
+	return ev.stream
+}
+
+func (ev *jetStreamEvent) Tag(key string) (value string) {
+	return ev.message.Headers()[key]
+}
+
+func (ev *jetStreamEvent) Unserialize(value interface{}) {
+	err := ev.registry.decodePayload(ev.message.Data(), value)
+	checkError(err)
+}
+
+type jetStreamConsumer struct {
+	eventConsumerBase
+	client   JetStreamClient
+	pool     string
+	streams  []string
+	group    string
+	lockTTL  time.Duration
+	lockTick time.Duration
+}
+
+func (eb *eventBroker) jetStreamConsumer(group string, streams []string) EventsConsumer {
+	pool := eb.engine.registry.jetStreamStreamPools[streams[0]]
+	for _, stream := range streams[1:] {
+		if eb.engine.registry.jetStreamStreamPools[stream] != pool {
+			panic(fmt.Errorf("group %s is registered in more than one jet stream pool", group))
+		}
+	}
+	client, _ := getJetStreamForStream(eb.engine, streams[0])
+	return &jetStreamConsumer{
+		eventConsumerBase: eventConsumerBase{engine: eb.engine, block: true, blockTime: time.Second * 5},
+		client:            client,
+		pool:              pool,
+		streams:           streams,
+		group:             group,
+		lockTTL:           time.Second * 90,
+		lockTick:          time.Minute,
+	}
+}
+
+func (r *jetStreamConsumer) Consume(ctx context.Context, count int, handler EventConsumerHandler) bool {
+	return r.ConsumeMany(ctx, 1, count, handler)
+}
+
+// ConsumeMany returns false when consumer nr of this group is already running, exclusivity is guarded
+// by lock in default redis pool. Members of a group share one durable JetStream consumer, so events are
+// balanced between them by the server.
+func (r *jetStreamConsumer) ConsumeMany(ctx context.Context, nr, count int, handler EventConsumerHandler) bool {
+	if nr < 1 {
+		panic(fmt.Errorf("invalid consumer number %d", nr))
+	}
+	ctx, cancel := r.engine.withCloseContext(ctx)
+	defer cancel()
+	redisCache := r.engine.GetRedis()
+	lockKey := redisCache.config.GetNamespace() + "jet_stream:" + r.pool + ":" + r.group + "_consumer-" + strconv.Itoa(nr)
+	lock, has := redisCache.GetLocker().Obtain(ctx, lockKey, r.lockTTL, 0)
+	if !has {
+		return false
+	}
+	timer := time.NewTimer(r.lockTick)
+	defer func() {
+		lock.Release()
+		timer.Stop()
+	}()
+	for _, stream := range r.streams {
+		checkError(r.client.AddConsumer(ctx, stream, r.group))
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return true
+		case <-timer.C:
+			if !lock.Refresh(ctx) {
+				return false
+			}
+			timer.Reset(r.lockTick)
+		default:
+			if r.digest(ctx, count, handler) && !r.block {
+				return true
+			}
+		}
+	}
+}
+
+func (r *jetStreamConsumer) digest(ctx context.Context, count int, handler EventConsumerHandler) (finished bool) {
+	wait := time.Duration(0)
+	if r.block {
+		wait = r.blockTime / time.Duration(len(r.streams))
+	}
+	events := make([]Event, 0)
+	for _, stream := range r.streams {
+		messages, err := r.client.Fetch(ctx, stream, r.group, count, wait)
+		if err != nil && ctx.Err() != nil {
+			return true
+		}
+		checkError(err)
+		for _, message := range messages {
+			events = append(events, &jetStreamEvent{stream: stream, message: message, registry: r.engine.registry})
+		}
+	}
+	if len(events) == 0 {
+		return true
+	}
+	handler(events)
+	for _, ev := range events {
+		ev := ev.(*jetStreamEvent)
+		if !ev.ack {
+			ev.Ack()
+		}
+	}
+	return false
+}
+
+// Claim is not supported. JetStream redelivers events not acknowledged within the consumer AckWait
+// to any running member of the group.
+func (r *jetStreamConsumer) Claim(_, _ int) {
+	panic(fmt.Errorf("claim is not supported in jet stream group %s, unacknowledged events are redelivered after AckWait", r.group))
+}
+
+func (eb *eventBroker) getJetStreamStatistics(stream ...string) []*RedisStreamStatistics {
+	now := time.Now()
+	results := make([]*RedisStreamStatistics, 0)
+	ctx := context.Background()
+	for pool, channels := range eb.engine.registry.jetStreamGroups {
+		client := eb.engine.registry.jetStreamServers[pool].GetClient()
+		for streamName, groups := range channels {
+			validName := len(stream) == 0
+			for _, name := range stream {
+				if name == streamName {
+					validName = true
+					break
+				}
+			}
+			if !validName {
+				continue
+			}
+			info, err := client.StreamInfo(ctx, streamName)
+			checkError(err)
+			stat := &RedisStreamStatistics{Stream: streamName, RedisPool: pool, Len: info.Messages}
+			stat.Groups = make([]*RedisStreamGroupStatistics, 0)
+			results = append(results, stat)
+			for group := range groups {
+				consumerInfo, err := client.ConsumerInfo(ctx, streamName, group)
+				checkError(err)
+				groupStats := &RedisStreamGroupStatistics{Group: group, Lag: int64(consumerInfo.NumPending),
+					Pending: consumerInfo.NumAckPending, Consumers: make([]*RedisStreamConsumerStatistics, 0)}
+				groupStats.EntriesRead = int64(consumerInfo.DeliveredSequence)
+				groupStats.DeliveryRate = eb.engine.registry.deliveryRates.getDeliveryRate(streamName, group, groupStats.EntriesRead, now)
+				if consumerInfo.DeliveredSequence > 0 {
+					groupStats.LastDeliveredID = strconv.FormatUint(consumerInfo.DeliveredSequence, 10)
+					groupStats.LastDeliveredDuration = now.Sub(consumerInfo.DeliveredTime)
+				}
+				if consumerInfo.NumAckPending > 0 {
+					groupStats.LowerID = strconv.FormatUint(consumerInfo.AckFloorSequence+1, 10)
+					groupStats.LowerDuration = now.Sub(consumerInfo.AckFloorTime)
+					oldest := int(groupStats.LowerDuration.Seconds())
+					if oldest > stat.OldestEventSeconds {
+						stat.OldestEventSeconds = oldest
+					}
+				}
+				stat.Groups = append(stat.Groups, groupStats)
+			}
+		}
+	}
+	return results
+}
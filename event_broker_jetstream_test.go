@@ -0,0 +1,152 @@
+package beeorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testJetStreamMessage struct {
+	client   *testJetStreamClient
+	stream   string
+	consumer string
+	sequence uint64
+	data     []byte
+	headers  map[string]string
+}
+
+func (m *testJetStreamMessage) Sequence() uint64 {
+	return m.sequence
+}
+
+func (m *testJetStreamMessage) Data() []byte {
+	return m.data
+}
+
+func (m *testJetStreamMessage) Headers() map[string]string {
+	return m.headers
+}
+
+func (m *testJetStreamMessage) Ack() error {
+	delete(m.client.pending[m.stream+m.consumer], m.sequence)
+	return nil
+}
+
+type testJetStreamClient struct {
+	messages  map[string][]*testJetStreamMessage
+	delivered map[string]int
+	pending   map[string]map[uint64]bool
+	infoError error
+}
+
+func (c *testJetStreamClient) Publish(_ context.Context, stream string, data []byte, headers map[string]string) (uint64, error) {
+	sequence := uint64(len(c.messages[stream]) + 1)
+	c.messages[stream] = append(c.messages[stream], &testJetStreamMessage{client: c, stream: stream, sequence: sequence,
+		data: data, headers: headers})
+	return sequence, nil
+}
+
+func (c *testJetStreamClient) AddConsumer(_ context.Context, stream, consumer string) error {
+	if c.pending[stream+consumer] == nil {
+		c.pending[stream+consumer] = make(map[uint64]bool)
+	}
+	return nil
+}
+
+func (c *testJetStreamClient) Fetch(_ context.Context, stream, consumer string, batch int, _ time.Duration) ([]JetStreamMessage, error) {
+	result := make([]JetStreamMessage, 0)
+	for _, message := range c.messages[stream][c.delivered[stream+consumer]:] {
+		if len(result) == batch {
+			break
+		}
+		m := *message
+		m.consumer = consumer
+		result = append(result, &m)
+		c.pending[stream+consumer][m.sequence] = true
+		c.delivered[stream+consumer]++
+	}
+	return result, nil
+}
+
+func (c *testJetStreamClient) StreamInfo(_ context.Context, stream string) (*JetStreamStreamInfo, error) {
+	if c.infoError != nil {
+		return nil, c.infoError
+	}
+	return &JetStreamStreamInfo{Messages: uint64(len(c.messages[stream])), FirstSequence: 1}, nil
+}
+
+func (c *testJetStreamClient) ConsumerInfo(_ context.Context, stream, consumer string) (*JetStreamConsumerInfo, error) {
+	delivered := c.delivered[stream+consumer]
+	return &JetStreamConsumerInfo{NumPending: uint64(len(c.messages[stream]) - delivered),
+		NumAckPending: uint64(len(c.pending[stream+consumer])), DeliveredSequence: uint64(delivered),
+		DeliveredTime: time.Now()}, nil
+}
+
+func TestJetStream(t *testing.T) {
+	client := &testJetStreamClient{messages: make(map[string][]*testJetStreamMessage), delivered: make(map[string]int),
+		pending: make(map[string]map[uint64]bool)}
+	registry := &Registry{}
+	registry.RegisterRedis("localhost:6382", "", 15)
+	registry.RegisterJetStream(client, "nats")
+	registry.RegisterJetStreamStream("jet-stream", "nats", []string{"test-group"})
+	assert.PanicsWithError(t, "stream with name jet-stream already exists", func() {
+		registry.RegisterRedisStream("jet-stream", "default", []string{"test-group"})
+	})
+	validatedRegistry, err := registry.Validate()
+	assert.NoError(t, err)
+	assert.Len(t, validatedRegistry.GetJetStreamPools(), 1)
+	engine := validatedRegistry.CreateEngine()
+	broker := engine.GetEventBroker()
+
+	type testEvent struct {
+		Name string
+	}
+	assert.Equal(t, "1", broker.Publish("jet-stream", testEvent{"a"}, "tag", "value"))
+	flusher := broker.NewFlusher()
+	flusher.Publish("jet-stream", testEvent{"b"})
+	flusher.Publish("jet-stream", testEvent{"c"})
+	flusher.Flush()
+
+	redisFlusher := &redisFlusher{engine: engine.(*engineImplementation)}
+	redisFlusher.Publish("jet-stream", testEvent{"d"})
+	assert.Len(t, client.messages["jet-stream"], 3)
+	redisFlusher.jetStreamEvents = nil
+	redisFlusher.Flush()
+	assert.Len(t, client.messages["jet-stream"], 3)
+
+	stats := broker.GetStreamStatistics("jet-stream")
+	assert.Equal(t, "nats", stats.RedisPool)
+	assert.Equal(t, uint64(3), stats.Len)
+	assert.Len(t, stats.Groups, 1)
+	assert.Equal(t, int64(3), stats.Groups[0].Lag)
+
+	consumer := broker.Consumer("test-group")
+	consumer.DisableBlockMode()
+	names := make([]string, 0)
+	consumer.Consume(context.Background(), 2, func(events []Event) {
+		for _, e := range events {
+			assert.Equal(t, "jet-stream", e.Stream())
+			val := &testEvent{}
+			e.Unserialize(val)
+			names = append(names, val.Name)
+			if val.Name == "a" {
+				assert.False(t, broker.Consumer("test-group").ConsumeMany(context.Background(), 1, 1, func(_ []Event) {}))
+				assert.Equal(t, "1", e.ID())
+				assert.Equal(t, "value", e.Tag("tag"))
+				e.Ack()
+			}
+		}
+	})
+	assert.Equal(t, []string{"a", "b", "c"}, names)
+
+	stats = broker.GetStreamStatistics("jet-stream")
+	assert.Equal(t, int64(0), stats.Groups[0].Lag)
+	assert.Equal(t, uint64(0), stats.Groups[0].Pending)
+	assert.Equal(t, "3", stats.Groups[0].LastDeliveredID)
+
+	assert.PanicsWithError(t, "claim is not supported in jet stream group test-group, unacknowledged events are redelivered after AckWait", func() {
+		consumer.Claim(1, 2)
+	})
+}
@@ -0,0 +1,136 @@
+package beeorm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type EventGroupCheckpoint struct {
+	Stream            string
+	Group             string
+	LastDeliveredID   string
+	LastDeliveredTime time.Time
+	LastProcessedID   string
+	LastProcessedTime time.Time
+	LastEventID       string
+	LastEventTime     time.Time
+	Pending           uint64
+	CaughtUp          bool
+}
+
+func (eb *eventBroker) GetGroupCheckpoint(stream, group string) *EventGroupCheckpoint {
+	r := eb.getCheckpointRedis(stream, group)
+	checkpoint := &EventGroupCheckpoint{Stream: stream, Group: group, LastDeliveredID: "0-0", LastProcessedID: "0-0"}
+	last := r.XRevRange(stream, "+", "-", 1)
+	if len(last) > 0 {
+		checkpoint.LastEventID = last[0].ID
+		checkpoint.LastEventTime = streamIDToTime(last[0].ID)
+	}
+	for _, info := range r.XInfoGroups(stream) {
+		if info.Name != group {
+			continue
+		}
+		checkpoint.LastDeliveredID = info.LastDeliveredID
+		checkpoint.LastProcessedID = info.LastDeliveredID
+		checkpoint.Pending = uint64(info.Pending)
+		if info.Pending > 0 {
+			pending := r.XPending(stream, group)
+			checkpoint.LastProcessedID = "0-0"
+			before := r.XRevRange(stream, "("+pending.Lower, "-", 1)
+			if len(before) > 0 {
+				checkpoint.LastProcessedID = before[0].ID
+			}
+		}
+		break
+	}
+	checkpoint.LastDeliveredTime = streamIDToTime(checkpoint.LastDeliveredID)
+	checkpoint.LastProcessedTime = streamIDToTime(checkpoint.LastProcessedID)
+	checkpoint.CaughtUp = checkpoint.Pending == 0 && compareStreamIDs(checkpoint.LastDeliveredID, checkpoint.LastEventID) >= 0
+	return checkpoint
+}
+
+func (eb *eventBroker) SetGroupCheckpoint(stream, group, id string) {
+	r := eb.getCheckpointRedis(stream, group)
+	last := "0-0"
+	lastEvent := r.XRevRange(stream, "+", "-", 1)
+	if len(lastEvent) > 0 {
+		last = lastEvent[0].ID
+	}
+	if id == "$" {
+		id = last
+	}
+	if _, _, valid := parseStreamID(id); !valid {
+		panic(fmt.Errorf("invalid checkpoint '%s' for group %s in stream %s", id, group, stream))
+	}
+	if compareStreamIDs(id, last) > 0 {
+		panic(fmt.Errorf("checkpoint %s for group %s is ahead of last event %s in stream %s", id, group, last, stream))
+	}
+	for _, info := range r.XInfoGroups(stream) {
+		if info.Name != group {
+			continue
+		}
+		if info.Pending > 0 {
+			panic(fmt.Errorf("group %s has %d pending events in stream %s", group, info.Pending, stream))
+		}
+		r.XGroupSetID(stream, group, id)
+		return
+	}
+	r.XGroupCreateMkStream(stream, group, id)
+}
+
+func (eb *eventBroker) getCheckpointRedis(stream, group string) *RedisCache {
+	if _, isJetStream := getJetStreamForStream(eb.engine, stream); isJetStream {
+		panic(fmt.Errorf("checkpoints are not supported for jet stream %s", stream))
+	}
+	r := getRedisForStream(eb.engine, stream)
+	if !eb.engine.registry.redisStreamGroups[r.config.GetCode()][stream][group] {
+		panic(fmt.Errorf("group %s is not registered for stream %s", group, stream))
+	}
+	return r
+}
+
+func parseStreamID(id string) (ms uint64, seq uint64, valid bool) {
+	parts := strings.Split(id, "-")
+	if len(parts) > 2 {
+		return 0, 0, false
+	}
+	ms, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if len(parts) == 2 {
+		seq, err = strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+	}
+	return ms, seq, true
+}
+
+func compareStreamIDs(a, b string) int {
+	msA, seqA, _ := parseStreamID(a)
+	msB, seqB, _ := parseStreamID(b)
+	if msA != msB {
+		if msA < msB {
+			return -1
+		}
+		return 1
+	}
+	if seqA != seqB {
+		if seqA < seqB {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+func streamIDToTime(id string) time.Time {
+	ms, _, _ := parseStreamID(id)
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(ms)*int64(time.Millisecond))
+}
@@ -0,0 +1,83 @@
+package beeorm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupCheckpoint(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterRedis("localhost:6382", "", 15)
+	registry.RegisterRedisStream("test-stream", "default", []string{"test-group"})
+	validatedRegistry, err := registry.Validate()
+	assert.NoError(t, err)
+	engine := validatedRegistry.CreateEngine()
+	engine.GetRedis().FlushDB()
+	broker := engine.GetEventBroker()
+
+	checkpoint := broker.GetGroupCheckpoint("test-stream", "test-group")
+	assert.Equal(t, "test-stream", checkpoint.Stream)
+	assert.Equal(t, "test-group", checkpoint.Group)
+	assert.Equal(t, "", checkpoint.LastEventID)
+	assert.True(t, checkpoint.CaughtUp)
+
+	type testEvent struct {
+		Name string
+	}
+	ids := make([]string, 10)
+	for i := 0; i < 10; i++ {
+		ids[i] = broker.Publish("test-stream", testEvent{fmt.Sprintf("a%d", i)})
+	}
+	checkpoint = broker.GetGroupCheckpoint("test-stream", "test-group")
+	assert.Equal(t, ids[9], checkpoint.LastEventID)
+	assert.False(t, checkpoint.LastEventTime.IsZero())
+	assert.False(t, checkpoint.CaughtUp)
+
+	consumer := broker.Consumer("test-group")
+	consumer.(*eventsConsumer).blockTime = time.Millisecond
+	consumer.DisableBlockMode()
+	consumer.Consume(context.Background(), 100, func(events []Event) {})
+	checkpoint = broker.GetGroupCheckpoint("test-stream", "test-group")
+	assert.Equal(t, ids[9], checkpoint.LastDeliveredID)
+	assert.Equal(t, ids[9], checkpoint.LastProcessedID)
+	assert.Equal(t, uint64(0), checkpoint.Pending)
+	assert.True(t, checkpoint.CaughtUp)
+
+	broker.SetGroupCheckpoint("test-stream", "test-group", ids[4])
+	checkpoint = broker.GetGroupCheckpoint("test-stream", "test-group")
+	assert.Equal(t, ids[4], checkpoint.LastDeliveredID)
+	assert.False(t, checkpoint.CaughtUp)
+
+	broker.SetGroupCheckpoint("test-stream", "test-group", "$")
+	checkpoint = broker.GetGroupCheckpoint("test-stream", "test-group")
+	assert.Equal(t, ids[9], checkpoint.LastDeliveredID)
+	assert.True(t, checkpoint.CaughtUp)
+
+	assert.PanicsWithError(t, "invalid checkpoint 'abc' for group test-group in stream test-stream", func() {
+		broker.SetGroupCheckpoint("test-stream", "test-group", "abc")
+	})
+	assert.PanicsWithError(t, "checkpoint 99999999999999-0 for group test-group is ahead of last event "+ids[9]+" in stream test-stream", func() {
+		broker.SetGroupCheckpoint("test-stream", "test-group", "99999999999999-0")
+	})
+	assert.PanicsWithError(t, "group invalid-group is not registered for stream test-stream", func() {
+		broker.GetGroupCheckpoint("test-stream", "invalid-group")
+	})
+	assert.PanicsWithError(t, "unregistered stream invalid-stream", func() {
+		broker.GetGroupCheckpoint("invalid-stream", "test-group")
+	})
+}
+
+func TestCompareStreamIDs(t *testing.T) {
+	assert.Equal(t, 0, compareStreamIDs("1-1", "1-1"))
+	assert.Equal(t, -1, compareStreamIDs("1-1", "1-2"))
+	assert.Equal(t, 1, compareStreamIDs("2-0", "1-9"))
+	assert.Equal(t, 1, compareStreamIDs("1-0", ""))
+	_, _, valid := parseStreamID("1-2-3")
+	assert.False(t, valid)
+	assert.True(t, streamIDToTime("0-0").IsZero())
+	assert.Equal(t, int64(1500), streamIDToTime("1500-1").UnixMilli())
+}
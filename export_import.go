@@ -0,0 +1,375 @@
+package beeorm
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV    ExportFormat = "csv"
+	ExportFormatNDJSON ExportFormat = "ndjson"
+)
+
+const exportNullValue = "\\N"
+const exportBatchSize = 1000
+
+type ImportOptions struct {
+	BatchSize int
+	Lazy      bool
+	SkipIDs   bool
+}
+
+type entityRowsWriter interface {
+	write(values []*string) error
+	flush() error
+}
+
+type csvRowsWriter struct {
+	writer *csv.Writer
+	row    []string
+}
+
+func (w *csvRowsWriter) write(values []*string) error {
+	for i, value := range values {
+		if value == nil {
+			w.row[i] = exportNullValue
+		} else {
+			w.row[i] = *value
+		}
+	}
+	return w.writer.Write(w.row)
+}
+
+func (w *csvRowsWriter) flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type ndjsonRowsWriter struct {
+	writer  *bufio.Writer
+	columns []string
+}
+
+func (w *ndjsonRowsWriter) write(values []*string) error {
+	row := make(map[string]interface{}, len(values))
+	for i, value := range values {
+		if value == nil {
+			row[w.columns[i]] = nil
+		} else {
+			row[w.columns[i]] = *value
+		}
+	}
+	encoded, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(row)
+	if err != nil {
+		return err
+	}
+	_, err = w.writer.Write(append(encoded, '\n'))
+	return err
+}
+
+func (w *ndjsonRowsWriter) flush() error {
+	return w.writer.Flush()
+}
+
+func (e *engineImplementation) ExportEntities(entity Entity, where *Where, writer io.Writer, format ExportFormat) (rows int, err error) {
+	schema := initIfNeeded(e.registry, entity).tableSchema
+	columns := make([]string, len(schema.columnNames))
+	timeColumns := make(map[int]bool)
+	getExportTimeColumns(schema.fields, 0, timeColumns)
+	query := ""
+	for i, name := range schema.columnNames {
+		columns[i] = schema.getColumnName(name)
+		if timeColumns[i] {
+			query += ",CAST(`" + columns[i] + "` AS CHAR)"
+		} else {
+			query += ",`" + columns[i] + "`"
+		}
+	}
+	var rowsWriter entityRowsWriter
+	switch format {
+	case ExportFormatCSV:
+		csvWriter := csv.NewWriter(writer)
+		err = csvWriter.Write(columns)
+		if err != nil {
+			return 0, err
+		}
+		rowsWriter = &csvRowsWriter{writer: csvWriter, row: make([]string, len(columns))}
+	case ExportFormatNDJSON:
+		rowsWriter = &ndjsonRowsWriter{writer: bufio.NewWriter(writer), columns: columns}
+	default:
+		return 0, fmt.Errorf("unsupported export format '%s'", format)
+	}
+	if where == nil {
+		where = NewWhere("1")
+	}
+	whereQuery := "(" + where.String() + ")"
+	if !where.showFakeDeleted && schema.hasFakeDelete {
+		whereQuery = "`FakeDelete` = 0 AND " + whereQuery
+	} else if !where.showFakeDeleted && schema.hasSoftDelete {
+		whereQuery = "`" + schema.getColumnName(schema.softDeleteColumn) + "` IS NULL AND " + whereQuery
+	}
+	db := schema.GetMysql(e)
+	lastID := uint64(0)
+	for {
+		/* #nosec */
+		sqlQuery := "SELECT " + query[1:] + " FROM `" + schema.tableName + "` WHERE " + whereQuery + " AND `ID` > ? ORDER BY `ID` LIMIT " +
+			strconv.Itoa(exportBatchSize)
+		parameters := append(where.GetParameters(), lastID)
+		results, def := db.Query(sqlQuery, parameters...)
+		batch := 0
+		for results.Next() {
+			pointers := make([]interface{}, len(columns))
+			for i := range pointers {
+				pointers[i] = &sql.NullString{}
+			}
+			results.Scan(pointers...)
+			values := make([]*string, len(columns))
+			for i, pointer := range pointers {
+				value := pointer.(*sql.NullString)
+				if value.Valid {
+					values[i] = &value.String
+				}
+			}
+			lastID, _ = strconv.ParseUint(*values[schema.idIndex], 10, 64)
+			err = rowsWriter.write(values)
+			if err != nil {
+				def()
+				return rows, err
+			}
+			batch++
+			rows++
+		}
+		def()
+		if batch < exportBatchSize {
+			break
+		}
+	}
+	return rows, rowsWriter.flush()
+}
+
+func (e *engineImplementation) ImportEntities(entity Entity, reader io.Reader, format ExportFormat, options *ImportOptions) (rows int, err error) {
+	schema := initIfNeeded(e.registry, entity).tableSchema
+	if options == nil {
+		options = &ImportOptions{}
+	}
+	batchSize := options.BatchSize
+	if batchSize <= 0 {
+		batchSize = exportBatchSize
+	}
+	columns := make(map[string]int, len(schema.columnNames))
+	for i, name := range schema.columnNames {
+		columns[schema.getColumnName(name)] = i
+	}
+	timeColumns := make(map[int]bool)
+	getExportTimeColumns(schema.fields, 0, timeColumns)
+	var next func() (map[int]*string, error)
+	switch format {
+	case ExportFormatCSV:
+		csvReader := csv.NewReader(reader)
+		header, err := csvReader.Read()
+		if err != nil {
+			return 0, err
+		}
+		indexes := make([]int, len(header))
+		for i, column := range header {
+			index, has := columns[column]
+			if !has {
+				return 0, fmt.Errorf("unknown column '%s' in %s import", column, schema.t.String())
+			}
+			indexes[i] = index
+		}
+		next = func() (map[int]*string, error) {
+			record, err := csvReader.Read()
+			if err != nil {
+				return nil, err
+			}
+			values := make(map[int]*string, len(record))
+			for i, value := range record {
+				if value != exportNullValue {
+					v := value
+					values[indexes[i]] = &v
+				}
+			}
+			return values, nil
+		}
+	case ExportFormatNDJSON:
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+		next = func() (map[int]*string, error) {
+			for scanner.Scan() {
+				line := strings.TrimSpace(scanner.Text())
+				if line == "" {
+					continue
+				}
+				row := make(map[string]*string)
+				err := jsoniter.ConfigCompatibleWithStandardLibrary.UnmarshalFromString(line, &row)
+				if err != nil {
+					return nil, err
+				}
+				values := make(map[int]*string, len(row))
+				for column, value := range row {
+					index, has := columns[column]
+					if !has {
+						return nil, fmt.Errorf("unknown column '%s' in %s import", column, schema.t.String())
+					}
+					values[index] = value
+				}
+				return values, nil
+			}
+			err := scanner.Err()
+			if err == nil {
+				err = io.EOF
+			}
+			return nil, err
+		}
+	default:
+		return 0, fmt.Errorf("unsupported import format '%s'", format)
+	}
+	batch := make([]Entity, 0, batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if options.Lazy {
+			e.FlushLazy(batch...)
+		} else {
+			e.Flush(batch...)
+		}
+		batch = batch[0:0]
+	}
+	serializer := newSerializer(nil)
+	for {
+		values, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			flush()
+			return rows, err
+		}
+		pointers := prepareScan(schema)
+		for index, value := range values {
+			err = setImportPointer(pointers[index], value, timeColumns[index])
+			if err != nil {
+				flush()
+				return rows, fmt.Errorf("invalid value for column '%s' in %s import: %w",
+					schema.getColumnName(schema.columnNames[index]), schema.t.String(), err)
+			}
+		}
+		imported := schema.NewEntity()
+		fillFromDBRow(serializer, *pointers[schema.idIndex].(*uint64), e.registry, pointers, imported)
+		orm := imported.getORM()
+		orm.inDB = false
+		orm.loaded = false
+		orm.binary = nil
+		if options.SkipIDs {
+			orm.idElem.SetUint(0)
+		}
+		batch = append(batch, imported)
+		rows++
+		if len(batch) >= batchSize {
+			flush()
+		}
+	}
+	flush()
+	return rows, nil
+}
+
+func setImportPointer(pointer interface{}, value *string, isTime bool) error {
+	if isTime && value != nil {
+		seconds, err := parseImportTime(*value)
+		if err != nil {
+			return err
+		}
+		asString := strconv.FormatInt(seconds, 10)
+		value = &asString
+	}
+	switch v := pointer.(type) {
+	case *uint64:
+		if value == nil {
+			return nil
+		}
+		parsed, err := strconv.ParseUint(*value, 10, 64)
+		*v = parsed
+		return err
+	case *int64:
+		if value == nil {
+			return nil
+		}
+		parsed, err := strconv.ParseInt(*value, 10, 64)
+		*v = parsed
+		return err
+	case *bool:
+		if value == nil {
+			return nil
+		}
+		*v = *value == "1" || strings.ToLower(*value) == "true"
+		return nil
+	case *float64:
+		if value == nil {
+			return nil
+		}
+		parsed, err := strconv.ParseFloat(*value, 64)
+		*v = parsed
+		return err
+	case sql.Scanner:
+		if value == nil {
+			return v.Scan(nil)
+		}
+		if nullBool, is := v.(*sql.NullBool); is {
+			nullBool.Valid = true
+			nullBool.Bool = *value == "1" || strings.ToLower(*value) == "true"
+			return nil
+		}
+		return v.Scan(*value)
+	}
+	return fmt.Errorf("unsupported column type %T", pointer)
+}
+
+func parseImportTime(value string) (int64, error) {
+	if strings.HasPrefix(value, "0000-00-00") {
+		return zeroDateSeconds, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05.999999", timeFormat, dateformat, time.RFC3339} {
+		parsed, err := time.ParseInLocation(layout, value, time.UTC)
+		if err == nil {
+			if layout == time.RFC3339 {
+				parsed = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), parsed.Hour(), parsed.Minute(), parsed.Second(), 0, time.UTC)
+			}
+			return parsed.Unix() + timeStampSeconds, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid time '%s'", value)
+}
+
+func getExportTimeColumns(fields *tableFields, start int, result map[int]bool) int {
+	start += len(fields.refs) + len(fields.uintegers) + len(fields.integers) + len(fields.booleans) + len(fields.floats)
+	for i := 0; i < len(fields.times)+len(fields.dates); i++ {
+		result[start] = true
+		start++
+	}
+	if fields.fakeDelete > 0 {
+		start++
+	}
+	start += len(fields.strings) + len(fields.uintegersNullable) + len(fields.integersNullable) + len(fields.stringsEnums) +
+		len(fields.bytes) + len(fields.sliceStringsSets) + len(fields.booleansNullable) + len(fields.floatsNullable)
+	for i := 0; i < len(fields.timesNullable)+len(fields.datesNullable); i++ {
+		result[start] = true
+		start++
+	}
+	start += len(fields.jsons) + len(fields.refsMany) + len(fields.customs)
+	for _, subFields := range fields.structsFields {
+		start = getExportTimeColumns(subFields, start, result)
+	}
+	return start
+}
@@ -0,0 +1,121 @@
+package beeorm
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type exportImportEntity struct {
+	ORM      `orm:"localCache"`
+	ID       uint
+	Name     string `orm:"required"`
+	Age      int
+	Active   bool
+	Balance  *float64
+	Born     time.Time
+	Visited  *time.Time `orm:"time"`
+	Nickname *string
+}
+
+func TestExportImportCSV(t *testing.T) {
+	testExportImport(t, ExportFormatCSV)
+}
+
+func TestExportImportNDJSON(t *testing.T) {
+	testExportImport(t, ExportFormatNDJSON)
+}
+
+func testExportImport(t *testing.T, format ExportFormat) {
+	var entity *exportImportEntity
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+
+	balance := 12.5
+	visited := time.Date(2022, 3, 4, 10, 11, 12, 0, time.UTC)
+	nickname := "Tom"
+	flusher := engine.NewFlusher()
+	for i := 1; i <= 10; i++ {
+		e := &exportImportEntity{Name: "name " + strings.Repeat("a", i), Age: i, Active: i%2 == 0,
+			Born: time.Date(1990, 1, i, 0, 0, 0, 0, time.UTC)}
+		if i == 1 {
+			e.Balance = &balance
+			e.Visited = &visited
+			e.Nickname = &nickname
+		}
+		flusher.Track(e)
+	}
+	flusher.Flush()
+
+	buffer := &bytes.Buffer{}
+	rows, err := engine.ExportEntities(entity, NewWhere("`Age` <= ?", 5), buffer, format)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, rows)
+	if format == ExportFormatCSV {
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		assert.Len(t, lines, 6)
+		assert.Equal(t, "ID,Name,Age,Active,Balance,Born,Visited,Nickname", lines[0])
+	} else {
+		assert.Len(t, strings.Split(strings.TrimSpace(buffer.String()), "\n"), 5)
+	}
+
+	engine.GetMysql().Exec("DELETE FROM `exportImportEntity`")
+	engine.GetLocalCache().Clear()
+
+	rows, err = engine.ImportEntities(entity, buffer, format, &ImportOptions{BatchSize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, rows)
+
+	var loaded []*exportImportEntity
+	engine.Search(NewWhere("1 ORDER BY `ID`"), nil, &loaded)
+	assert.Len(t, loaded, 5)
+	assert.Equal(t, uint(1), loaded[0].ID)
+	assert.Equal(t, "name a", loaded[0].Name)
+	assert.Equal(t, 1, loaded[0].Age)
+	assert.False(t, loaded[0].Active)
+	assert.True(t, loaded[1].Active)
+	assert.Equal(t, 12.5, *loaded[0].Balance)
+	assert.Nil(t, loaded[1].Balance)
+	assert.Equal(t, "1990-01-01", loaded[0].Born.Format(dateformat))
+	assert.Equal(t, visited.Unix(), loaded[0].Visited.Unix())
+	assert.Nil(t, loaded[1].Visited)
+	assert.Equal(t, "Tom", *loaded[0].Nickname)
+	assert.Nil(t, loaded[1].Nickname)
+
+	buffer.Reset()
+	_, err = engine.ExportEntities(entity, nil, buffer, format)
+	assert.NoError(t, err)
+	rows, err = engine.ImportEntities(entity, buffer, format, &ImportOptions{SkipIDs: true, Lazy: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, rows)
+	receiver := NewBackgroundConsumer(engine)
+	receiver.DisableBlockMode()
+	receiver.blockTime = time.Millisecond
+	receiver.Digest(context.Background())
+	assert.Equal(t, 10, engine.SearchWithCount(NewWhere("1"), nil, &loaded))
+
+	_, err = engine.ExportEntities(entity, nil, buffer, "xml")
+	assert.EqualError(t, err, "unsupported export format 'xml'")
+	_, err = engine.ImportEntities(entity, strings.NewReader("ID,Invalid\n1,2\n"), ExportFormatCSV, nil)
+	assert.EqualError(t, err, "unknown column 'Invalid' in beeorm.exportImportEntity import")
+	_, err = engine.ImportEntities(entity, strings.NewReader("{\"ID\":\"x\"}\n"), ExportFormatNDJSON, nil)
+	assert.EqualError(t, err, "invalid value for column 'ID' in beeorm.exportImportEntity import: strconv.ParseUint: parsing \"x\": invalid syntax")
+}
+
+func TestParseImportTime(t *testing.T) {
+	seconds, err := parseImportTime("2022-03-04 10:11:12")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2022, 3, 4, 10, 11, 12, 0, time.UTC).Unix()+timeStampSeconds, seconds)
+	seconds, err = parseImportTime("2022-03-04")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC).Unix()+timeStampSeconds, seconds)
+	seconds, err = parseImportTime("0000-00-00 00:00:00")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(zeroDateSeconds), seconds)
+	_, err = parseImportTime("invalid")
+	assert.EqualError(t, err, "invalid time 'invalid'")
+}
@@ -0,0 +1,299 @@
+package beeorm
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var factoryWords = []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel", "india", "juliet",
+	"kilo", "lima", "mike", "november", "oscar", "papa", "quebec", "romeo", "sierra", "tango", "uniform", "victor",
+	"whiskey", "xray", "yankee", "zulu"}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+type FieldGenerator func(random *rand.Rand, sequence uint64) interface{}
+
+type EntityFactory[T Entity] struct {
+	schema     *tableSchema
+	generators map[string]FieldGenerator
+	unique     map[string]bool
+	random     *rand.Rand
+	sequence   uint64
+	mutex      sync.Mutex
+}
+
+func Factory[T Entity](registry ValidatedRegistry, seed ...int64) *EntityFactory[T] {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Ptr {
+		panic(fmt.Errorf("factory type %s must be a pointer to entity", t.String()))
+	}
+	schema := getTableSchema(registry.(*validatedRegistry), t.Elem())
+	if schema == nil {
+		panic(fmt.Errorf("entity '%s' is not registered", t.Elem().String()))
+	}
+	randomSeed := time.Now().UnixNano()
+	if len(seed) > 0 {
+		randomSeed = seed[0]
+	}
+	unique := make(map[string]bool)
+	for _, fields := range schema.uniqueIndices {
+		for _, field := range fields {
+			unique[field] = true
+		}
+	}
+	/* #nosec */
+	return &EntityFactory[T]{schema: schema, generators: make(map[string]FieldGenerator), unique: unique,
+		random: rand.New(rand.NewSource(randomSeed))}
+}
+
+func (f *EntityFactory[T]) Set(field string, generator FieldGenerator) *EntityFactory[T] {
+	f.generators[field] = generator
+	return f
+}
+
+func (f *EntityFactory[T]) SetValue(field string, value interface{}) *EntityFactory[T] {
+	return f.Set(field, func(_ *rand.Rand, _ uint64) interface{} {
+		return value
+	})
+}
+
+func (f *EntityFactory[T]) New() T {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.sequence++
+	entity := f.schema.NewEntity()
+	f.fill(entity.getORM().elem, f.schema.t, "", true)
+	return entity.(T)
+}
+
+func (f *EntityFactory[T]) NewMany(count int) []T {
+	entities := make([]T, count)
+	for i := 0; i < count; i++ {
+		entities[i] = f.New()
+	}
+	return entities
+}
+
+func (f *EntityFactory[T]) Create(engine Engine) T {
+	entity := f.New()
+	engine.Flush(entity)
+	return entity
+}
+
+func (f *EntityFactory[T]) CreateMany(engine Engine, count int) []T {
+	entities := f.NewMany(count)
+	toFlush := make([]Entity, count)
+	for i, entity := range entities {
+		toFlush[i] = entity
+	}
+	engine.Flush(toFlush...)
+	return entities
+}
+
+func (f *EntityFactory[T]) fill(elem reflect.Value, t reflect.Type, prefix string, root bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := prefix + field.Name
+		if field.PkgPath != "" || (root && (i < 2 || name == "FakeDelete")) {
+			continue
+		}
+		value := elem.Field(i)
+		generator, has := f.generators[name]
+		if has {
+			generated := generator(f.random, f.sequence)
+			if generated == nil {
+				value.Set(reflect.Zero(value.Type()))
+			} else {
+				value.Set(reflect.ValueOf(generated).Convert(value.Type()))
+			}
+			continue
+		}
+		if f.isSkipped(name) {
+			continue
+		}
+		tags := f.schema.tags[name]
+		_, isCustom := getCustomColumnType(field.Type)
+		if field.Type.Kind() == reflect.Struct && field.Type != timeType && !isCustom {
+			subPrefix := ""
+			if !field.Anonymous {
+				subPrefix = field.Name
+			}
+			f.fill(value, field.Type, subPrefix, false)
+			continue
+		}
+		if field.Type.Kind() == reflect.Ptr {
+			elem := field.Type.Elem()
+			if (elem.Kind() == reflect.Struct && elem != timeType) || (tags["required"] != "true" && f.random.Intn(2) == 0) {
+				continue
+			}
+			pointer := reflect.New(elem)
+			if f.generate(pointer.Elem(), name, tags) {
+				value.Set(pointer)
+			}
+			continue
+		}
+		f.generate(value, name, tags)
+	}
+}
+
+func (f *EntityFactory[T]) isSkipped(name string) bool {
+	schema := f.schema
+	if name == schema.createdAtColumn || name == schema.updatedAtColumn || name == schema.softDeleteColumn ||
+		name == schema.treePathColumn || name == schema.treeParentColumn {
+		return true
+	}
+	for _, column := range schema.generatedColumns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *EntityFactory[T]) generate(value reflect.Value, name string, tags map[string]string) bool {
+	if _, is := getCustomColumnType(value.Type()); is {
+		return false
+	}
+	if value.Type() == timeType {
+		generated := time.Now().Add(-time.Duration(f.random.Int63n(int64(time.Hour * 24 * 365)))).Truncate(time.Second)
+		if tags["time"] != "true" {
+			generated = time.Date(generated.Year(), generated.Month(), generated.Day(), 0, 0, 0, 0, time.Local)
+		}
+		value.Set(reflect.ValueOf(generated))
+		return true
+	}
+	minValue, maxValue, hasRange := f.getRange(value, tags)
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(f.generateString(name, tags))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value.SetUint(uint64(f.generateNumber(name, minValue, maxValue, hasRange)))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value.SetInt(f.generateNumber(name, minValue, maxValue, hasRange))
+	case reflect.Float32, reflect.Float64:
+		generated := minValue + f.random.Float64()*(maxValue-minValue)
+		precision := 2
+		if asInt, err := strconv.Atoi(tags["precision"]); err == nil {
+			precision = asInt
+		}
+		if decimal := strings.Split(tags["decimal"], ","); len(decimal) == 2 {
+			precision, _ = strconv.Atoi(decimal[1])
+		}
+		p := math.Pow10(precision)
+		value.SetFloat(math.Round(generated*p) / p)
+	case reflect.Bool:
+		value.SetBool(f.random.Intn(2) == 1)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			generated := make([]byte, 16)
+			_, _ = f.random.Read(generated)
+			value.SetBytes(generated)
+			return true
+		}
+		if value.Type().Elem().Kind() != reflect.String {
+			return false
+		}
+		enum := f.schema.registry.registry.enums[tags["set"]]
+		if enum == nil {
+			return false
+		}
+		generated := make([]string, 0)
+		for _, option := range enum.GetFields() {
+			if f.random.Intn(2) == 1 {
+				generated = append(generated, option)
+			}
+		}
+		if len(generated) == 0 && tags["required"] == "true" {
+			generated = append(generated, enum.GetFields()[0])
+		}
+		value.Set(reflect.ValueOf(generated))
+	default:
+		return false
+	}
+	return true
+}
+
+func (f *EntityFactory[T]) getRange(value reflect.Value, tags map[string]string) (minValue, maxValue float64, has bool) {
+	minValue = 0
+	maxValue = 1000
+	switch value.Kind() {
+	case reflect.Uint8, reflect.Int8:
+		maxValue = 100
+	case reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+		if tags["unsigned"] != "true" {
+			minValue = -1000
+		}
+	}
+	if asFloat, err := strconv.ParseFloat(tags["min"], 64); err == nil {
+		minValue = asFloat
+		has = true
+	}
+	if asFloat, err := strconv.ParseFloat(tags["max"], 64); err == nil {
+		maxValue = asFloat
+		has = true
+	}
+	return minValue, maxValue, has
+}
+
+func (f *EntityFactory[T]) generateNumber(name string, minValue, maxValue float64, hasRange bool) int64 {
+	if f.unique[name] && !hasRange {
+		return int64(f.sequence)
+	}
+	from := int64(math.Ceil(minValue))
+	to := int64(math.Floor(maxValue))
+	if to <= from {
+		return from
+	}
+	return from + f.random.Int63n(to-from+1)
+}
+
+func (f *EntityFactory[T]) generateString(name string, tags map[string]string) string {
+	enumCode, hasEnum := tags["enum"]
+	if hasEnum {
+		fields := f.schema.registry.registry.enums[enumCode].GetFields()
+		return fields[f.random.Intn(len(fields))]
+	}
+	maxLength := 255
+	if asInt, err := strconv.Atoi(tags["length"]); err == nil {
+		maxLength = asInt
+	} else if tags["length"] == "max" {
+		maxLength = 65535
+	}
+	if asInt, err := strconv.Atoi(tags["max"]); err == nil && asInt < maxLength {
+		maxLength = asInt
+	}
+	generated := ""
+	if tags["email"] == "true" {
+		generated = factoryWords[f.random.Intn(len(factoryWords))] + "." + strconv.FormatUint(f.sequence, 10) + "@example.com"
+		return generated
+	}
+	words := 1 + f.random.Intn(3)
+	for i := 0; i < words; i++ {
+		if i > 0 {
+			generated += " "
+		}
+		generated += factoryWords[f.random.Intn(len(factoryWords))]
+	}
+	if f.unique[name] {
+		suffix := "-" + strconv.FormatUint(f.sequence, 10)
+		if len(generated)+len(suffix) > maxLength {
+			generated = generated[0:int(math.Max(0, float64(maxLength-len(suffix))))]
+		}
+		generated += suffix
+	}
+	if len(generated) > maxLength {
+		generated = generated[0:maxLength]
+	}
+	if minLength, err := strconv.Atoi(tags["min"]); err == nil {
+		for len(generated) < minLength {
+			generated += factoryWords[f.random.Intn(len(factoryWords))]
+		}
+	}
+	return generated
+}
@@ -0,0 +1,99 @@
+package beeorm
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type factoryAddress struct {
+	City   string `orm:"length=30"`
+	Street string
+}
+
+type factoryEntity struct {
+	ORM       `orm:"localCache"`
+	ID        uint
+	Name      string   `orm:"required;length=20"`
+	Email     string   `orm:"unique=Email;email"`
+	Code      string   `orm:"unique=Code;length=8"`
+	Number    uint     `orm:"unique=Number"`
+	Status    string   `orm:"enum=beeorm.TestEnum;required"`
+	Tags      []string `orm:"set=beeorm.TestSet"`
+	Age       uint8    `orm:"min=18;max=65"`
+	Balance   int
+	Price     float64  `orm:"decimal=10,2;unsigned"`
+	Rating    *float32 `orm:"required"`
+	Nickname  *string
+	Born      time.Time
+	Visited   *time.Time `orm:"time"`
+	Active    bool
+	Avatar    []byte
+	CreatedAt time.Time `orm:"createdAt;time"`
+	Parent    *factoryEntity
+	Address   factoryAddress
+}
+
+func TestFactory(t *testing.T) {
+	var entity *factoryEntity
+	registry := &Registry{}
+	registry.RegisterEnum("beeorm.TestEnum", []string{"a", "b", "c"})
+	registry.RegisterEnum("beeorm.TestSet", []string{"a", "b", "c"})
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+
+	factory := Factory[*factoryEntity](engine.GetRegistry(), 1)
+	entities := factory.NewMany(50)
+	emails := make(map[string]bool)
+	codes := make(map[string]bool)
+	for i, entity := range entities {
+		assert.Equal(t, uint64(0), entity.GetID())
+		assert.NotEmpty(t, entity.Name)
+		assert.LessOrEqual(t, utf8.RuneCountInString(entity.Name), 20)
+		assert.Regexp(t, `^[a-z]+\.\d+@example\.com$`, entity.Email)
+		assert.False(t, emails[entity.Email])
+		emails[entity.Email] = true
+		assert.LessOrEqual(t, len(entity.Code), 8)
+		assert.False(t, codes[entity.Code])
+		codes[entity.Code] = true
+		assert.Equal(t, uint(i+1), entity.Number)
+		assert.Contains(t, []string{"a", "b", "c"}, entity.Status)
+		for _, tag := range entity.Tags {
+			assert.Contains(t, []string{"a", "b", "c"}, tag)
+		}
+		assert.GreaterOrEqual(t, entity.Age, uint8(18))
+		assert.LessOrEqual(t, entity.Age, uint8(65))
+		assert.GreaterOrEqual(t, entity.Price, float64(0))
+		assert.NotNil(t, entity.Rating)
+		assert.False(t, entity.Born.IsZero())
+		assert.Len(t, entity.Avatar, 16)
+		assert.True(t, entity.CreatedAt.IsZero())
+		assert.Nil(t, entity.Parent)
+		assert.NotEmpty(t, entity.Address.City)
+		assert.LessOrEqual(t, len(entity.Address.City), 30)
+		assert.Empty(t, validateEntity(entity))
+	}
+	assert.Equal(t, Factory[*factoryEntity](engine.GetRegistry(), 1).New().Name, entities[0].Name)
+
+	parent := factory.Create(engine)
+	assert.Equal(t, uint64(1), parent.GetID())
+	factory.SetValue("Parent", parent).SetValue("Status", "b").Set("Name", func(_ *rand.Rand, sequence uint64) interface{} {
+		return "user " + string(rune('a'+sequence%26))
+	})
+	created := factory.CreateMany(engine, 10)
+	assert.Len(t, created, 10)
+	for _, entity := range created {
+		assert.Greater(t, entity.GetID(), uint64(1))
+		assert.Equal(t, parent, entity.Parent)
+		assert.Equal(t, "b", entity.Status)
+		loaded := &factoryEntity{}
+		assert.True(t, engine.LoadByID(entity.GetID(), loaded))
+		assert.Equal(t, entity.Name, loaded.Name)
+		assert.Equal(t, entity.Email, loaded.Email)
+	}
+	assert.PanicsWithError(t, "entity 'beeorm.flushEntity' is not registered", func() {
+		Factory[*flushEntity](engine.GetRegistry())
+	})
+}
@@ -0,0 +1,137 @@
+package beeorm
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+type ObjectStore interface {
+	Put(key, contentType string, content io.Reader) (size int64, err error)
+	Open(key string) (io.ReadCloser, error)
+	Delete(keys ...string) error
+	SignURL(key string, expires time.Duration) (string, error)
+}
+
+type File struct {
+	Store       string
+	Key         string
+	Name        string
+	ContentType string
+	Size        int64
+}
+
+func (r *Registry) RegisterObjectStore(store ObjectStore, code ...string) {
+	storeCode := "default"
+	if len(code) > 0 {
+		storeCode = code[0]
+	}
+	if r.objectStores == nil {
+		r.objectStores = make(map[string]ObjectStore)
+	}
+	r.objectStores[storeCode] = store
+}
+
+func initFileFields(tableSchema *tableSchema, registry *Registry, entityType reflect.Type) error {
+	tableSchema.fileColumns = nil
+	for i := 0; i < entityType.NumField(); i++ {
+		field := entityType.Field(i)
+		storeCode, has := tableSchema.tags[field.Name]["file"]
+		if !has {
+			continue
+		}
+		if field.Type != reflect.TypeOf(&File{}) {
+			return fmt.Errorf("file field %s in %s must be *beeorm.File", field.Name, entityType.String())
+		}
+		if storeCode == "true" {
+			storeCode = "default"
+		}
+		_, has = registry.objectStores[storeCode]
+		if !has {
+			return fmt.Errorf("object store '%s' for field %s in %s is not registered", storeCode, field.Name, entityType.String())
+		}
+		if tableSchema.fileColumns == nil {
+			tableSchema.fileColumns = make(map[string]string)
+		}
+		tableSchema.fileColumns[field.Name] = storeCode
+	}
+	return nil
+}
+
+func (e *engineImplementation) getObjectStore(code string) ObjectStore {
+	store, has := e.registry.registry.objectStores[code]
+	if !has {
+		panic(fmt.Errorf("unregistered object store '%s'", code))
+	}
+	return store
+}
+
+func attachFile(engine *engineImplementation, entity Entity, field, name, contentType string, content io.Reader) *File {
+	orm := initIfNeeded(engine.registry, entity)
+	storeCode, has := orm.tableSchema.fileColumns[field]
+	if !has {
+		panic(fmt.Errorf("field %s in %s is not a file field", field, orm.tableSchema.t.String()))
+	}
+	random := make([]byte, 16)
+	_, err := rand.Read(random)
+	checkError(err)
+	key := orm.tableSchema.tableName + "/" + hex.EncodeToString(random) + "/" + strings.ReplaceAll(name, "/", "_")
+	size, err := engine.getObjectStore(storeCode).Put(key, contentType, content)
+	checkError(err)
+	file := &File{Store: storeCode, Key: key, Name: name, ContentType: contentType, Size: size}
+	orm.elem.FieldByName(field).Set(reflect.ValueOf(file))
+	return file
+}
+
+func openFile(engine *engineImplementation, file *File) io.ReadCloser {
+	reader, err := engine.getObjectStore(file.Store).Open(file.Key)
+	checkError(err)
+	return reader
+}
+
+func signFileURL(engine *engineImplementation, file *File, expires time.Duration) string {
+	url, err := engine.getObjectStore(file.Store).SignURL(file.Key, expires)
+	checkError(err)
+	return url
+}
+
+func (f *flusher) addOrphanedFiles(orm *ORM, bindBuilder *bindBuilder) {
+	for column := range orm.tableSchema.fileColumns {
+		_, changed := bindBuilder.bind[column]
+		if !orm.delete && !changed {
+			continue
+		}
+		old, _ := bindBuilder.current[column].(string)
+		if old == "" {
+			continue
+		}
+		oldFile := &File{}
+		if jsoniter.ConfigFastest.UnmarshalFromString(old, oldFile) != nil || oldFile.Key == "" {
+			continue
+		}
+		if !orm.delete {
+			newFile, _ := orm.elem.FieldByName(column).Interface().(*File)
+			if newFile != nil && newFile.Store == oldFile.Store && newFile.Key == oldFile.Key {
+				continue
+			}
+		}
+		if f.orphanedFiles == nil {
+			f.orphanedFiles = make(map[string][]string)
+		}
+		f.orphanedFiles[oldFile.Store] = append(f.orphanedFiles[oldFile.Store], oldFile.Key)
+	}
+}
+
+func (f *flusher) flushOrphanedFiles() {
+	orphanedFiles := f.orphanedFiles
+	f.orphanedFiles = nil
+	for storeCode, keys := range orphanedFiles {
+		checkError(f.engine.getObjectStore(storeCode).Delete(keys...))
+	}
+}
@@ -0,0 +1,127 @@
+package beeorm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fileFieldsEntity struct {
+	ORM    `orm:"localCache;redisCache"`
+	ID     uint
+	Name   string
+	Avatar *File `orm:"file"`
+	Resume *File `orm:"file=documents"`
+}
+
+type fileFieldsEntityInvalid struct {
+	ORM
+	ID     uint
+	Avatar string `orm:"file"`
+}
+
+type memoryObjectStore struct {
+	objects map[string][]byte
+}
+
+func (s *memoryObjectStore) Put(key, _ string, content io.Reader) (int64, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return 0, err
+	}
+	s.objects[key] = data
+	return int64(len(data)), nil
+}
+
+func (s *memoryObjectStore) Open(key string) (io.ReadCloser, error) {
+	data, has := s.objects[key]
+	if !has {
+		return nil, fmt.Errorf("object %s not found", key)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryObjectStore) Delete(keys ...string) error {
+	for _, key := range keys {
+		delete(s.objects, key)
+	}
+	return nil
+}
+
+func (s *memoryObjectStore) SignURL(key string, expires time.Duration) (string, error) {
+	return fmt.Sprintf("https://objects.local/%s?expires=%d", key, int(expires.Seconds())), nil
+}
+
+func TestFileFields(t *testing.T) {
+	var entity *fileFieldsEntity
+	images := &memoryObjectStore{objects: make(map[string][]byte)}
+	documents := &memoryObjectStore{objects: make(map[string][]byte)}
+	registry := &Registry{}
+	registry.RegisterObjectStore(images)
+	registry.RegisterObjectStore(documents, "documents")
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema)
+	assert.Equal(t, map[string]string{"Avatar": "default", "Resume": "documents"}, schema.fileColumns)
+
+	entity = &fileFieldsEntity{Name: "John"}
+	file := engine.AttachFile(entity, "Avatar", "me.png", "image/png", strings.NewReader("png content"))
+	assert.Equal(t, "default", file.Store)
+	assert.Equal(t, "me.png", file.Name)
+	assert.Equal(t, int64(11), file.Size)
+	assert.True(t, strings.HasPrefix(file.Key, "fileFieldsEntity/"))
+	assert.Same(t, file, entity.Avatar)
+	engine.AttachFile(entity, "Resume", "cv.pdf", "application/pdf", strings.NewReader("pdf content"))
+	engine.Flush(entity)
+	assert.Len(t, images.objects, 1)
+	assert.Len(t, documents.objects, 1)
+
+	entity = &fileFieldsEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "image/png", entity.Avatar.ContentType)
+	content, err := io.ReadAll(engine.OpenFile(entity.Avatar))
+	assert.NoError(t, err)
+	assert.Equal(t, "png content", string(content))
+	assert.Equal(t, "https://objects.local/"+entity.Resume.Key+"?expires=60", engine.SignFileURL(entity.Resume, time.Minute))
+
+	oldKey := entity.Avatar.Key
+	engine.AttachFile(entity, "Avatar", "new.png", "image/png", strings.NewReader("new png"))
+	engine.Flush(entity)
+	assert.Len(t, images.objects, 1)
+	_, has := images.objects[oldKey]
+	assert.False(t, has)
+	_, has = images.objects[entity.Avatar.Key]
+	assert.True(t, has)
+
+	entity.Name = "Tom"
+	engine.Flush(entity)
+	assert.Len(t, images.objects, 1)
+
+	entity.Resume = nil
+	engine.Flush(entity)
+	assert.Len(t, documents.objects, 0)
+
+	engine.Delete(entity)
+	assert.Len(t, images.objects, 0)
+
+	assert.PanicsWithError(t, "field Name in beeorm.fileFieldsEntity is not a file field", func() {
+		engine.AttachFile(&fileFieldsEntity{}, "Name", "a.txt", "text/plain", strings.NewReader("a"))
+	})
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&fileFieldsEntity{})
+	_, err = registry.Validate()
+	assert.EqualError(t, err, "object store 'default' for field Avatar in beeorm.fileFieldsEntity is not registered")
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterObjectStore(images)
+	registry.RegisterEntity(&fileFieldsEntityInvalid{})
+	_, err = registry.Validate()
+	assert.EqualError(t, err, "file field Avatar in beeorm.fileFieldsEntityInvalid must be *beeorm.File")
+}
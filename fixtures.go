@@ -0,0 +1,293 @@
+package beeorm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const fixtureReferencePrefix = "@"
+
+type fixtureDefinition struct {
+	name       string
+	entityType reflect.Type
+	fields     yaml.MapSlice
+	depends    []string
+}
+
+type Fixtures struct {
+	engine      *engineImplementation
+	definitions []*fixtureDefinition
+	entities    map[string]Entity
+	loaded      map[string]bool
+	types       []reflect.Type
+}
+
+func NewFixtures(engine Engine) *Fixtures {
+	return &Fixtures{engine: engine.(*engineImplementation), entities: make(map[string]Entity), loaded: make(map[string]bool)}
+}
+
+func (f *Fixtures) LoadFiles(paths ...string) error {
+	for _, path := range paths {
+		err := f.LoadFile(path)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Fixtures) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return f.LoadYaml(data)
+	case ".json":
+		return f.LoadJSON(data)
+	}
+	return fmt.Errorf("unsupported fixture file '%s'", path)
+}
+
+func (f *Fixtures) LoadJSON(data []byte) error {
+	return f.LoadYaml(data)
+}
+
+func (f *Fixtures) LoadYaml(data []byte) error {
+	content := yaml.MapSlice{}
+	err := yaml.Unmarshal(data, &content)
+	if err != nil {
+		return err
+	}
+	definitions := make([]*fixtureDefinition, 0)
+	names := make(map[string]bool)
+	for _, group := range content {
+		entityName := fmt.Sprintf("%v", group.Key)
+		entityType, err := findRegisteredEntity(f.engine.registry, entityName)
+		if err != nil {
+			return err
+		}
+		rows, is := group.Value.(yaml.MapSlice)
+		if !is {
+			return fmt.Errorf("invalid fixtures for entity '%s'", entityName)
+		}
+		for _, row := range rows {
+			name := fmt.Sprintf("%v", row.Key)
+			if names[name] || f.isDefined(name) {
+				return fmt.Errorf("duplicated fixture '%s'", name)
+			}
+			names[name] = true
+			fields, is := row.Value.(yaml.MapSlice)
+			if !is && row.Value != nil {
+				return fmt.Errorf("invalid fixture '%s'", name)
+			}
+			definitions = append(definitions, &fixtureDefinition{name: name, entityType: entityType, fields: fields})
+		}
+	}
+	f.definitions = append(f.definitions, definitions...)
+	for _, definition := range definitions {
+		err = f.initDependencies(definition)
+		if err != nil {
+			f.removeNotLoaded(names)
+			return err
+		}
+	}
+	err = f.flush()
+	if err != nil {
+		f.removeNotLoaded(names)
+	}
+	return err
+}
+
+func (f *Fixtures) removeNotLoaded(names map[string]bool) {
+	definitions := make([]*fixtureDefinition, 0, len(f.definitions))
+	for _, definition := range f.definitions {
+		if f.loaded[definition.name] || !names[definition.name] {
+			definitions = append(definitions, definition)
+		}
+	}
+	f.definitions = definitions
+}
+
+func (f *Fixtures) Get(name string) Entity {
+	return f.entities[name]
+}
+
+func (f *Fixtures) GetID(name string) uint64 {
+	entity, has := f.entities[name]
+	if !has {
+		return 0
+	}
+	return entity.GetID()
+}
+
+func (f *Fixtures) Reload() error {
+	for i := len(f.types) - 1; i >= 0; i-- {
+		schema := getTableSchema(f.engine.registry, f.types[i])
+		ids := make([]uint64, 0)
+		for _, entity := range f.entities {
+			if entity.getORM().tableSchema == schema && entity.GetID() > 0 {
+				ids = append(ids, entity.GetID())
+			}
+		}
+		if len(ids) > 0 {
+			f.engine.ClearCacheByIDs(schema.NewEntity(), ids...)
+		}
+		schema.TruncateTable(f.engine)
+	}
+	f.entities = make(map[string]Entity)
+	f.loaded = make(map[string]bool)
+	f.types = nil
+	return f.flush()
+}
+
+func (f *Fixtures) isDefined(name string) bool {
+	for _, definition := range f.definitions {
+		if definition.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Fixtures) initDependencies(definition *fixtureDefinition) error {
+	definition.depends = nil
+	for _, field := range definition.fields {
+		fieldName := fmt.Sprintf("%v", field.Key)
+		structField, has := definition.entityType.FieldByName(fieldName)
+		if !has {
+			return fmt.Errorf("fixture '%s' field %s not found", definition.name, fieldName)
+		}
+		for _, name := range getFixtureReferences(structField.Type, field.Value) {
+			if !f.isDefined(name) {
+				return fmt.Errorf("fixture '%s' references unknown fixture '%s'", definition.name, name)
+			}
+			definition.depends = append(definition.depends, name)
+		}
+	}
+	return nil
+}
+
+func getFixtureReferences(t reflect.Type, value interface{}) []string {
+	references := make([]string, 0)
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+		values, is := value.([]interface{})
+		if !is || !t.Implements(entityInterfaceType) {
+			return nil
+		}
+		for _, row := range values {
+			asString, is := row.(string)
+			if is && strings.HasPrefix(asString, fixtureReferencePrefix) {
+				references = append(references, asString[1:])
+			}
+		}
+		return references
+	}
+	asString, is := value.(string)
+	if t.Kind() == reflect.Ptr && t.Implements(entityInterfaceType) && is && strings.HasPrefix(asString, fixtureReferencePrefix) {
+		references = append(references, asString[1:])
+	}
+	return references
+}
+
+func (f *Fixtures) flush() error {
+	for {
+		wave := make([]*fixtureDefinition, 0)
+		pending := 0
+		for _, definition := range f.definitions {
+			if f.loaded[definition.name] {
+				continue
+			}
+			pending++
+			ready := true
+			for _, name := range definition.depends {
+				if !f.loaded[name] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				wave = append(wave, definition)
+			}
+		}
+		if pending == 0 {
+			return nil
+		}
+		if len(wave) == 0 {
+			missing := make([]string, 0)
+			for _, definition := range f.definitions {
+				if !f.loaded[definition.name] {
+					missing = append(missing, definition.name)
+				}
+			}
+			sort.Strings(missing)
+			return fmt.Errorf("circular fixture references between %s", strings.Join(missing, ", "))
+		}
+		entities := make([]Entity, len(wave))
+		for i, definition := range wave {
+			entity, err := f.newEntity(definition)
+			if err != nil {
+				return err
+			}
+			entities[i] = entity
+		}
+		f.engine.Flush(entities...)
+		for i, definition := range wave {
+			f.entities[definition.name] = entities[i]
+			f.loaded[definition.name] = true
+			f.addType(definition.entityType)
+		}
+	}
+}
+
+func (f *Fixtures) addType(t reflect.Type) {
+	for _, existing := range f.types {
+		if existing == t {
+			return
+		}
+	}
+	f.types = append(f.types, t)
+}
+
+func (f *Fixtures) newEntity(definition *fixtureDefinition) (Entity, error) {
+	entity := getTableSchema(f.engine.registry, definition.entityType).NewEntity()
+	orm := entity.getORM()
+	for _, field := range definition.fields {
+		fieldName := fmt.Sprintf("%v", field.Key)
+		structField, _ := definition.entityType.FieldByName(fieldName)
+		references := getFixtureReferences(structField.Type, field.Value)
+		if len(references) == 0 {
+			err := orm.SetField(fieldName, field.Value)
+			if err != nil {
+				return nil, fmt.Errorf("fixture '%s': %w", definition.name, err)
+			}
+			continue
+		}
+		if structField.Type.Kind() == reflect.Slice {
+			slice := reflect.MakeSlice(structField.Type, len(references), len(references))
+			for i, name := range references {
+				referenced := f.entities[name]
+				if reflect.TypeOf(referenced) != structField.Type.Elem() {
+					return nil, fmt.Errorf("fixture '%s' field %s can't reference '%s'", definition.name, fieldName, name)
+				}
+				slice.Index(i).Set(reflect.ValueOf(referenced))
+			}
+			orm.elem.FieldByName(fieldName).Set(slice)
+			continue
+		}
+		referenced := f.entities[references[0]]
+		if reflect.TypeOf(referenced) != structField.Type {
+			return nil, fmt.Errorf("fixture '%s' field %s can't reference '%s'", definition.name, fieldName, references[0])
+		}
+		orm.elem.FieldByName(fieldName).Set(reflect.ValueOf(referenced))
+	}
+	return entity, nil
+}
@@ -0,0 +1,94 @@
+package beeorm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fixtureCountryEntity struct {
+	ORM  `orm:"redisCache"`
+	ID   uint
+	Code string
+}
+
+type fixtureUserEntity struct {
+	ORM       `orm:"localCache"`
+	ID        uint
+	Name      string
+	Age       uint8
+	Country   *fixtureCountryEntity
+	Visited   []*fixtureCountryEntity
+	Manager   *fixtureUserEntity
+	Confirmed bool
+}
+
+func TestFixtures(t *testing.T) {
+	var country *fixtureCountryEntity
+	var user *fixtureUserEntity
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", country, user)
+
+	dir := t.TempDir()
+	usersFile := filepath.Join(dir, "users.yaml")
+	assert.NoError(t, os.WriteFile(usersFile, []byte(`
+fixtureUserEntity:
+  tom:
+    Name: Tom
+    Age: 30
+    Country: "@poland"
+    Visited: ["@poland", "@germany"]
+    Manager: "@ann"
+  ann:
+    Name: Ann
+    Age: 40
+    Country: "@germany"
+    Confirmed: true
+`), 0600))
+	countriesFile := filepath.Join(dir, "countries.json")
+	assert.NoError(t, os.WriteFile(countriesFile, []byte(`{"beeorm.fixtureCountryEntity": {"poland": {"Code": "PL"}, "germany": {"Code": "DE"}}}`), 0600))
+
+	fixtures := NewFixtures(engine)
+	assert.EqualError(t, fixtures.LoadFile(usersFile), "fixture 'tom' references unknown fixture 'poland'")
+	assert.NoError(t, fixtures.LoadFiles(countriesFile, usersFile))
+	assert.Equal(t, uint64(1), fixtures.GetID("poland"))
+	assert.Equal(t, uint64(2), fixtures.GetID("germany"))
+	assert.Equal(t, uint64(1), fixtures.GetID("ann"))
+	assert.Equal(t, uint64(2), fixtures.GetID("tom"))
+	assert.Equal(t, uint64(0), fixtures.GetID("missing"))
+	assert.Nil(t, fixtures.Get("missing"))
+
+	user = &fixtureUserEntity{}
+	assert.True(t, engine.LoadByID(fixtures.GetID("tom"), user, "Country", "Visited", "Manager"))
+	assert.Equal(t, "Tom", user.Name)
+	assert.Equal(t, uint8(30), user.Age)
+	assert.Equal(t, "PL", user.Country.Code)
+	assert.Len(t, user.Visited, 2)
+	assert.Equal(t, "DE", user.Visited[1].Code)
+	assert.Equal(t, "Ann", user.Manager.Name)
+	assert.True(t, user.Manager.Confirmed)
+	assert.Same(t, fixtures.Get("ann"), fixtures.Get("tom").(*fixtureUserEntity).Manager)
+
+	user.Name = "Changed"
+	engine.Flush(user)
+	engine.Flush(&fixtureUserEntity{Name: "Extra"})
+	assert.NoError(t, fixtures.Reload())
+	user = &fixtureUserEntity{}
+	assert.True(t, engine.LoadByID(fixtures.GetID("tom"), user))
+	assert.Equal(t, "Tom", user.Name)
+	assert.False(t, engine.LoadByID(3, user))
+	country = &fixtureCountryEntity{}
+	assert.True(t, engine.LoadByID(1, country))
+	assert.Equal(t, "PL", country.Code)
+
+	assert.EqualError(t, fixtures.LoadYaml([]byte("fixtureUserEntity:\n  tom:\n    Name: Tom\n")), "duplicated fixture 'tom'")
+	assert.EqualError(t, fixtures.LoadYaml([]byte("unknownEntity:\n  a:\n    Name: A\n")), "entity 'unknownEntity' is not registered")
+	assert.EqualError(t, fixtures.LoadYaml([]byte("fixtureUserEntity:\n  a:\n    Invalid: A\n")), "fixture 'a' field Invalid not found")
+	assert.EqualError(t, fixtures.LoadYaml([]byte("fixtureUserEntity:\n  a:\n    Manager: '@b'\n  b:\n    Manager: '@a'\n")),
+		"circular fixture references between a, b")
+	assert.NoError(t, fixtures.LoadYaml([]byte("fixtureUserEntity:\n  a:\n    Manager: '@tom'\n")))
+	assert.Equal(t, uint64(3), fixtures.GetID("a"))
+	assert.EqualError(t, fixtures.LoadFile(filepath.Join(dir, "fixtures.txt")), "open "+filepath.Join(dir, "fixtures.txt")+": no such file or directory")
+}
@@ -0,0 +1,97 @@
+package beeorm
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// flushFingerprintsLimit bounds fingerprints kept by engine, all are dropped when it is reached
+const flushFingerprintsLimit = 10000
+
+func (e *engineImplementation) EnableFlushDeduplication() {
+	e.flushDeduplication = true
+}
+
+func (e *engineImplementation) DisableFlushDeduplication() {
+	e.flushDeduplication = false
+	e.flushedFingerprints = nil
+	e.afterCommitFingerprints = nil
+}
+
+func getFlushFingerprint(orm *ORM, bind Bind) string {
+	if orm.delete {
+		return "delete:" + strconv.FormatUint(orm.GetID(), 10)
+	}
+	keys := make([]string, 0, len(bind))
+	for key := range bind {
+		if key != "ID" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	sb.WriteString("insert")
+	for _, key := range keys {
+		sb.WriteString("\x00")
+		sb.WriteString(key)
+		sb.WriteString("=")
+		sb.WriteString(fmt.Sprintf("%v", bind[key]))
+	}
+	return sb.String()
+}
+
+func (f *flusher) isDuplicatedFlush(entity Entity, bindBuilder *bindBuilder) bool {
+	if !f.engine.flushDeduplication {
+		return false
+	}
+	fingerprint, has := f.engine.flushedFingerprints[entity]
+	return has && fingerprint == getFlushFingerprint(entity.getORM(), bindBuilder.bind)
+}
+
+func (f *flusher) addFlushFingerprint(entity Entity, bindBuilder *bindBuilder, lazy bool) {
+	if !f.engine.flushDeduplication {
+		return
+	}
+	orm := entity.getORM()
+	if !orm.delete && (orm.inDB || !lazy) {
+		return
+	}
+	if f.flushFingerprints == nil {
+		f.flushFingerprints = make(map[Entity]string)
+	}
+	f.flushFingerprints[entity] = getFlushFingerprint(orm, bindBuilder.bind)
+}
+
+func (f *flusher) commitFlushFingerprints() {
+	if len(f.flushFingerprints) == 0 {
+		return
+	}
+	fingerprints := f.flushFingerprints
+	f.flushFingerprints = nil
+	for entity := range fingerprints {
+		if entity.getORM().tableSchema.GetMysql(f.engine).IsInTransaction() {
+			if f.engine.afterCommitFingerprints == nil {
+				f.engine.afterCommitFingerprints = make(map[Entity]string)
+			}
+			for entity, fingerprint := range fingerprints {
+				f.engine.afterCommitFingerprints[entity] = fingerprint
+			}
+			return
+		}
+	}
+	f.engine.addFlushedFingerprints(fingerprints)
+}
+
+func (e *engineImplementation) addFlushedFingerprints(fingerprints map[Entity]string) {
+	if !e.flushDeduplication {
+		return
+	}
+	if e.flushedFingerprints == nil || len(e.flushedFingerprints)+len(fingerprints) > flushFingerprintsLimit {
+		e.flushedFingerprints = make(map[Entity]string)
+	}
+	for entity, fingerprint := range fingerprints {
+		e.flushedFingerprints[entity] = fingerprint
+	}
+}
@@ -0,0 +1,95 @@
+package beeorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type flushDedupEntity struct {
+	ORM  `orm:"localCache;redisCache"`
+	ID   uint
+	Name string
+}
+
+func TestFlushDeduplication(t *testing.T) {
+	var entity *flushDedupEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	dbLogger := &testLogHandler{}
+	engine.RegisterQueryLogger(dbLogger, true, false, false)
+
+	entity = &flushDedupEntity{Name: "a"}
+	engine.Flush(entity)
+	engine.Delete(entity)
+	dbLogger.clear()
+	engine.Delete(entity)
+	assert.Len(t, dbLogger.Logs, 1)
+
+	engine.EnableFlushDeduplication()
+	entity = &flushDedupEntity{Name: "b"}
+	engine.Flush(entity)
+	engine.Flush(entity)
+	engine.Delete(entity)
+	dbLogger.clear()
+	engine.Delete(entity)
+	engine.Delete(entity)
+	assert.Len(t, dbLogger.Logs, 0)
+
+	entity = &flushDedupEntity{Name: "c"}
+	engine.FlushLazy(entity)
+	engine.FlushLazy(entity)
+	entity2 := &flushDedupEntity{Name: "c"}
+	engine.FlushLazy(entity2)
+	receiver := NewBackgroundConsumer(engine)
+	receiver.DisableBlockMode()
+	receiver.blockTime = time.Millisecond
+	dbLogger.clear()
+	receiver.Digest(context.Background())
+	assert.Len(t, dbLogger.Logs, 2)
+
+	entity.Name = "d"
+	engine.FlushLazy(entity)
+	dbLogger.clear()
+	receiver.Digest(context.Background())
+	assert.Len(t, dbLogger.Logs, 1)
+
+	db := engine.GetMysql()
+	db.Begin()
+	entity = &flushDedupEntity{Name: "e"}
+	engine.FlushLazy(entity)
+	assert.NotContains(t, engine.flushedFingerprints, entity)
+	db.Rollback()
+	assert.NotContains(t, engine.flushedFingerprints, entity)
+	assert.Nil(t, engine.afterCommitFingerprints)
+	db.Begin()
+	engine.FlushLazy(entity)
+	db.Commit()
+	assert.Contains(t, engine.flushedFingerprints, entity)
+
+	engine.DisableFlushDeduplication()
+	assert.Nil(t, engine.flushedFingerprints)
+}
+
+func TestFlushFingerprintsLimit(t *testing.T) {
+	engine := &engineImplementation{}
+	engine.EnableFlushDeduplication()
+	fingerprints := make(map[Entity]string, flushFingerprintsLimit)
+	for i := 0; i < flushFingerprintsLimit; i++ {
+		fingerprints[&flushDedupEntity{}] = "insert"
+	}
+	engine.addFlushedFingerprints(fingerprints)
+	assert.Len(t, engine.flushedFingerprints, flushFingerprintsLimit)
+	entity := &flushDedupEntity{}
+	engine.addFlushedFingerprints(map[Entity]string{entity: "insert"})
+	assert.Len(t, engine.flushedFingerprints, 1)
+	assert.Equal(t, "insert", engine.flushedFingerprints[entity])
+}
+
+func TestFlushFingerprint(t *testing.T) {
+	orm := &ORM{}
+	assert.Equal(t, "insert\x00Age=3\x00Name=a", getFlushFingerprint(orm, Bind{"Name": "a", "Age": 3, "ID": 5}))
+	orm.delete = true
+	assert.Equal(t, "delete:0", getFlushFingerprint(orm, Bind{"Name": "a"}))
+}
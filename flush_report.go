@@ -0,0 +1,168 @@
+package beeorm
+
+import (
+	"fmt"
+	"sort"
+)
+
+type FlushPoolStatus int
+
+const (
+	FlushPoolSkipped FlushPoolStatus = iota
+	FlushPoolCommitted
+	FlushPoolRolledBack
+	FlushPoolFailed
+)
+
+type FlushPoolReport struct {
+	Pool    string
+	Status  FlushPoolStatus
+	Error   error
+	pending bool
+}
+
+type FlushReport struct {
+	Pools map[string]*FlushPoolReport
+	Error error
+}
+
+type FlushCompensationHandler func(engine Engine, report *FlushReport)
+
+func newFlushReport(f *flusher) *FlushReport {
+	report := &FlushReport{Pools: make(map[string]*FlushPoolReport)}
+	for _, entity := range f.trackedEntities {
+		report.pool(entity.getORM().tableSchema.mysqlPoolName)
+	}
+	return report
+}
+
+func (r *FlushReport) Committed() []string {
+	return r.poolsWithStatus(FlushPoolCommitted)
+}
+
+func (r *FlushReport) RolledBack() []string {
+	return r.poolsWithStatus(FlushPoolRolledBack)
+}
+
+func (r *FlushReport) Failed() []string {
+	return r.poolsWithStatus(FlushPoolFailed)
+}
+
+func (r *FlushReport) Partial() bool {
+	return len(r.Committed()) > 0 && (len(r.Failed()) > 0 || len(r.RolledBack()) > 0)
+}
+
+func (r *FlushReport) poolsWithStatus(status FlushPoolStatus) []string {
+	pools := make([]string, 0)
+	for code, pool := range r.Pools {
+		if pool.Status == status {
+			pools = append(pools, code)
+		}
+	}
+	sort.Strings(pools)
+	return pools
+}
+
+func (r *FlushReport) pool(code string) *FlushPoolReport {
+	pool, has := r.Pools[code]
+	if !has {
+		pool = &FlushPoolReport{Pool: code}
+		r.Pools[code] = pool
+	}
+	return pool
+}
+
+func (r *FlushReport) executed(code string, inTransaction bool, err error) {
+	pool := r.pool(code)
+	if pool.Status == FlushPoolFailed {
+		return
+	}
+	if err != nil {
+		pool.Status = FlushPoolFailed
+		pool.Error = err
+		return
+	}
+	if inTransaction {
+		pool.pending = true
+	} else {
+		pool.Status = FlushPoolCommitted
+	}
+}
+
+func (r *FlushReport) committed(code string, err error) {
+	pool := r.pool(code)
+	if !pool.pending {
+		return
+	}
+	pool.pending = false
+	if err != nil {
+		pool.Status = FlushPoolFailed
+		pool.Error = err
+		return
+	}
+	pool.Status = FlushPoolCommitted
+}
+
+func (r *FlushReport) rolledBack(code string) {
+	pool := r.pool(code)
+	if !pool.pending {
+		return
+	}
+	pool.pending = false
+	if pool.Status != FlushPoolFailed {
+		pool.Status = FlushPoolRolledBack
+	}
+}
+
+func (f *flusher) SetCompensationHandler(handler FlushCompensationHandler) Flusher {
+	f.compensationHandler = handler
+	return f
+}
+
+func (f *flusher) FlushWithReport() (report *FlushReport) {
+	f.reporting = true
+	defer func() {
+		f.reporting = false
+		report = f.lastReport
+		f.lastReport = nil
+		if report == nil {
+			report = &FlushReport{Pools: make(map[string]*FlushPoolReport)}
+		}
+		if rec := recover(); rec != nil {
+			f.Clear()
+			if report.Error == nil {
+				report.Error = flushPanicToError(rec)
+			}
+		}
+	}()
+	f.flushTrackedEntities(false, false)
+	return
+}
+
+func (f *flusher) startFlushReport() *FlushReport {
+	if !f.reporting && f.compensationHandler == nil {
+		return nil
+	}
+	report := newFlushReport(f)
+	f.engine.flushReport = report
+	f.lastReport = report
+	return report
+}
+
+func (f *flusher) finishFlushReport(report *FlushReport, rec interface{}) {
+	f.engine.flushReport = nil
+	if rec != nil {
+		report.Error = flushPanicToError(rec)
+	}
+	if f.compensationHandler != nil && report.Partial() {
+		f.compensationHandler(f.engine, report)
+	}
+}
+
+func flushPanicToError(rec interface{}) error {
+	asErr, is := rec.(error)
+	if is {
+		return asErr
+	}
+	return fmt.Errorf("%v", rec)
+}
@@ -0,0 +1,101 @@
+package beeorm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type flushReportEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+type flushReportLogEntity struct {
+	ORM  `orm:"mysql=log"`
+	ID   uint
+	Name string `orm:"unique=Name"`
+}
+
+func TestFlushReport(t *testing.T) {
+	var entity *flushReportEntity
+	var logEntity *flushReportLogEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity, logEntity)
+	engine.Flush(&flushReportLogEntity{Name: "taken"})
+
+	flusher := engine.NewFlusher()
+	flusher.Track(&flushReportEntity{Name: "a"}, &flushReportLogEntity{Name: "b"})
+	report := flusher.FlushWithReport()
+	assert.NoError(t, report.Error)
+	assert.Equal(t, []string{"default", "log"}, report.Committed())
+	assert.False(t, report.Partial())
+
+	compensated := false
+	flusher.SetCompensationHandler(func(_ Engine, _ *FlushReport) {
+		compensated = true
+	})
+	flusher.Track(&flushReportEntity{Name: "c"}, &flushReportLogEntity{Name: "taken"})
+	report = flusher.FlushWithReport()
+	assert.IsType(t, &DuplicatedKeyError{}, report.Error)
+	assert.Equal(t, []string{"log"}, report.Failed())
+	assert.NotNil(t, report.Pools["log"].Error)
+	assert.Len(t, report.Committed(), 0)
+	assert.False(t, report.Partial())
+	assert.False(t, compensated)
+	assert.False(t, engine.LoadByID(2, &flushReportEntity{}))
+	assert.Nil(t, engine.flushReport)
+
+	report = engine.NewFlusher().FlushWithReport()
+	assert.NotNil(t, report)
+	assert.Len(t, report.Pools, 0)
+}
+
+func TestFlushReportStatuses(t *testing.T) {
+	report := &FlushReport{Pools: make(map[string]*FlushPoolReport)}
+	report.executed("default", false, nil)
+	report.executed("log", true, nil)
+	report.executed("other", true, nil)
+	report.committed("log", nil)
+	report.rolledBack("other")
+	report.rolledBack("default")
+	report.committed("missing", nil)
+	assert.Equal(t, []string{"default", "log"}, report.Committed())
+	assert.Equal(t, []string{"other"}, report.RolledBack())
+	assert.True(t, report.Partial())
+	assert.Equal(t, FlushPoolSkipped, report.Pools["missing"].Status)
+
+	report = &FlushReport{Pools: make(map[string]*FlushPoolReport)}
+	report.executed("default", true, nil)
+	report.committed("default", fmt.Errorf("commit failed"))
+	report.executed("log", true, fmt.Errorf("insert failed"))
+	report.executed("log", true, nil)
+	report.rolledBack("log")
+	assert.Equal(t, []string{"default", "log"}, report.Failed())
+	assert.EqualError(t, report.Pools["log"].Error, "insert failed")
+	assert.False(t, report.Partial())
+}
+
+func TestFlushReportCompensation(t *testing.T) {
+	f := &flusher{engine: &engineImplementation{}}
+	var handled *FlushReport
+	f.SetCompensationHandler(func(engine Engine, report *FlushReport) {
+		handled = report
+	})
+	report := f.startFlushReport()
+	assert.Same(t, report, f.engine.flushReport)
+	report.executed("default", false, nil)
+	report.executed("log", false, fmt.Errorf("failed"))
+	f.finishFlushReport(report, "panic value")
+	assert.Nil(t, f.engine.flushReport)
+	assert.Same(t, report, handled)
+	assert.EqualError(t, report.Error, "panic value")
+
+	handled = nil
+	report = f.startFlushReport()
+	report.executed("default", false, nil)
+	f.finishFlushReport(report, nil)
+	assert.Nil(t, handled)
+	assert.NoError(t, report.Error)
+}
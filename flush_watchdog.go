@@ -0,0 +1,110 @@
+package beeorm
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+const flushWatchdogPluginCode = "beeorm/flush_watchdog"
+
+type FlushWatchdogAlert struct {
+	TableSchema TableSchema
+	ID          uint64
+	Flushes     int
+	Threshold   int
+	Interval    time.Duration
+}
+
+type FlushWatchdogHandler func(engine Engine, alert *FlushWatchdogAlert)
+
+type FlushWatchdog struct {
+	interval   time.Duration
+	threshold  int
+	thresholds map[reflect.Type]int
+	handler    FlushWatchdogHandler
+	counters   map[reflect.Type]map[uint64]*flushWatchdogCounter
+	lastSweep  time.Time
+	mutex      sync.Mutex
+}
+
+type flushWatchdogCounter struct {
+	started time.Time
+	flushes int
+}
+
+func NewFlushWatchdog(interval time.Duration, threshold int, handler FlushWatchdogHandler) *FlushWatchdog {
+	return &FlushWatchdog{
+		interval:   interval,
+		threshold:  threshold,
+		thresholds: make(map[reflect.Type]int),
+		handler:    handler,
+		counters:   make(map[reflect.Type]map[uint64]*flushWatchdogCounter),
+		lastSweep:  time.Now(),
+	}
+}
+
+func (w *FlushWatchdog) GetCode() string {
+	return flushWatchdogPluginCode
+}
+
+func (w *FlushWatchdog) SetThreshold(entity Entity, threshold int) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	t := reflect.TypeOf(entity)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	w.thresholds[t] = threshold
+}
+
+func (w *FlushWatchdog) PluginInterfaceEntityFlushed(engine Engine, event *EntityFlushedEvent) {
+	if event.ID == 0 {
+		return
+	}
+	t := event.TableSchema.GetType()
+	w.mutex.Lock()
+	threshold, has := w.thresholds[t]
+	if !has {
+		threshold = w.threshold
+	}
+	if threshold <= 0 {
+		w.mutex.Unlock()
+		return
+	}
+	now := time.Now()
+	if now.Sub(w.lastSweep) >= w.interval {
+		w.sweep(now)
+	}
+	rows, has := w.counters[t]
+	if !has {
+		rows = make(map[uint64]*flushWatchdogCounter)
+		w.counters[t] = rows
+	}
+	counter, has := rows[event.ID]
+	if !has || now.Sub(counter.started) >= w.interval {
+		counter = &flushWatchdogCounter{started: now}
+		rows[event.ID] = counter
+	}
+	counter.flushes++
+	flushes := counter.flushes
+	w.mutex.Unlock()
+	if flushes == threshold+1 && w.handler != nil {
+		w.handler(engine, &FlushWatchdogAlert{TableSchema: event.TableSchema, ID: event.ID, Flushes: flushes,
+			Threshold: threshold, Interval: w.interval})
+	}
+}
+
+func (w *FlushWatchdog) sweep(now time.Time) {
+	for t, rows := range w.counters {
+		for id, counter := range rows {
+			if now.Sub(counter.started) >= w.interval {
+				delete(rows, id)
+			}
+		}
+		if len(rows) == 0 {
+			delete(w.counters, t)
+		}
+	}
+	w.lastSweep = now
+}
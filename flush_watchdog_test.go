@@ -0,0 +1,67 @@
+package beeorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type flushWatchdogEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+type flushWatchdogEntitySkipped struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestFlushWatchdog(t *testing.T) {
+	alerts := make([]*FlushWatchdogAlert, 0)
+	watchdog := NewFlushWatchdog(time.Minute, 2, func(engine Engine, alert *FlushWatchdogAlert) {
+		alerts = append(alerts, alert)
+	})
+	watchdog.SetThreshold(&flushWatchdogEntitySkipped{}, 0)
+	registry := &Registry{}
+	registry.RegisterPlugin(watchdog)
+	assert.PanicsWithError(t, "plugin 'beeorm/flush_watchdog' already registered", func() {
+		registry.RegisterPlugin(watchdog)
+	})
+	var entity *flushWatchdogEntity
+	var skipped *flushWatchdogEntitySkipped
+	engine := prepareTables(t, registry, 5, 6, "", entity, skipped)
+	assert.Equal(t, watchdog, engine.GetRegistry().GetPlugin(flushWatchdogPluginCode))
+	assert.Nil(t, engine.GetRegistry().GetPlugin("missing"))
+
+	entity = &flushWatchdogEntity{Name: "a"}
+	skipped = &flushWatchdogEntitySkipped{Name: "a"}
+	engine.Flush(entity, skipped)
+	assert.Len(t, alerts, 0)
+	entity.Name = "b"
+	skipped.Name = "b"
+	engine.Flush(entity, skipped)
+	assert.Len(t, alerts, 0)
+	entity.Name = "c"
+	skipped.Name = "c"
+	engine.Flush(entity, skipped)
+	assert.Len(t, alerts, 1)
+	assert.Equal(t, uint64(1), alerts[0].ID)
+	assert.Equal(t, 3, alerts[0].Flushes)
+	assert.Equal(t, 2, alerts[0].Threshold)
+	assert.Equal(t, time.Minute, alerts[0].Interval)
+	assert.Equal(t, "flushWatchdogEntity", alerts[0].TableSchema.GetTableName())
+
+	entity.Name = "d"
+	engine.Flush(entity)
+	assert.Len(t, alerts, 1)
+
+	engine.Delete(entity)
+	assert.Len(t, alerts, 1)
+
+	entity2 := &flushWatchdogEntity{Name: "e"}
+	engine.FlushLazy(entity2)
+	assert.Len(t, alerts, 1)
+}
@@ -38,6 +38,9 @@ type Flusher interface {
 	Delete(entity ...Entity) Flusher
 	ForceDelete(entity ...Entity) Flusher
 	CancelDelete(entity ...Entity) Flusher
+	FlushWithReport() *FlushReport
+	SetCompensationHandler(handler FlushCompensationHandler) Flusher
+	Release()
 }
 
 type flusher struct {
@@ -52,6 +55,17 @@ type flusher struct {
 	localCacheSets         map[string][]interface{}
 	stringBuilder          strings.Builder
 	serializer             *serializer
+	flushedEvents          []*EntityFlushedEvent
+	translations           []pendingTranslation
+	translationRestores    []translationRestore
+	treeMoves              []treeMove
+	orphanedFiles          map[string][]string
+	flushFingerprints      map[Entity]string
+	strictCacheDeletes     *cacheDeletes
+	doubleDeletes          map[time.Duration]*cacheDeletes
+	reporting              bool
+	lastReport             *FlushReport
+	compensationHandler    FlushCompensationHandler
 }
 
 func (f *flusher) Track(entity ...Entity) Flusher {
@@ -110,7 +124,10 @@ func (f *flusher) FlushWithCheck() error {
 }
 
 func (f *flusher) FlushWithFullCheck() error {
-	var err error
+	err := f.validateTrackedEntities()
+	if err != nil {
+		return err
+	}
 	func() {
 		defer func() {
 			if r := recover(); r != nil {
@@ -135,20 +152,37 @@ func (f *flusher) Clear() {
 	f.deleteBinds = nil
 	f.localCacheDeletes = nil
 	f.localCacheSets = nil
+	f.flushedEvents = nil
+	f.engine.queryMasks = nil
+	f.translations = nil
+	f.translationRestores = nil
+	f.treeMoves = nil
+	f.orphanedFiles = nil
+	f.flushFingerprints = nil
+	f.strictCacheDeletes = nil
+	f.doubleDeletes = nil
 }
 
 func (f *flusher) flushTrackedEntities(lazy bool, transaction bool) {
 	if f.trackedEntitiesCounter == 0 {
 		return
 	}
+	if !transaction {
+		lazy = f.applyWriteFreeze(lazy)
+	}
+	if report := f.startFlushReport(); report != nil {
+		defer func() {
+			rec := recover()
+			f.finishFlushReport(report, rec)
+			if rec != nil {
+				panic(rec)
+			}
+		}()
+	}
 	var dbPools map[string]*DB
 	executed := false
 	if transaction {
-		dbPools = make(map[string]*DB)
-		for _, entity := range f.trackedEntities {
-			db := entity.getORM().tableSchema.GetMysql(f.engine)
-			dbPools[db.GetPoolConfig().GetCode()] = db
-		}
+		dbPools = f.getTransactionPools(lazy)
 		for _, db := range dbPools {
 			db.Begin()
 		}
@@ -156,11 +190,7 @@ func (f *flusher) flushTrackedEntities(lazy bool, transaction bool) {
 	defer func() {
 		if !executed {
 			if dbPools == nil {
-				dbPools = make(map[string]*DB)
-				for _, entity := range f.trackedEntities {
-					db := entity.getORM().tableSchema.GetMysql(f.engine)
-					dbPools[db.GetPoolConfig().GetCode()] = db
-				}
+				dbPools = f.getTransactionPools(lazy)
 			}
 			for _, db := range dbPools {
 				db.Rollback()
@@ -168,28 +198,35 @@ func (f *flusher) flushTrackedEntities(lazy bool, transaction bool) {
 		}
 	}()
 	useTransaction := f.flush(true, lazy, transaction, f.trackedEntities...)
+	f.emitFlushedEventsInTransaction()
 	if transaction {
 		for _, db := range dbPools {
 			db.Commit()
 		}
 	} else if useTransaction {
 		if dbPools == nil {
-			dbPools = make(map[string]*DB)
-			for _, entity := range f.trackedEntities {
-				db := entity.getORM().tableSchema.GetMysql(f.engine)
-				dbPools[db.GetPoolConfig().GetCode()] = db
-			}
+			dbPools = f.getTransactionPools(lazy)
 		}
 		for _, db := range dbPools {
 			db.Commit()
 		}
 	}
 	executed = true
+	f.commitFlushFingerprints()
+	f.flushTreeMoves()
+	f.flushTranslations()
+	f.flushOrphanedFiles()
+	flushedEvents := f.flushedEvents
 	f.Clear()
+	f.flushedEvents = flushedEvents
+	f.emitFlushedEvents()
 }
 
 func (f *flusher) flushWithCheck(transaction bool) error {
-	var err error
+	err := f.validateTrackedEntities()
+	if err != nil {
+		return err
+	}
 	func() {
 		defer func() {
 			if r := recover(); r != nil {
@@ -205,6 +242,16 @@ func (f *flusher) flushWithCheck(transaction bool) error {
 					err = assErr2
 					return
 				}
+				assErr3, is := asErr.(*WriteFrozenError)
+				if is {
+					err = assErr3
+					return
+				}
+				assErr4, is := asErr.(*ValidationError)
+				if is {
+					err = assErr4
+					return
+				}
 				panic(asErr)
 			}
 		}()
@@ -240,6 +287,9 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 	for _, entity := range entities {
 		initIfNeeded(f.engine.registry, entity)
 		schema := entity.getORM().tableSchema
+		if schema.projectionOf != nil {
+			panic(fmt.Errorf("projection '%s' is read-only", schema.t.String()))
+		}
 		if !transaction && schema.GetMysql(f.engine).inTransaction {
 			transaction = true
 		}
@@ -248,21 +298,60 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 		}
 
 		orm := entity.getORM()
+		if schema.treeParentColumn != "" && !orm.delete {
+			f.fillTreePath(orm)
+		}
+		if orm.fakeDelete && !schema.hasFakeDelete && schema.hasSoftDelete {
+			field := orm.elem.FieldByName(schema.softDeleteColumn)
+			if field.IsNil() {
+				now := f.engine.now()
+				field.Set(reflect.ValueOf(&now))
+			}
+		}
 		bindBuilder, isDirty := orm.buildDirtyBind(f.getSerializer())
-		if !isDirty {
+		if !isDirty || f.isDuplicatedFlush(entity, bindBuilder) {
 			continue
 		}
+		if f.engine.owner != "" && schema.owner != "" && schema.owner != f.engine.owner {
+			panic(fmt.Errorf("entity '%s' is owned by '%s'", schema.t.String(), schema.owner))
+		}
+		if len(schema.translatedColumns) > 0 {
+			f.extractTranslations(orm, bindBuilder)
+			if orm.inDB && !orm.delete && len(bindBuilder.bind) == 0 {
+				continue
+			}
+		}
+		if !orm.delete {
+			changed := orm.fillTimestamps(f.engine, bindBuilder.bind)
+			if !orm.inDB && orm.fillDefaults() {
+				changed = true
+			}
+			if !orm.inDB && orm.fillUUIDs() {
+				changed = true
+			}
+			if changed {
+				bindBuilder, _ = orm.buildDirtyBind(f.getSerializer())
+			}
+		}
+		if schema.treeParentColumn != "" {
+			f.addTreeMove(orm, bindBuilder)
+		}
 
 		t := orm.tableSchema.t
 		currentID := entity.GetID()
-		if orm.fakeDelete && !orm.tableSchema.hasFakeDelete {
+		if orm.fakeDelete && !orm.tableSchema.hasFakeDelete && !orm.tableSchema.hasSoftDelete {
 			orm.delete = true
 		}
+		if len(schema.fileColumns) > 0 {
+			f.addOrphanedFiles(orm, bindBuilder)
+		}
+		f.addFlushFingerprint(entity, bindBuilder, lazy)
+		f.engine.invalidateReferenceBatch(schema)
 		if orm.delete {
 			f.flushDelete(t, currentID, entity)
 		} else if !orm.inDB {
 			if currentID == 0 && schema.hasUUID {
-				currentID = uuid()
+				currentID = schema.nextID()
 				orm.idElem.SetUint(currentID)
 			}
 			if currentID > 0 {
@@ -276,7 +365,8 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 			}
 			f.flushInsert(t, bindBuilder, flushPackage, entity)
 		} else {
-			f.flushUpdate(entity, bindBuilder, currentID, schema, lazy)
+			// write behind updates in transaction are not lazy, they must be rolled back with the transaction
+			f.flushUpdate(entity, bindBuilder, currentID, schema, lazy || (schema.cacheMode == cacheModeWriteBehind && !transaction))
 		}
 	}
 
@@ -296,11 +386,14 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 				}
 			}
 		}
-		if diffs > 1 {
-			f.startTransaction()
+		if diffs > 1 || f.requiresPluginTransaction(lazy, entities) {
+			f.startTransaction(lazy)
 			useTransaction = true
 		}
 	}
+	if !lazy {
+		f.applyStrictCacheDeletes(useTransaction || transaction)
+	}
 	f.executeDeletes(lazy)
 	f.executeInserts(flushPackage, lazy)
 	if root {
@@ -308,6 +401,9 @@ func (f *flusher) flush(root bool, lazy bool, transaction bool, entities ...Enti
 		f.updateLocalCache(lazy, useTransaction || transaction)
 	}
 	f.updateRedisCache(root, lazy, useTransaction || transaction)
+	if root && !lazy {
+		f.applyDoubleDeletes(useTransaction || transaction)
+	}
 	return useTransaction
 }
 
@@ -325,6 +421,9 @@ func (f *flusher) updateRedisCache(root bool, lazy bool, transaction bool) {
 				deletesRedisCache[cacheCode] = commands.deletes
 			}
 		}
+		if len(f.getRedisFlusher().variants) > 0 {
+			lazyMap["cv"] = f.getRedisFlusher().variants
+		}
 		if transaction {
 			f.engine.afterCommitRedisFlusher = f.getRedisFlusher()
 		}
@@ -368,23 +467,7 @@ func (f *flusher) executeDeletes(lazy bool) {
 	for typeOf, deleteBinds := range f.deleteBinds {
 		queryExecuted := false
 		schema := getTableSchema(f.engine.registry, typeOf)
-		i := 0
-		f.stringBuilder.WriteString("DELETE FROM `")
-		f.stringBuilder.WriteString(schema.tableName)
-		f.stringBuilder.WriteString("` WHERE `ID` IN (")
-		deleteSQLPrefix := f.stringBuilder.String()
-		if !lazy {
-			for id := range deleteBinds {
-				if i > 0 {
-					f.stringBuilder.WriteString(",")
-				}
-				f.stringBuilder.WriteString(strconv.FormatUint(id, 10))
-				i++
-			}
-		}
-		f.stringBuilder.WriteString(")")
-		deleteSQL := f.stringBuilder.String()
-		f.stringBuilder.Reset()
+		deleteSQLPrefix := "DELETE FROM `" + schema.tableName + "` WHERE `ID` IN ("
 		db := schema.GetMysql(f.engine)
 		localCache, hasLocalCache := schema.GetLocalCache(f.engine)
 		redisCache, hasRedis := schema.GetRedisCache(f.engine)
@@ -393,11 +476,21 @@ func (f *flusher) executeDeletes(lazy bool) {
 			localCache = f.engine.GetLocalCache(requestCacheKey)
 		}
 		for id, entity := range deleteBinds {
+			if !lazy {
+				f.addDoubleDelete(schema, id)
+			}
 			orm := entity.getORM()
 			bindBuilder, _ := orm.buildDirtyBind(f.getSerializer())
+			f.addFlushedEvent(FlushTypeDelete, schema, id, bindBuilder.current, nil, lazy)
 			if !lazy {
 				if !queryExecuted {
-					_ = db.Exec(deleteSQL)
+					ids := make([]uint64, 0, len(deleteBinds))
+					for deleteID := range deleteBinds {
+						ids = append(ids, deleteID)
+					}
+					for _, chunk := range chunkIDs(ids, db.GetPoolConfig().getIDsChunkSize(len(deleteSQLPrefix)+1)) {
+						_ = db.Exec(deleteSQLPrefix + joinIDs(chunk) + ")")
+					}
 					queryExecuted = true
 				}
 				f.addToLogQueue(schema, id, bindBuilder.current, nil, entity.getORM().logMeta, lazy)
@@ -407,10 +500,13 @@ func (f *flusher) executeDeletes(lazy bool) {
 				if logEvent != nil {
 					logEvents = append(logEvents, logEvent)
 				}
-				f.fillLazyQuery(db.GetPoolConfig().GetCode(), deleteSQLPrefix+strconv.FormatUint(id, 10)+")", false, id, logEvents)
+				f.fillLazyQuery(db.GetPoolConfig().GetCode(), schema.tableName, deleteSQLPrefix+strconv.FormatUint(id, 10)+")", "", false, id, logEvents)
 			}
+			f.invalidateTableCacheTag(schema)
+			f.invalidateProjections(schema, id)
+			f.engine.removeFromIdentityMap(schema, id)
 			if hasLocalCache || hasRedis {
-				cacheKey := schema.getCacheKey(id)
+				cacheKey := schema.getCacheKey(f.engine, id)
 				keys := f.getCacheQueriesKeys(schema, bindBuilder.bind, bindBuilder.current, true, true)
 				if hasLocalCache {
 					f.addLocalCacheSet(localCache.config.GetCode(), cacheKey, cacheNilValue)
@@ -419,6 +515,7 @@ func (f *flusher) executeDeletes(lazy bool) {
 				if hasRedis {
 					f.getRedisFlusher().Del(redisCache.config.GetCode(), cacheKey)
 					f.getRedisFlusher().Del(redisCache.config.GetCode(), keys...)
+					f.addCacheKeyVariantsDeletes(schema, redisCache, id)
 				}
 			}
 		}
@@ -441,40 +538,14 @@ func (f *flusher) executeUpdates() {
 func (f *flusher) executeInserts(flushPackage *flushPackage, lazy bool) {
 	for typeOf, values := range flushPackage.insertKeys {
 		schema := getTableSchema(f.engine.registry, typeOf)
-		f.stringBuilder.WriteString("INSERT INTO `")
-		f.stringBuilder.WriteString(schema.tableName)
-		f.stringBuilder.WriteString("`")
-		l := len(values)
-		if l > 0 {
-			f.stringBuilder.WriteString("(")
-		}
-		first := true
-		for _, val := range values {
-			if !first {
-				f.stringBuilder.WriteString(",")
-			}
-			first = false
-			f.stringBuilder.WriteString("`" + val + "`")
-		}
-		if l > 0 {
-			f.stringBuilder.WriteString(")")
-		}
-		f.stringBuilder.WriteString(" VALUES ")
-		for i, row := range flushPackage.insertSQLBinds[typeOf] {
-			if i > 0 {
-				f.stringBuilder.WriteString(",")
-			}
-			f.stringBuilder.WriteString("(")
-			for j, val := range values {
-				if j > 0 {
-					f.stringBuilder.WriteString(",")
-				}
-				f.stringBuilder.WriteString(row[val])
+		sql := f.buildInsertSQL(schema, values, flushPackage.insertSQLBinds[typeOf], false)
+		maskedSQL := ""
+		if f.queryMaskRequired(schema, lazy) {
+			maskedSQL = f.buildInsertSQL(schema, values, flushPackage.insertSQLBinds[typeOf], true)
+			if !lazy {
+				f.engine.addQueryMask(sql, maskedSQL)
 			}
-			f.stringBuilder.WriteString(")")
 		}
-		sql := f.stringBuilder.String()
-		f.stringBuilder.Reset()
 		db := schema.GetMysql(f.engine)
 		if lazy {
 			var logEvents []*LogQueueValue
@@ -486,8 +557,9 @@ func (f *flusher) executeInserts(flushPackage *flushPackage, lazy bool) {
 				if logEvent != nil {
 					logEvents = append(logEvents, logEvent)
 				}
+				f.addFlushedEvent(FlushTypeInsert, schema, entity.GetID(), nil, flushPackage.insertBinds[typeOf][key], lazy)
 			}
-			f.fillLazyQuery(db.GetPoolConfig().GetCode(), sql, true, 0, logEvents)
+			f.fillLazyQuery(db.GetPoolConfig().GetCode(), schema.tableName, sql, maskedSQL, true, 0, logEvents)
 		} else {
 			res := db.Exec(sql)
 			id := res.LastInsertId()
@@ -504,9 +576,52 @@ func (f *flusher) executeInserts(flushPackage *flushPackage, lazy bool) {
 				}
 				orm.serialize(f.getSerializer())
 				f.updateCacheForInserted(entity, lazy, insertedID, bind)
+				f.addFlushedEvent(FlushTypeInsert, schema, insertedID, nil, bind, lazy)
+			}
+		}
+	}
+}
+
+func (f *flusher) buildInsertSQL(schema *tableSchema, values []string, rows []map[string]string, mask bool) string {
+	f.stringBuilder.WriteString("INSERT INTO `")
+	f.stringBuilder.WriteString(schema.tableName)
+	f.stringBuilder.WriteString("`")
+	l := len(values)
+	if l > 0 {
+		f.stringBuilder.WriteString("(")
+	}
+	first := true
+	for _, val := range values {
+		if !first {
+			f.stringBuilder.WriteString(",")
+		}
+		first = false
+		f.stringBuilder.WriteString("`" + schema.getColumnName(val) + "`")
+	}
+	if l > 0 {
+		f.stringBuilder.WriteString(")")
+	}
+	f.stringBuilder.WriteString(" VALUES ")
+	for i, row := range rows {
+		if i > 0 {
+			f.stringBuilder.WriteString(",")
+		}
+		f.stringBuilder.WriteString("(")
+		for j, val := range values {
+			if j > 0 {
+				f.stringBuilder.WriteString(",")
+			}
+			if mask {
+				f.stringBuilder.WriteString(schema.maskSQLValue(val, row[val]))
+			} else {
+				f.stringBuilder.WriteString(row[val])
 			}
 		}
+		f.stringBuilder.WriteString(")")
 	}
+	sql := f.stringBuilder.String()
+	f.stringBuilder.Reset()
+	return sql
 }
 
 func (f *flusher) flushInsert(t reflect.Type, bindBuilder *bindBuilder, flushPackage *flushPackage, entity Entity) {
@@ -539,15 +654,46 @@ func (f *flusher) flushDelete(t reflect.Type, currentID uint64, entity Entity) {
 	f.deleteBinds[t][currentID] = entity
 }
 
-func (f *flusher) startTransaction() {
+func (f *flusher) startTransaction(lazy bool) {
+	for _, db := range f.getTransactionPools(lazy) {
+		db.Begin()
+	}
+}
+
+func (f *flusher) getTransactionPools(lazy bool) map[string]*DB {
 	dbPools := make(map[string]*DB)
 	for _, entity := range f.trackedEntities {
-		db := entity.getORM().tableSchema.GetMysql(f.engine)
+		schema := entity.getORM().tableSchema
+		db := schema.GetMysql(f.engine)
 		dbPools[db.GetPoolConfig().GetCode()] = db
+		if !lazy && f.engine.registry.hasFlushedInTransactionPlugin {
+			if schema.hasAudit {
+				dbPools[schema.auditPoolName] = f.engine.GetMysql(schema.auditPoolName)
+			}
+			if schema.hasTemporal {
+				dbPools[schema.temporalPoolName] = f.engine.GetMysql(schema.temporalPoolName)
+			}
+		}
 	}
-	for _, db := range dbPools {
-		db.Begin()
+	return dbPools
+}
+
+// requiresPluginTransaction reports if in-transaction plugins write rows for flushed entities
+func (f *flusher) requiresPluginTransaction(lazy bool, entities []Entity) bool {
+	if lazy || !f.engine.registry.hasFlushedInTransactionPlugin {
+		return false
+	}
+	required := false
+	for _, entity := range entities {
+		schema := entity.getORM().tableSchema
+		if schema.GetMysql(f.engine).IsInTransaction() {
+			return false
+		}
+		if schema.hasAudit || schema.hasTemporal {
+			required = true
+		}
 	}
+	return required
 }
 
 func (f *flusher) flushReferences(flushPackage *flushPackage, lazy bool, transaction bool, entities []Entity) bool {
@@ -556,7 +702,7 @@ func (f *flusher) flushReferences(flushPackage *flushPackage, lazy bool, transac
 			panic(fmt.Errorf("lazy flush for unsaved references is not supported"))
 		}
 		if !transaction {
-			f.startTransaction()
+			f.startTransaction(lazy)
 		}
 		toFlush := make([]Entity, len(flushPackage.referencesToFlash))
 		i := 0
@@ -584,6 +730,8 @@ func (f *flusher) flushUpdate(entity Entity, bindBuilder *bindBuilder, currentID
 	if !entity.IsLoaded() {
 		panic(fmt.Errorf("entity is not loaded and can't be updated: %v [%d]", entity.getORM().elem.Type().String(), currentID))
 	}
+	mask := f.queryMaskRequired(schema, lazy)
+	var maskedBuilder strings.Builder
 	f.stringBuilder.WriteString("UPDATE `")
 	f.stringBuilder.WriteString(schema.GetTableName())
 	f.stringBuilder.WriteString("` SET ")
@@ -591,15 +739,29 @@ func (f *flusher) flushUpdate(entity Entity, bindBuilder *bindBuilder, currentID
 	for key, value := range bindBuilder.sqlBind {
 		if !first {
 			f.stringBuilder.WriteString(",")
+			if mask {
+				maskedBuilder.WriteString(",")
+			}
 		}
 		first = false
-		f.stringBuilder.WriteString("`" + key + "`=" + value)
+		f.stringBuilder.WriteString("`" + schema.getColumnName(key) + "`=" + value)
+		if mask {
+			maskedBuilder.WriteString("`" + schema.getColumnName(key) + "`=" + schema.maskSQLValue(key, value))
+		}
 	}
-	f.stringBuilder.WriteString(" WHERE `ID` = ")
-	f.stringBuilder.WriteString(strconv.FormatUint(currentID, 10))
+	where := " WHERE `ID` = " + strconv.FormatUint(currentID, 10)
+	f.stringBuilder.WriteString(where)
 	sql := f.stringBuilder.String()
 	f.stringBuilder.Reset()
+	maskedSQL := ""
+	if mask {
+		maskedSQL = "UPDATE `" + schema.GetTableName() + "` SET " + maskedBuilder.String() + where
+		if !lazy {
+			f.engine.addQueryMask(sql, maskedSQL)
+		}
+	}
 	db := schema.GetMysql(f.engine)
+	f.addFlushedEvent(FlushTypeUpdate, schema, currentID, bindBuilder.current, bindBuilder.bind, lazy)
 	if lazy {
 		var logEvents []*LogQueueValue
 		entity.getORM().serialize(f.getSerializer())
@@ -607,7 +769,7 @@ func (f *flusher) flushUpdate(entity Entity, bindBuilder *bindBuilder, currentID
 		if logEvent != nil {
 			logEvents = append(logEvents, logEvent)
 		}
-		f.fillLazyQuery(db.GetPoolConfig().GetCode(), sql, false, currentID, logEvents)
+		f.fillLazyQuery(db.GetPoolConfig().GetCode(), schema.tableName, sql, maskedSQL, false, currentID, logEvents)
 	} else {
 		if f.updateSQLs == nil {
 			f.updateSQLs = make(map[string][]string)
@@ -631,9 +793,17 @@ func (f *flusher) flushOnDuplicateKey(lazy bool, bindBuilder *bindBuilder, schem
 	columns := make([]string, bindLength)
 	i := 0
 
+	mask := f.queryMaskRequired(schema, lazy)
+	var maskedValues []string
+	if mask {
+		maskedValues = make([]string, bindLength)
+	}
 	for key, val := range bindBuilder.sqlBind {
-		columns[i] = "`" + key + "`"
+		columns[i] = "`" + schema.getColumnName(key) + "`"
 		values[i] = val
+		if mask {
+			maskedValues[i] = schema.maskSQLValue(key, val)
+		}
 		i++
 	}
 	f.stringBuilder.WriteString("INSERT INTO ")
@@ -646,21 +816,31 @@ func (f *flusher) flushOnDuplicateKey(lazy bool, bindBuilder *bindBuilder, schem
 	/* #nosec */
 	f.stringBuilder.WriteString(" ON DUPLICATE KEY UPDATE ")
 	first := true
+	var maskedUpdate strings.Builder
 	for k, v := range onUpdate {
 		if !first {
 			f.stringBuilder.WriteString(",")
+			maskedUpdate.WriteString(",")
 		}
 		f.stringBuilder.WriteString("`")
-		f.stringBuilder.WriteString(k)
+		f.stringBuilder.WriteString(schema.getColumnName(k))
 		f.stringBuilder.WriteString("` = ")
 		f.stringBuilder.WriteString(escapeSQLValue(v))
+		if mask {
+			maskedUpdate.WriteString("`" + schema.getColumnName(k) + "` = " + schema.maskSQLValue(k, escapeSQLValue(v)))
+		}
 		first = false
 	}
 	if len(onUpdate) == 0 {
 		f.stringBuilder.WriteString("ID = ID")
+		maskedUpdate.WriteString("ID = ID")
 	}
 	sql := f.stringBuilder.String()
 	f.stringBuilder.Reset()
+	if mask {
+		f.engine.addQueryMask(sql, "INSERT INTO "+schema.tableName+"("+strings.Join(columns, ",")+") VALUES ("+
+			strings.Join(maskedValues, ",")+") ON DUPLICATE KEY UPDATE "+maskedUpdate.String())
+	}
 	db := schema.GetMysql(f.engine)
 	result := db.Exec(sql)
 	affected := result.RowsAffected()
@@ -673,6 +853,7 @@ func (f *flusher) flushOnDuplicateKey(lazy bool, bindBuilder *bindBuilder, schem
 		orm.serialize(f.getSerializer())
 		if affected == 1 {
 			f.updateCacheForInserted(entity, lazy, lastID, bindBuilder.bind)
+			f.addFlushedEvent(FlushTypeInsert, schema, lastID, nil, bindBuilder.bind, lazy)
 		} else {
 			for k, v := range onUpdate {
 				err := entity.SetField(k, v)
@@ -681,6 +862,7 @@ func (f *flusher) flushOnDuplicateKey(lazy bool, bindBuilder *bindBuilder, schem
 			bindBuilderNew, _ := orm.buildDirtyBind(f.getSerializer())
 			_, _ = loadByID(f.getSerializer(), f.engine, lastID, entity, false)
 			f.updateCacheAfterUpdate(entity, bindBuilderNew.bind, bindBuilderNew.current, schema, lastID, false)
+			f.addFlushedEvent(FlushTypeUpdate, schema, lastID, bindBuilderNew.current, bindBuilderNew.bind, lazy)
 		}
 	} else {
 	OUTER:
@@ -691,7 +873,7 @@ func (f *flusher) flushOnDuplicateKey(lazy bool, bindBuilder *bindBuilder, schem
 				if bindBuilder.bind[column] == nil {
 					continue OUTER
 				}
-				fields = append(fields, "`"+column+"` = ?")
+				fields = append(fields, "`"+schema.getColumnName(column)+"` = ?")
 				binds = append(binds, bindBuilder.bind[column])
 			}
 			findWhere := NewWhere(strings.Join(fields, " AND "), binds)
@@ -741,28 +923,40 @@ func (f *flusher) updateCacheForInserted(entity Entity, lazy bool, id uint64, bi
 	}
 	redisCache, hasRedis := schema.GetRedisCache(f.engine)
 	if hasLocalCache || hasRedis {
-		cacheKey := schema.getCacheKey(id)
+		cacheKey := schema.getCacheKey(f.engine, id)
 		keys := f.getCacheQueriesKeys(schema, bind, nil, false, true)
 		if hasLocalCache {
-			if !lazy || schema.hasUUID {
+			if (!lazy || schema.hasUUID) && len(schema.generatedColumns) == 0 && schema.cacheMode != cacheModeAside {
 				f.addLocalCacheSet(localCache.config.GetCode(), cacheKey, entity.getORM().copyBinary())
 			} else {
-				f.addLocalCacheDeletes(localCache.config.GetCode(), schema.getCacheKey(id))
+				f.addLocalCacheDeletes(localCache.config.GetCode(), schema.getCacheKey(f.engine, id))
 			}
 			f.addLocalCacheDeletes(localCache.config.GetCode(), keys...)
 		}
 		if hasRedis {
-			if schema.hasUUID {
-				f.getRedisFlusher().Set(redisCache.config.GetCode(), cacheKey, entity.getORM().binary)
+			if schema.hasUUID && len(schema.generatedColumns) == 0 && !schema.hasCacheKeyVariants() {
+				f.getRedisFlusher().Set(redisCache.config.GetCode(), cacheKey, schema.getRedisCacheValue(entity.getORM().binary), schema.cacheTTL)
 			} else {
 				f.getRedisFlusher().Del(redisCache.config.GetCode(), cacheKey)
 			}
 			f.getRedisFlusher().Del(redisCache.config.GetCode(), keys...)
+			f.addCacheKeyVariantsDeletes(schema, redisCache, id)
 		}
 	}
+	f.invalidateTableCacheTag(schema)
+	f.invalidateProjections(schema, id)
 	return f.addToLogQueue(schema, id, nil, bind, entity.getORM().logMeta, lazy)
 }
 
+// addCacheKeyVariantsDeletes removes cached entity in every cache key dimension.
+// Entities with cache key providers are never written to Redis by flusher, only loaded keys are tracked.
+func (f *flusher) addCacheKeyVariantsDeletes(schema *tableSchema, redisCache *RedisCache, id uint64) {
+	if !schema.hasCacheKeyVariants() || id == 0 {
+		return
+	}
+	f.getRedisFlusher().DelCacheKeyVariants(redisCache.config.GetCode(), schema.getCacheKeyVariantsKey(f.engine, id))
+}
+
 func (f *flusher) getRedisFlusher() *redisFlusher {
 	if f.redisFlusher == nil {
 		f.redisFlusher = f.engine.afterCommitRedisFlusher
@@ -788,21 +982,36 @@ func (f *flusher) updateCacheAfterUpdate(entity Entity, bind, current Bind, sche
 		localCache = f.engine.GetLocalCache(requestCacheKey)
 	}
 	if hasLocalCache || hasRedis {
-		cacheKey := schema.getCacheKey(currentID)
+		if !lazy {
+			f.addStrictCacheDeletes(schema, currentID)
+			f.addDoubleDelete(schema, currentID)
+		}
+		cacheKey := schema.getCacheKey(f.engine, currentID)
 		keysOld := f.getCacheQueriesKeys(schema, bind, current, true, false)
 		keysNew := f.getCacheQueriesKeys(schema, bind, current, false, false)
 		if hasLocalCache {
-			f.addLocalCacheSet(localCache.config.GetCode(), cacheKey, entity.getORM().copyBinary())
+			if len(schema.generatedColumns) == 0 && schema.cacheMode != cacheModeAside {
+				f.addLocalCacheSet(localCache.config.GetCode(), cacheKey, entity.getORM().copyBinary())
+			} else {
+				f.addLocalCacheDeletes(localCache.config.GetCode(), cacheKey)
+			}
 			f.addLocalCacheDeletes(localCache.config.GetCode(), keysOld...)
 			f.addLocalCacheDeletes(localCache.config.GetCode(), keysNew...)
 		}
 		if hasRedis {
 			redisFlusher := f.getRedisFlusher()
-			redisFlusher.Del(redisCache.config.GetCode(), cacheKey)
+			if schema.cacheMode == cacheModeWriteBehind && len(schema.generatedColumns) == 0 && !schema.hasCacheKeyVariants() {
+				redisFlusher.Set(redisCache.config.GetCode(), cacheKey, schema.getRedisCacheValue(entity.getORM().binary), schema.cacheTTL)
+			} else {
+				redisFlusher.Del(redisCache.config.GetCode(), cacheKey)
+			}
 			redisFlusher.Del(redisCache.config.GetCode(), keysOld...)
 			redisFlusher.Del(redisCache.config.GetCode(), keysNew...)
+			f.addCacheKeyVariantsDeletes(schema, redisCache, currentID)
 		}
 	}
+	f.invalidateTableCacheTag(schema)
+	f.invalidateProjections(schema, currentID)
 	if schema.hasLog {
 		return f.addToLogQueue(schema, currentID, current, bind, entity.getORM().logMeta, lazy)
 	}
@@ -826,8 +1035,8 @@ func (f *flusher) addToLogQueue(tableSchema *tableSchema, id uint64, before, cha
 		}
 	}
 	val := &LogQueueValue{TableName: tableSchema.logTableName, ID: id,
-		PoolName: tableSchema.logPoolName, Before: before,
-		Changes: changes, Updated: time.Now(), Meta: entityMeta}
+		PoolName: tableSchema.logPoolName, Before: tableSchema.maskBind(before),
+		Changes: tableSchema.maskBind(changes), Updated: time.Now(), Meta: entityMeta}
 	if val.Meta == nil {
 		val.Meta = f.engine.logMetaData
 	} else {
@@ -847,8 +1056,11 @@ func (f *flusher) getCacheQueriesKeys(schema *tableSchema, bind, current Bind, o
 		if !addedDeleted && schema.hasFakeDelete {
 			_, addedDeleted = bind["FakeDelete"]
 		}
+		if !addedDeleted && schema.hasSoftDelete {
+			_, addedDeleted = bind[schema.softDeleteColumn]
+		}
 		if addedDeleted && len(definition.TrackedFields) == 0 {
-			keys = append(keys, getCacheKeySearch(schema, indexName))
+			keys = append(keys, getCacheKeySearch(f.engine, schema, indexName))
 		}
 		for _, trackedField := range definition.TrackedFields {
 			_, has := bind[trackedField]
@@ -859,11 +1071,12 @@ func (f *flusher) getCacheQueriesKeys(schema *tableSchema, bind, current Bind, o
 					if !has || old {
 						val = current[trackedFieldSub]
 					}
-					if !schema.hasFakeDelete || trackedFieldSub != "FakeDelete" {
+					if (!schema.hasFakeDelete || trackedFieldSub != "FakeDelete") &&
+						(!schema.hasSoftDelete || trackedFieldSub != schema.softDeleteColumn) {
 						attributes = append(attributes, val)
 					}
 				}
-				keys = append(keys, getCacheKeySearch(schema, indexName, attributes...))
+				keys = append(keys, getCacheKeySearch(f.engine, schema, indexName, attributes...))
 				break
 			}
 		}
@@ -888,7 +1101,7 @@ func (f *flusher) addLocalCacheDeletes(cacheCode string, keys ...string) {
 	f.localCacheDeletes[cacheCode] = append(f.localCacheDeletes[cacheCode], keys...)
 }
 
-func (f *flusher) fillLazyQuery(dbCode string, sql string, insert bool, id uint64, logEvent []*LogQueueValue) {
+func (f *flusher) fillLazyQuery(dbCode, tableName, sql, maskedSQL string, insert bool, id uint64, logEvent []*LogQueueValue) {
 	lazyMap := f.getLazyMap()
 	updatesMap := lazyMap["q"]
 	idsMap := lazyMap["i"]
@@ -898,9 +1111,13 @@ func (f *flusher) fillLazyQuery(dbCode string, sql string, insert bool, id uint6
 		idsMap = make([]interface{}, 0)
 		lazyMap["i"] = updatesMap
 	}
-	lazyValue := make([]interface{}, 3)
+	lazyValue := make([]interface{}, 3, 4)
 	lazyValue[0] = dbCode
 	lazyValue[1] = sql
+	lazyValue[2] = tableName
+	if maskedSQL != "" && maskedSQL != sql {
+		lazyValue = append(lazyValue, maskedSQL)
+	}
 	lazyMap["q"] = append(updatesMap.([]interface{}), lazyValue)
 	lazyMap["i"] = append(idsMap.([]interface{}), id)
 	lazyMap["o"] = "i"
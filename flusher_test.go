@@ -1135,6 +1135,164 @@ func testFlush(t *testing.T, local bool, redis bool) {
 	assert.Equal(t, "testSub", clonedEntity.SubName)
 }
 
+type flushOwnerEntity struct {
+	ORM  `orm:"owner=payments"`
+	ID   uint
+	Name string
+}
+
+type flushNoOwnerEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestFlushOwnership(t *testing.T) {
+	var entity *flushOwnerEntity
+	var entityNoOwner *flushNoOwnerEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity, entityNoOwner)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(&flushOwnerEntity{})
+	assert.Equal(t, "payments", schema.GetOwner())
+	assert.Equal(t, "", engine.GetRegistry().GetTableSchemaForEntity(&flushNoOwnerEntity{}).GetOwner())
+
+	engine.Flush(&flushOwnerEntity{Name: "a"})
+	engine.EnableOwnershipEnforcement("orders")
+	assert.PanicsWithError(t, "entity 'beeorm.flushOwnerEntity' is owned by 'payments'", func() {
+		engine.Flush(&flushOwnerEntity{Name: "b"})
+	})
+	engine.Flush(&flushNoOwnerEntity{Name: "c"})
+	entity = &flushOwnerEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.PanicsWithError(t, "entity 'beeorm.flushOwnerEntity' is owned by 'payments'", func() {
+		engine.Delete(entity)
+	})
+
+	engine.EnableOwnershipEnforcement("payments")
+	entity.Name = "d"
+	engine.Flush(entity)
+	engine.EnableOwnershipEnforcement("")
+	engine.Delete(entity)
+}
+
+type flushTimestampsEntity struct {
+	ORM       `orm:"localCache"`
+	ID        uint
+	Name      string
+	CreatedAt time.Time  `orm:"createdAt"`
+	UpdatedAt *time.Time `orm:"updatedAt"`
+}
+
+type flushTimestampsInvalidEntity struct {
+	ORM
+	ID        uint
+	CreatedAt string `orm:"createdAt"`
+}
+
+func TestFlushTimestamps(t *testing.T) {
+	var entity *flushTimestampsEntity
+	registry := &Registry{}
+	registry.SetTimestampsLocation(time.UTC)
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	entity = &flushTimestampsEntity{Name: "a"}
+	engine.Flush(entity)
+	assert.False(t, entity.CreatedAt.Before(now))
+	assert.Equal(t, time.UTC, entity.CreatedAt.Location())
+	assert.NotNil(t, entity.UpdatedAt)
+	assert.Equal(t, entity.CreatedAt, *entity.UpdatedAt)
+	createdAt := entity.CreatedAt
+
+	time.Sleep(time.Second)
+	entity.Name = "b"
+	engine.Flush(entity)
+	assert.Equal(t, createdAt, entity.CreatedAt)
+	assert.True(t, entity.UpdatedAt.After(createdAt))
+	updatedAt := *entity.UpdatedAt
+	entity = &flushTimestampsEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, createdAt.Unix(), entity.CreatedAt.Unix())
+	assert.Equal(t, updatedAt.Unix(), entity.UpdatedAt.Unix())
+	engine.Flush(entity)
+	assert.Equal(t, updatedAt.Unix(), entity.UpdatedAt.Unix())
+
+	past := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	entity = &flushTimestampsEntity{Name: "c", CreatedAt: past}
+	engine.FlushLazy(entity)
+	assert.Equal(t, past, entity.CreatedAt)
+	assert.NotNil(t, entity.UpdatedAt)
+	receiver := NewBackgroundConsumer(engine)
+	receiver.DisableBlockMode()
+	receiver.blockTime = time.Millisecond
+	receiver.Digest(context.Background())
+	entity = &flushTimestampsEntity{}
+	engine.GetLocalCache().Clear()
+	assert.True(t, engine.LoadByID(2, entity))
+	assert.Equal(t, past.Unix(), entity.CreatedAt.Unix())
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&flushTimestampsInvalidEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "createdAt field CreatedAt in beeorm.flushTimestampsInvalidEntity must be time.Time")
+}
+
+type flushDefaultsEntity struct {
+	ORM
+	ID       uint
+	Name     string  `orm:"default=unknown"`
+	Age      uint8   `orm:"default=18"`
+	Balance  float64 `orm:"default=1.5"`
+	Active   bool    `orm:"default=true"`
+	Priority *int    `orm:"default=-3"`
+}
+
+type flushDefaultsInvalidEntity struct {
+	ORM
+	ID  uint
+	Age uint8 `orm:"default=abc"`
+}
+
+func TestFlushDefaults(t *testing.T) {
+	var entity *flushDefaultsEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	assert.Len(t, engine.GetAlters(), 0)
+
+	entity = &flushDefaultsEntity{}
+	engine.Flush(entity)
+	assert.Equal(t, "unknown", entity.Name)
+	assert.Equal(t, uint8(18), entity.Age)
+	assert.Equal(t, 1.5, entity.Balance)
+	assert.True(t, entity.Active)
+	assert.Equal(t, -3, *entity.Priority)
+
+	priority := 7
+	entity = &flushDefaultsEntity{Name: "John", Age: 30, Priority: &priority}
+	engine.Flush(entity)
+	entity.Age = 0
+	entity.Priority = nil
+	engine.Flush(entity)
+	entity = &flushDefaultsEntity{}
+	assert.True(t, engine.LoadByID(2, entity))
+	assert.Equal(t, "John", entity.Name)
+	assert.Equal(t, uint8(0), entity.Age)
+	assert.Nil(t, entity.Priority)
+
+	engine.GetMysql().Exec("INSERT INTO `flushDefaultsEntity`(`ID`) VALUES(3)")
+	entity = &flushDefaultsEntity{}
+	assert.True(t, engine.LoadByID(3, entity))
+	assert.Equal(t, "unknown", entity.Name)
+	assert.Equal(t, uint8(18), entity.Age)
+	assert.True(t, entity.Active)
+	assert.Equal(t, -3, *entity.Priority)
+
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&flushDefaultsInvalidEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "invalid default 'abc' for field Age in beeorm.flushDefaultsInvalidEntity")
+}
+
 // 17 allocs/op - 6 for Exec
 func BenchmarkFlusherUpdateNoCache(b *testing.B) {
 	benchmarkFlusher(b, false, false)
@@ -0,0 +1,73 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var generatedColumnIntroducer = regexp.MustCompile(`_[a-z0-9]+'`)
+
+func initGeneratedColumns(tableSchema *tableSchema, entityType reflect.Type) error {
+	tableSchema.generatedColumns = nil
+	for field, tags := range tableSchema.tags {
+		expression, has := tags["generated"]
+		if !has {
+			continue
+		}
+		if expression == "true" || expression == "" {
+			return fmt.Errorf("missing expression for generated field %s in %s", field, entityType.String())
+		}
+		if _, hasDefault := tags["default"]; hasDefault {
+			return fmt.Errorf("default value not allowed for generated field %s in %s", field, entityType.String())
+		}
+		if field == "ID" {
+			return fmt.Errorf("ID in %s can't be generated", entityType.String())
+		}
+		tableSchema.generatedColumns = append(tableSchema.generatedColumns, field)
+	}
+	sort.Strings(tableSchema.generatedColumns)
+	return nil
+}
+
+func getGeneratedColumnDefinition(definition string, attributes map[string]string) string {
+	storage := "VIRTUAL"
+	if attributes["stored"] == "true" {
+		storage = "STORED"
+	}
+	return definition + " GENERATED ALWAYS AS (" + attributes["generated"] + ") " + storage
+}
+
+func isGeneratedColumnDefinition(definition string) bool {
+	return strings.Contains(definition, " GENERATED ALWAYS AS (")
+}
+
+func normalizeGeneratedColumnDefinition(definition string) string {
+	definition = strings.ToLower(definition)
+	definition = generatedColumnIntroducer.ReplaceAllString(definition, "'")
+	return strings.NewReplacer("`", "", " ", "", "(", "", ")", "").Replace(definition)
+}
+
+func matchGeneratedColumns(columns, tableDBColumns [][2]string) {
+	for _, column := range columns {
+		if !isGeneratedColumnDefinition(column[1]) {
+			continue
+		}
+		for i, columnDB := range tableDBColumns {
+			if columnDB[0] == column[0] && normalizeGeneratedColumnDefinition(columnDB[1]) == normalizeGeneratedColumnDefinition(column[1]) {
+				tableDBColumns[i][1] = column[1]
+			}
+		}
+	}
+}
+
+func (b *bindBuilder) removeGeneratedColumns() {
+	for _, column := range b.orm.tableSchema.generatedColumns {
+		delete(b.bind, column)
+		if b.sqlBind != nil {
+			delete(b.sqlBind, column)
+		}
+	}
+}
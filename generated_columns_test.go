@@ -0,0 +1,76 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type generatedColumnsEntity struct {
+	ORM
+	ID        uint
+	FirstName string `orm:"required;length=50"`
+	LastName  string `orm:"required;length=50"`
+	FullName  string "orm:\"length=101;generated=concat(`FirstName`,' ',`LastName`);index=FullName\""
+	Price     uint
+	Quantity  uint
+	Total     uint "orm:\"generated=`Price` * `Quantity`;stored\""
+}
+
+type generatedColumnsInvalidEntity struct {
+	ORM
+	ID    uint
+	Total uint `orm:"generated=1 + 1;default=2"`
+}
+
+func TestGeneratedColumnDefinitionNormalization(t *testing.T) {
+	assert.Equal(t, normalizeGeneratedColumnDefinition("`FullName` varchar(101) GENERATED ALWAYS AS (concat(`FirstName`,' ',`LastName`)) VIRTUAL"),
+		normalizeGeneratedColumnDefinition("`FullName` varchar(101) GENERATED ALWAYS AS (concat(`FirstName`,_utf8mb4' ',`LastName`)) VIRTUAL"))
+	assert.Equal(t, normalizeGeneratedColumnDefinition("`Total` int(10) unsigned GENERATED ALWAYS AS (`Price` * `Quantity`) STORED NOT NULL"),
+		normalizeGeneratedColumnDefinition("`Total` int(10) unsigned GENERATED ALWAYS AS ((`Price` * `Quantity`)) STORED NOT NULL"))
+	assert.NotEqual(t, normalizeGeneratedColumnDefinition("`Total` int GENERATED ALWAYS AS (`Price` * `Quantity`) STORED"),
+		normalizeGeneratedColumnDefinition("`Total` int GENERATED ALWAYS AS (`Price` * `Quantity`) VIRTUAL"))
+}
+
+func TestGeneratedColumns(t *testing.T) {
+	var entity *generatedColumnsEntity
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	schema.DropTable(engine)
+	has, alters := schema.GetSchemaChanges(engine)
+	assert.True(t, has)
+	assert.Equal(t, "CREATE TABLE `test`.`generatedColumnsEntity` (\n  `ID` int(10) unsigned NOT NULL AUTO_INCREMENT,\n  "+
+		"`FirstName` varchar(50) NOT NULL,\n  `LastName` varchar(50) NOT NULL,\n  "+
+		"`FullName` varchar(101) GENERATED ALWAYS AS (concat(`FirstName`,' ',`LastName`)) VIRTUAL,\n  "+
+		"`Price` int(10) unsigned NOT NULL DEFAULT '0',\n  `Quantity` int(10) unsigned NOT NULL DEFAULT '0',\n  "+
+		"`Total` int(10) unsigned GENERATED ALWAYS AS (`Price` * `Quantity`) STORED NOT NULL,\n  "+
+		"INDEX `FullName` (`FullName`),\n  PRIMARY KEY (`ID`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;", alters[0].SQL)
+	schema.UpdateSchema(engine)
+	assert.Len(t, engine.GetAlters(), 0)
+
+	entity = &generatedColumnsEntity{FirstName: "John", LastName: "Smith", FullName: "ignored", Price: 10, Quantity: 3, Total: 1}
+	engine.Flush(entity)
+	entity = &generatedColumnsEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "John Smith", entity.FullName)
+	assert.Equal(t, uint(30), entity.Total)
+
+	entity.Total = 100
+	assert.False(t, entity.IsDirty())
+	entity.Quantity = 5
+	engine.Flush(entity)
+	entity = &generatedColumnsEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, uint(50), entity.Total)
+
+	var rows []*generatedColumnsEntity
+	engine.Search(NewWhere("`FullName` = ?", "John Smith"), nil, &rows)
+	assert.Len(t, rows, 1)
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&generatedColumnsInvalidEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "default value not allowed for generated field Total in beeorm.generatedColumnsInvalidEntity")
+}
@@ -0,0 +1,79 @@
+package beeorm
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+const geoMetersPerDegree = 111320.0
+
+type GeoPoint struct {
+	Lat float64
+	Lng float64
+}
+
+func (p *GeoPoint) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		p.Lat = 0
+		p.Lng = 0
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported point value %T", src)
+	}
+	if len(data) == 0 {
+		p.Lat = 0
+		p.Lng = 0
+		return nil
+	}
+	if len(data) != 25 {
+		return fmt.Errorf("invalid point value of length %d", len(data))
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if data[4] == 0 {
+		order = binary.BigEndian
+	}
+	if order.Uint32(data[5:9]) != 1 {
+		return fmt.Errorf("geometry value is not a point")
+	}
+	p.Lng = math.Float64frombits(order.Uint64(data[9:17]))
+	p.Lat = math.Float64frombits(order.Uint64(data[17:25]))
+	return nil
+}
+
+func (p GeoPoint) Value() (driver.Value, error) {
+	data := make([]byte, 25)
+	data[4] = 1
+	binary.LittleEndian.PutUint32(data[5:9], 1)
+	binary.LittleEndian.PutUint64(data[9:17], math.Float64bits(p.Lng))
+	binary.LittleEndian.PutUint64(data[17:25], math.Float64bits(p.Lat))
+	return data, nil
+}
+
+func (p *GeoPoint) ColumnDefinition(_ int) string {
+	return "point"
+}
+
+func (p GeoPoint) String() string {
+	return "POINT(" + strconv.FormatFloat(p.Lng, 'f', -1, 64) + " " + strconv.FormatFloat(p.Lat, 'f', -1, 64) + ")"
+}
+
+func WhereWithinRadius(field string, lat, lng, meters float64) *Where {
+	latDelta := meters / geoMetersPerDegree
+	lngDelta := meters / (geoMetersPerDegree * math.Max(math.Cos(lat*math.Pi/180), 0.000001))
+	minLat := GeoPoint{Lat: lat - latDelta, Lng: lng - lngDelta}
+	maxLat := GeoPoint{Lat: lat + latDelta, Lng: lng + lngDelta}
+	box := fmt.Sprintf("POLYGON((%[1]s %[2]s,%[3]s %[2]s,%[3]s %[4]s,%[1]s %[4]s,%[1]s %[2]s))",
+		strconv.FormatFloat(minLat.Lng, 'f', -1, 64), strconv.FormatFloat(minLat.Lat, 'f', -1, 64),
+		strconv.FormatFloat(maxLat.Lng, 'f', -1, 64), strconv.FormatFloat(maxLat.Lat, 'f', -1, 64))
+	return NewWhere("MBRContains(ST_GeomFromText(?), `"+field+"`) AND ST_Distance_Sphere(`"+field+"`, ST_GeomFromText(?)) <= ?",
+		box, GeoPoint{Lat: lat, Lng: lng}.String(), meters)
+}
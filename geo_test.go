@@ -0,0 +1,74 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type geoEntity struct {
+	ORM
+	ID       uint
+	Name     string
+	Location GeoPoint  `orm:"spatial"`
+	Area     *GeoPoint `orm:"columnType=geometry"`
+}
+
+func TestGeoPointValue(t *testing.T) {
+	point := GeoPoint{Lat: 52.2297, Lng: 21.0122}
+	value, err := point.Value()
+	assert.NoError(t, err)
+	assert.Len(t, value, 25)
+	scanned := &GeoPoint{}
+	assert.NoError(t, scanned.Scan(value))
+	assert.Equal(t, point, *scanned)
+	assert.Equal(t, "POINT(21.0122 52.2297)", point.String())
+	assert.NoError(t, scanned.Scan(nil))
+	assert.Equal(t, GeoPoint{}, *scanned)
+	assert.EqualError(t, scanned.Scan([]byte{1, 2}), "invalid point value of length 2")
+}
+
+func TestWhereWithinRadius(t *testing.T) {
+	where := WhereWithinRadius("Location", 0, 0, 11132)
+	assert.Equal(t, "MBRContains(ST_GeomFromText(?), `Location`) AND ST_Distance_Sphere(`Location`, ST_GeomFromText(?)) <= ?", where.String())
+	assert.Equal(t, []interface{}{"POLYGON((-0.1 -0.1,0.1 -0.1,0.1 0.1,-0.1 0.1,-0.1 -0.1))", "POINT(0 0)", float64(11132)}, where.GetParameters())
+}
+
+func TestGeo(t *testing.T) {
+	var entity *geoEntity
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 8, 6, "", entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	schema.DropTable(engine)
+	has, alters := schema.GetSchemaChanges(engine)
+	assert.True(t, has)
+	assert.Equal(t, "CREATE TABLE `test`.`geoEntity` (\n  `ID` int unsigned NOT NULL AUTO_INCREMENT,\n  "+
+		"`Name` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL,\n  `Location` point NOT NULL,\n  "+
+		"`Area` geometry DEFAULT NULL,\n  SPATIAL INDEX `Location` (`Location`),\n  PRIMARY KEY (`ID`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;", alters[0].SQL)
+	schema.UpdateSchema(engine)
+	assert.Len(t, engine.GetAlters(), 0)
+
+	warsaw := &geoEntity{Name: "Warsaw", Location: GeoPoint{Lat: 52.2297, Lng: 21.0122}}
+	krakow := &geoEntity{Name: "Krakow", Location: GeoPoint{Lat: 50.0647, Lng: 19.945}}
+	berlin := &geoEntity{Name: "Berlin", Location: GeoPoint{Lat: 52.52, Lng: 13.405}}
+	engine.Flush(warsaw, krakow, berlin)
+
+	entity = &geoEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, GeoPoint{Lat: 52.2297, Lng: 21.0122}, entity.Location)
+	assert.Nil(t, entity.Area)
+	assert.False(t, entity.IsDirty())
+
+	var rows []*geoEntity
+	engine.Search(WhereWithinRadius("Location", 52.2297, 21.0122, 300000), nil, &rows)
+	assert.Len(t, rows, 2)
+	engine.Search(WhereWithinRadius("Location", 52.2297, 21.0122, 1000), nil, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "Warsaw", rows[0].Name)
+
+	entity.Location = GeoPoint{Lat: 52.23, Lng: 21.01}
+	engine.Flush(entity)
+	entity = &geoEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, GeoPoint{Lat: 52.23, Lng: 21.01}, entity.Location)
+}
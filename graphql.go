@@ -0,0 +1,352 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const graphQLDefaultPageSize = 100
+
+func GenerateGraphQLSchema(registry ValidatedRegistry) string {
+	validated := registry.(*validatedRegistry)
+	names := make([]string, 0)
+	for name := range validated.entities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	b := &strings.Builder{}
+	b.WriteString("scalar Time\nscalar JSON\n\ntype Query {\n")
+	for _, name := range names {
+		typeName := validated.entities[name].Name()
+		queryName := graphQLQueryName(typeName)
+		b.WriteString("  " + queryName + "(id: ID!): " + typeName + "\n")
+		b.WriteString("  " + queryName + "List(page: Int = 1, pageSize: Int = " + strconv.Itoa(graphQLDefaultPageSize) +
+			"): [" + typeName + "!]!\n")
+	}
+	b.WriteString("}\n")
+	for _, name := range names {
+		t := validated.entities[name]
+		schema := getTableSchema(validated, t)
+		nested := make([]string, 0)
+		b.WriteString("\ntype " + t.Name() + " {\n")
+		writeGraphQLFields(b, validated, schema, t, "", t.Name(), &nested)
+		b.WriteString("}\n")
+		for _, definition := range nested {
+			b.WriteString(definition)
+		}
+	}
+	return b.String()
+}
+
+func writeGraphQLFields(b *strings.Builder, registry *validatedRegistry, schema *tableSchema, t reflect.Type,
+	prefix, typeName string, nested *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Name() == "ORM" {
+			continue
+		}
+		_, ignored := schema.tags[prefix+field.Name]["ignore"]
+		if ignored || field.Type == reflect.TypeOf(&CachedQuery{}) {
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct && field.Type.String() != "time.Time" {
+			if field.Anonymous {
+				writeGraphQLFields(b, registry, schema, field.Type, "", typeName, nested)
+				continue
+			}
+			nestedName := typeName + "_" + field.Name
+			sub := &strings.Builder{}
+			sub.WriteString("\ntype " + nestedName + " {\n")
+			writeGraphQLFields(sub, registry, schema, field.Type, field.Name, nestedName, nested)
+			sub.WriteString("}\n")
+			*nested = append(*nested, sub.String())
+			b.WriteString("  " + field.Name + ": " + nestedName + "!\n")
+			continue
+		}
+		b.WriteString("  " + field.Name + ": " + graphQLType(registry, field) + "\n")
+	}
+}
+
+func graphQLType(registry *validatedRegistry, field reflect.StructField) string {
+	t := field.Type
+	switch t.Kind() {
+	case reflect.Ptr:
+		if t.Elem().Kind() == reflect.Struct {
+			_, isEntity := registry.tableSchemas[t.Elem()]
+			if isEntity {
+				return t.Elem().Name()
+			}
+			if t.Elem().String() == "time.Time" {
+				return "Time"
+			}
+			return "JSON"
+		}
+		return strings.TrimSuffix(graphQLScalar(field.Name, t.Elem()), "!")
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Ptr {
+			_, isEntity := registry.tableSchemas[t.Elem().Elem()]
+			if isEntity {
+				return "[" + t.Elem().Elem().Name() + "!]"
+			}
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "String"
+		}
+		if t.Elem().Kind() == reflect.String {
+			return "[String!]"
+		}
+		return "JSON"
+	case reflect.Struct:
+		return "Time!"
+	}
+	return graphQLScalar(field.Name, t)
+}
+
+func graphQLScalar(name string, t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if name == "ID" {
+			return "ID!"
+		}
+		return "Int!"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "Int!"
+	case reflect.Float32, reflect.Float64:
+		return "Float!"
+	case reflect.Bool:
+		return "Boolean!"
+	case reflect.String:
+		return "String!"
+	}
+	return "JSON"
+}
+
+func graphQLQueryName(typeName string) string {
+	runes := []rune(typeName)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+type GraphQLResolver struct {
+	engine      Engine
+	batchWait   time.Duration
+	queries     map[string]*tableSchema
+	lists       map[string]*tableSchema
+	mutex       sync.Mutex
+	engineMutex sync.Mutex
+	batches     map[reflect.Type]*graphQLBatch
+}
+
+type graphQLBatch struct {
+	ids    []uint64
+	done   chan struct{}
+	result map[uint64]Entity
+	err    error
+}
+
+func NewGraphQLResolver(engine Engine, batchWait time.Duration) *GraphQLResolver {
+	registry := engine.GetRegistry().(*validatedRegistry)
+	resolver := &GraphQLResolver{engine: engine, batchWait: batchWait, queries: make(map[string]*tableSchema),
+		lists: make(map[string]*tableSchema), batches: make(map[reflect.Type]*graphQLBatch)}
+	for _, t := range registry.entities {
+		queryName := graphQLQueryName(t.Name())
+		resolver.queries[queryName] = getTableSchema(registry, t)
+		resolver.lists[queryName+"List"] = getTableSchema(registry, t)
+	}
+	return resolver
+}
+
+func (r *GraphQLResolver) ResolveQuery(field string, args map[string]interface{}) (interface{}, error) {
+	schema, has := r.queries[field]
+	if has {
+		id, err := graphQLUintArgument(args, "id", 0)
+		if err != nil {
+			return nil, err
+		}
+		entities, err := r.load(schema, id)
+		if err != nil {
+			return nil, err
+		}
+		if entities[0] == nil {
+			return nil, nil
+		}
+		return entities[0], nil
+	}
+	schema, has = r.lists[field]
+	if !has {
+		return nil, fmt.Errorf("unknown query '%s'", field)
+	}
+	page, err := graphQLUintArgument(args, "page", 1)
+	if err != nil {
+		return nil, err
+	}
+	pageSize, err := graphQLUintArgument(args, "pageSize", graphQLDefaultPageSize)
+	if err != nil {
+		return nil, err
+	}
+	entities := reflect.New(reflect.SliceOf(reflect.PtrTo(schema.t)))
+	r.engineMutex.Lock()
+	defer r.engineMutex.Unlock()
+	r.engine.Search(NewWhere("1 ORDER BY `ID`"), NewPager(int(page), int(pageSize)), entities.Interface())
+	return entities.Elem().Interface(), nil
+}
+
+func (r *GraphQLResolver) ResolveField(source interface{}, field string) (interface{}, error) {
+	value := reflect.ValueOf(source)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("invalid source type %s", value.Type().String())
+	}
+	fieldValue := value.FieldByName(field)
+	if !fieldValue.IsValid() {
+		return nil, fmt.Errorf("unknown field '%s' in %s", field, value.Type().String())
+	}
+	registry := r.engine.GetRegistry().(*validatedRegistry)
+	switch fieldValue.Kind() {
+	case reflect.Ptr:
+		if fieldValue.IsNil() {
+			return nil, nil
+		}
+		schema, isEntity := registry.tableSchemas[fieldValue.Type().Elem()]
+		if !isEntity {
+			return fieldValue.Interface(), nil
+		}
+		reference := fieldValue.Interface().(Entity)
+		if reference.IsLoaded() {
+			return reference, nil
+		}
+		loaded, err := r.load(schema, reference.GetID())
+		if err != nil {
+			return nil, err
+		}
+		if loaded[0] == nil {
+			return nil, nil
+		}
+		return loaded[0], nil
+	case reflect.Slice:
+		if fieldValue.Type().Elem().Kind() != reflect.Ptr {
+			return fieldValue.Interface(), nil
+		}
+		schema, isEntity := registry.tableSchemas[fieldValue.Type().Elem().Elem()]
+		if !isEntity {
+			return fieldValue.Interface(), nil
+		}
+		ids := make([]uint64, fieldValue.Len())
+		for i := 0; i < fieldValue.Len(); i++ {
+			ids[i] = fieldValue.Index(i).Interface().(Entity).GetID()
+		}
+		loaded, err := r.load(schema, ids...)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]Entity, 0, len(ids))
+		for _, entity := range loaded {
+			if entity != nil {
+				result = append(result, entity)
+			}
+		}
+		return result, nil
+	}
+	return fieldValue.Interface(), nil
+}
+
+func (r *GraphQLResolver) load(schema *tableSchema, ids ...uint64) ([]Entity, error) {
+	r.mutex.Lock()
+	batch, has := r.batches[schema.t]
+	if !has {
+		batch = &graphQLBatch{done: make(chan struct{})}
+		r.batches[schema.t] = batch
+		time.AfterFunc(r.batchWait, func() {
+			r.executeBatch(schema, batch)
+		})
+	}
+	batch.ids = append(batch.ids, ids...)
+	r.mutex.Unlock()
+	<-batch.done
+	if batch.err != nil {
+		return nil, batch.err
+	}
+	result := make([]Entity, len(ids))
+	for i, id := range ids {
+		result[i] = batch.result[id]
+	}
+	return result, nil
+}
+
+func (r *GraphQLResolver) executeBatch(schema *tableSchema, batch *graphQLBatch) {
+	r.mutex.Lock()
+	delete(r.batches, schema.t)
+	r.mutex.Unlock()
+	defer close(batch.done)
+	defer func() {
+		if rec := recover(); rec != nil {
+			asErr, is := rec.(error)
+			if !is {
+				asErr = fmt.Errorf("%v", rec)
+			}
+			batch.err = asErr
+		}
+	}()
+	batch.result = make(map[uint64]Entity)
+	unique := make(map[uint64]bool)
+	ids := make([]uint64, 0, len(batch.ids))
+	for _, id := range batch.ids {
+		if !unique[id] {
+			unique[id] = true
+			ids = append(ids, id)
+		}
+	}
+	entities := reflect.New(reflect.SliceOf(reflect.PtrTo(schema.t)))
+	r.engineMutex.Lock()
+	defer r.engineMutex.Unlock()
+	r.engine.LoadByIDs(ids, entities.Interface())
+	for i := 0; i < entities.Elem().Len(); i++ {
+		entity := entities.Elem().Index(i)
+		if !entity.IsNil() {
+			batch.result[ids[i]] = entity.Interface().(Entity)
+		}
+	}
+}
+
+func graphQLUintArgument(args map[string]interface{}, name string, defaultValue uint64) (uint64, error) {
+	value, has := args[name]
+	if !has || value == nil {
+		if defaultValue == 0 {
+			return 0, fmt.Errorf("missing argument '%s'", name)
+		}
+		return defaultValue, nil
+	}
+	switch v := value.(type) {
+	case int:
+		if v < 0 {
+			return 0, fmt.Errorf("negative argument '%s'", name)
+		}
+		return uint64(v), nil
+	case int64:
+		if v < 0 {
+			return 0, fmt.Errorf("negative argument '%s'", name)
+		}
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	case float64:
+		if v < 0 {
+			return 0, fmt.Errorf("negative argument '%s'", name)
+		}
+		return uint64(v), nil
+	case string:
+		return strconv.ParseUint(v, 10, 64)
+	}
+	return 0, fmt.Errorf("invalid argument '%s'", name)
+}
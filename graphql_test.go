@@ -0,0 +1,103 @@
+package beeorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type graphQLEntity struct {
+	ORM
+	ID         uint
+	Name       string `orm:"unique=Name"`
+	Age        *uint8
+	Active     bool
+	Born       time.Time
+	Address    graphQLAddress
+	Reference  *graphQLEntityReference
+	References []*graphQLEntityReference
+	ByName     *CachedQuery `queryOne:":Name = ?"`
+	Ignored    string       `orm:"ignore"`
+}
+
+type graphQLAddress struct {
+	City   string
+	Weight float64
+}
+
+type graphQLEntityReference struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestGenerateGraphQLSchema(t *testing.T) {
+	var entity *graphQLEntity
+	var reference *graphQLEntityReference
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", entity, reference)
+
+	schema := GenerateGraphQLSchema(engine.GetRegistry())
+	assert.Contains(t, schema, "  graphQLEntity(id: ID!): graphQLEntity\n")
+	assert.Contains(t, schema, "  graphQLEntityList(page: Int = 1, pageSize: Int = 100): [graphQLEntity!]!\n")
+	assert.Contains(t, schema, "type graphQLEntity {\n  ID: ID!\n  Name: String!\n  Age: Int\n  Active: Boolean!\n"+
+		"  Born: Time!\n  Address: graphQLEntity_Address!\n  Reference: graphQLEntityReference\n"+
+		"  References: [graphQLEntityReference!]\n}\n")
+	assert.Contains(t, schema, "type graphQLEntity_Address {\n  City: String!\n  Weight: Float!\n}\n")
+	assert.Contains(t, schema, "type graphQLEntityReference {\n  ID: ID!\n  Name: String!\n}\n")
+}
+
+func TestGraphQLResolver(t *testing.T) {
+	var entity *graphQLEntity
+	var reference *graphQLEntityReference
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", entity, reference)
+
+	ref1 := &graphQLEntityReference{Name: "r1"}
+	ref2 := &graphQLEntityReference{Name: "r2"}
+	engine.Flush(ref1, ref2)
+	e := &graphQLEntity{Name: "a", Reference: ref1, References: []*graphQLEntityReference{ref1, ref2}}
+	engine.Flush(e)
+
+	resolver := NewGraphQLResolver(engine.GetRegistry().CreateEngine(), time.Millisecond)
+	result, err := resolver.ResolveQuery("graphQLEntity", map[string]interface{}{"id": "1"})
+	assert.NoError(t, err)
+	loaded := result.(*graphQLEntity)
+	assert.Equal(t, "a", loaded.Name)
+
+	result, err = resolver.ResolveQuery("graphQLEntity", map[string]interface{}{"id": 2})
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+
+	result, err = resolver.ResolveField(loaded, "Reference")
+	assert.NoError(t, err)
+	assert.Equal(t, "r1", result.(*graphQLEntityReference).Name)
+	result, err = resolver.ResolveField(loaded, "References")
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "r2", result.([]Entity)[1].(*graphQLEntityReference).Name)
+	result, err = resolver.ResolveField(loaded, "Name")
+	assert.NoError(t, err)
+	assert.Equal(t, "a", result)
+
+	result, err = resolver.ResolveQuery("graphQLEntityReferenceList", map[string]interface{}{"pageSize": 1})
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "r1", result.([]*graphQLEntityReference)[0].Name)
+
+	_, err = resolver.ResolveQuery("invalid", nil)
+	assert.EqualError(t, err, "unknown query 'invalid'")
+	_, err = resolver.ResolveQuery("graphQLEntity", nil)
+	assert.EqualError(t, err, "missing argument 'id'")
+}
+
+func TestGraphQLUintArgument(t *testing.T) {
+	id, err := graphQLUintArgument(map[string]interface{}{"id": float64(12)}, "id", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12), id)
+	for _, value := range []interface{}{-1, int64(-1), float64(-1)} {
+		_, err = graphQLUintArgument(map[string]interface{}{"id": value}, "id", 0)
+		assert.EqualError(t, err, "negative argument 'id'")
+	}
+}
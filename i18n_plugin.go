@@ -0,0 +1,306 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const I18nPluginCode = "beeorm/i18n"
+
+type I18nPlugin struct {
+	defaultLocale string
+}
+
+type pendingTranslation struct {
+	schema *tableSchema
+	id     uint64
+	column string
+	locale string
+	value  *string
+}
+
+type translationRestore struct {
+	field reflect.Value
+	value reflect.Value
+}
+
+func NewI18nPlugin(defaultLocale string) *I18nPlugin {
+	return &I18nPlugin{defaultLocale: defaultLocale}
+}
+
+func (p *I18nPlugin) GetCode() string {
+	return I18nPluginCode
+}
+
+func (p *I18nPlugin) GetDefaultLocale() string {
+	return p.defaultLocale
+}
+
+func (e *engineImplementation) SetLocale(locale string) {
+	e.locale = locale
+}
+
+func (e *engineImplementation) GetLocale() string {
+	return e.locale
+}
+
+func initTranslatedFields(tableSchema *tableSchema, entityType reflect.Type) error {
+	tableSchema.translatedColumns = nil
+	for i := 0; i < entityType.NumField(); i++ {
+		field := entityType.Field(i)
+		if tableSchema.tags[field.Name]["translate"] != "true" {
+			continue
+		}
+		if field.Type.String() != "string" && field.Type.String() != "*string" {
+			return fmt.Errorf("translated field %s in %s must be string", field.Name, entityType.String())
+		}
+		tableSchema.translatedColumns = append(tableSchema.translatedColumns, field.Name)
+	}
+	tableSchema.i18nTableName = "_i18n_" + tableSchema.tableName
+	tableSchema.i18nFallbacks = nil
+	fallbacks := tableSchema.getTag("i18nFallback", "", "")
+	if fallbacks != "" {
+		tableSchema.i18nFallbacks = strings.Split(fallbacks, "|")
+	}
+	return nil
+}
+
+func (p *I18nPlugin) PluginInterfaceSchemaTables(engine Engine) []PluginTable {
+	tables := make([]PluginTable, 0)
+	registry := engine.(*engineImplementation).registry
+	for _, t := range registry.entities {
+		schema := getTableSchema(registry, t)
+		if len(schema.translatedColumns) == 0 {
+			continue
+		}
+		poolConfig := engine.GetMysql(schema.mysqlPoolName).GetPoolConfig()
+		var createSQL string
+		if poolConfig.GetVersion() == 5 {
+			createSQL = fmt.Sprintf("CREATE TABLE `%s`.`%s` (\n  `entity_id` bigint(20) unsigned NOT NULL,\n  "+
+				"`locale` varchar(16) NOT NULL,\n  `field` varchar(100) NOT NULL,\n  `value` mediumtext NOT NULL,\n  "+
+				"PRIMARY KEY (`entity_id`,`locale`,`field`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+				poolConfig.GetDatabase(), schema.i18nTableName)
+		} else {
+			createSQL = fmt.Sprintf("CREATE TABLE `%s`.`%s` (\n  `entity_id` bigint unsigned NOT NULL,\n  "+
+				"`locale` varchar(16) NOT NULL,\n  `field` varchar(100) NOT NULL,\n  `value` mediumtext NOT NULL,\n  "+
+				"PRIMARY KEY (`entity_id`,`locale`,`field`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_%s;",
+				poolConfig.GetDatabase(), schema.i18nTableName, registry.registry.defaultCollate)
+		}
+		tables = append(tables, PluginTable{Pool: schema.mysqlPoolName, Name: schema.i18nTableName, CreateSQL: createSQL})
+	}
+	return tables
+}
+
+func (p *I18nPlugin) PluginInterfaceEntityFlushed(engine Engine, event *EntityFlushedEvent) {
+	schema := event.TableSchema.(*tableSchema)
+	if len(schema.translatedColumns) == 0 || event.Type != FlushTypeDelete || event.ID == 0 {
+		return
+	}
+	engine.GetMysql(schema.mysqlPoolName).Exec("DELETE FROM `"+schema.i18nTableName+"` WHERE `entity_id` = ?", event.ID)
+}
+
+func (p *I18nPlugin) GetTranslations(engine Engine, entity Entity) map[string]map[string]string {
+	orm := initIfNeeded(engine.(*engineImplementation).registry, entity)
+	schema := orm.tableSchema
+	if len(schema.translatedColumns) == 0 {
+		panic(fmt.Errorf("entity '%s' has no translated fields", schema.t.String()))
+	}
+	translations := make(map[string]map[string]string)
+	query := "SELECT `locale`, `field`, `value` FROM `" + schema.i18nTableName + "` WHERE `entity_id` = ?"
+	rows, closeF := engine.GetMysql(schema.mysqlPoolName).Query(query, orm.GetID())
+	defer closeF()
+	for rows.Next() {
+		var locale, field, value string
+		rows.Scan(&locale, &field, &value)
+		if translations[locale] == nil {
+			translations[locale] = make(map[string]string)
+		}
+		translations[locale][field] = value
+	}
+	return translations
+}
+
+func (p *I18nPlugin) getLocales(schema *tableSchema, locale string) []string {
+	locales := make([]string, 0, len(schema.i18nFallbacks)+1)
+	for _, candidate := range append([]string{locale}, schema.i18nFallbacks...) {
+		if candidate == p.defaultLocale {
+			break
+		}
+		locales = append(locales, candidate)
+	}
+	return locales
+}
+
+func (e *engineImplementation) getI18nPlugin(schema *tableSchema) *I18nPlugin {
+	if e.locale == "" || len(schema.translatedColumns) == 0 {
+		return nil
+	}
+	plugin, is := e.registry.GetPlugin(I18nPluginCode).(*I18nPlugin)
+	if !is || e.locale == plugin.defaultLocale {
+		return nil
+	}
+	return plugin
+}
+
+func (e *engineImplementation) translate(entities interface{}) {
+	if e.locale == "" {
+		return
+	}
+	entity, is := entities.(Entity)
+	if is {
+		e.translateEntities([]Entity{entity})
+		return
+	}
+	value := reflect.ValueOf(entities)
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Slice || value.Len() == 0 {
+		return
+	}
+	rows := make([]Entity, 0, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		row, is := value.Index(i).Interface().(Entity)
+		if is && !reflect.ValueOf(row).IsNil() {
+			rows = append(rows, row)
+		}
+	}
+	e.translateEntities(rows)
+}
+
+func (e *engineImplementation) translateEntities(entities []Entity) {
+	bySchema := make(map[*tableSchema]map[uint64]*ORM)
+	for _, entity := range entities {
+		orm := entity.getORM()
+		if !orm.loaded || orm.tableSchema == nil || orm.GetID() == 0 {
+			continue
+		}
+		if bySchema[orm.tableSchema] == nil {
+			bySchema[orm.tableSchema] = make(map[uint64]*ORM)
+		}
+		bySchema[orm.tableSchema][orm.GetID()] = orm
+	}
+	for schema, orms := range bySchema {
+		plugin := e.getI18nPlugin(schema)
+		if plugin == nil {
+			continue
+		}
+		locales := plugin.getLocales(schema, e.locale)
+		if len(locales) == 0 {
+			continue
+		}
+		ids := make([]interface{}, 0, len(orms))
+		for id := range orms {
+			ids = append(ids, id)
+		}
+		args := append(ids, make([]interface{}, len(locales))...)
+		for i, locale := range locales {
+			args[len(ids)+i] = locale
+		}
+		query := "SELECT `entity_id`, `locale`, `field`, `value` FROM `" + schema.i18nTableName + "` WHERE `entity_id` IN (" +
+			strings.Repeat(",?", len(ids))[1:] + ") AND `locale` IN (" + strings.Repeat(",?", len(locales))[1:] + ")"
+		found := make(map[uint64]map[string]map[string]string)
+		rows, closeF := e.GetMysql(schema.mysqlPoolName).Query(query, args...)
+		for rows.Next() {
+			var id uint64
+			var locale, field, value string
+			rows.Scan(&id, &locale, &field, &value)
+			if found[id] == nil {
+				found[id] = make(map[string]map[string]string)
+			}
+			if found[id][field] == nil {
+				found[id][field] = make(map[string]string)
+			}
+			found[id][field][locale] = value
+		}
+		closeF()
+		for id, orm := range orms {
+			orm.translations = nil
+			for _, column := range schema.translatedColumns {
+				for _, locale := range locales {
+					value, has := found[id][column][locale]
+					if !has {
+						continue
+					}
+					setTranslatedField(orm.elem.FieldByName(column), value)
+					if orm.translations == nil {
+						orm.translations = make(map[string]string)
+					}
+					orm.translations[column] = value
+					break
+				}
+			}
+		}
+	}
+}
+
+func setTranslatedField(field reflect.Value, value string) {
+	if field.Kind() == reflect.Ptr {
+		field.Set(reflect.ValueOf(&value))
+		return
+	}
+	field.SetString(value)
+}
+
+func (f *flusher) extractTranslations(orm *ORM, bindBuilder *bindBuilder) {
+	if !orm.inDB || orm.delete || f.engine.getI18nPlugin(orm.tableSchema) == nil {
+		return
+	}
+	var base *ORM
+	for _, column := range orm.tableSchema.translatedColumns {
+		value, has := bindBuilder.bind[column]
+		if !has {
+			continue
+		}
+		if base == nil {
+			base = orm.tableSchema.NewEntity().getORM()
+			base.binary = orm.binary
+			base.deserialize(f.getSerializer())
+		}
+		field := orm.elem.FieldByName(column)
+		translated := reflect.New(field.Type()).Elem()
+		translated.Set(field)
+		f.translationRestores = append(f.translationRestores, translationRestore{field: field, value: translated})
+		field.Set(base.elem.FieldByName(column))
+		delete(bindBuilder.bind, column)
+		delete(bindBuilder.sqlBind, column)
+		var asString *string
+		if value != nil {
+			converted := value.(string)
+			asString = &converted
+		}
+		current, hasCurrent := orm.translations[column]
+		if hasCurrent && asString != nil && current == *asString {
+			continue
+		}
+		if asString == nil {
+			delete(orm.translations, column)
+		} else {
+			if orm.translations == nil {
+				orm.translations = make(map[string]string)
+			}
+			orm.translations[column] = *asString
+		}
+		f.translations = append(f.translations, pendingTranslation{schema: orm.tableSchema, id: orm.GetID(),
+			column: column, locale: f.engine.locale, value: asString})
+	}
+}
+
+func (f *flusher) flushTranslations() {
+	for _, restore := range f.translationRestores {
+		restore.field.Set(restore.value)
+	}
+	for _, translation := range f.translations {
+		db := f.engine.GetMysql(translation.schema.mysqlPoolName)
+		if translation.value == nil {
+			db.Exec("DELETE FROM `"+translation.schema.i18nTableName+"` WHERE `entity_id` = ? AND `locale` = ? AND `field` = ?",
+				translation.id, translation.locale, translation.column)
+			continue
+		}
+		db.Exec("INSERT INTO `"+translation.schema.i18nTableName+"`(`entity_id`,`locale`,`field`,`value`) VALUES(?,?,?,?) "+
+			"ON DUPLICATE KEY UPDATE `value` = VALUES(`value`)", translation.id, translation.locale, translation.column, *translation.value)
+	}
+	f.translationRestores = nil
+	f.translations = nil
+}
@@ -0,0 +1,103 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type i18nEntity struct {
+	ORM         `orm:"i18nFallback=de|en;redisCache"`
+	ID          uint
+	Code        string
+	Name        string  `orm:"translate"`
+	Description *string `orm:"translate"`
+}
+
+type i18nEntityInvalid struct {
+	ORM
+	ID  uint
+	Age int `orm:"translate"`
+}
+
+func TestI18nPlugin(t *testing.T) {
+	var entity *i18nEntity
+	registry := &Registry{}
+	plugin := NewI18nPlugin("en")
+	registry.RegisterPlugin(plugin)
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	assert.Len(t, engine.GetAlters(), 0)
+	engine.GetMysql().Exec("TRUNCATE TABLE `_i18n_i18nEntity`")
+	assert.Equal(t, "en", plugin.GetDefaultLocale())
+
+	description := "A shoe"
+	entity = &i18nEntity{Code: "a", Name: "Shoe", Description: &description}
+	engine.Flush(entity)
+
+	engine.SetLocale("de")
+	assert.Equal(t, "de", engine.GetLocale())
+	assert.Equal(t, "de", engine.Clone().GetLocale())
+	entity = &i18nEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "Shoe", entity.Name)
+	entity.Name = "Schuh"
+	engine.Flush(entity)
+	assert.Equal(t, "Schuh", entity.Name)
+	assert.Equal(t, map[string]map[string]string{"de": {"Name": "Schuh"}}, plugin.GetTranslations(engine, entity))
+
+	entity = &i18nEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "Schuh", entity.Name)
+	assert.Equal(t, "A shoe", *entity.Description)
+	entity.Code = "b"
+	engine.Flush(entity)
+	assert.Equal(t, "Schuh", entity.Name)
+
+	engine.SetLocale("en")
+	entity = &i18nEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "Shoe", entity.Name)
+	assert.Equal(t, "b", entity.Code)
+
+	engine.SetLocale("ch")
+	entity = &i18nEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "Schuh", entity.Name)
+	newDescription := "Ein Schuh"
+	entity.Description = &newDescription
+	engine.Flush(entity)
+
+	engine.SetLocale("en")
+	var rows []*i18nEntity
+	engine.Search(NewWhere("1"), nil, &rows)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "Shoe", rows[0].Name)
+	engine.SetLocale("pl")
+	rows = nil
+	engine.Search(NewWhere("1"), nil, &rows)
+	assert.Equal(t, "Schuh", rows[0].Name)
+	assert.Equal(t, "A shoe", *rows[0].Description)
+	engine.SetLocale("ch")
+	rows = nil
+	engine.LoadByIDs([]uint64{1}, &rows)
+	assert.Equal(t, "Schuh", rows[0].Name)
+	assert.Equal(t, "Ein Schuh", *rows[0].Description)
+	rows[0].Description = nil
+	engine.Flush(rows[0])
+	assert.Equal(t, map[string]map[string]string{"de": {"Name": "Schuh"}}, plugin.GetTranslations(engine, rows[0]))
+
+	engine.SetLocale("")
+	entity = &i18nEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "Shoe", entity.Name)
+	assert.Equal(t, "A shoe", *entity.Description)
+
+	engine.Delete(entity)
+	assert.Len(t, plugin.GetTranslations(engine, entity), 0)
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&i18nEntityInvalid{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "translated field Age in beeorm.i18nEntityInvalid must be string")
+}
@@ -0,0 +1,45 @@
+package beeorm
+
+import (
+	"strconv"
+	"strings"
+)
+
+const maxIDInQueryLength = 21
+
+func (p *mySQLPoolConfig) getIDsChunkSize(queryLength int) int {
+	size := p.maxInSize
+	if p.maxAllowedPacket > 0 {
+		packetLimit := (p.maxAllowedPacket - queryLength) / maxIDInQueryLength
+		if packetLimit < 1 {
+			packetLimit = 1
+		}
+		if size == 0 || packetLimit < size {
+			size = packetLimit
+		}
+	}
+	return size
+}
+
+func chunkIDs(ids []uint64, size int) [][]uint64 {
+	if size <= 0 || len(ids) <= size {
+		return [][]uint64{ids}
+	}
+	chunks := make([][]uint64, 0, (len(ids)+size-1)/size)
+	for len(ids) > size {
+		chunks = append(chunks, ids[0:size])
+		ids = ids[size:]
+	}
+	return append(chunks, ids)
+}
+
+func joinIDs(ids []uint64) string {
+	var sb strings.Builder
+	for i, id := range ids {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(strconv.FormatUint(id, 10))
+	}
+	return sb.String()
+}
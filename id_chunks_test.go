@@ -0,0 +1,73 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type idChunksEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestChunkIDs(t *testing.T) {
+	assert.Equal(t, [][]uint64{{1, 2, 3}}, chunkIDs([]uint64{1, 2, 3}, 0))
+	assert.Equal(t, [][]uint64{{1, 2, 3}}, chunkIDs([]uint64{1, 2, 3}, 3))
+	assert.Equal(t, [][]uint64{{1, 2}, {3, 4}, {5}}, chunkIDs([]uint64{1, 2, 3, 4, 5}, 2))
+	assert.Equal(t, "1,20,300", joinIDs([]uint64{1, 20, 300}))
+
+	pool := &mySQLPoolConfig{}
+	assert.Equal(t, 0, pool.getIDsChunkSize(100))
+	pool.maxInSize = 500
+	assert.Equal(t, 500, pool.getIDsChunkSize(100))
+	pool.maxAllowedPacket = 100 + 21*10
+	assert.Equal(t, 10, pool.getIDsChunkSize(100))
+	pool.maxAllowedPacket = 50
+	assert.Equal(t, 1, pool.getIDsChunkSize(100))
+
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test?limit_connections=10&max_in_size=200&parseTime=true")
+	config := registry.mysqlPools["default"].(*mySQLPoolConfig)
+	assert.Equal(t, 10, config.getMaxConnections())
+	assert.Equal(t, 200, config.GetMaxInSize())
+	assert.Equal(t, "root:root@tcp(localhost:3311)/test?parseTime=true&multiStatements=true", config.GetDataSourceURI())
+}
+
+func TestLoadAndDeleteInChunks(t *testing.T) {
+	var entity *idChunksEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	config := engine.GetMysql().GetPoolConfig().(*mySQLPoolConfig)
+	assert.Greater(t, config.GetMaxAllowedPacket(), 0)
+	config.maxInSize = 2
+	defer func() {
+		config.maxInSize = 0
+	}()
+
+	flusher := engine.NewFlusher()
+	for i := 0; i < 5; i++ {
+		flusher.Track(&idChunksEntity{Name: "Name"})
+	}
+	flusher.Flush()
+
+	dbLogger := &testLogHandler{}
+	engine.RegisterQueryLogger(dbLogger, true, false, false)
+	var rows []*idChunksEntity
+	engine.LoadByIDs([]uint64{1, 2, 3, 4, 5}, &rows)
+	assert.Len(t, rows, 5)
+	for _, row := range rows {
+		assert.NotNil(t, row)
+		assert.Equal(t, "Name", row.Name)
+	}
+	assert.Len(t, dbLogger.Logs, 3)
+
+	dbLogger.clear()
+	for _, row := range rows {
+		flusher.Delete(row)
+	}
+	flusher.Flush()
+	assert.Len(t, dbLogger.Logs, 3)
+	found := engine.LoadByIDs([]uint64{1, 2, 3, 4, 5}, &rows)
+	assert.False(t, found)
+}
@@ -0,0 +1,105 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+var snowflakeEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+
+type IDGenerator interface {
+	NextID() uint64
+}
+
+type snowflakeIDGenerator struct {
+	mutex    sync.Mutex
+	lastTime int64
+	sequence uint64
+}
+
+func (g *snowflakeIDGenerator) NextID() uint64 {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	now := time.Now().UnixMilli() - snowflakeEpoch
+	if now < g.lastTime {
+		now = g.lastTime
+	}
+	if now == g.lastTime {
+		g.sequence = (g.sequence + 1) & 4095
+		if g.sequence == 0 {
+			now++
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.lastTime = now
+	return uint64(now)<<22 | (uuidServerID&1023)<<12 | g.sequence
+}
+
+// uuid7IDGenerator keeps the 48-bit unix millisecond prefix of UUIDv7, followed by server ID set with
+// SetUUIDServerID and per millisecond sequence, so generators on different servers never collide
+type uuid7IDGenerator struct {
+	mutex    sync.Mutex
+	lastTime int64
+	sequence uint64
+}
+
+func (g *uuid7IDGenerator) NextID() uint64 {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	now := time.Now().UnixMilli()
+	if now < g.lastTime {
+		now = g.lastTime
+	}
+	if now == g.lastTime {
+		g.sequence = (g.sequence + 1) & 255
+		if g.sequence == 0 {
+			now++
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.lastTime = now
+	return uint64(now)<<16 | (uuidServerID&255)<<8 | g.sequence
+}
+
+func (r *Registry) RegisterIDGenerator(code string, generator IDGenerator) {
+	if r.idGenerators == nil {
+		r.idGenerators = make(map[string]IDGenerator)
+	}
+	r.idGenerators[code] = generator
+}
+
+func getIDGenerator(registry *Registry, tableSchema *tableSchema, entityType reflect.Type) (IDGenerator, error) {
+	code := tableSchema.getTag("idGenerator", "", "")
+	if code == "" {
+		return nil, nil
+	}
+	idField, is := entityType.FieldByName("ID")
+	if is && idField.Type.String() != "uint64" {
+		return nil, fmt.Errorf("entity %s with id generator must be uint64", entityType.String())
+	}
+	generator, has := registry.idGenerators[code]
+	if has {
+		return generator, nil
+	}
+	switch code {
+	case "snowflake":
+		generator = &snowflakeIDGenerator{}
+	case "uuid7":
+		generator = &uuid7IDGenerator{}
+	default:
+		return nil, fmt.Errorf("unregistered id generator '%s' for entity %s", code, entityType.String())
+	}
+	registry.RegisterIDGenerator(code, generator)
+	return generator, nil
+}
+
+func (tableSchema *tableSchema) nextID() uint64 {
+	if tableSchema.idGenerator != nil {
+		return tableSchema.idGenerator.NextID()
+	}
+	return uuid()
+}
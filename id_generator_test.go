@@ -0,0 +1,114 @@
+package beeorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type idGeneratorSnowflakeEntity struct {
+	ORM  `orm:"idGenerator=snowflake;localCache;redisCache"`
+	ID   uint64
+	Name string
+}
+
+type idGeneratorCustomEntity struct {
+	ORM  `orm:"idGenerator=counter"`
+	ID   uint64
+	Name string
+}
+
+type idGeneratorInvalidEntity struct {
+	ORM `orm:"idGenerator=snowflake"`
+	ID  uint
+}
+
+type idGeneratorUnknownEntity struct {
+	ORM `orm:"idGenerator=unknown"`
+	ID  uint64
+}
+
+type testCounterIDGenerator struct {
+	counter uint64
+}
+
+func (g *testCounterIDGenerator) NextID() uint64 {
+	g.counter++
+	return g.counter
+}
+
+func TestIDGenerators(t *testing.T) {
+	snowflake := &snowflakeIDGenerator{}
+	uuid7 := &uuid7IDGenerator{}
+	lastSnowflake := uint64(0)
+	lastUUID7 := uint64(0)
+	for i := 0; i < 10000; i++ {
+		id := snowflake.NextID()
+		assert.Greater(t, id, lastSnowflake)
+		lastSnowflake = id
+		id = uuid7.NextID()
+		assert.Greater(t, id, lastUUID7)
+		assert.Less(t, id, uint64(1)<<63)
+		lastUUID7 = id
+	}
+}
+
+func TestUUID7IDGeneratorServerID(t *testing.T) {
+	defer SetUUIDServerID(0)
+	first := &uuid7IDGenerator{}
+	second := &uuid7IDGenerator{}
+	SetUUIDServerID(1)
+	id1 := first.NextID()
+	SetUUIDServerID(2)
+	id2 := second.NextID()
+	assert.NotEqual(t, id1, id2)
+	assert.Equal(t, uint64(1), id1>>8&255)
+	assert.Equal(t, uint64(2), id2>>8&255)
+	assert.InDelta(t, time.Now().UnixMilli(), int64(id2>>16), 1000)
+}
+
+func TestIDGeneratorInvalidSchema(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&idGeneratorInvalidEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "entity beeorm.idGeneratorInvalidEntity with id generator must be uint64")
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&idGeneratorUnknownEntity{})
+	_, err = registry.Validate()
+	assert.EqualError(t, err, "unregistered id generator 'unknown' for entity beeorm.idGeneratorUnknownEntity")
+}
+
+func TestIDGeneratorFlush(t *testing.T) {
+	var entity *idGeneratorSnowflakeEntity
+	var customEntity *idGeneratorCustomEntity
+	registry := &Registry{}
+	generator := &testCounterIDGenerator{counter: 100}
+	registry.RegisterIDGenerator("counter", generator)
+	engine := prepareTables(t, registry, 8, 6, "", entity, customEntity)
+
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema)
+	assert.True(t, schema.hasUUID)
+	alters := engine.GetAlters()
+	assert.Len(t, alters, 0)
+
+	entity = &idGeneratorSnowflakeEntity{Name: "a"}
+	entity2 := &idGeneratorSnowflakeEntity{Name: "b"}
+	engine.Flush(entity, entity2)
+	assert.Greater(t, entity.GetID(), uint64(1)<<22)
+	assert.Greater(t, entity2.GetID(), entity.GetID())
+	loaded := &idGeneratorSnowflakeEntity{}
+	assert.True(t, engine.LoadByID(entity2.GetID(), loaded))
+	assert.Equal(t, "b", loaded.Name)
+
+	customEntity = &idGeneratorCustomEntity{Name: "c"}
+	engine.Flush(customEntity)
+	assert.Equal(t, uint64(101), customEntity.GetID())
+	customEntity = &idGeneratorCustomEntity{ID: 5, Name: "d"}
+	engine.Flush(customEntity)
+	assert.Equal(t, uint64(5), customEntity.GetID())
+	assert.Equal(t, uint64(101), generator.counter)
+}
@@ -0,0 +1,56 @@
+package beeorm
+
+// EnableIdentityMap makes LoadByIDs, Search and references return one instance per entity ID.
+// LoadByID and Load fill the struct passed by caller, so when it is not the mapped instance it gets
+// state of that instance as last loaded or flushed, without its unsaved changes.
+func (e *engineImplementation) EnableIdentityMap() {
+	if e.identityMap == nil {
+		e.identityMap = make(map[*tableSchema]map[uint64]Entity)
+	}
+}
+
+func (e *engineImplementation) DisableIdentityMap() {
+	e.identityMap = nil
+}
+
+func (e *engineImplementation) ClearIdentityMap() {
+	if e.identityMap != nil {
+		e.identityMap = make(map[*tableSchema]map[uint64]Entity)
+	}
+}
+
+func (e *engineImplementation) getFromIdentityMap(schema *tableSchema, id uint64) (Entity, bool) {
+	if e.identityMap == nil || id == 0 {
+		return nil, false
+	}
+	entity, has := e.identityMap[schema][id]
+	return entity, has
+}
+
+func (e *engineImplementation) addToIdentityMap(entity Entity) Entity {
+	if e.identityMap == nil {
+		return entity
+	}
+	orm := entity.getORM()
+	id := orm.GetID()
+	if id == 0 {
+		return entity
+	}
+	entities, has := e.identityMap[orm.tableSchema]
+	if !has {
+		entities = make(map[uint64]Entity)
+		e.identityMap[orm.tableSchema] = entities
+	}
+	existing, has := entities[id]
+	if has {
+		return existing
+	}
+	entities[id] = entity
+	return entity
+}
+
+func (e *engineImplementation) removeFromIdentityMap(schema *tableSchema, id uint64) {
+	if e.identityMap != nil {
+		delete(e.identityMap[schema], id)
+	}
+}
@@ -0,0 +1,65 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type identityMapEntity struct {
+	ORM  `orm:"redisCache"`
+	ID   uint
+	Name string
+}
+
+func TestIdentityMap(t *testing.T) {
+	var entity *identityMapEntity
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	engine.Flush(&identityMapEntity{Name: "a"}, &identityMapEntity{Name: "b"}, &identityMapEntity{Name: "c"})
+
+	var rows []*identityMapEntity
+	engine.LoadByIDs([]uint64{1, 2}, &rows)
+	first := &identityMapEntity{}
+	engine.LoadByID(1, first)
+	assert.NotSame(t, rows[0], first)
+
+	engine.EnableIdentityMap()
+	engine.LoadByID(1, first)
+	first.Name = "changed"
+	var again []*identityMapEntity
+	engine.LoadByIDs([]uint64{3, 1, 1}, &again)
+	assert.Len(t, again, 3)
+	assert.Same(t, first, again[1])
+	assert.Same(t, first, again[2])
+	assert.Equal(t, "changed", again[1].Name)
+
+	dbLogger := &testLogHandler{}
+	engine.RegisterQueryLogger(dbLogger, true, true, false)
+	engine.Search(NewWhere("1 ORDER BY `ID`"), nil, &rows)
+	assert.Len(t, rows, 3)
+	assert.Same(t, first, rows[0])
+	assert.Same(t, again[0], rows[2])
+	dbLogger.clear()
+	second := &identityMapEntity{}
+	assert.True(t, engine.LoadByID(1, second))
+	assert.Equal(t, "a", second.Name)
+	assert.Len(t, dbLogger.Logs, 0)
+
+	engine.Delete(first)
+	assert.False(t, engine.LoadByID(1, &identityMapEntity{}))
+
+	engine.ClearIdentityMap()
+	engine.LoadByIDs([]uint64{2}, &again)
+	assert.NotSame(t, rows[1], again[0])
+
+	engine.GetRedis().FlushDB()
+	fromDB := &identityMapEntity{}
+	assert.True(t, engine.LoadByID(3, fromDB))
+	engine.LoadByIDs([]uint64{3}, &again)
+	assert.Same(t, fromDB, again[0])
+
+	engine.DisableIdentityMap()
+	engine.LoadByIDs([]uint64{2}, &rows)
+	assert.NotSame(t, rows[0], again[0])
+}
@@ -0,0 +1,163 @@
+package beeorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+const indexerStreamPrefix = "beeorm-indexer-"
+
+type IndexerHandler func(engine Engine, schema TableSchema, ids []uint64) error
+
+type IndexerEvent struct {
+	Entity string
+	ID     uint64
+	Type   FlushType
+}
+
+type IndexerMetrics struct {
+	Processed uint64
+	Retried   uint64
+	Failed    uint64
+	Lag       int64
+	Pending   uint64
+}
+
+type indexerDefinition struct {
+	name     string
+	stream   string
+	handler  IndexerHandler
+	entities []reflect.Type
+}
+
+type IndexerConsumer struct {
+	eventConsumerBase
+	indexer   *indexerDefinition
+	batchSize int
+	retries   int
+	backoff   time.Duration
+	processed uint64
+	retried   uint64
+	failed    uint64
+}
+
+func (r *Registry) RegisterIndexer(name, redisPool string, handler IndexerHandler, entity ...Entity) {
+	if r.indexers == nil {
+		r.indexers = make(map[string]*indexerDefinition)
+	}
+	if _, has := r.indexers[name]; has {
+		panic(fmt.Errorf("indexer '%s' already registered", name))
+	}
+	definition := &indexerDefinition{name: name, stream: indexerStreamPrefix + name, handler: handler}
+	for _, e := range entity {
+		t := reflect.TypeOf(e)
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		definition.entities = append(definition.entities, t)
+	}
+	r.RegisterRedisStream(definition.stream, redisPool, []string{definition.stream})
+	r.indexers[name] = definition
+}
+
+func initIndexers(r *Registry, registry *validatedRegistry) error {
+	for name, definition := range r.indexers {
+		for _, t := range definition.entities {
+			schema := registry.tableSchemas[t]
+			if schema == nil {
+				return fmt.Errorf("indexer '%s' entity '%s' is not registered", name, t.String())
+			}
+			schema.indexerStreams = append(schema.indexerStreams, definition.stream)
+		}
+	}
+	return nil
+}
+
+func (f *flusher) addIndexerEvents(flushType FlushType, schema *tableSchema, id uint64) {
+	for _, stream := range schema.indexerStreams {
+		f.getRedisFlusher().Publish(stream, IndexerEvent{Entity: schema.t.String(), ID: id, Type: flushType})
+	}
+}
+
+func NewIndexerConsumer(engine Engine, name string) *IndexerConsumer {
+	e := engine.(*engineImplementation)
+	definition, has := e.registry.registry.indexers[name]
+	if !has {
+		panic(fmt.Errorf("indexer '%s' is not registered", name))
+	}
+	c := &IndexerConsumer{indexer: definition, batchSize: 100, retries: 3, backoff: time.Second}
+	c.engine = e
+	c.block = true
+	c.blockTime = time.Second * 30
+	return c
+}
+
+func (c *IndexerConsumer) SetBatchSize(size int) {
+	c.batchSize = size
+}
+
+func (c *IndexerConsumer) SetRetries(retries int, backoff time.Duration) {
+	c.retries = retries
+	c.backoff = backoff
+}
+
+func (c *IndexerConsumer) Digest(ctx context.Context) bool {
+	consumer := c.engine.GetEventBroker().Consumer(c.indexer.stream).(*eventsConsumer)
+	consumer.eventConsumerBase = c.eventConsumerBase
+	return consumer.Consume(ctx, c.batchSize, func(events []Event) {
+		entities := make([]string, 0)
+		ids := make(map[string][]uint64)
+		added := make(map[string]map[uint64]bool)
+		for _, event := range events {
+			var data IndexerEvent
+			event.Unserialize(&data)
+			if _, has := ids[data.Entity]; !has {
+				entities = append(entities, data.Entity)
+				added[data.Entity] = make(map[uint64]bool)
+			}
+			if !added[data.Entity][data.ID] {
+				added[data.Entity][data.ID] = true
+				ids[data.Entity] = append(ids[data.Entity], data.ID)
+			}
+		}
+		for _, entity := range entities {
+			t, has := c.engine.registry.entities[entity]
+			if !has {
+				continue
+			}
+			c.handle(getTableSchema(c.engine.registry, t), ids[entity])
+		}
+	})
+}
+
+func (c *IndexerConsumer) handle(schema *tableSchema, ids []uint64) {
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		err := c.indexer.handler(c.engine, schema, ids)
+		if err == nil {
+			atomic.AddUint64(&c.processed, uint64(len(ids)))
+			return
+		}
+		if attempt >= c.retries {
+			atomic.AddUint64(&c.failed, uint64(len(ids)))
+			panic(fmt.Errorf("indexer '%s' failed for %s: %w", c.indexer.name, schema.t.String(), err))
+		}
+		atomic.AddUint64(&c.retried, uint64(len(ids)))
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (c *IndexerConsumer) GetMetrics() IndexerMetrics {
+	stats := c.engine.GetEventBroker().GetStreamGroupStatistics(c.indexer.stream, c.indexer.stream)
+	return IndexerMetrics{
+		Processed: atomic.LoadUint64(&c.processed),
+		Retried:   atomic.LoadUint64(&c.retried),
+		Failed:    atomic.LoadUint64(&c.failed),
+		Lag:       stats.Lag,
+		Pending:   stats.Pending,
+	}
+}
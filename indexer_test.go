@@ -0,0 +1,66 @@
+package beeorm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type indexerEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestIndexer(t *testing.T) {
+	var entity *indexerEntity
+	indexed := make([][]uint64, 0)
+	fail := 1
+	registry := &Registry{}
+	registry.RegisterIndexer("search", "default", func(engine Engine, schema TableSchema, ids []uint64) error {
+		assert.Equal(t, "beeorm.indexerEntity", schema.GetType().String())
+		if fail > 0 {
+			fail--
+			return fmt.Errorf("unavailable")
+		}
+		indexed = append(indexed, ids)
+		return nil
+	}, entity)
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+
+	e1 := &indexerEntity{Name: "a"}
+	e2 := &indexerEntity{Name: "b"}
+	engine.Flush(e1, e2)
+	e1.Name = "c"
+	engine.Flush(e1)
+	engine.Delete(e2)
+
+	consumer := NewIndexerConsumer(engine, "search")
+	consumer.DisableBlockMode()
+	consumer.blockTime = time.Millisecond
+	consumer.SetRetries(1, time.Millisecond)
+	assert.Equal(t, int64(4), consumer.GetMetrics().Lag)
+	consumer.Digest(context.Background())
+	assert.Equal(t, [][]uint64{{1, 2}}, indexed)
+	metrics := consumer.GetMetrics()
+	assert.Equal(t, uint64(2), metrics.Processed)
+	assert.Equal(t, uint64(2), metrics.Retried)
+	assert.Equal(t, uint64(0), metrics.Failed)
+	assert.Equal(t, int64(0), metrics.Lag)
+
+	fail = 2
+	engine.Flush(&indexerEntity{Name: "d"})
+	assert.Panics(t, func() {
+		consumer.Digest(context.Background())
+	})
+	assert.Equal(t, uint64(1), consumer.GetMetrics().Failed)
+	consumer.Digest(context.Background())
+	assert.Equal(t, [][]uint64{{1, 2}, {3}}, indexed)
+
+	assert.PanicsWithError(t, "indexer 'invalid' is not registered", func() {
+		NewIndexerConsumer(engine, "invalid")
+	})
+}
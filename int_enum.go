@@ -0,0 +1,112 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+type intEnum struct {
+	t      reflect.Type
+	names  map[int64]string
+	values map[string]int64
+	keys   []int64
+}
+
+func (r *Registry) RegisterIntEnum(names interface{}) {
+	mapping := reflect.ValueOf(names)
+	if mapping.Kind() != reflect.Map || mapping.Type().Elem().Kind() != reflect.String {
+		panic(fmt.Errorf("int enum mapping must be map[T]string, %T given", names))
+	}
+	t := mapping.Type().Key()
+	if t.PkgPath() == "" || !isIntKind(t.Kind()) {
+		panic(fmt.Errorf("int enum type %s must be a named integer type", t.String()))
+	}
+	enum := &intEnum{t: t, names: make(map[int64]string), values: make(map[string]int64)}
+	iter := mapping.MapRange()
+	for iter.Next() {
+		value := intEnumValue(iter.Key())
+		enum.names[value] = iter.Value().String()
+		enum.values[iter.Value().String()] = value
+		enum.keys = append(enum.keys, value)
+	}
+	sort.Slice(enum.keys, func(i, j int) bool {
+		return enum.keys[i] < enum.keys[j]
+	})
+	if r.intEnums == nil {
+		r.intEnums = make(map[reflect.Type]*intEnum)
+	}
+	r.intEnums[t] = enum
+}
+
+func getIntEnum(registry *Registry, t reflect.Type) (enum *intEnum, nullable bool) {
+	if t.Kind() == reflect.Ptr {
+		nullable = true
+		t = t.Elem()
+	}
+	return registry.intEnums[t], nullable
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func intEnumValue(f reflect.Value) int64 {
+	switch f.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(f.Uint())
+	default:
+		return f.Int()
+	}
+}
+
+func (e *intEnum) definition(version int, registry *Registry, attributes map[string]string) (string, error) {
+	switch attributes["enumStorage"] {
+	case "", "int":
+		return convertIntToSchema(version, e.t.Kind().String(), attributes), nil
+	case "enum":
+		definition := "enum("
+		for i, key := range e.keys {
+			if i > 0 {
+				definition += ","
+			}
+			definition += "'" + e.names[key] + "'"
+		}
+		definition += ")"
+		if version == 8 {
+			definition += " CHARACTER SET " + registry.defaultEncoding + " COLLATE " + registry.defaultEncoding + "_0900_ai_ci"
+		}
+		return definition, nil
+	}
+	return "", fmt.Errorf("invalid enum storage '%s' for int enum %s", attributes["enumStorage"], e.t.String())
+}
+
+func (e *intEnum) format(f reflect.Value, enumStorage bool) (string, bool) {
+	value := intEnumValue(f)
+	name, has := e.names[value]
+	if enumStorage && has {
+		return name, true
+	}
+	return strconv.FormatInt(value, 10), has
+}
+
+func (e *intEnum) set(f reflect.Value, value string, enumStorage bool) {
+	var asInt int64
+	if enumStorage {
+		asInt = e.values[value]
+	} else {
+		asInt, _ = strconv.ParseInt(value, 10, 64)
+	}
+	switch f.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f.SetUint(uint64(asInt))
+	default:
+		f.SetInt(asInt)
+	}
+}
@@ -0,0 +1,78 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testIntEnumStatus uint8
+
+const (
+	testIntEnumStatusActive   testIntEnumStatus = 1
+	testIntEnumStatusInactive testIntEnumStatus = 2
+)
+
+type testIntEnumPriority int16
+
+type intEnumEntity struct {
+	ORM
+	ID       uint
+	Status   testIntEnumStatus
+	Priority *testIntEnumPriority `orm:"enumStorage=enum"`
+}
+
+func TestIntEnums(t *testing.T) {
+	var entity *intEnumEntity
+	registry := &Registry{}
+	registry.RegisterIntEnum(map[testIntEnumStatus]string{testIntEnumStatusActive: "active", testIntEnumStatusInactive: "inactive"})
+	registry.RegisterIntEnum(map[testIntEnumPriority]string{-1: "low", 0: "normal", 1: "high"})
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	schema.DropTable(engine)
+	has, alters := schema.GetSchemaChanges(engine)
+	assert.True(t, has)
+	assert.Equal(t, "CREATE TABLE `test`.`intEnumEntity` (\n  `ID` int(10) unsigned NOT NULL AUTO_INCREMENT,\n  "+
+		"`Status` tinyint(3) unsigned NOT NULL,\n  `Priority` enum('low','normal','high') DEFAULT NULL,\n  "+
+		"PRIMARY KEY (`ID`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;", alters[0].SQL)
+	schema.UpdateSchema(engine)
+	assert.Len(t, engine.GetAlters(), 0)
+
+	high := testIntEnumPriority(1)
+	entity = &intEnumEntity{Status: testIntEnumStatusActive, Priority: &high}
+	engine.Flush(entity)
+	var priority string
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Priority` FROM `intEnumEntity` WHERE `ID` = 1"), &priority)
+	assert.Equal(t, "high", priority)
+
+	entity = &intEnumEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, testIntEnumStatusActive, entity.Status)
+	assert.Equal(t, high, *entity.Priority)
+	assert.False(t, entity.IsDirty())
+
+	entity.Status = testIntEnumStatusInactive
+	entity.Priority = nil
+	engine.Flush(entity)
+	entity = &intEnumEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, testIntEnumStatusInactive, entity.Status)
+	assert.Nil(t, entity.Priority)
+
+	entity.Status = 7
+	err := engine.FlushWithCheck(entity)
+	assert.EqualError(t, err, "validation failed for beeorm.intEnumEntity: Status: invalid value '7'")
+}
+
+func TestIntEnumInvalidRegistration(t *testing.T) {
+	registry := &Registry{}
+	assert.PanicsWithError(t, "int enum mapping must be map[T]string, []string given", func() {
+		registry.RegisterIntEnum([]string{"a"})
+	})
+	assert.PanicsWithError(t, "int enum type int must be a named integer type", func() {
+		registry.RegisterIntEnum(map[int]string{1: "a"})
+	})
+	assert.PanicsWithError(t, "int enum type string must be a named integer type", func() {
+		registry.RegisterIntEnum(map[string]string{"a": "a"})
+	})
+}
@@ -0,0 +1,270 @@
+package beeorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const jobStatusEnum = "beeorm_job_status"
+
+const (
+	JobStatusPending  = "pending"
+	JobStatusRunning  = "running"
+	JobStatusDone     = "done"
+	JobStatusFailed   = "failed"
+	JobStatusCanceled = "canceled"
+)
+
+var jobEntityType = reflect.TypeOf(JobEntity{})
+
+var jobStatusTransitions = map[string][]string{
+	JobStatusPending: {JobStatusRunning, JobStatusCanceled},
+	JobStatusRunning: {JobStatusPending, JobStatusDone, JobStatusFailed},
+}
+
+type JobEntity struct {
+	ORM         `orm:"table=_beeorm_jobs"`
+	ID          uint64
+	Queue       string    `orm:"length=100;required;index=Ready:1"`
+	Status      string    `orm:"enum=beeorm_job_status;required;index=Ready:2"`
+	RunAt       time.Time `orm:"time;index=Ready:3"`
+	Payload     string    `orm:"length=max"`
+	Attempts    uint16
+	MaxAttempts uint16
+	LockedUntil *time.Time `orm:"time"`
+	LastError   string     `orm:"length=max"`
+	CreatedAt   time.Time  `orm:"time"`
+	FinishedAt  *time.Time `orm:"time"`
+}
+
+type JobOptions struct {
+	Delay       time.Duration
+	MaxAttempts int
+}
+
+type JobHandler func(ctx context.Context, job *JobEntity) error
+
+type JobWorkerMetrics struct {
+	Claimed   uint64
+	Succeeded uint64
+	Retried   uint64
+	Failed    uint64
+}
+
+type JobWorker struct {
+	eventConsumerBase
+	queue             string
+	handler           JobHandler
+	batchSize         int
+	visibilityTimeout time.Duration
+	retryBackoff      time.Duration
+	claimed           uint64
+	succeeded         uint64
+	retried           uint64
+	failed            uint64
+}
+
+func (r *Registry) RegisterJobs() {
+	r.RegisterEnum(jobStatusEnum, []string{JobStatusPending, JobStatusRunning, JobStatusDone, JobStatusFailed, JobStatusCanceled})
+	r.RegisterEntity(&JobEntity{})
+}
+
+func (job *JobEntity) Unserialize(value interface{}) {
+	checkError(jsoniter.ConfigFastest.UnmarshalFromString(job.Payload, value))
+}
+
+func (job *JobEntity) transition(status string) {
+	for _, allowed := range jobStatusTransitions[job.Status] {
+		if allowed == status {
+			job.Status = status
+			return
+		}
+	}
+	panic(fmt.Errorf("invalid job status transition from %s to %s", job.Status, status))
+}
+
+func (e *engineImplementation) EnqueueJob(queue string, payload interface{}, options ...*JobOptions) *JobEntity {
+	asString, err := jsoniter.ConfigFastest.MarshalToString(payload)
+	checkError(err)
+	now := e.now()
+	job := &JobEntity{Queue: queue, Status: JobStatusPending, RunAt: now, Payload: asString, MaxAttempts: 1, CreatedAt: now}
+	if len(options) > 0 && options[0] != nil {
+		job.RunAt = now.Add(options[0].Delay)
+		if options[0].MaxAttempts > 0 {
+			job.MaxAttempts = uint16(options[0].MaxAttempts)
+		}
+	}
+	e.Flush(job)
+	return job
+}
+
+func (e *engineImplementation) CancelJob(job *JobEntity) {
+	job.transition(JobStatusCanceled)
+	now := e.now()
+	job.FinishedAt = &now
+	e.Flush(job)
+}
+
+func (e *engineImplementation) GetJobQueueStatistics(queue string) map[string]int {
+	schema := getTableSchema(e.registry, jobEntityType)
+	if schema == nil {
+		panic(fmt.Errorf("jobs are not registered"))
+	}
+	stats := map[string]int{JobStatusPending: 0, JobStatusRunning: 0, JobStatusDone: 0, JobStatusFailed: 0, JobStatusCanceled: 0}
+	status := schema.getColumnName("Status")
+	/* #nosec */
+	results, def := schema.GetMysql(e).Query("SELECT `"+status+"`, COUNT(*) FROM `"+schema.tableName+"` WHERE `"+
+		schema.getColumnName("Queue")+"` = ? GROUP BY `"+status+"`", queue)
+	defer def()
+	for results.Next() {
+		var status string
+		var total int
+		results.Scan(&status, &total)
+		stats[status] = total
+	}
+	return stats
+}
+
+func NewJobWorker(engine Engine, queue string, handler JobHandler) *JobWorker {
+	w := &JobWorker{queue: queue, handler: handler, batchSize: 10, visibilityTimeout: time.Minute * 5, retryBackoff: time.Second * 10}
+	w.engine = engine.(*engineImplementation)
+	w.block = true
+	w.blockTime = time.Second
+	return w
+}
+
+func (w *JobWorker) SetBatchSize(size int) {
+	w.batchSize = size
+}
+
+func (w *JobWorker) SetVisibilityTimeout(timeout time.Duration) {
+	w.visibilityTimeout = timeout
+}
+
+func (w *JobWorker) SetRetryBackoff(backoff time.Duration) {
+	w.retryBackoff = backoff
+}
+
+func (w *JobWorker) GetMetrics() JobWorkerMetrics {
+	return JobWorkerMetrics{
+		Claimed:   atomic.LoadUint64(&w.claimed),
+		Succeeded: atomic.LoadUint64(&w.succeeded),
+		Retried:   atomic.LoadUint64(&w.retried),
+		Failed:    atomic.LoadUint64(&w.failed),
+	}
+}
+
+func (w *JobWorker) Run(ctx context.Context) {
+	for {
+		processed := w.RunOnce(ctx)
+		if !w.block {
+			return
+		}
+		if processed == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(w.blockTime):
+			}
+		} else if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (w *JobWorker) RunOnce(ctx context.Context) int {
+	jobs := w.claim()
+	for _, job := range jobs {
+		err := w.handle(ctx, job)
+		now := w.engine.now()
+		job.LockedUntil = nil
+		if err == nil {
+			job.transition(JobStatusDone)
+			job.FinishedAt = &now
+			job.LastError = ""
+			atomic.AddUint64(&w.succeeded, 1)
+		} else if job.Attempts >= job.MaxAttempts {
+			job.transition(JobStatusFailed)
+			job.FinishedAt = &now
+			job.LastError = err.Error()
+			atomic.AddUint64(&w.failed, 1)
+		} else {
+			job.transition(JobStatusPending)
+			job.RunAt = now.Add(w.retryBackoff * time.Duration(job.Attempts))
+			job.LastError = err.Error()
+			atomic.AddUint64(&w.retried, 1)
+		}
+		w.engine.Flush(job)
+	}
+	return len(jobs)
+}
+
+func (w *JobWorker) handle(ctx context.Context, job *JobEntity) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			asErr, is := rec.(error)
+			if !is {
+				asErr = fmt.Errorf("%v", rec)
+			}
+			err = asErr
+		}
+	}()
+	return w.handler(ctx, job)
+}
+
+func (w *JobWorker) claim() []*JobEntity {
+	schema := getTableSchema(w.engine.registry, jobEntityType)
+	if schema == nil {
+		panic(fmt.Errorf("jobs are not registered"))
+	}
+	db := schema.GetMysql(w.engine)
+	now := w.engine.now().Format(timeFormat)
+	lockedUntil := w.engine.now().Add(w.visibilityTimeout).Format(timeFormat)
+	status := schema.getColumnName("Status")
+	runAt := schema.getColumnName("RunAt")
+	locked := schema.getColumnName("LockedUntil")
+	attempts := schema.getColumnName("Attempts")
+	query := "SELECT `ID` FROM `" + schema.tableName + "` WHERE `" + schema.getColumnName("Queue") + "` = ? AND ((`" + status +
+		"` = ? AND `" + runAt + "` <= ?) OR (`" + status + "` = ? AND `" + locked + "` < ?)) ORDER BY `" + runAt + "` LIMIT " +
+		strconv.Itoa(w.batchSize) + " FOR UPDATE"
+	if db.GetPoolConfig().GetVersion() >= 8 {
+		query += " SKIP LOCKED"
+	}
+	db.Begin()
+	defer db.Rollback()
+	results, def := db.Query(query, w.queue, JobStatusPending, now, JobStatusRunning, now)
+	ids := make([]uint64, 0)
+	for results.Next() {
+		var id uint64
+		results.Scan(&id)
+		ids = append(ids, id)
+	}
+	def()
+	if len(ids) == 0 {
+		return nil
+	}
+	/* #nosec */
+	db.Exec("UPDATE `"+schema.tableName+"` SET `"+status+"` = ?, `"+locked+"` = ?, `"+attempts+"` = `"+attempts+"` + 1 WHERE `ID` IN ("+
+		strings.Repeat(",?", len(ids))[1:]+")", append([]interface{}{JobStatusRunning, lockedUntil}, uint64SliceToInterfaces(ids)...)...)
+	db.Commit()
+	clearByIDs(w.engine, schema.NewEntity(), ids...)
+	var jobs []*JobEntity
+	w.engine.LoadByIDs(ids, &jobs)
+	atomic.AddUint64(&w.claimed, uint64(len(ids)))
+	return jobs
+}
+
+func uint64SliceToInterfaces(ids []uint64) []interface{} {
+	result := make([]interface{}, len(ids))
+	for i, id := range ids {
+		result[i] = id
+	}
+	return result
+}
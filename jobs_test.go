@@ -0,0 +1,100 @@
+package beeorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type jobsTestPayload struct {
+	Email string
+}
+
+func TestJobs(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterJobs()
+	engine := prepareTables(t, registry, 8, 6, "")
+	engine.GetRegistry().GetTableSchemaForEntity(&JobEntity{}).TruncateTable(engine)
+
+	job := engine.EnqueueJob("emails", jobsTestPayload{Email: "a@test.com"})
+	assert.Greater(t, job.GetID(), uint64(0))
+	assert.Equal(t, JobStatusPending, job.Status)
+	engine.EnqueueJob("emails", jobsTestPayload{Email: "b@test.com"}, &JobOptions{MaxAttempts: 2})
+	engine.EnqueueJob("emails", jobsTestPayload{Email: "c@test.com"}, &JobOptions{Delay: time.Hour})
+	canceled := engine.EnqueueJob("emails", jobsTestPayload{Email: "d@test.com"}, &JobOptions{Delay: time.Hour})
+	engine.CancelJob(canceled)
+	assert.Equal(t, JobStatusCanceled, canceled.Status)
+	assert.PanicsWithError(t, "invalid job status transition from canceled to running", func() {
+		canceled.transition(JobStatusRunning)
+	})
+
+	handled := make([]string, 0)
+	worker := NewJobWorker(engine, "emails", func(ctx context.Context, job *JobEntity) error {
+		payload := jobsTestPayload{}
+		job.Unserialize(&payload)
+		handled = append(handled, payload.Email)
+		if payload.Email == "b@test.com" {
+			return errors.New("smtp error")
+		}
+		return nil
+	})
+	worker.DisableBlockMode()
+	worker.SetRetryBackoff(0)
+	worker.Run(context.Background())
+	assert.Equal(t, []string{"a@test.com", "b@test.com"}, handled)
+	assert.Equal(t, JobWorkerMetrics{Claimed: 2, Succeeded: 1, Retried: 1}, worker.GetMetrics())
+
+	job = &JobEntity{}
+	assert.True(t, engine.LoadByID(1, job))
+	assert.Equal(t, JobStatusDone, job.Status)
+	assert.NotNil(t, job.FinishedAt)
+	assert.Nil(t, job.LockedUntil)
+	job = &JobEntity{}
+	assert.True(t, engine.LoadByID(2, job))
+	assert.Equal(t, JobStatusPending, job.Status)
+	assert.Equal(t, uint16(1), job.Attempts)
+	assert.Equal(t, "smtp error", job.LastError)
+
+	handled = handled[0:0]
+	assert.Equal(t, 1, worker.RunOnce(context.Background()))
+	assert.Equal(t, []string{"b@test.com"}, handled)
+	job = &JobEntity{}
+	assert.True(t, engine.LoadByID(2, job))
+	assert.Equal(t, JobStatusFailed, job.Status)
+	assert.Equal(t, uint16(2), job.Attempts)
+	assert.Equal(t, uint64(1), worker.GetMetrics().Failed)
+
+	stats := engine.GetJobQueueStatistics("emails")
+	assert.Equal(t, map[string]int{JobStatusPending: 1, JobStatusRunning: 0, JobStatusDone: 1, JobStatusFailed: 1, JobStatusCanceled: 1}, stats)
+}
+
+func TestJobsVisibilityTimeout(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterJobs()
+	engine := prepareTables(t, registry, 8, 6, "")
+	engine.GetRegistry().GetTableSchemaForEntity(&JobEntity{}).TruncateTable(engine)
+	engine.EnqueueJob("reports", "monthly", &JobOptions{MaxAttempts: 3})
+
+	crashed := NewJobWorker(engine, "reports", func(ctx context.Context, job *JobEntity) error {
+		panic(errors.New("worker crashed"))
+	})
+	crashed.SetVisibilityTimeout(-time.Second)
+	crashed.claim()
+
+	var payload string
+	worker := NewJobWorker(engine, "reports", func(ctx context.Context, job *JobEntity) error {
+		job.Unserialize(&payload)
+		return nil
+	})
+	assert.Equal(t, 1, worker.RunOnce(context.Background()))
+	assert.Equal(t, "monthly", payload)
+	job := &JobEntity{}
+	assert.True(t, engine.LoadByID(1, job))
+	assert.Equal(t, JobStatusDone, job.Status)
+	assert.Equal(t, uint16(2), job.Attempts)
+
+	assert.Equal(t, 0, crashed.RunOnce(context.Background()))
+}
@@ -0,0 +1,81 @@
+package beeorm
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+func getJSONIndexColumns(version int, columnName, tag string, indexes map[string]*index) ([][2]string, error) {
+	columns := make([][2]string, 0)
+	for _, definition := range strings.Split(tag, ",") {
+		parts := strings.Split(definition, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || !strings.HasPrefix(parts[1], "$") {
+			return nil, fmt.Errorf("invalid jsonIndex '%s' for field %s", definition, columnName)
+		}
+		columnType := "varchar(255)"
+		if len(parts) == 3 {
+			columnType = parts[2]
+		}
+		path := "'" + strings.ReplaceAll(parts[1], "'", "''") + "'"
+		if version == 8 {
+			path = "_utf8mb4" + path
+		}
+		expression := fmt.Sprintf("json_extract(`%s`,%s)", columnName, path)
+		if strings.HasPrefix(columnType, "varchar") || strings.HasPrefix(columnType, "char") {
+			expression = "json_unquote(" + expression + ")"
+		}
+		columns = append(columns, [2]string{parts[0], fmt.Sprintf("`%s` %s GENERATED ALWAYS AS (%s) VIRTUAL", parts[0], columnType, expression)})
+		indexes[parts[0]] = &index{Unique: false, Columns: map[int]string{1: parts[0]}}
+	}
+	return columns, nil
+}
+
+func buildJSONSetExpression(column string, old, new []byte) (string, bool) {
+	oldValues := make(map[string]jsoniter.RawMessage)
+	newValues := make(map[string]jsoniter.RawMessage)
+	if jsoniter.ConfigFastest.Unmarshal(old, &oldValues) != nil || jsoniter.ConfigFastest.Unmarshal(new, &newValues) != nil {
+		return "", false
+	}
+	changed := make([]string, 0)
+	removed := make([]string, 0)
+	for key, value := range newValues {
+		oldValue, has := oldValues[key]
+		if !has || !bytes.Equal(oldValue, value) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range oldValues {
+		if _, has := newValues[key]; !has {
+			removed = append(removed, key)
+		}
+	}
+	if len(changed)+len(removed) >= len(newValues) {
+		return "", false
+	}
+	sort.Strings(changed)
+	sort.Strings(removed)
+	expression := "`" + column + "`"
+	if len(removed) > 0 {
+		expression = "JSON_REMOVE(" + expression
+		for _, key := range removed {
+			expression += "," + escapeSQLString(jsonObjectPath(key))
+		}
+		expression += ")"
+	}
+	if len(changed) > 0 {
+		expression = "JSON_SET(" + expression
+		for _, key := range changed {
+			expression += "," + escapeSQLString(jsonObjectPath(key)) + ",CAST(" + escapeSQLString(string(newValues[key])) + " AS JSON)"
+		}
+		expression += ")"
+	}
+	return expression, true
+}
+
+func jsonObjectPath(key string) string {
+	return `$."` + strings.ReplaceAll(strings.ReplaceAll(key, `\`, `\\`), `"`, `\"`) + `"`
+}
@@ -0,0 +1,82 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type jsonColumnsSettings struct {
+	Theme    string
+	Language string
+	Tags     []string
+	Limits   map[string]int
+}
+
+type jsonColumnsEntity struct {
+	ORM
+	ID       uint
+	Settings *jsonColumnsSettings `orm:"jsonSet;jsonIndex=SettingsLanguage:$.Language:varchar(10)"`
+	Meta     map[string]interface{}
+}
+
+func TestJSONColumns(t *testing.T) {
+	var entity *jsonColumnsEntity
+	engine := prepareTables(t, &Registry{}, 8, 6, "", entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	schema.DropTable(engine)
+	has, alters := schema.GetSchemaChanges(engine)
+	assert.True(t, has)
+	assert.Equal(t, "CREATE TABLE `test`.`jsonColumnsEntity` (\n  `ID` int unsigned NOT NULL AUTO_INCREMENT,\n  "+
+		"`Settings` json DEFAULT NULL,\n  `SettingsLanguage` varchar(10) GENERATED ALWAYS AS "+
+		"(json_unquote(json_extract(`Settings`,_utf8mb4'$.Language'))) VIRTUAL,\n  `Meta` json DEFAULT NULL,\n  "+
+		"INDEX `SettingsLanguage` (`SettingsLanguage`),\n  PRIMARY KEY (`ID`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;", alters[0].SQL)
+	schema.UpdateSchema(engine)
+	assert.Len(t, engine.GetAlters(), 0)
+
+	entity = &jsonColumnsEntity{Settings: &jsonColumnsSettings{Theme: "dark", Language: "en", Tags: []string{"a"}}}
+	engine.Flush(entity)
+	assert.False(t, entity.IsDirty())
+	entity.Settings.Tags = []string{"a"}
+	assert.False(t, entity.IsDirty())
+
+	dbLogger := &testLogHandler{}
+	engine.RegisterQueryLogger(dbLogger, true, false, false)
+	entity.Settings.Theme = "light"
+	engine.Flush(entity)
+	assert.Len(t, dbLogger.Logs, 1)
+	assert.Equal(t, "UPDATE `jsonColumnsEntity` SET `Settings`=JSON_SET(`Settings`,'$.\\\"Theme\\\"',CAST('\\\"light\\\"' AS JSON)) WHERE `ID` = 1",
+		dbLogger.Logs[0]["query"])
+
+	var id uint
+	found := engine.GetMysql().QueryRow(NewWhere("SELECT `ID` FROM `jsonColumnsEntity` WHERE `SettingsLanguage` = ?", "en"), &id)
+	assert.True(t, found)
+	assert.Equal(t, uint(1), id)
+
+	entity = &jsonColumnsEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "light", entity.Settings.Theme)
+	assert.Equal(t, "en", entity.Settings.Language)
+	assert.Equal(t, []string{"a"}, entity.Settings.Tags)
+}
+
+func TestJSONSetExpression(t *testing.T) {
+	expression, valid := buildJSONSetExpression("Meta", []byte(`{"a":1,"b":"x","c":[1],"d":null}`), []byte(`{"a":2,"b":"x","c":[1]}`))
+	assert.True(t, valid)
+	assert.Equal(t, "JSON_SET(JSON_REMOVE(`Meta`,'$.\\\"d\\\"'),'$.\\\"a\\\"',CAST('2' AS JSON))", expression)
+	_, valid = buildJSONSetExpression("Meta", []byte(`{"a":1}`), []byte(`{"a":2}`))
+	assert.False(t, valid)
+	_, valid = buildJSONSetExpression("Meta", []byte(`[1,2]`), []byte(`[1,3]`))
+	assert.False(t, valid)
+
+	indexes := make(map[string]*index)
+	columns, err := getJSONIndexColumns(5, "Meta", "MetaAge:$.age:int(11),MetaName:$.name", indexes)
+	assert.NoError(t, err)
+	assert.Equal(t, [][2]string{
+		{"MetaAge", "`MetaAge` int(11) GENERATED ALWAYS AS (json_extract(`Meta`,'$.age')) VIRTUAL"},
+		{"MetaName", "`MetaName` varchar(255) GENERATED ALWAYS AS (json_unquote(json_extract(`Meta`,'$.name'))) VIRTUAL"},
+	}, columns)
+	assert.Len(t, indexes, 2)
+	_, err = getJSONIndexColumns(5, "Meta", "MetaAge", indexes)
+	assert.EqualError(t, err, "invalid jsonIndex 'MetaAge' for field Meta")
+}
@@ -0,0 +1,145 @@
+package beeorm
+
+import (
+	"reflect"
+	"strconv"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+var entityInterfaceType = reflect.TypeOf((*Entity)(nil)).Elem()
+
+var stringIDsJSON = newStringIDsJSON()
+
+type JSONID uint64
+
+func (id JSONID) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + strconv.FormatUint(uint64(id), 10) + `"`), nil
+}
+
+func (id *JSONID) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if value == "null" {
+		return nil
+	}
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		value = value[1 : len(value)-1]
+	}
+	if value == "" {
+		*id = 0
+		return nil
+	}
+	asUint, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return err
+	}
+	*id = JSONID(asUint)
+	return nil
+}
+
+func (r *Registry) EnableJSONStringIDs() {
+	r.jsonStringIDs = true
+}
+
+func (r *validatedRegistry) EncodeJSON(value interface{}) ([]byte, error) {
+	if r.registry.jsonStringIDs {
+		return stringIDsJSON.Marshal(value)
+	}
+	return jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(value)
+}
+
+func (r *validatedRegistry) DecodeJSON(data []byte, value interface{}) error {
+	return stringIDsJSON.Unmarshal(data, value)
+}
+
+func newStringIDsJSON() jsoniter.API {
+	api := jsoniter.Config{EscapeHTML: true, SortMapKeys: true, ValidateJsonRawMessage: true}.Froze()
+	api.RegisterExtension(&stringIDsExtension{})
+	return api
+}
+
+type stringIDsExtension struct {
+	jsoniter.DummyExtension
+}
+
+func (e *stringIDsExtension) UpdateStructDescriptor(structDescriptor *jsoniter.StructDescriptor) {
+	if !reflect.PtrTo(structDescriptor.Type.Type1()).Implements(entityInterfaceType) {
+		return
+	}
+	for _, binding := range structDescriptor.Fields {
+		if binding.Field.Name() != "ID" {
+			continue
+		}
+		switch binding.Field.Type().Kind() {
+		case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+			codec := &stringIDCodec{kind: binding.Field.Type().Kind()}
+			binding.Encoder = codec
+			binding.Decoder = codec
+		}
+	}
+}
+
+type stringIDCodec struct {
+	kind reflect.Kind
+}
+
+func (c *stringIDCodec) get(ptr unsafe.Pointer) uint64 {
+	switch c.kind {
+	case reflect.Uint:
+		return uint64(*(*uint)(ptr))
+	case reflect.Uint32:
+		return uint64(*(*uint32)(ptr))
+	case reflect.Uint16:
+		return uint64(*(*uint16)(ptr))
+	case reflect.Uint8:
+		return uint64(*(*uint8)(ptr))
+	default:
+		return *(*uint64)(ptr)
+	}
+}
+
+func (c *stringIDCodec) set(ptr unsafe.Pointer, value uint64) {
+	switch c.kind {
+	case reflect.Uint:
+		*(*uint)(ptr) = uint(value)
+	case reflect.Uint32:
+		*(*uint32)(ptr) = uint32(value)
+	case reflect.Uint16:
+		*(*uint16)(ptr) = uint16(value)
+	case reflect.Uint8:
+		*(*uint8)(ptr) = uint8(value)
+	default:
+		*(*uint64)(ptr) = value
+	}
+}
+
+func (c *stringIDCodec) IsEmpty(ptr unsafe.Pointer) bool {
+	return c.get(ptr) == 0
+}
+
+func (c *stringIDCodec) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	stream.WriteString(strconv.FormatUint(c.get(ptr), 10))
+}
+
+func (c *stringIDCodec) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		c.set(ptr, 0)
+	case jsoniter.StringValue:
+		value := iter.ReadString()
+		if value == "" {
+			c.set(ptr, 0)
+			return
+		}
+		asUint, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			iter.ReportError("decode ID", err.Error())
+			return
+		}
+		c.set(ptr, asUint)
+	default:
+		c.set(ptr, iter.ReadUint64())
+	}
+}
@@ -0,0 +1,73 @@
+package beeorm
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type jsonIDsEntity struct {
+	ORM
+	ID     uint64
+	Name   string
+	Parent *jsonIDsEntity
+}
+
+type jsonIDsEntitySmall struct {
+	ORM
+	ID  uint
+	Age uint64
+}
+
+type jsonIDsResponse struct {
+	UserID JSONID
+	Items  []JSONID
+}
+
+func TestJSONStringIDs(t *testing.T) {
+	var entity *jsonIDsEntity
+	var entitySmall *jsonIDsEntitySmall
+	registry := &Registry{}
+	registry.EnableJSONStringIDs()
+	engine := prepareTables(t, registry, 5, 6, "", entity, entitySmall)
+	validated := engine.GetRegistry()
+
+	entity = &jsonIDsEntity{ID: math.MaxUint64, Name: "a", Parent: &jsonIDsEntity{ID: 2}}
+	encoded, err := validated.EncodeJSON(entity)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"ID":"18446744073709551615","Name":"a","Parent":{"ID":"2","Name":"","Parent":null}}`, string(encoded))
+
+	decoded := &jsonIDsEntity{}
+	assert.NoError(t, validated.DecodeJSON(encoded, decoded))
+	assert.Equal(t, uint64(math.MaxUint64), decoded.ID)
+	assert.Equal(t, uint64(2), decoded.Parent.ID)
+	assert.NoError(t, validated.DecodeJSON([]byte(`{"ID":12,"Parent":{"ID":"3"}}`), decoded))
+	assert.Equal(t, uint64(12), decoded.ID)
+	assert.Equal(t, uint64(3), decoded.Parent.ID)
+	assert.Error(t, validated.DecodeJSON([]byte(`{"ID":"abc"}`), decoded))
+
+	entitySmall = &jsonIDsEntitySmall{ID: 7, Age: 18}
+	encoded, err = validated.EncodeJSON([]*jsonIDsEntitySmall{entitySmall})
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"ID":"7","Age":18}]`, string(encoded))
+	var rows []*jsonIDsEntitySmall
+	assert.NoError(t, validated.DecodeJSON(encoded, &rows))
+	assert.Equal(t, uint(7), rows[0].ID)
+
+	registry = &Registry{}
+	engine = prepareTables(t, registry, 5, 6, "", entity, entitySmall)
+	encoded, err = engine.GetRegistry().EncodeJSON(entitySmall)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"ID":7,"Age":18}`, string(encoded))
+
+	response := jsonIDsResponse{UserID: math.MaxUint64, Items: []JSONID{1, 2}}
+	encoded, err = json.Marshal(response)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"UserID":"18446744073709551615","Items":["1","2"]}`, string(encoded))
+	decodedResponse := jsonIDsResponse{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"UserID":"5","Items":[3,"4"]}`), &decodedResponse))
+	assert.Equal(t, JSONID(5), decodedResponse.UserID)
+	assert.Equal(t, []JSONID{3, 4}, decodedResponse.Items)
+}
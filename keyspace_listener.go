@@ -0,0 +1,173 @@
+package beeorm
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var keyspaceEventClasses = map[string]byte{
+	"expired": 'x', "evicted": 'e', "del": 'g', "rename_from": 'g', "rename_to": 'g', "expire": 'g',
+	"set": '$', "setrange": '$', "incrby": '$', "incrbyfloat": '$', "append": '$',
+	"hset": 'h', "hdel": 'h', "hincrby": 'h', "lpush": 'l', "rpush": 'l', "lpop": 'l', "rpop": 'l',
+	"sadd": 's', "srem": 's', "zadd": 'z', "zrem": 'z', "zincr": 'z', "xadd": 't', "xtrim": 't', "xdel": 't',
+}
+
+type KeyspaceEvent struct {
+	Pool  string
+	Event string
+	Key   string
+}
+
+type KeyspaceHandler func(engine Engine, event KeyspaceEvent)
+
+type keyspaceSubscription struct {
+	event   string
+	pattern string
+	handler KeyspaceHandler
+}
+
+type KeyspaceListener struct {
+	engine        *engineImplementation
+	pool          string
+	subscriptions []*keyspaceSubscription
+}
+
+func NewKeyspaceListener(engine Engine, pool ...string) *KeyspaceListener {
+	code := "default"
+	if len(pool) > 0 {
+		code = pool[0]
+	}
+	return &KeyspaceListener{engine: engine.(*engineImplementation), pool: code}
+}
+
+func (l *KeyspaceListener) On(event, pattern string, handler KeyspaceHandler) {
+	if _, has := keyspaceEventClasses[event]; !has {
+		panic(fmt.Errorf("unsupported keyspace event '%s'", event))
+	}
+	l.subscriptions = append(l.subscriptions, &keyspaceSubscription{event: event, pattern: pattern, handler: handler})
+}
+
+func (l *KeyspaceListener) OnExpired(pattern string, handler KeyspaceHandler) {
+	l.On("expired", pattern, handler)
+}
+
+func (l *KeyspaceListener) OnEntityExpired(entity Entity, handler func(engine Engine, id uint64)) {
+	schema := initIfNeeded(l.engine.registry, entity).tableSchema
+	if !schema.hasRedisCache || schema.redisCacheName != l.pool {
+		panic(fmt.Errorf("entity '%s' is not cached in redis pool '%s'", schema.t.String(), l.pool))
+	}
+	l.OnExpired(schema.cachePrefix+"*", func(engine Engine, event KeyspaceEvent) {
+		id, valid := parseEntityCacheKey(schema, event.Key)
+		if valid {
+			handler(engine, id)
+		}
+	})
+}
+
+func (l *KeyspaceListener) EnableNotifications() {
+	r := l.engine.GetRedis(l.pool)
+	current, err := r.client.ConfigGet(context.Background(), "notify-keyspace-events").Result()
+	checkError(err)
+	flags := current["notify-keyspace-events"]
+	required := keyspaceNotificationFlags(l.subscriptions)
+	for _, flag := range required {
+		if !strings.ContainsRune(flags, flag) && !(flag != 'E' && flag != 'K' && strings.ContainsRune(flags, 'A')) {
+			flags += string(flag)
+		}
+	}
+	checkError(r.client.ConfigSet(context.Background(), "notify-keyspace-events", flags).Err())
+}
+
+func (l *KeyspaceListener) Listen(ctx context.Context) {
+	if len(l.subscriptions) == 0 {
+		return
+	}
+	ctx, cancel := l.engine.withCloseContext(ctx)
+	defer cancel()
+	r := l.engine.GetRedis(l.pool)
+	prefix := "__keyevent@" + strconv.Itoa(r.config.GetDatabase()) + "__:"
+	channels := make([]string, 0)
+	subscribed := make(map[string]bool)
+	for _, subscription := range l.subscriptions {
+		if !subscribed[subscription.event] {
+			subscribed[subscription.event] = true
+			channels = append(channels, prefix+subscription.event)
+		}
+	}
+	pubSub := r.client.Subscribe(ctx, channels...)
+	defer func() {
+		_ = pubSub.Close()
+	}()
+	_, err := pubSub.Receive(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+	checkError(err)
+	messages := pubSub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			key := message.Payload
+			if r.config.HasNamespace() {
+				if !strings.HasPrefix(key, r.config.GetNamespace()+":") {
+					continue
+				}
+				key = r.removeNamespacePrefix(key)
+			}
+			l.dispatch(KeyspaceEvent{Pool: l.pool, Event: strings.TrimPrefix(message.Channel, prefix), Key: key})
+		}
+	}
+}
+
+func (l *KeyspaceListener) dispatch(event KeyspaceEvent) {
+	for _, subscription := range l.subscriptions {
+		if subscription.event != event.Event {
+			continue
+		}
+		matched, _ := path.Match(subscription.pattern, event.Key)
+		if matched {
+			subscription.handler(l.engine, event)
+		}
+	}
+}
+
+func keyspaceNotificationFlags(subscriptions []*keyspaceSubscription) string {
+	flags := "E"
+	for _, subscription := range subscriptions {
+		class := keyspaceEventClasses[subscription.event]
+		if !strings.ContainsRune(flags, rune(class)) {
+			flags += string(class)
+		}
+	}
+	return flags
+}
+
+func parseEntityCacheKey(schema *tableSchema, key string) (id uint64, valid bool) {
+	if !strings.HasPrefix(key, schema.cachePrefix) {
+		return 0, false
+	}
+	rest := key[len(schema.cachePrefix):]
+	pos := strings.LastIndex(rest, ":")
+	if pos < 0 {
+		return 0, false
+	}
+	version := rest[0:pos]
+	if version != "" {
+		if version[0] != 'v' {
+			return 0, false
+		}
+		if _, err := strconv.ParseUint(version[1:], 10, 64); err != nil {
+			return 0, false
+		}
+	}
+	id, err := strconv.ParseUint(rest[pos+1:], 10, 64)
+	return id, err == nil
+}
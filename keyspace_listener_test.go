@@ -0,0 +1,82 @@
+package beeorm
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type keyspaceListenerEntity struct {
+	ORM  `orm:"redisCache"`
+	ID   uint
+	Name string
+}
+
+func TestKeyspaceNotificationFlags(t *testing.T) {
+	flags := keyspaceNotificationFlags([]*keyspaceSubscription{{event: "expired"}, {event: "del"}, {event: "expire"}, {event: "hset"}})
+	assert.Equal(t, "Exgh", flags)
+}
+
+func TestKeyspaceListener(t *testing.T) {
+	var entity *keyspaceListenerEntity
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema)
+
+	id, valid := parseEntityCacheKey(schema, schema.cachePrefix+":12")
+	assert.True(t, valid)
+	assert.Equal(t, uint64(12), id)
+	id, valid = parseEntityCacheKey(schema, schema.cachePrefix+"v3:7")
+	assert.True(t, valid)
+	assert.Equal(t, uint64(7), id)
+	_, valid = parseEntityCacheKey(schema, schema.cachePrefix+"x:7")
+	assert.False(t, valid)
+
+	listener := NewKeyspaceListener(engine)
+	lock := sync.Mutex{}
+	keys := make([]string, 0)
+	ids := make([]uint64, 0)
+	listener.OnExpired("session:*", func(_ Engine, event KeyspaceEvent) {
+		lock.Lock()
+		defer lock.Unlock()
+		assert.Equal(t, "expired", event.Event)
+		assert.Equal(t, "default", event.Pool)
+		keys = append(keys, event.Key)
+	})
+	listener.OnEntityExpired(entity, func(_ Engine, id uint64) {
+		lock.Lock()
+		defer lock.Unlock()
+		ids = append(ids, id)
+	})
+	assert.PanicsWithError(t, "unsupported keyspace event 'invalid'", func() {
+		listener.On("invalid", "*", nil)
+	})
+	listener.EnableNotifications()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		listener.Listen(ctx)
+		close(done)
+	}()
+	time.Sleep(time.Millisecond * 100)
+
+	r := engine.GetRedis()
+	r.Set("session:1", "a", 1)
+	r.Set("other:1", "a", 1)
+	r.Set(schema.getCacheKey(engine, 5), "a", 1)
+	assert.Eventually(t, func() bool {
+		r.Exists("session:1", "other:1")
+		lock.Lock()
+		defer lock.Unlock()
+		return len(keys) == 1 && len(ids) == 1
+	}, time.Second*3, time.Millisecond*50)
+	assert.Equal(t, []string{"session:1"}, keys)
+	assert.Equal(t, []uint64{5}, ids)
+	cancel()
+	<-done
+}
@@ -0,0 +1,222 @@
+package beeorm
+
+import (
+	"context"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const lazyParkedEventsKey = "_beeorm_lazy_parked"
+const lazyParkedDueKey = "_beeorm_lazy_parked_due"
+const lazyParkedQueuePrefix = "_beeorm_lazy_parked:"
+
+// Lazy events touching an entity with parked events are parked behind them in a per entity Redis list,
+// so ordering holds across consumer restarts. KEYS: events hash, due set, entity lists.
+var lazyParkIfBlockedScript = newRedisScript("beeorm_lazy_park_if_blocked", `
+if redis.call('HEXISTS', KEYS[1], ARGV[1]) == 1 then
+	return 1
+end
+local blocked = false
+for i = 3, #KEYS do
+	if redis.call('LLEN', KEYS[i]) > 0 then
+		blocked = true
+		break
+	end
+end
+if not blocked then
+	return 0
+end
+for i = 3, #KEYS do
+	redis.call('RPUSH', KEYS[i], ARGV[1])
+end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+return 1
+`)
+
+var lazyParkScript = newRedisScript("beeorm_lazy_park", `
+if redis.call('HEXISTS', KEYS[1], ARGV[1]) == 0 then
+	for i = 3, #KEYS do
+		redis.call('RPUSH', KEYS[i], ARGV[1])
+	end
+end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+redis.call('ZADD', KEYS[2], ARGV[3], ARGV[1])
+return 1
+`)
+
+var lazyParkedHeadScript = newRedisScript("beeorm_lazy_parked_head", `
+for i = 3, #KEYS do
+	if redis.call('LINDEX', KEYS[i], 0) ~= ARGV[1] then
+		redis.call('ZREM', KEYS[2], ARGV[1])
+		return 0
+	end
+end
+return 1
+`)
+
+var lazyUnparkScript = newRedisScript("beeorm_lazy_unpark", `
+redis.call('HDEL', KEYS[1], ARGV[1])
+redis.call('ZREM', KEYS[2], ARGV[1])
+for i = 3, #KEYS do
+	if redis.call('LINDEX', KEYS[i], 0) == ARGV[1] then
+		redis.call('LPOP', KEYS[i])
+		local next = redis.call('LINDEX', KEYS[i], 0)
+		if next then
+			redis.call('ZADD', KEYS[2], 'NX', ARGV[2], next)
+		end
+	end
+end
+return 1
+`)
+
+var lazyParkedDueScript = newRedisScript("beeorm_lazy_parked_due", `
+local tokens = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+local result = {}
+for _, token in ipairs(tokens) do
+	local payload = redis.call('HGET', KEYS[1], token)
+	if payload then
+		table.insert(result, token)
+		table.insert(result, payload)
+	else
+		redis.call('ZREM', KEYS[2], token)
+	end
+end
+return result
+`)
+
+type lazyParkedEvent struct {
+	token    string
+	values   []string
+	registry *validatedRegistry
+}
+
+func (ev *lazyParkedEvent) Ack() {
+}
+
+func (ev *lazyParkedEvent) delete() {
+}
+
+func (ev *lazyParkedEvent) ID() string {
+	return ev.token
+}
+
+func (ev *lazyParkedEvent) Stream() string {
+	return LazyChannelName
+}
+
+func (ev *lazyParkedEvent) Tag(key string) (value string) {
+	for i := 2; i+1 < len(ev.values); i += 2 {
+		if ev.values[i] == key {
+			return ev.values[i+1]
+		}
+	}
+	return ""
+}
+
+func (ev *lazyParkedEvent) Unserialize(value interface{}) {
+	err := ev.registry.decodePayload([]byte(ev.values[1]), value)
+	checkError(err)
+}
+
+func (r *BackgroundConsumer) hasLazyParked() bool {
+	r.lazyOrderLock.Lock()
+	defer r.lazyOrderLock.Unlock()
+	return r.lazyParked
+}
+
+func (r *BackgroundConsumer) setLazyParked(parked bool) {
+	r.lazyOrderLock.Lock()
+	defer r.lazyOrderLock.Unlock()
+	r.lazyParked = parked
+}
+
+func (r *BackgroundConsumer) lazyParkingKeys(data map[string]interface{}) []string {
+	redisCache := getRedisForStream(r.engine, LazyChannelName)
+	entityKeys := getLazyEventKeys(data)
+	keys := make([]string, len(entityKeys)+2)
+	keys[0] = redisCache.addNamespacePrefix(lazyParkedEventsKey)
+	keys[1] = redisCache.addNamespacePrefix(lazyParkedDueKey)
+	for i, key := range entityKeys {
+		keys[i+2] = redisCache.addNamespacePrefix(lazyParkedQueuePrefix + key)
+	}
+	return keys
+}
+
+// lazyBlocked returns true when an earlier event for the same entity is still parked.
+// Blocked events from the stream are parked behind it and acknowledged.
+func (r *BackgroundConsumer) lazyBlocked(event Event, data map[string]interface{}) bool {
+	parked, isParked := event.(*lazyParkedEvent)
+	if isParked {
+		res := getRedisForStream(r.engine, LazyChannelName).runScript(lazyParkedHeadScript, r.lazyParkingKeys(data), parked.token)
+		return res != int64(1)
+	}
+	if !r.hasLazyParked() {
+		return false
+	}
+	values := createEventSlice(r.engine, LazyChannelName, data, []string{"lazy_token", getLazyEventToken(event)})
+	payload, _ := jsoniter.ConfigFastest.MarshalToString(values)
+	res := getRedisForStream(r.engine, LazyChannelName).runScript(lazyParkIfBlockedScript, r.lazyParkingKeys(data),
+		getLazyEventToken(event), payload)
+	if res != int64(1) {
+		return false
+	}
+	event.Ack()
+	return true
+}
+
+func (r *BackgroundConsumer) parkLazy(event Event, data map[string]interface{}, meta []string, retryAt time.Time) {
+	values := createEventSlice(r.engine, LazyChannelName, data, meta)
+	payload, _ := jsoniter.ConfigFastest.MarshalToString(values)
+	r.setLazyParked(true)
+	getRedisForStream(r.engine, LazyChannelName).runScript(lazyParkScript, r.lazyParkingKeys(data), getLazyEventToken(event),
+		payload, retryAt.UnixMilli())
+	event.Ack()
+}
+
+func (r *BackgroundConsumer) lazyExecuted(event Event, data map[string]interface{}) {
+	parked, isParked := event.(*lazyParkedEvent)
+	if !isParked {
+		return
+	}
+	getRedisForStream(r.engine, LazyChannelName).runScript(lazyUnparkScript, r.lazyParkingKeys(data), parked.token,
+		time.Now().UnixMilli())
+}
+
+// retryParkedLazy executes parked events that are due and returns time left to the next one, zero if nothing is parked
+func (r *BackgroundConsumer) retryParkedLazy(ctx context.Context) time.Duration {
+	if !r.hasLazyParked() {
+		return 0
+	}
+	redisCache := getRedisForStream(r.engine, LazyChannelName)
+	keys := []string{redisCache.addNamespacePrefix(lazyParkedEventsKey), redisCache.addNamespacePrefix(lazyParkedDueKey)}
+	for ctx.Err() == nil {
+		due, _ := redisCache.runScript(lazyParkedDueScript, keys, time.Now().UnixMilli(), 100).([]interface{})
+		if len(due) == 0 {
+			break
+		}
+		r.engine.registry.writeFreeze.wait()
+		for i := 0; i+1 < len(due); i += 2 {
+			event := &lazyParkedEvent{token: due[i].(string), registry: r.engine.registry}
+			err := jsoniter.ConfigFastest.UnmarshalFromString(due[i+1].(string), &event.values)
+			checkError(err)
+			var data map[string]interface{}
+			event.Unserialize(&data)
+			r.addLazyQueryMasks(data)
+			r.handleLazy(event, data)
+			r.engine.queryMasks = nil
+		}
+	}
+	next := redisCache.ZRangeWithScores(lazyParkedDueKey, 0, 0)
+	if len(next) == 0 {
+		if redisCache.HLen(lazyParkedEventsKey) == 0 {
+			r.setLazyParked(false)
+		}
+		return 0
+	}
+	wait := time.Until(time.UnixMilli(int64(next[0].Score)))
+	if wait < time.Millisecond {
+		wait = time.Millisecond
+	}
+	return wait
+}
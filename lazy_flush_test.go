@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-redis/redis/v9"
 	"github.com/go-sql-driver/mysql"
 
 	"github.com/stretchr/testify/assert"
@@ -213,3 +214,132 @@ func TestLazyFlush(t *testing.T) {
 	assert.True(t, valid)
 	assert.True(t, valid2)
 }
+
+func TestLazyFlushRetry(t *testing.T) {
+	var entity *lazyReceiverEntity
+	var ref *lazyReceiverReference
+
+	registry := &Registry{}
+	registry.RegisterEnum("beeorm.TestEnum", []string{"a", "b", "c"})
+	registry.RegisterRedisStream(LazyFlushDeadLetterChannelName, "default", []string{"test-dead-letter"})
+	engine := prepareTables(t, registry, 5, 6, "", entity, ref)
+	engine.GetRedis().FlushDB()
+
+	receiver := NewBackgroundConsumer(engine)
+	receiver.DisableBlockMode()
+	receiver.blockTime = time.Millisecond
+	receiver.SetLazyFlushRetryPolicy(&LazyFlushRetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: time.Millisecond * 5})
+
+	e1 := &lazyReceiverEntity{Name: "John"}
+	engine.Flush(e1)
+	e2 := &lazyReceiverEntity{Name: "John"}
+	engine.FlushLazy(e2)
+	e3 := &lazyReceiverEntity{Name: "Ivona"}
+	engine.FlushLazy(e3)
+
+	receiver.Digest(context.Background())
+	stats := engine.GetEventBroker().GetStreamStatistics(LazyFlushDeadLetterChannelName)
+	assert.Equal(t, uint64(1), stats.Len)
+
+	e3 = &lazyReceiverEntity{}
+	assert.True(t, engine.SearchOne(NewWhere("`Name` = ?", "Ivona"), e3))
+
+	consumer := engine.GetEventBroker().Consumer("test-dead-letter")
+	consumer.DisableBlockMode()
+	consumed := false
+	consumer.Consume(context.Background(), 10, func(events []Event) {
+		assert.Len(t, events, 1)
+		assert.Equal(t, "3", events[0].Tag("attempt"))
+		assert.Equal(t, "Error 1062 (23000): Duplicate entry 'John' for key 'name'", events[0].Tag("error"))
+		var data map[string]interface{}
+		events[0].Unserialize(&data)
+		assert.Equal(t, "i", data["o"])
+		consumed = true
+	})
+	assert.True(t, consumed)
+}
+
+func TestLazyFlushRetryOrder(t *testing.T) {
+	var entity *lazyReceiverEntity
+	var ref *lazyReceiverReference
+
+	registry := &Registry{}
+	registry.RegisterEnum("beeorm.TestEnum", []string{"a", "b", "c"})
+	engine := prepareTables(t, registry, 5, 6, "", entity, ref)
+
+	receiver := NewBackgroundConsumer(engine)
+	receiver.DisableBlockMode()
+	receiver.blockTime = time.Millisecond
+	receiver.SetLazyFlushRetryPolicy(&LazyFlushRetryPolicy{MaxAttempts: 5, Backoff: time.Millisecond})
+	calls := 0
+	receiver.RegisterLazyFlushQueryErrorResolver(func(engine Engine, db *DB, sql string, queryError *mysql.MySQLError) error {
+		calls++
+		if calls == 2 {
+			engine.GetMysql().Exec("UPDATE `lazyReceiverEntity` SET `Name` = 'Other' WHERE `ID` = 2")
+		}
+		return queryError
+	})
+
+	e1 := &lazyReceiverEntity{Name: "John"}
+	e2 := &lazyReceiverEntity{Name: "Ivona"}
+	engine.Flush(e1, e2)
+	e1.Name = "Ivona"
+	engine.FlushLazy(e1)
+	e1.Name = "Tom"
+	engine.FlushLazy(e1)
+	receiver.Digest(context.Background())
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, int64(0), engine.GetRedis().HLen(lazyParkedEventsKey))
+	assert.Equal(t, int64(0), engine.GetRedis().ZCard(lazyParkedDueKey))
+
+	var name string
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Name` FROM `lazyReceiverEntity` WHERE `ID` = 1"), &name)
+	assert.Equal(t, "Tom", name)
+}
+
+func TestLazyFlushOrderAfterRestart(t *testing.T) {
+	var entity *lazyReceiverEntity
+	var ref *lazyReceiverReference
+
+	registry := &Registry{}
+	registry.RegisterEnum("beeorm.TestEnum", []string{"a", "b", "c"})
+	engine := prepareTables(t, registry, 5, 6, "", entity, ref)
+	engine.GetRedis().FlushDB()
+
+	e1 := &lazyReceiverEntity{Name: "John"}
+	e2 := &lazyReceiverEntity{Name: "Ivona"}
+	engine.Flush(e1, e2)
+
+	receiver := NewBackgroundConsumer(engine)
+	receiver.DisableBlockMode()
+	receiver.blockTime = time.Millisecond
+	receiver.SetLazyFlushRetryPolicy(&LazyFlushRetryPolicy{MaxAttempts: 5, Backoff: time.Hour})
+	e1.Name = "Ivona"
+	engine.FlushLazy(e1)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	receiver.Digest(ctx)
+	assert.Equal(t, int64(1), engine.GetRedis().HLen(lazyParkedEventsKey))
+
+	e1.Name = "Tom"
+	engine.FlushLazy(e1)
+	receiver = NewBackgroundConsumer(engine)
+	receiver.DisableBlockMode()
+	receiver.blockTime = time.Millisecond
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	receiver.Digest(ctx)
+	assert.Equal(t, int64(2), engine.GetRedis().HLen(lazyParkedEventsKey))
+	assert.Equal(t, uint64(0), engine.GetEventBroker().GetStreamStatistics(LazyChannelName).Groups[0].Pending)
+
+	var name string
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Name` FROM `lazyReceiverEntity` WHERE `ID` = 1"), &name)
+	assert.Equal(t, "John", name)
+
+	engine.GetMysql().Exec("UPDATE `lazyReceiverEntity` SET `Name` = 'Other' WHERE `ID` = 2")
+	engine.GetRedis().ZAdd(lazyParkedDueKey, redis.Z{Score: 0, Member: engine.GetRedis().ZRangeWithScores(lazyParkedDueKey, 0, 0)[0].Member})
+	receiver.Digest(context.Background())
+	assert.Equal(t, int64(0), engine.GetRedis().HLen(lazyParkedEventsKey))
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Name` FROM `lazyReceiverEntity` WHERE `ID` = 1"), &name)
+	assert.Equal(t, "Tom", name)
+}
@@ -14,13 +14,24 @@ func loadByID(serializer *serializer, engine *engineImplementation, id uint64, e
 	redisCache, hasRedis := schema.GetRedisCache(engine)
 	var cacheKey string
 	if useCache {
+		mapped, isMapped := engine.getFromIdentityMap(schema, id)
+		if isMapped {
+			if mapped != entity {
+				// caller struct can't be replaced with mapped instance, it gets mapped entity persisted state
+				fillFromBinary(serializer, engine.registry, mapped.getORM().copyBinary(), entity)
+			}
+			if len(references) > 0 {
+				warmUpReferences(serializer, engine, schema, orm.value, references, false)
+			}
+			return true, schema
+		}
 		if !hasLocalCache && engine.hasRequestCache {
 			hasLocalCache = true
 			localCache = engine.GetLocalCache(requestCacheKey)
 		}
 
 		if hasLocalCache {
-			cacheKey = schema.getCacheKey(id)
+			cacheKey = schema.getCacheKey(engine, id)
 			e, has := localCache.Get(cacheKey)
 			if has {
 				if e == cacheNilValue {
@@ -28,6 +39,7 @@ func loadByID(serializer *serializer, engine *engineImplementation, id uint64, e
 				}
 				data := e.([]byte)
 				fillFromBinary(serializer, engine.registry, data, entity)
+				engine.entityLoaded(entity)
 				if len(references) > 0 {
 					warmUpReferences(serializer, engine, schema, orm.value, references, false)
 				}
@@ -35,7 +47,7 @@ func loadByID(serializer *serializer, engine *engineImplementation, id uint64, e
 			}
 		}
 		if hasRedis {
-			cacheKey = schema.getCacheKey(id)
+			cacheKey = schema.getCacheKey(engine, id)
 			row, has := redisCache.Get(cacheKey)
 			if has {
 				if row == cacheNilValue {
@@ -44,14 +56,18 @@ func loadByID(serializer *serializer, engine *engineImplementation, id uint64, e
 					}
 					return false, schema
 				}
-				fillFromBinary(serializer, engine.registry, []byte(row), entity)
-				if len(references) > 0 {
-					warmUpReferences(serializer, engine, schema, orm.value, references, false)
-				}
-				if localCache != nil {
-					localCache.Set(cacheKey, orm.copyBinary())
+				binary, valid := decodeRedisCacheValue(redisCache, schema, cacheKey, row)
+				if valid {
+					fillFromBinary(serializer, engine.registry, binary, entity)
+					engine.entityLoaded(entity)
+					if len(references) > 0 {
+						warmUpReferences(serializer, engine, schema, orm.value, references, false)
+					}
+					if localCache != nil {
+						localCache.Set(cacheKey, orm.copyBinary())
+					}
+					return true, schema
 				}
-				return true, schema
 			}
 		}
 	}
@@ -63,7 +79,8 @@ func loadByID(serializer *serializer, engine *engineImplementation, id uint64, e
 			localCache.Set(cacheKey, cacheNilValue)
 		}
 		if redisCache != nil {
-			redisCache.Set(cacheKey, cacheNilValue, 60)
+			redisCache.Set(cacheKey, cacheNilValue, engine.registry.runtime.getRedisCacheNilTTL())
+			schema.trackCacheKeyVariants(engine, redisCache, schema.cacheTTL, []uint64{id}, []string{cacheKey})
 		}
 		return false, schema
 	}
@@ -72,7 +89,8 @@ func loadByID(serializer *serializer, engine *engineImplementation, id uint64, e
 			localCache.Set(cacheKey, orm.copyBinary())
 		}
 		if redisCache != nil {
-			redisCache.Set(cacheKey, orm.binary, 0)
+			redisCache.Set(cacheKey, schema.getRedisCacheValue(orm.binary), schema.cacheTTL)
+			schema.trackCacheKeyVariants(engine, redisCache, schema.cacheTTL, []uint64{id}, []string{cacheKey})
 		}
 	}
 
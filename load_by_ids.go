@@ -5,8 +5,11 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 )
 
+const maxParallelPoolLoads = 4
+
 func tryByIDs(serializer *serializer, engine *engineImplementation, ids []uint64, entities reflect.Value, references []string) (schema *tableSchema, hasMissing bool) {
 	lenIDs := len(ids)
 	newSlice := reflect.MakeSlice(entities.Type(), lenIDs, lenIDs)
@@ -20,6 +23,9 @@ func tryByIDs(serializer *serializer, engine *engineImplementation, ids []uint64
 	}
 
 	schema = getTableSchema(engine.registry, t)
+	if engine.queryResultLimit > 0 {
+		engine.checkQueryResultLimit(schema, lenIDs, true)
+	}
 	hasLocalCache := schema.hasLocalCache
 	hasRedis := schema.hasRedisCache
 
@@ -32,12 +38,15 @@ func tryByIDs(serializer *serializer, engine *engineImplementation, ids []uint64
 		localCache = engine.GetLocalCache(requestCacheKey)
 	}
 
-	cacheKeysMap := make(map[string]int)
-	duplicates := make(map[string][]int)
+	cacheKeysMap := make(map[string]int, lenIDs)
+	var duplicates map[string][]int
 	for i, id := range ids {
-		key := schema.getCacheKey(id)
+		key := schema.getCacheKey(engine, id)
 		oldValue, hasDuplicate := cacheKeysMap[key]
 		if hasDuplicate {
+			if duplicates == nil {
+				duplicates = make(map[string][]int)
+			}
 			if len(duplicates[key]) == 0 {
 				duplicates[key] = append(duplicates[key], oldValue)
 			}
@@ -46,6 +55,16 @@ func tryByIDs(serializer *serializer, engine *engineImplementation, ids []uint64
 			cacheKeysMap[key] = i
 		}
 	}
+	if engine.identityMap != nil {
+		for key, k := range cacheKeysMap {
+			mapped, has := engine.getFromIdentityMap(schema, ids[k])
+			if has {
+				newSlice.Index(k).Set(mapped.getORM().value)
+				hasValid = true
+				delete(cacheKeysMap, key)
+			}
+		}
+	}
 	cacheKeys := make([]string, len(cacheKeysMap))
 	j := 0
 	for key := range cacheKeysMap {
@@ -55,6 +74,7 @@ func tryByIDs(serializer *serializer, engine *engineImplementation, ids []uint64
 
 	var localCacheToSet []interface{}
 	var redisCacheToSet []interface{}
+	var redisCacheIDs []uint64
 	if hasLocalCache {
 		if localCache == nil {
 			localCache, _ = schema.GetLocalCache(engine)
@@ -88,10 +108,14 @@ func tryByIDs(serializer *serializer, engine *engineImplementation, ids []uint64
 		for i, val := range inCache {
 			if val != nil {
 				if val != cacheNilValue {
+					binary, valid := decodeRedisCacheValue(redisCache, schema, cacheKeys[i], val.(string))
+					if !valid {
+						continue
+					}
 					e := schema.NewEntity()
 					k := cacheKeysMap[cacheKeys[i]]
 					newSlice.Index(k).Set(e.getORM().value)
-					fillFromBinary(serializer, engine.registry, []byte(val.(string)), e)
+					fillFromBinary(serializer, engine.registry, binary, e)
 					if hasLocalCache {
 						localCacheToSet = append(localCacheToSet, cacheKeys[i], e.getORM().copyBinary())
 					}
@@ -110,34 +134,32 @@ func tryByIDs(serializer *serializer, engine *engineImplementation, ids []uint64
 		}
 	}
 	if len(idsDB) > 0 {
-		query := "SELECT " + schema.fieldsQuery + " FROM `" + schema.tableName + "` WHERE `ID` IN (" + strconv.FormatUint(idsDB[0], 10)
-		for _, id := range idsDB[1:] {
-			query += "," + strconv.FormatUint(id, 10)
-		}
-		query += ")"
+		queryPrefix := "SELECT " + schema.fieldsQuery + " FROM `" + schema.tableName + "` WHERE `ID` IN ("
 		pool := schema.GetMysql(engine)
-		results, def := pool.Query(query)
-		defer def()
 		found := 0
-		for results.Next() {
-			pointers := prepareScan(schema)
-			results.Scan(pointers...)
-			id := *pointers[schema.idIndex].(*uint64)
-			cacheKey := schema.getCacheKey(id)
-			e := schema.NewEntity()
-			k := cacheKeysMap[cacheKey]
-			newSlice.Index(k).Set(e.getORM().value)
-			fillFromDBRow(serializer, id, engine.registry, pointers, e)
-			if hasLocalCache {
-				localCacheToSet = append(localCacheToSet, cacheKey, e.getORM().copyBinary())
-			}
-			if hasRedis {
-				redisCacheToSet = append(redisCacheToSet, cacheKey, e.getORM().binary)
+		for _, chunk := range chunkIDs(idsDB, pool.GetPoolConfig().getIDsChunkSize(len(queryPrefix)+1)) {
+			results, def := pool.Query(queryPrefix + joinIDs(chunk) + ")")
+			for results.Next() {
+				pointers := prepareScan(schema)
+				results.Scan(pointers...)
+				id := *pointers[schema.idIndex].(*uint64)
+				cacheKey := schema.getCacheKey(engine, id)
+				e := schema.NewEntity()
+				k := cacheKeysMap[cacheKey]
+				newSlice.Index(k).Set(e.getORM().value)
+				fillFromDBRow(serializer, id, engine.registry, pointers, e)
+				if hasLocalCache {
+					localCacheToSet = append(localCacheToSet, cacheKey, e.getORM().copyBinary())
+				}
+				if hasRedis {
+					redisCacheToSet = append(redisCacheToSet, cacheKey, schema.getRedisCacheValue(e.getORM().binary))
+					redisCacheIDs = append(redisCacheIDs, id)
+				}
+				hasValid = true
+				found++
 			}
-			hasValid = true
-			found++
+			def()
 		}
-		def()
 		if !hasMissing && found < len(idsDB) {
 			hasMissing = true
 		}
@@ -146,7 +168,22 @@ func tryByIDs(serializer *serializer, engine *engineImplementation, ids []uint64
 		localCache.MSet(localCacheToSet...)
 	}
 	if len(redisCacheToSet) > 0 && redisCache != nil {
-		redisCache.MSet(redisCacheToSet...)
+		setEntityRedisCache(redisCache, schema.cacheTTL, redisCacheToSet...)
+		if schema.hasCacheKeyVariants() {
+			variantKeys := make([]string, len(redisCacheIDs))
+			for i := range redisCacheIDs {
+				variantKeys[i] = redisCacheToSet[i*2].(string)
+			}
+			schema.trackCacheKeyVariants(engine, redisCache, schema.cacheTTL, redisCacheIDs, variantKeys)
+		}
+	}
+	if engine.identityMap != nil || engine.unitOfWork {
+		for i := 0; i < lenIDs; i++ {
+			val := newSlice.Index(i)
+			if !val.IsNil() {
+				engine.entityLoaded(val.Interface().(Entity))
+			}
+		}
 	}
 	for _, list := range duplicates {
 		for _, k := range list[1:] {
@@ -167,6 +204,11 @@ func tryByIDs(serializer *serializer, engine *engineImplementation, ids []uint64
 }
 
 func warmUpReferences(serializer *serializer, engine *engineImplementation, schema *tableSchema, rows reflect.Value, references []string, many bool) {
+	warmUpReferencesLevel(serializer, engine, schema, rows, references, many, nil)
+}
+
+func warmUpReferencesLevel(serializer *serializer, engine *engineImplementation, schema *tableSchema, rows reflect.Value, references []string,
+	many bool, path []*tableSchema) {
 	dbMap := make(map[string]map[*tableSchema]map[string][]Entity)
 	var localMap map[string]map[string][]Entity
 	var redisMap map[string]map[string][]Entity
@@ -174,40 +216,18 @@ func warmUpReferences(serializer *serializer, engine *engineImplementation, sche
 	if many {
 		l = rows.Len()
 	}
-	if references[0] == "*" {
-		references = schema.refOne
-	}
-	var referencesNextNames map[string][]string
+	expanded, err := expandReferences(engine.registry, schema, references, path)
+	checkError(err)
 	var referencesNextEntities map[string][]Entity
-	for _, ref := range references {
-		refName := ref
-		pos := strings.Index(refName, "/")
-		if pos > 0 {
-			if referencesNextNames == nil {
-				referencesNextNames = make(map[string][]string)
-			}
+	for _, refName := range expanded.names {
+		if len(expanded.next[refName]) > 0 {
 			if referencesNextEntities == nil {
 				referencesNextEntities = make(map[string][]Entity)
 			}
-			nextRef := refName[pos+1:]
-			refName = refName[0:pos]
-			referencesNextNames[refName] = append(referencesNextNames[refName], nextRef)
 			referencesNextEntities[refName] = nil
 		}
-		_, has := schema.tags[refName]
-		if !has {
-			panic(fmt.Errorf("reference %s in %s is not valid", ref, schema.tableName))
-		}
-		parentRef, has := schema.tags[refName]["ref"]
-		manyRef := false
-		if !has {
-			parentRef, has = schema.tags[refName]["refs"]
-			manyRef = true
-			if !has {
-				panic(fmt.Errorf("reference tag %s is not valid", ref))
-			}
-		}
-		parentSchema := engine.registry.tableSchemas[engine.registry.entities[parentRef]]
+		_, manyRef := schema.tags[refName]["refs"]
+		parentSchema := getReferenceSchema(engine.registry, schema, refName)
 		hasLocalCache := parentSchema.hasLocalCache
 		if !hasLocalCache && engine.hasRequestCache {
 			hasLocalCache = true
@@ -290,6 +310,9 @@ func warmUpReferences(serializer *serializer, engine *engineImplementation, sche
 			}
 		}
 	}
+	toBatch := engine.useReferenceBatch(serializer, dbMap, localMap, redisMap)
+	redisPools := make([]string, 0, len(redisMap))
+	redisKeys := make([][]string, 0, len(redisMap))
 	for k, v := range redisMap {
 		l := len(v)
 		if l == 0 {
@@ -301,51 +324,93 @@ func warmUpReferences(serializer *serializer, engine *engineImplementation, sche
 			keys[i] = k
 			i++
 		}
-		for key, fromCache := range engine.GetRedis(k).MGet(keys...) {
+		redisPools = append(redisPools, k)
+		redisKeys = append(redisKeys, keys)
+	}
+	redisResults := make([][]interface{}, len(redisPools))
+	loadPerPool(engine, len(redisPools), true, func(engine *engineImplementation, i int) {
+		redisResults[i] = engine.GetRedis(redisPools[i]).MGet(redisKeys[i]...)
+	})
+	for i, pool := range redisPools {
+		v := redisMap[pool]
+		keys := redisKeys[i]
+		for key, fromCache := range redisResults[i] {
 			if fromCache != nil && fromCache != cacheNilValue {
-				for _, r := range v[keys[key]] {
-					fillFromBinary(serializer, engine.registry, []byte(fromCache.(string)), r)
+				refs := v[keys[key]]
+				schema := initIfNeeded(engine.registry, refs[0]).tableSchema
+				binary, valid := decodeRedisCacheValue(engine.GetRedis(pool), schema, keys[key], fromCache.(string))
+				if !valid {
+					continue
+				}
+				for _, r := range refs {
+					fillFromBinary(serializer, engine.registry, binary, r)
 				}
 				fillRef(keys[key], nil, redisMap, dbMap)
 			}
 		}
 	}
+	dbPools := make([]string, 0, len(dbMap))
+	parallel := true
 	for k, v := range dbMap {
-		db := engine.GetMysql(k)
-		for schema, v2 := range v {
+		for _, v2 := range v {
+			if len(v2) > 0 {
+				dbPools = append(dbPools, k)
+				if engine.inTransaction(k) {
+					parallel = false
+				}
+				break
+			}
+		}
+	}
+	dbResults := make([][]referenceRow, len(dbPools))
+	loadPerPool(engine, len(dbPools), parallel, func(engine *engineImplementation, i int) {
+		db := engine.GetMysql(dbPools[i])
+		for schema, v2 := range dbMap[dbPools[i]] {
 			if len(v2) == 0 {
 				continue
 			}
-			keys := make([]string, len(v2))
-			q := make([]string, len(v2))
-			i := 0
+			ids := make([]uint64, 0, len(v2))
 			for k2 := range v2 {
-				keys[i] = k2[strings.Index(k2, ":")+1:]
-				q[i] = keys[i]
-				i++
+				id, _ := strconv.ParseUint(k2[strings.Index(k2, ":")+1:], 10, 64)
+				ids = append(ids, id)
 			}
-			query := "SELECT " + schema.fieldsQuery + " FROM `" + schema.tableName + "` WHERE `ID` IN (" + strings.Join(q, ",") + ")"
-			results, def := db.Query(query)
-			for results.Next() {
-				pointers := prepareScan(schema)
-				results.Scan(pointers...)
-				id := *pointers[schema.idIndex].(*uint64)
-				for _, r := range v2[schema.getCacheKey(id)] {
-					fillFromDBRow(serializer, id, engine.registry, pointers, r)
+			queryPrefix := "SELECT " + schema.fieldsQuery + " FROM `" + schema.tableName + "` WHERE `ID` IN ("
+			for _, chunk := range chunkIDs(ids, db.GetPoolConfig().getIDsChunkSize(len(queryPrefix)+1)) {
+				results, def := db.Query(queryPrefix + joinIDs(chunk) + ")")
+				for results.Next() {
+					pointers := prepareScan(schema)
+					results.Scan(pointers...)
+					dbResults[i] = append(dbResults[i], referenceRow{schema: schema, pointers: pointers})
 				}
+				def()
+			}
+		}
+	})
+	for i, pool := range dbPools {
+		for _, row := range dbResults[i] {
+			id := *row.pointers[row.schema.idIndex].(*uint64)
+			for _, r := range dbMap[pool][row.schema][row.schema.getCacheKey(engine, id)] {
+				fillFromDBRow(serializer, id, engine.registry, row.pointers, r)
 			}
-			def()
 		}
 	}
+	engine.addToReferenceBatch(toBatch)
 	for pool, v := range redisMap {
 		if len(v) == 0 {
 			continue
 		}
-		values := make([]interface{}, 0)
+		values := make(map[int][]interface{})
 		for cacheKey, refs := range v {
-			values = append(values, cacheKey, refs[0].getORM().binary)
+			refSchema := refs[0].getORM().tableSchema
+			values[refSchema.cacheTTL] = append(values[refSchema.cacheTTL], cacheKey, refSchema.getRedisCacheValue(refs[0].getORM().binary))
+		}
+		for ttl, pairs := range values {
+			setEntityRedisCache(engine.GetRedis(pool), ttl, pairs...)
+		}
+		for cacheKey, refs := range v {
+			refSchema := refs[0].getORM().tableSchema
+			refSchema.trackCacheKeyVariants(engine, engine.GetRedis(pool), refSchema.cacheTTL, []uint64{refs[0].GetID()}, []string{cacheKey})
 		}
-		engine.GetRedis(pool).MSet(values...)
 	}
 	for pool, v := range localMap {
 		if len(v) == 0 {
@@ -363,15 +428,88 @@ func warmUpReferences(serializer *serializer, engine *engineImplementation, sche
 		engine.GetLocalCache(pool).MSet(values...)
 	}
 
-	for refName, entities := range referencesNextEntities {
-		l := len(entities)
-		if l == 1 {
-			warmUpReferences(serializer, engine, entities[0].getORM().tableSchema, reflect.ValueOf(entities[0]),
-				referencesNextNames[refName], false)
-		} else if l > 1 {
-			warmUpReferences(serializer, engine, entities[0].getORM().tableSchema, reflect.ValueOf(entities),
-				referencesNextNames[refName], true)
+	if len(referencesNextEntities) == 0 {
+		return
+	}
+	path = append(path[:len(path):len(path)], schema)
+	nextSchemas := make([]*tableSchema, 0)
+	nextEntities := make(map[*tableSchema][]Entity)
+	nextNames := make(map[*tableSchema][]string)
+	for _, refName := range expanded.names {
+		entities := referencesNextEntities[refName]
+		if len(entities) == 0 {
+			continue
+		}
+		nextSchema := getReferenceSchema(engine.registry, schema, refName)
+		if _, has := nextEntities[nextSchema]; !has {
+			nextSchemas = append(nextSchemas, nextSchema)
 		}
+		nextEntities[nextSchema] = append(nextEntities[nextSchema], entities...)
+	main:
+		for _, name := range expanded.next[refName] {
+			for _, existing := range nextNames[nextSchema] {
+				if existing == name {
+					continue main
+				}
+			}
+			nextNames[nextSchema] = append(nextNames[nextSchema], name)
+		}
+	}
+	for _, nextSchema := range nextSchemas {
+		entities := nextEntities[nextSchema]
+		if len(entities) == 1 {
+			warmUpReferencesLevel(serializer, engine, nextSchema, reflect.ValueOf(entities[0]), nextNames[nextSchema], false, path)
+		} else {
+			warmUpReferencesLevel(serializer, engine, nextSchema, reflect.ValueOf(entities), nextNames[nextSchema], true, path)
+		}
+	}
+}
+
+type referenceRow struct {
+	schema   *tableSchema
+	pointers []interface{}
+}
+
+func loadPerPool(engine *engineImplementation, pools int, parallel bool, load func(engine *engineImplementation, i int)) {
+	if pools <= 1 || !parallel {
+		for i := 0; i < pools; i++ {
+			load(engine, i)
+		}
+		return
+	}
+	concurrency := maxParallelPoolLoads
+	if concurrency > pools {
+		concurrency = pools
+	}
+	queue := make(chan int, pools)
+	for i := 0; i < pools; i++ {
+		queue <- i
+	}
+	close(queue)
+	var mutex sync.Mutex
+	var panicValue interface{}
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			workerEngine := engine.Clone().(*engineImplementation)
+			defer func() {
+				if rec := recover(); rec != nil {
+					mutex.Lock()
+					panicValue = rec
+					mutex.Unlock()
+				}
+				workerEngine.Release()
+				wg.Done()
+			}()
+			for i := range queue {
+				load(workerEngine, i)
+			}
+		}()
+	}
+	wg.Wait()
+	if panicValue != nil {
+		panic(panicValue)
 	}
 }
 
@@ -397,7 +535,7 @@ func fillRefMap(engine *engineImplementation, id uint64, referencesNextEntities
 	if has {
 		referencesNextEntities[refName] = append(referencesNextEntities[refName], v)
 	}
-	cacheKey := parentSchema.getCacheKey(id)
+	cacheKey := parentSchema.getCacheKey(engine, id)
 	if dbMap[parentSchema.mysqlPoolName] == nil {
 		dbMap[parentSchema.mysqlPoolName] = make(map[*tableSchema]map[string][]Entity)
 	}
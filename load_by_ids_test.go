@@ -312,3 +312,77 @@ func benchmarkLoadByIDsLocalCache(b *testing.B) {
 		engine.LoadByIDs(ids, &rows)
 	}
 }
+
+type loadByIdsMultiPoolEntity struct {
+	ORM      `orm:"redisCache"`
+	ID       uint
+	Name     string
+	Log      *loadByIdsMultiPoolLog
+	Category *loadByIdsMultiPoolCategory
+}
+
+type loadByIdsMultiPoolLog struct {
+	ORM  `orm:"mysql=log;redisCache=default_queue"`
+	ID   uint
+	Name string
+}
+
+type loadByIdsMultiPoolCategory struct {
+	ORM  `orm:"redisCache=search"`
+	ID   uint
+	Name string
+}
+
+func TestLoadByIdsMultiplePools(t *testing.T) {
+	var entity *loadByIdsMultiPoolEntity
+	var log *loadByIdsMultiPoolLog
+	var category *loadByIdsMultiPoolCategory
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity, log, category)
+
+	engine.Flush(&loadByIdsMultiPoolLog{Name: "l1"}, &loadByIdsMultiPoolLog{Name: "l2"},
+		&loadByIdsMultiPoolCategory{Name: "c1"}, &loadByIdsMultiPoolCategory{Name: "c2"})
+	engine.Flush(&loadByIdsMultiPoolEntity{Name: "a", Log: &loadByIdsMultiPoolLog{ID: 1}, Category: &loadByIdsMultiPoolCategory{ID: 2}},
+		&loadByIdsMultiPoolEntity{Name: "b", Log: &loadByIdsMultiPoolLog{ID: 2}, Category: &loadByIdsMultiPoolCategory{ID: 1}})
+
+	for i := 0; i < 2; i++ {
+		var rows []*loadByIdsMultiPoolEntity
+		assert.True(t, engine.LoadByIDs([]uint64{1, 2}, &rows, "Log", "Category"))
+		assert.Len(t, rows, 2)
+		assert.Equal(t, "l1", rows[0].Log.Name)
+		assert.Equal(t, "c2", rows[0].Category.Name)
+		assert.Equal(t, "l2", rows[1].Log.Name)
+		assert.Equal(t, "c1", rows[1].Category.Name)
+	}
+
+	engine.GetMysql("log").Begin()
+	var rows []*loadByIdsMultiPoolEntity
+	engine.GetRedis("default_queue").FlushDB()
+	assert.True(t, engine.LoadByIDs([]uint64{1}, &rows, "Log", "Category"))
+	assert.Equal(t, "l1", rows[0].Log.Name)
+	engine.GetMysql("log").Rollback()
+}
+
+func TestLoadPerPool(t *testing.T) {
+	engine := &engineImplementation{}
+	loaded := make([]int, 10)
+	loadPerPool(engine, 10, true, func(worker *engineImplementation, i int) {
+		assert.NotSame(t, engine, worker)
+		loaded[i] = i + 1
+	})
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, loaded)
+
+	loadPerPool(engine, 2, false, func(worker *engineImplementation, i int) {
+		assert.Same(t, engine, worker)
+	})
+	loadPerPool(engine, 1, true, func(worker *engineImplementation, i int) {
+		assert.Same(t, engine, worker)
+	})
+
+	assert.PanicsWithError(t, "load failed", func() {
+		loadPerPool(engine, 3, true, func(worker *engineImplementation, i int) {
+			if i == 1 {
+				panic(fmt.Errorf("load failed"))
+			}
+		})
+	})
+}
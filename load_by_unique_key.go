@@ -0,0 +1,94 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+func (e *engineImplementation) LoadByUniqueKey(entity Entity, indexName string, arguments ...interface{}) (found bool) {
+	schema := initIfNeeded(e.registry, entity).tableSchema
+	fields := getUniqueKeyFields(schema, indexName)
+	if len(arguments) != len(fields) {
+		panic(fmt.Errorf("unique index %s requires %d values", indexName, len(fields)))
+	}
+	values := make([]interface{}, len(arguments))
+	for i, value := range arguments {
+		values[i] = normalizeUniqueKeyValue(value)
+		if values[i] == nil {
+			return false
+		}
+	}
+	hashKey := getUniqueKeyHashKey(e, schema, indexName)
+	hashField := fmt.Sprintf("%v", values)
+	redisCache, hasRedis := schema.GetRedisCache(e)
+	if hasRedis {
+		cachedID, has := redisCache.HGet(hashKey, hashField)
+		if has {
+			id, _ := strconv.ParseUint(cachedID, 10, 64)
+			if id > 0 && e.LoadByID(id, entity) && uniqueKeyMatches(entity, fields, values) {
+				return true
+			}
+		}
+	}
+	conditions := make([]string, len(fields))
+	for i, field := range fields {
+		conditions[i] = "`" + schema.getColumnName(field) + "` = ?"
+	}
+	if !e.SearchOne(NewWhere(strings.Join(conditions, " AND "), values...), entity) {
+		return false
+	}
+	if hasRedis {
+		redisCache.HSet(hashKey, hashField, strconv.FormatUint(entity.GetID(), 10))
+	}
+	return true
+}
+
+func getUniqueKeyFields(schema *tableSchema, indexName string) []string {
+	index, has := schema.uniqueIndices[indexName]
+	if !has {
+		panic(fmt.Errorf("unique index %s not found", indexName))
+	}
+	fields := make([]string, 0, len(index))
+	for _, field := range index {
+		if !schema.hasFakeDelete || field != "FakeDelete" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+func getUniqueKeyHashKey(engine *engineImplementation, schema *tableSchema, indexName string) string {
+	return "_unique:" + schema.cachePrefix + schema.getCacheVersionSuffix(engine, "") + ":" + indexName
+}
+
+func normalizeUniqueKeyValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	if asEntity, is := value.(Entity); is {
+		if reflect.ValueOf(asEntity).IsNil() {
+			return nil
+		}
+		return asEntity.GetID()
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		return v.Elem().Interface()
+	}
+	return value
+}
+
+func uniqueKeyMatches(entity Entity, fields []string, values []interface{}) bool {
+	snapshot := entity.Snapshot()
+	for i, field := range fields {
+		if fmt.Sprintf("%v", snapshot[field]) != fmt.Sprintf("%v", values[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,49 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type loadByUniqueKeyEntity struct {
+	ORM   `orm:"redisCache"`
+	ID    uint
+	Email string `orm:"unique=Email"`
+	Code  string `orm:"unique=CodeAge"`
+	Age   uint   `orm:"unique=CodeAge:2"`
+}
+
+func TestLoadByUniqueKey(t *testing.T) {
+	var entity *loadByUniqueKeyEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	engine.Flush(&loadByUniqueKeyEntity{Email: "a@example.com", Code: "a", Age: 10},
+		&loadByUniqueKeyEntity{Email: "b@example.com", Code: "a", Age: 20})
+
+	loaded := &loadByUniqueKeyEntity{}
+	assert.True(t, engine.LoadByUniqueKey(loaded, "Email", "b@example.com"))
+	assert.Equal(t, uint(2), loaded.ID)
+	assert.True(t, engine.LoadByUniqueKey(loaded, "CodeAge", "a", 10))
+	assert.Equal(t, uint(1), loaded.ID)
+	assert.False(t, engine.LoadByUniqueKey(loaded, "Email", "c@example.com"))
+
+	testLogger := &testLogHandler{}
+	engine.RegisterQueryLogger(testLogger, true, false, false)
+	loaded = &loadByUniqueKeyEntity{}
+	assert.True(t, engine.LoadByUniqueKey(loaded, "Email", "b@example.com"))
+	assert.Equal(t, uint(2), loaded.ID)
+	assert.Len(t, testLogger.Logs, 0)
+
+	loaded.Email = "c@example.com"
+	engine.Flush(loaded)
+	assert.False(t, engine.LoadByUniqueKey(loaded, "Email", "b@example.com"))
+	assert.True(t, engine.LoadByUniqueKey(loaded, "Email", "c@example.com"))
+	assert.Equal(t, uint(2), loaded.ID)
+
+	assert.PanicsWithError(t, "unique index Invalid not found", func() {
+		engine.LoadByUniqueKey(loaded, "Invalid", "a")
+	})
+	assert.PanicsWithError(t, "unique index CodeAge requires 2 values", func() {
+		engine.LoadByUniqueKey(loaded, "CodeAge", "a")
+	})
+}
@@ -64,12 +64,12 @@ func (c *LocalCache) GetSet(key string, ttl time.Duration, provider func() inter
 	if has {
 		ttlVal := val.(ttlValue)
 		seconds := int64(ttl.Seconds())
-		if seconds == 0 || time.Now().Unix()-ttlVal.time <= seconds {
+		if seconds == 0 || c.engine.now().Unix()-ttlVal.time <= seconds {
 			return ttlVal.value
 		}
 	}
 	userVal := provider()
-	val = ttlValue{value: userVal, time: time.Now().Unix()}
+	val = ttlValue{value: userVal, time: c.engine.now().Unix()}
 	c.Set(key, val)
 	return userVal
 }
@@ -167,5 +167,8 @@ func (c *LocalCache) getLruMutex(s string) *localCacheLruMutex {
 }
 
 func (c *LocalCache) fillLogFields(operation, query string, cacheMiss bool) {
+	if !c.engine.registry.runtime.sampleLog(nil) {
+		return
+	}
 	fillLogFields(c.engine.queryLoggersLocalCache, c.config.GetCode(), sourceLocalCache, operation, query, nil, cacheMiss, nil)
 }
@@ -3,6 +3,8 @@ package beeorm
 import (
 	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-redsync/redsync/v4"
@@ -24,9 +26,39 @@ func (l *standardLockerClient) Obtain(ctx context.Context, key string, options .
 
 }
 
+const lockerFencingSuffix = ":_fencing"
+
+// Fencing counter outlives lock by this period, so tokens grow as long as lock is used at least once a day.
+const lockerFencingTTL = time.Hour * 24
+
+// Token is issued only while lock is still held by obtaining mutex, so holders get tokens in order they owned the lock.
+var lockerFencingScript = newRedisScript("beeorm_lock_fencing", `
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+local token = redis.call('INCR', KEYS[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[2])
+return token
+`)
+
+type LockerMetrics struct {
+	Obtained  uint64
+	Failed    uint64
+	TotalWait time.Duration
+	MaxWait   time.Duration
+}
+
+type lockerMetrics struct {
+	obtained  uint64
+	failed    uint64
+	totalWait int64
+	maxWait   int64
+}
+
 type Locker struct {
-	locker lockerClient
-	r      *RedisCache
+	locker  lockerClient
+	r       *RedisCache
+	metrics *lockerMetrics
 }
 
 func (r *RedisCache) GetLocker() *Locker {
@@ -37,11 +69,94 @@ func (r *RedisCache) GetLocker() *Locker {
 	pool := goredis.NewPool(client)
 	rs := redsync.New(pool)
 	lockerClient := &standardLockerClient{client: rs}
-	r.locker = &Locker{locker: lockerClient, r: r}
+	r.locker = &Locker{locker: lockerClient, r: r, metrics: r.engine.registry.getLockerMetrics(r.config.GetCode())}
 	return r.locker
 }
 
+func (r *validatedRegistry) getLockerMetrics(pool string) *lockerMetrics {
+	metrics, _ := r.lockerMetrics.LoadOrStore(pool, &lockerMetrics{})
+	return metrics.(*lockerMetrics)
+}
+
+// GetLockerMetrics returns lock metrics of all engines created from this registry, grouped by Redis pool
+func (r *validatedRegistry) GetLockerMetrics() map[string]LockerMetrics {
+	result := make(map[string]LockerMetrics)
+	r.lockerMetrics.Range(func(pool, metrics interface{}) bool {
+		result[pool.(string)] = metrics.(*lockerMetrics).get()
+		return true
+	})
+	return result
+}
+
 func (l *Locker) Obtain(ctx context.Context, key string, ttl time.Duration, waitTimeout time.Duration) (lock *Lock, obtained bool) {
+	start := time.Now()
+	lock, obtained = l.obtain(ctx, key, ttl, waitTimeout)
+	l.metrics.recordWait(start, obtained)
+	return lock, obtained
+}
+
+func (l *Locker) TryLockWithTimeout(ctx context.Context, key string, ttl time.Duration, timeout time.Duration) (lock *Lock, obtained bool) {
+	start := time.Now()
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	delay := timeout / 10
+	if delay < 10*time.Millisecond {
+		delay = 10 * time.Millisecond
+	}
+	for {
+		if ctx.Err() == nil {
+			lock, obtained = l.obtain(ctx, key, ttl, 0)
+			if obtained {
+				l.metrics.recordWait(start, true)
+				return lock, true
+			}
+		}
+		retry := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			retry.Stop()
+			l.metrics.recordWait(start, false)
+			return nil, false
+		case <-deadline.C:
+			retry.Stop()
+			l.metrics.recordWait(start, false)
+			return nil, false
+		case <-retry.C:
+		}
+	}
+}
+
+// GetMetrics returns lock metrics of this Redis pool, shared by all engines created from the same registry
+func (l *Locker) GetMetrics() LockerMetrics {
+	return l.metrics.get()
+}
+
+func (m *lockerMetrics) get() LockerMetrics {
+	return LockerMetrics{
+		Obtained:  atomic.LoadUint64(&m.obtained),
+		Failed:    atomic.LoadUint64(&m.failed),
+		TotalWait: time.Duration(atomic.LoadInt64(&m.totalWait)),
+		MaxWait:   time.Duration(atomic.LoadInt64(&m.maxWait)),
+	}
+}
+
+func (m *lockerMetrics) recordWait(start time.Time, obtained bool) {
+	if obtained {
+		atomic.AddUint64(&m.obtained, 1)
+	} else {
+		atomic.AddUint64(&m.failed, 1)
+	}
+	wait := int64(time.Since(start))
+	atomic.AddInt64(&m.totalWait, wait)
+	for {
+		current := atomic.LoadInt64(&m.maxWait)
+		if wait <= current || atomic.CompareAndSwapInt64(&m.maxWait, current, wait) {
+			return
+		}
+	}
+}
+
+func (l *Locker) obtain(ctx context.Context, key string, ttl time.Duration, waitTimeout time.Duration) (lock *Lock, obtained bool) {
 	key = l.r.addNamespacePrefix(key)
 	if ttl == 0 {
 		panic(errors.New("ttl must be higher than zero"))
@@ -83,68 +198,141 @@ func (l *Locker) Obtain(ctx context.Context, key string, ttl time.Duration, wait
 		l.fillLogFields("LOCK OBTAIN", message, start, false, nil)
 	}
 	checkError(err)
-	lock = &Lock{lock: mutex, locker: l, ttl: ttl, key: key, has: true, engine: l.r.engine}
+	token, _ := l.r.runScript(lockerFencingScript, []string{key, key + lockerFencingSuffix}, mutex.Value(),
+		(ttl + lockerFencingTTL).Milliseconds()).(int64)
+	if token == 0 {
+		return nil, false
+	}
+	lock = &Lock{lock: mutex, ttl: ttl, key: key, has: true, pool: l.r.config.GetCode(), loggers: l.r.engine.queryLoggersRedis,
+		fencingToken: uint64(token), lost: make(chan struct{})}
 	return lock, true
 }
 
+// Lock keeps only Redis pool code and loggers of engine that obtained it,
+// so it can be renewed and released after that engine is released.
 type Lock struct {
-	lock   *redsync.Mutex
-	key    string
-	ttl    time.Duration
-	locker *Locker
-	has    bool
-	engine *engineImplementation
+	lock         *redsync.Mutex
+	key          string
+	ttl          time.Duration
+	has          bool
+	pool         string
+	loggers      []LogHandler
+	fencingToken uint64
+	mutex        sync.Mutex
+	stopRenew    chan struct{}
+	lost         chan struct{}
+}
+
+func (l *Lock) FencingToken() uint64 {
+	return l.fencingToken
+}
+
+func (l *Lock) AutoRenew(interval time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if !l.has || l.stopRenew != nil {
+		return
+	}
+	if interval <= 0 {
+		interval = l.ttl / 3
+	}
+	l.stopRenew = make(chan struct{})
+	go l.renew(l.stopRenew, interval)
+}
+
+func (l *Lock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *Lock) renew(stop chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	defer func() {
+		if rec := recover(); rec != nil {
+			l.mutex.Lock()
+			l.markLost()
+			l.mutex.Unlock()
+		}
+	}()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if !l.Refresh(context.Background()) {
+				return
+			}
+		}
+	}
+}
+
+func (l *Lock) markLost() {
+	l.has = false
+	select {
+	case <-l.lost:
+	default:
+		close(l.lost)
+	}
 }
 
 func (l *Lock) Release() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
 	if !l.has {
 		return
 	}
 	l.has = false
-	start := getNow(l.engine.hasRedisLogger)
+	if l.stopRenew != nil {
+		close(l.stopRenew)
+	}
+	start := getNow(len(l.loggers) > 0)
 	ok, err := l.lock.UnlockContext(context.Background())
 	_, is := err.(redsync.ErrTaken)
 	if is {
 		err = nil
 	}
-	if l.engine.hasRedisLogger {
-		l.locker.fillLogFields("LOCK RELEASE", "LOCK RELEASE "+l.key, start, !ok, err)
+	if len(l.loggers) > 0 {
+		l.fillLogFields("LOCK RELEASE", "LOCK RELEASE "+l.key, start, !ok, err)
 	}
 	checkError(err)
 }
 
 func (l *Lock) TTL() time.Duration {
-	start := getNow(l.engine.hasRedisLogger)
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	start := getNow(len(l.loggers) > 0)
 	t := l.lock.Until()
-	if l.engine.hasRedisLogger {
-		l.locker.fillLogFields("LOCK TTL", "LOCK TTL "+l.key, start, false, nil)
+	if len(l.loggers) > 0 {
+		l.fillLogFields("LOCK TTL", "LOCK TTL "+l.key, start, false, nil)
 	}
 	return t.Sub(time.Now())
 }
 
 func (l *Lock) Refresh(ctx context.Context) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
 	if !l.has {
 		return false
 	}
-	start := getNow(l.engine.hasRedisLogger)
+	start := getNow(len(l.loggers) > 0)
 	ok, err := l.lock.ExtendContext(ctx)
 	if err != nil {
 		if err == redsync.ErrExtendFailed {
 			ok = false
 			err = nil
-			l.has = false
+			l.markLost()
 		} else {
 			_, is := err.(redsync.ErrTaken)
 			if is {
 				ok = false
 				err = nil
-				l.has = false
+				l.markLost()
 			}
 		}
 	}
-	if l.engine.hasRedisLogger {
+	if len(l.loggers) > 0 {
 		message := fmt.Sprintf("LOCK REFRESH %s %s", l.key, l.ttl)
-		l.locker.fillLogFields("LOCK REFRESH", message, start, !ok, err)
+		l.fillLogFields("LOCK REFRESH", message, start, !ok, err)
 	}
 	checkError(err)
 	return ok
@@ -153,3 +341,7 @@ func (l *Lock) Refresh(ctx context.Context) bool {
 func (l *Locker) fillLogFields(operation, query string, start *time.Time, cacheMiss bool, err error) {
 	fillLogFields(l.r.engine.queryLoggersRedis, l.r.config.GetCode(), sourceRedis, operation, query, start, cacheMiss, err)
 }
+
+func (l *Lock) fillLogFields(operation, query string, start *time.Time, cacheMiss bool, err error) {
+	fillLogFields(l.loggers, l.pool, sourceRedis, operation, query, start, cacheMiss, err)
+}
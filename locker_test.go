@@ -51,3 +51,80 @@ func testLocker(t *testing.T, namespace string) {
 		_, _ = l.Obtain(context.Background(), "test_key", 0, time.Millisecond)
 	})
 }
+
+func TestLockerFencingAndRenewal(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterRedis("localhost:6382", "", 15)
+	validatedRegistry, err := registry.Validate()
+	assert.Nil(t, err)
+	engine := validatedRegistry.CreateEngine()
+	engine.GetRedis().FlushDB()
+
+	l := engine.GetRedis().GetLocker()
+	lock, has := l.Obtain(context.Background(), "fenced", time.Millisecond*300, 0)
+	assert.True(t, has)
+	assert.Equal(t, uint64(1), lock.FencingToken())
+	fencingTTL := engine.GetRedis().client.PTTL(context.Background(), "fenced"+lockerFencingSuffix).Val()
+	assert.Greater(t, fencingTTL, lockerFencingTTL)
+	token := engine.GetRedis().runScript(lockerFencingScript, []string{"fenced", "fenced" + lockerFencingSuffix}, "other-owner",
+		lockerFencingTTL.Milliseconds())
+	assert.Equal(t, int64(0), token)
+	lock.AutoRenew(time.Millisecond * 50)
+	time.Sleep(time.Millisecond * 500)
+
+	start := time.Now()
+	_, has = l.TryLockWithTimeout(context.Background(), "fenced", time.Second, time.Millisecond*200)
+	assert.False(t, has)
+	assert.GreaterOrEqual(t, time.Since(start), time.Millisecond*200)
+
+	lock.Release()
+	select {
+	case <-lock.Lost():
+		assert.Fail(t, "released lock marked as lost")
+	default:
+	}
+	lock2, has := l.TryLockWithTimeout(context.Background(), "fenced", time.Second, time.Millisecond*200)
+	assert.True(t, has)
+	assert.Equal(t, uint64(2), lock2.FencingToken())
+
+	engine.GetRedis().Del("fenced")
+	assert.False(t, lock2.Refresh(context.Background()))
+	<-lock2.Lost()
+
+	metrics := l.GetMetrics()
+	assert.Equal(t, uint64(2), metrics.Obtained)
+	assert.Equal(t, uint64(1), metrics.Failed)
+	assert.GreaterOrEqual(t, metrics.MaxWait, time.Millisecond*200)
+	assert.GreaterOrEqual(t, metrics.TotalWait, metrics.MaxWait)
+
+	other := validatedRegistry.CreateEngine()
+	_, has = other.GetRedis().GetLocker().Obtain(context.Background(), "other", time.Second, 0)
+	assert.True(t, has)
+	assert.Equal(t, uint64(3), l.GetMetrics().Obtained)
+	assert.Equal(t, uint64(3), validatedRegistry.GetLockerMetrics()["default"].Obtained)
+}
+
+func TestLockerAfterEngineRelease(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterRedis("localhost:6382", "", 15)
+	validatedRegistry, err := registry.Validate()
+	assert.Nil(t, err)
+	engine := validatedRegistry.CreateEngine()
+	engine.GetRedis().FlushDB()
+
+	lock, has := engine.GetRedis().GetLocker().Obtain(context.Background(), "released", time.Millisecond*300, 0)
+	assert.True(t, has)
+	lock.AutoRenew(time.Millisecond * 50)
+	engine.Release()
+	time.Sleep(time.Millisecond * 500)
+	select {
+	case <-lock.Lost():
+		assert.Fail(t, "lock lost after engine release")
+	default:
+	}
+	lock.Release()
+
+	engine = validatedRegistry.CreateEngine()
+	_, has = engine.GetRedis().GetLocker().Obtain(context.Background(), "released", time.Second, 0)
+	assert.True(t, has)
+}
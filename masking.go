@@ -0,0 +1,48 @@
+package beeorm
+
+import (
+	"strings"
+)
+
+const maskedValue = "***"
+
+func (tableSchema *tableSchema) maskBind(bind Bind) Bind {
+	if bind == nil || len(tableSchema.maskedColumns) == 0 {
+		return bind
+	}
+	masked := make(Bind, len(bind))
+	for column, value := range bind {
+		if value != nil && tableSchema.maskedColumns[column] {
+			masked[column] = maskedValue
+		} else {
+			masked[column] = value
+		}
+	}
+	return masked
+}
+
+func (tableSchema *tableSchema) maskSQLValue(column, value string) string {
+	if value == "NULL" || !tableSchema.maskedColumns[column] {
+		return value
+	}
+	return "'" + maskedValue + "'"
+}
+
+// queryMaskRequired returns true when query with masked values should be built for query logs.
+// Lazy queries carry it in event payload, so consumer never logs values of masked columns.
+func (f *flusher) queryMaskRequired(schema *tableSchema, lazy bool) bool {
+	return len(schema.maskedColumns) > 0 && (lazy || f.engine.hasDBLogger)
+}
+
+func (e *engineImplementation) addQueryMask(query, masked string) {
+	if query != masked {
+		e.queryMasks = append(e.queryMasks, query, masked)
+	}
+}
+
+func (e *engineImplementation) maskQuery(query string) string {
+	if len(e.queryMasks) == 0 {
+		return query
+	}
+	return strings.NewReplacer(e.queryMasks...).Replace(query)
+}
@@ -0,0 +1,94 @@
+package beeorm
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type maskedEntity struct {
+	ORM      `orm:"log;audit"`
+	ID       uint
+	Name     string
+	Password string  `orm:"mask"`
+	Token    *string `orm:"mask"`
+	PIN      uint32  `orm:"mask"`
+}
+
+func TestMaskedColumns(t *testing.T) {
+	var entity *maskedEntity
+	registry := &Registry{}
+	plugin := NewAuditLogPlugin()
+	registry.RegisterPlugin(plugin)
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	engine.GetMysql().Exec("TRUNCATE TABLE `_log_default_maskedEntity`")
+	engine.GetMysql().Exec("TRUNCATE TABLE `_audit_maskedEntity`")
+	engine.GetRedis().FlushDB()
+	logger := &testLogHandler{}
+	engine.RegisterQueryLogger(logger, true, false, false)
+
+	entity = &maskedEntity{Name: "John", Password: "secret-hash", PIN: 987654}
+	engine.Flush(entity)
+	assert.NotEmpty(t, logger.Logs)
+	for _, log := range logger.Logs {
+		assert.NotContains(t, log["query"], "secret-hash")
+		assert.NotContains(t, log["query"], "987654")
+	}
+	assert.Contains(t, logger.Logs[0]["query"], "'***'")
+	assert.Contains(t, logger.Logs[0]["query"], "'John'")
+
+	logger.clear()
+	token := "token-value"
+	entity.Token = &token
+	entity.Password = "other-hash"
+	engine.Flush(entity)
+	for _, log := range logger.Logs {
+		assert.NotContains(t, log["query"], "token-value")
+		assert.NotContains(t, log["query"], "other-hash")
+	}
+
+	entity = &maskedEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "other-hash", entity.Password)
+	assert.Equal(t, "token-value", *entity.Token)
+
+	history := plugin.GetHistory(engine, entity, nil)
+	assert.Len(t, history, 2)
+	assert.Equal(t, "***", history[0].Changes["Password"])
+	assert.Equal(t, "***", history[0].Changes["Token"])
+	assert.Equal(t, "***", history[0].Before["Password"])
+	assert.Nil(t, history[0].Before["Token"])
+	assert.Equal(t, "***", history[1].Changes["Password"])
+	assert.Equal(t, "John", history[1].Changes["Name"])
+
+	consumer := NewBackgroundConsumer(engine)
+	consumer.DisableBlockMode()
+	consumer.blockTime = time.Millisecond
+	consumer.Digest(context.Background())
+	logs := engine.GetRegistry().GetTableSchemaForEntity(entity).GetEntityLogs(engine, 1, nil, nil)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, "***", logs[0].Changes["Password"])
+	assert.Equal(t, "***", logs[1].Changes["Password"])
+
+	logger.clear()
+	entity = &maskedEntity{Name: "Tom", Password: "lazy-hash", PIN: 123987}
+	engine.FlushLazy(entity)
+	consumer.Digest(context.Background())
+	found := false
+	for _, log := range logger.Logs {
+		query := log["query"].(string)
+		assert.NotContains(t, query, "lazy-hash")
+		assert.NotContains(t, query, "123987")
+		if strings.Contains(query, "'Tom'") {
+			found = true
+		}
+	}
+	assert.True(t, found)
+	assert.Len(t, engine.queryMasks, 0)
+
+	engine.addQueryMask("SELECT 'secret'", "SELECT '***'")
+	assert.Equal(t, "SELECT '***'", engine.Clone().(*engineImplementation).maskQuery("SELECT 'secret'"))
+}
@@ -0,0 +1,84 @@
+package beeorm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type OnlineDDLStrategy string
+
+const (
+	OnlineDDLNone    OnlineDDLStrategy = ""
+	OnlineDDLInstant OnlineDDLStrategy = "instant"
+	OnlineDDLInplace OnlineDDLStrategy = "inplace"
+	OnlineDDLGhost   OnlineDDLStrategy = "gh-ost"
+	OnlineDDLPtOSC   OnlineDDLStrategy = "pt-osc"
+)
+
+var alterCommentRegexp = regexp.MustCompile(`/\*.*?\*/`)
+
+func (a Alter) GetOnlineSQL(strategy OnlineDDLStrategy) (string, error) {
+	if !strings.HasPrefix(a.SQL, "ALTER TABLE ") {
+		return "", fmt.Errorf("online DDL is supported only for ALTER TABLE")
+	}
+	end := strings.LastIndex(a.SQL, ";")
+	if end == -1 {
+		end = len(a.SQL)
+	}
+	switch strategy {
+	case OnlineDDLNone:
+		return a.SQL, nil
+	case OnlineDDLInstant:
+		return a.SQL[0:end] + ", ALGORITHM=INSTANT" + a.SQL[end:], nil
+	case OnlineDDLInplace:
+		return a.SQL[0:end] + ", ALGORITHM=INPLACE, LOCK=NONE" + a.SQL[end:], nil
+	}
+	return "", fmt.Errorf("online DDL strategy '%s' requires external tool", strategy)
+}
+
+func (a Alter) GetOnlineCommand(strategy OnlineDDLStrategy) (string, error) {
+	item := newAlterPlanItem(a)
+	if item.Operation != AlterOperationAlter {
+		return "", fmt.Errorf("online DDL is supported only for ALTER TABLE")
+	}
+	body := alterCommentRegexp.ReplaceAllString(a.SQL[strings.Index(a.SQL, "\n")+1:], "")
+	parts := strings.Split(body, "\n")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+	body = strings.TrimRight(strings.Join(parts, " "), "; ")
+	switch strategy {
+	case OnlineDDLGhost:
+		return fmt.Sprintf("gh-ost --database=%s --table=%s --alter=%s --allow-on-master --execute",
+			shellQuote(item.Database), shellQuote(item.Table), shellQuote(body)), nil
+	case OnlineDDLPtOSC:
+		return fmt.Sprintf("pt-online-schema-change --alter %s D=%s,t=%s --execute",
+			shellQuote(body), item.Database, item.Table), nil
+	}
+	return "", fmt.Errorf("online DDL strategy '%s' is not an external tool", strategy)
+}
+
+func (a Alter) ExecOnline(strategy OnlineDDLStrategy) error {
+	if strategy == OnlineDDLInstant && a.engine.GetMysql(a.Pool).GetPoolConfig().GetVersion() < 8 {
+		return fmt.Errorf("ALGORITHM=INSTANT requires MySQL 8")
+	}
+	sql, err := a.GetOnlineSQL(strategy)
+	if err != nil {
+		return err
+	}
+	_, err = a.engine.GetMysql(a.Pool).exec(sql)
+	return err
+}
+
+func (p *AltersPlan) UseOnlineDDL(strategy OnlineDDLStrategy, minEstimatedRows uint64) {
+	for i, item := range p.Items {
+		if item.Operation == AlterOperationAlter && item.EstimatedRows >= minEstimatedRows {
+			p.Items[i].OnlineDDL = strategy
+		}
+	}
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
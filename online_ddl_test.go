@@ -0,0 +1,80 @@
+package beeorm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type onlineDDLEntity struct {
+	ORM
+	ID   uint
+	Name string
+	Age  uint8
+}
+
+func TestOnlineDDLStatements(t *testing.T) {
+	alter := Alter{SQL: "ALTER TABLE `test`.`users`\n    ADD COLUMN `Age` int(11) DEFAULT NULL AFTER `ID`,\n" +
+		"    CHANGE COLUMN `Name` `Name` varchar(100) DEFAULT NULL AFTER `Age`;/*CHANGED FROM `Name` varchar(255) DEFAULT NULL*/", Pool: "default"}
+	sql, err := alter.GetOnlineSQL(OnlineDDLInstant)
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER TABLE `test`.`users`\n    ADD COLUMN `Age` int(11) DEFAULT NULL AFTER `ID`,\n"+
+		"    CHANGE COLUMN `Name` `Name` varchar(100) DEFAULT NULL AFTER `Age`, ALGORITHM=INSTANT;/*CHANGED FROM `Name` varchar(255) DEFAULT NULL*/", sql)
+	sql, err = alter.GetOnlineSQL(OnlineDDLInplace)
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "AFTER `Age`, ALGORITHM=INPLACE, LOCK=NONE;")
+	_, err = alter.GetOnlineSQL(OnlineDDLGhost)
+	assert.EqualError(t, err, "online DDL strategy 'gh-ost' requires external tool")
+
+	command, err := alter.GetOnlineCommand(OnlineDDLGhost)
+	assert.NoError(t, err)
+	assert.Equal(t, "gh-ost --database='test' --table='users' --alter='ADD COLUMN `Age` int(11) DEFAULT NULL AFTER `ID`, "+
+		"CHANGE COLUMN `Name` `Name` varchar(100) DEFAULT NULL AFTER `Age`' --allow-on-master --execute", command)
+	command, err = alter.GetOnlineCommand(OnlineDDLPtOSC)
+	assert.NoError(t, err)
+	assert.Equal(t, "pt-online-schema-change --alter 'ADD COLUMN `Age` int(11) DEFAULT NULL AFTER `ID`, "+
+		"CHANGE COLUMN `Name` `Name` varchar(100) DEFAULT NULL AFTER `Age`' D=test,t=users --execute", command)
+	_, err = alter.GetOnlineCommand(OnlineDDLInstant)
+	assert.EqualError(t, err, "online DDL strategy 'instant' is not an external tool")
+
+	create := Alter{SQL: "CREATE TABLE `test`.`users` (`ID` int);"}
+	_, err = create.GetOnlineSQL(OnlineDDLInplace)
+	assert.EqualError(t, err, "online DDL is supported only for ALTER TABLE")
+	_, err = create.GetOnlineCommand(OnlineDDLGhost)
+	assert.EqualError(t, err, "online DDL is supported only for ALTER TABLE")
+	assert.Equal(t, "'it'\\''s'", shellQuote("it's"))
+
+	plan := &AltersPlan{Items: []AlterPlanItem{newAlterPlanItem(create), newAlterPlanItem(alter)}}
+	plan.Items[1].EstimatedRows = 1000000
+	plan.UseOnlineDDL(OnlineDDLGhost, 500000)
+	assert.Equal(t, OnlineDDLNone, plan.Items[0].OnlineDDL)
+	assert.Equal(t, OnlineDDLGhost, plan.Items[1].OnlineDDL)
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, plan.WriteSQL(buffer))
+	assert.Contains(t, buffer.String(), "CREATE TABLE `test`.`users` (`ID` int);\n")
+	assert.Contains(t, buffer.String(), "-- run: gh-ost --database='test' --table='users'")
+}
+
+func TestOnlineDDL(t *testing.T) {
+	var entity *onlineDDLEntity
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 8, 6, "", entity)
+	engine.GetMysql().Exec("ALTER TABLE `onlineDDLEntity` DROP COLUMN `Age`")
+	alters := engine.GetAlters()
+	assert.Len(t, alters, 1)
+	assert.NoError(t, alters[0].ExecOnline(OnlineDDLInstant))
+	assert.Len(t, engine.GetAlters(), 0)
+
+	engine.GetMysql().Exec("ALTER TABLE `onlineDDLEntity` DROP COLUMN `Age`")
+	plan := engine.GetAltersPlan()
+	plan.UseOnlineDDL(OnlineDDLInplace, 0)
+	plan.Exec()
+	assert.Len(t, engine.GetAlters(), 0)
+
+	engine = prepareTables(t, &Registry{}, 5, 6, "", entity)
+	engine.GetMysql().Exec("ALTER TABLE `onlineDDLEntity` DROP COLUMN `Age`")
+	alters = engine.GetAlters()
+	assert.EqualError(t, alters[0].ExecOnline(OnlineDDLInstant), "ALGORITHM=INSTANT requires MySQL 8")
+	assert.NoError(t, alters[0].ExecOnline(OnlineDDLInplace))
+}
@@ -36,6 +36,7 @@ type Entity interface {
 	SetEntityLogMeta(key string, value interface{})
 	SetField(field string, value interface{}) error
 	Clone() Entity
+	Snapshot() Bind
 }
 
 type ORM struct {
@@ -51,6 +52,7 @@ type ORM struct {
 	elem                 reflect.Value
 	idElem               reflect.Value
 	logMeta              map[string]interface{}
+	translations         map[string]string
 }
 
 func DisableCacheHashCheck() {
@@ -118,7 +120,10 @@ func (orm *ORM) IsDirty() bool {
 	if !orm.inDB {
 		return true
 	}
-	_, is := orm.GetDirtyBind()
+	serializer := getSerializer()
+	bindBuilder, is := orm.buildDirtyBind(serializer)
+	putSerializer(serializer)
+	releaseBindBuilder(bindBuilder)
 	return is
 }
 
@@ -135,6 +140,12 @@ func (orm *ORM) buildDirtyBind(serializer *serializer) (bindBuilder *bindBuilder
 	if orm.fakeDelete {
 		if orm.tableSchema.hasFakeDelete {
 			orm.elem.FieldByName("FakeDelete").SetBool(true)
+		} else if orm.tableSchema.hasSoftDelete {
+			field := orm.elem.FieldByName(orm.tableSchema.softDeleteColumn)
+			if field.IsNil() {
+				now := time.Now()
+				field.Set(reflect.ValueOf(&now))
+			}
 		} else {
 			orm.delete = true
 		}
@@ -143,11 +154,74 @@ func (orm *ORM) buildDirtyBind(serializer *serializer) (bindBuilder *bindBuilder
 	serializer.Reset(orm.binary)
 	bindBuilder = newBindBuilder(id, orm)
 	bindBuilder.build(serializer, orm.tableSchema.fields, orm.elem, true)
+	bindBuilder.removeGeneratedColumns()
 	has = !orm.inDB || orm.delete || len(bindBuilder.bind) > 0
 	return bindBuilder, has
 }
 
+func (orm *ORM) fillTimestamps(engine *engineImplementation, bind Bind) bool {
+	schema := orm.tableSchema
+	if schema.createdAtColumn == "" && schema.updatedAtColumn == "" {
+		return false
+	}
+	location := schema.registry.registry.timestampsLocation
+	if location == nil {
+		location = time.Local
+	}
+	now := engine.now().In(location).Truncate(time.Second)
+	changed := false
+	if !orm.inDB && schema.createdAtColumn != "" {
+		changed = setTimestampField(orm.elem.FieldByName(schema.createdAtColumn), now, true) || changed
+	}
+	if schema.updatedAtColumn != "" {
+		if !orm.inDB {
+			changed = setTimestampField(orm.elem.FieldByName(schema.updatedAtColumn), now, true) || changed
+		} else if _, has := bind[schema.updatedAtColumn]; !has {
+			changed = setTimestampField(orm.elem.FieldByName(schema.updatedAtColumn), now, false) || changed
+		}
+	}
+	return changed
+}
+
+func (orm *ORM) fillDefaults() bool {
+	changed := false
+	for field, value := range orm.tableSchema.defaults {
+		fieldValue := orm.elem.FieldByName(field)
+		if fieldValue.IsZero() {
+			if value.Kind() == reflect.Ptr {
+				ptr := reflect.New(value.Type().Elem())
+				ptr.Elem().Set(value.Elem())
+				value = ptr
+			}
+			fieldValue.Set(value)
+			changed = true
+		}
+	}
+	return changed
+}
+
+func setTimestampField(field reflect.Value, now time.Time, onlyEmpty bool) bool {
+	if field.Kind() == reflect.Ptr {
+		if onlyEmpty && !field.IsNil() {
+			return false
+		}
+		field.Set(reflect.ValueOf(&now))
+		return true
+	}
+	if onlyEmpty && !field.Interface().(time.Time).IsZero() {
+		return false
+	}
+	field.Set(reflect.ValueOf(now))
+	return true
+}
+
 func (orm *ORM) serialize(serializer *serializer) {
+	if orm.tableSchema.hasBinder {
+		serializer.SerializeUInteger(orm.tableSchema.structureHash)
+		orm.value.Interface().(EntityBinder).BeeormSerialize(serializer)
+		orm.binary = serializer.Read()
+		return
+	}
 	orm.serializeFields(serializer, orm.tableSchema.fields, orm.elem, true)
 	orm.binary = serializer.Read()
 }
@@ -316,6 +390,12 @@ func (orm *ORM) deserializeStructFromDB(serializer *serializer, index int, field
 		}
 		index++
 	}
+	for k, i := range fields.customs {
+		val, valid := normalizeCustomColumnValue(fields.fields[i].Type, pointers[index].(*sql.NullString), fields.customsOptions[k])
+		serializer.SerializeBool(valid)
+		serializer.SerializeBytes([]byte(val))
+		index++
+	}
 	for _, subField := range fields.structsFields {
 		index = orm.deserializeStructFromDB(serializer, index, subField, pointers, false)
 	}
@@ -402,7 +482,7 @@ func (orm *ORM) serializeFields(serialized *serializer, fields *tableFields, ele
 	}
 	k := 0
 	for _, i := range fields.stringsEnums {
-		val := elem.Field(i).String()
+		val := getEnumFieldValue(elem.Field(i))
 		if val == "" {
 			serialized.SerializeUInteger(0)
 		} else {
@@ -499,6 +579,11 @@ func (orm *ORM) serializeFields(serialized *serializer, fields *tableFields, ele
 			}
 		}
 	}
+	for k, i := range fields.customs {
+		val, valid := getCustomColumnValue(elem.Field(i), fields.customsOptions[k])
+		serialized.SerializeBool(valid)
+		serialized.SerializeBytes([]byte(val))
+	}
 	for k, i := range fields.structs {
 		orm.serializeFields(serialized, fields.structsFields[k], elem.Field(i), false)
 	}
@@ -510,7 +595,11 @@ func (orm *ORM) deserialize(serializer *serializer) {
 	if !disableCacheHashCheck && hash != orm.tableSchema.structureHash {
 		panic(fmt.Errorf("%s entity cache data use wrong hash", orm.tableSchema.t.String()))
 	}
-	orm.deserializeFields(serializer, orm.tableSchema.fields, orm.elem)
+	if orm.tableSchema.hasBinder {
+		orm.value.Interface().(EntityBinder).BeeormDeserialize(serializer)
+	} else {
+		orm.deserializeFields(serializer, orm.tableSchema.fields, orm.elem)
+	}
 	orm.loaded = true
 }
 
@@ -549,7 +638,7 @@ func (orm *ORM) deserializeFields(serializer *serializer, fields *tableFields, e
 		if unix == zeroDateSeconds {
 			f.Set(reflect.Zero(f.Type()))
 		} else {
-			f.Set(reflect.ValueOf(time.Unix(unix-timeStampSeconds, 0)))
+			*f.Addr().Interface().(*time.Time) = time.Unix(unix-timeStampSeconds, 0)
 		}
 	}
 	for _, i := range fields.dates {
@@ -558,7 +647,7 @@ func (orm *ORM) deserializeFields(serializer *serializer, fields *tableFields, e
 		if unix == zeroDateSeconds {
 			f.Set(reflect.Zero(f.Type()))
 		} else {
-			f.Set(reflect.ValueOf(time.Unix(unix-timeStampSeconds, 0)))
+			*f.Addr().Interface().(*time.Time) = time.Unix(unix-timeStampSeconds, 0)
 		}
 	}
 	if fields.fakeDelete > 0 {
@@ -621,10 +710,20 @@ func (orm *ORM) deserializeFields(serializer *serializer, fields *tableFields, e
 	}
 	for z, i := range fields.stringsEnums {
 		index := serializer.DeserializeUInteger()
-		if index == 0 {
-			elem.Field(i).SetString("")
+		f := elem.Field(i)
+		if f.Kind() == reflect.Ptr {
+			if index == 0 {
+				if !f.IsNil() {
+					f.Set(reflect.Zero(f.Type()))
+				}
+			} else {
+				v := fields.enums[z].GetFields()[index-1]
+				f.Set(reflect.ValueOf(&v))
+			}
+		} else if index == 0 {
+			f.SetString("")
 		} else {
-			elem.Field(i).SetString(fields.enums[z].GetFields()[index-1])
+			f.SetString(fields.enums[z].GetFields()[index-1])
 		}
 	}
 	for _, i := range fields.bytes {
@@ -735,6 +834,10 @@ func (orm *ORM) deserializeFields(serializer *serializer, fields *tableFields, e
 		}
 		k++
 	}
+	for k, i := range fields.customs {
+		valid := serializer.DeserializeBool()
+		setCustomColumnValue(elem.Field(i), serializer.DeserializeBytes(), valid, fields.customsOptions[k])
+	}
 	for k, i := range fields.structs {
 		orm.deserializeFields(serializer, fields.structsFields[k], elem.Field(i))
 	}
@@ -0,0 +1,111 @@
+package beeorm
+
+import (
+	"fmt"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/shamaton/msgpack"
+)
+
+const payloadEnvelopeMarker = 0xc1
+const payloadEnvelopeVersion = 1
+
+type PayloadCodec interface {
+	Name() string
+	Marshal(value interface{}) ([]byte, error)
+	Unmarshal(data []byte, value interface{}) error
+}
+
+type msgpackCodec struct{}
+
+func (c *msgpackCodec) Name() string {
+	return "msgpack"
+}
+
+func (c *msgpackCodec) Marshal(value interface{}) ([]byte, error) {
+	return msgpack.Marshal(value)
+}
+
+func (c *msgpackCodec) Unmarshal(data []byte, value interface{}) error {
+	return msgpack.Unmarshal(data, &value)
+}
+
+type jsonCodec struct{}
+
+func (c *jsonCodec) Name() string {
+	return "json"
+}
+
+func (c *jsonCodec) Marshal(value interface{}) ([]byte, error) {
+	return jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(value)
+}
+
+func (c *jsonCodec) Unmarshal(data []byte, value interface{}) error {
+	return jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(data, value)
+}
+
+var MsgpackCodec PayloadCodec = &msgpackCodec{}
+var JSONCodec PayloadCodec = &jsonCodec{}
+
+func (r *Registry) RegisterPayloadCodec(codec PayloadCodec) {
+	if r.payloadCodecs == nil {
+		r.payloadCodecs = make(map[string]PayloadCodec)
+	}
+	r.payloadCodecs[codec.Name()] = codec
+}
+
+func (r *Registry) SetPayloadCodec(name string) {
+	r.payloadCodec = name
+}
+
+func initPayloadCodecs(r *Registry, registry *validatedRegistry) error {
+	registry.payloadCodecs = map[string]PayloadCodec{MsgpackCodec.Name(): MsgpackCodec, JSONCodec.Name(): JSONCodec}
+	for name, codec := range r.payloadCodecs {
+		if len(name) == 0 || len(name) > 255 {
+			return fmt.Errorf("invalid payload codec name '%s'", name)
+		}
+		registry.payloadCodecs[name] = codec
+	}
+	if r.payloadCodec != "" {
+		codec, has := registry.payloadCodecs[r.payloadCodec]
+		if !has {
+			return fmt.Errorf("payload codec '%s' is not registered", r.payloadCodec)
+		}
+		if codec != MsgpackCodec {
+			registry.payloadCodec = codec
+		}
+	}
+	return nil
+}
+
+func (r *validatedRegistry) encodePayload(value interface{}, stream string) []byte {
+	codec := r.payloadCodec
+	if codec == nil || strings.HasPrefix(stream, "orm-") || strings.HasPrefix(stream, "beeorm-") {
+		encoded, err := msgpack.Marshal(value)
+		checkError(err)
+		return encoded
+	}
+	encoded, err := codec.Marshal(value)
+	checkError(err)
+	name := codec.Name()
+	envelope := make([]byte, 0, len(encoded)+len(name)+3)
+	envelope = append(envelope, payloadEnvelopeMarker, payloadEnvelopeVersion, byte(len(name)))
+	envelope = append(envelope, name...)
+	return append(envelope, encoded...)
+}
+
+func (r *validatedRegistry) decodePayload(data []byte, value interface{}) error {
+	if len(data) == 0 || data[0] != payloadEnvelopeMarker {
+		return msgpack.Unmarshal(data, &value)
+	}
+	if len(data) < 3 || data[1] != payloadEnvelopeVersion || len(data) < 3+int(data[2]) {
+		return fmt.Errorf("invalid payload envelope")
+	}
+	name := string(data[3 : 3+int(data[2])])
+	codec, has := r.payloadCodecs[name]
+	if !has {
+		return fmt.Errorf("payload codec '%s' is not registered", name)
+	}
+	return codec.Unmarshal(data[3+int(data[2]):], value)
+}
@@ -0,0 +1,73 @@
+package beeorm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shamaton/msgpack"
+	"github.com/stretchr/testify/assert"
+)
+
+type upperPayloadCodec struct{}
+
+func (c *upperPayloadCodec) Name() string {
+	return "upper"
+}
+
+func (c *upperPayloadCodec) Marshal(value interface{}) ([]byte, error) {
+	return []byte(strings.ToUpper(value.(string))), nil
+}
+
+func (c *upperPayloadCodec) Unmarshal(data []byte, value interface{}) error {
+	*value.(*string) = string(data)
+	return nil
+}
+
+type payloadCodecEvent struct {
+	Name string
+	Age  int
+}
+
+func TestPayloadCodec(t *testing.T) {
+	registry := &Registry{}
+	registry.SetPayloadCodec("json")
+	validated := &validatedRegistry{}
+	assert.NoError(t, initPayloadCodecs(registry, validated))
+
+	encoded := validated.encodePayload(&payloadCodecEvent{Name: "Tom", Age: 12}, "events")
+	assert.Equal(t, byte(payloadEnvelopeMarker), encoded[0])
+	assert.Equal(t, `{"Name":"Tom","Age":12}`, string(encoded[7:]))
+	decoded := &payloadCodecEvent{}
+	assert.NoError(t, validated.decodePayload(encoded, decoded))
+	assert.Equal(t, "Tom", decoded.Name)
+	assert.Equal(t, 12, decoded.Age)
+
+	legacy, _ := msgpack.Marshal(&payloadCodecEvent{Name: "Adam", Age: 20})
+	decoded = &payloadCodecEvent{}
+	assert.NoError(t, validated.decodePayload(legacy, decoded))
+	assert.Equal(t, "Adam", decoded.Name)
+	assert.Equal(t, legacy, validated.encodePayload(&payloadCodecEvent{Name: "Adam", Age: 20}, LazyChannelName))
+
+	previous := &validatedRegistry{}
+	assert.NoError(t, initPayloadCodecs(&Registry{}, previous))
+	assert.Equal(t, legacy, previous.encodePayload(&payloadCodecEvent{Name: "Adam", Age: 20}, "events"))
+	decoded = &payloadCodecEvent{}
+	assert.NoError(t, previous.decodePayload(encoded, decoded))
+	assert.Equal(t, "Tom", decoded.Name)
+
+	registry = &Registry{}
+	registry.RegisterPayloadCodec(&upperPayloadCodec{})
+	registry.SetPayloadCodec("upper")
+	custom := &validatedRegistry{}
+	assert.NoError(t, initPayloadCodecs(registry, custom))
+	encoded = custom.encodePayload("hello", "events")
+	value := ""
+	assert.NoError(t, custom.decodePayload(encoded, &value))
+	assert.Equal(t, "HELLO", value)
+	assert.EqualError(t, previous.decodePayload(encoded, &value), "payload codec 'upper' is not registered")
+	assert.EqualError(t, previous.decodePayload([]byte{payloadEnvelopeMarker, 9, 1}, &value), "invalid payload envelope")
+
+	registry = &Registry{}
+	registry.SetPayloadCodec("protobuf")
+	assert.EqualError(t, initPayloadCodecs(registry, &validatedRegistry{}), "payload codec 'protobuf' is not registered")
+}
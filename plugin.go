@@ -0,0 +1,161 @@
+package beeorm
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type FlushType int
+
+const (
+	FlushTypeInsert FlushType = iota
+	FlushTypeUpdate
+	FlushTypeDelete
+)
+
+type Plugin interface {
+	GetCode() string
+}
+
+type PluginInterfaceEntityFlushed interface {
+	PluginInterfaceEntityFlushed(engine Engine, event *EntityFlushedEvent)
+}
+
+// PluginInterfaceEntityFlushedInTransaction is called before the flush transaction is committed, so plugin
+// writes are rolled back together with flushed entities. Lazy flushes emit the event again from the lazy consumer,
+// with Lazy set to false, once their queries are applied.
+type PluginInterfaceEntityFlushedInTransaction interface {
+	PluginInterfaceEntityFlushedInTransaction(engine Engine, event *EntityFlushedEvent)
+}
+
+type PluginInterfaceSchemaTables interface {
+	PluginInterfaceSchemaTables(engine Engine) []PluginTable
+}
+
+type PluginTable struct {
+	Pool      string
+	Name      string
+	CreateSQL string
+}
+
+type EntityFlushedEvent struct {
+	Type        FlushType
+	TableSchema TableSchema
+	ID          uint64
+	Before      Bind
+	Changes     Bind
+	Lazy        bool
+}
+
+func (r *Registry) RegisterPlugin(plugin Plugin) {
+	for _, registered := range r.plugins {
+		if registered.GetCode() == plugin.GetCode() {
+			panic(fmt.Errorf("plugin '%s' already registered", plugin.GetCode()))
+		}
+	}
+	r.plugins = append(r.plugins, plugin)
+}
+
+func (r *validatedRegistry) GetPlugin(code string) Plugin {
+	for _, plugin := range r.plugins {
+		if plugin.GetCode() == code {
+			return plugin
+		}
+	}
+	return nil
+}
+
+func (f *flusher) addFlushedEvent(flushType FlushType, schema *tableSchema, id uint64, before, changes Bind, lazy bool) {
+	f.addIndexerEvents(flushType, schema, id)
+	f.addCrudEvents(flushType, schema, id, before, changes)
+	if !f.engine.registry.hasFlushedPlugin {
+		return
+	}
+	f.flushedEvents = append(f.flushedEvents, &EntityFlushedEvent{Type: flushType, TableSchema: schema, ID: id,
+		Before: before, Changes: changes, Lazy: lazy})
+	if lazy && f.engine.registry.hasFlushedInTransactionPlugin {
+		lazyMap := f.getLazyMap()
+		queries, _ := lazyMap["q"].([]interface{})
+		events, _ := lazyMap["p"].([]interface{})
+		lazyMap["p"] = append(events, []interface{}{len(queries), int(flushType), schema.t.String(), id, before, changes})
+	}
+}
+
+func (f *flusher) emitFlushedEventsInTransaction() {
+	if len(f.flushedEvents) == 0 || !f.engine.registry.hasFlushedInTransactionPlugin {
+		return
+	}
+	for _, plugin := range f.engine.registry.plugins {
+		flushedPlugin, is := plugin.(PluginInterfaceEntityFlushedInTransaction)
+		if is {
+			for _, event := range f.flushedEvents {
+				flushedPlugin.PluginInterfaceEntityFlushedInTransaction(f.engine, event)
+			}
+		}
+	}
+}
+
+func (f *flusher) emitFlushedEvents() {
+	if len(f.flushedEvents) == 0 {
+		return
+	}
+	events := f.flushedEvents
+	f.flushedEvents = nil
+	for _, plugin := range f.engine.registry.plugins {
+		flushedPlugin, is := plugin.(PluginInterfaceEntityFlushed)
+		if is {
+			for _, event := range events {
+				flushedPlugin.PluginInterfaceEntityFlushed(f.engine, event)
+			}
+		}
+	}
+}
+
+// emitLazyFlushedEvents runs in-transaction plugins for events sent by lazy flush, after their queries are applied
+func emitLazyFlushedEvents(engine *engineImplementation, validMap map[string]interface{}, ids []uint64) {
+	events, has := validMap["p"]
+	if !has {
+		return
+	}
+	inserted := make(map[int]uint64)
+	flushedEvents := make([]*EntityFlushedEvent, 0)
+	for _, row := range events.([]interface{}) {
+		values := row.([]interface{})
+		queryIndex, _ := strconv.Atoi(fmt.Sprintf("%v", values[0]))
+		flushType, _ := strconv.Atoi(fmt.Sprintf("%v", values[1]))
+		schema := engine.registry.GetTableSchema(values[2].(string))
+		if schema == nil {
+			continue
+		}
+		id, _ := strconv.ParseUint(fmt.Sprintf("%v", values[3]), 10, 64)
+		if id == 0 && queryIndex < len(ids) && ids[queryIndex] > 0 {
+			id = ids[queryIndex] + inserted[queryIndex]
+			inserted[queryIndex] += schema.GetMysql(engine).GetPoolConfig().getAutoincrement()
+		}
+		flushedEvents = append(flushedEvents, &EntityFlushedEvent{Type: FlushType(flushType), TableSchema: schema, ID: id,
+			Before: convertLazyBind(values[4]), Changes: convertLazyBind(values[5])})
+	}
+	for _, plugin := range engine.registry.plugins {
+		flushedPlugin, is := plugin.(PluginInterfaceEntityFlushedInTransaction)
+		if is {
+			for _, event := range flushedEvents {
+				flushedPlugin.PluginInterfaceEntityFlushedInTransaction(engine, event)
+			}
+		}
+	}
+}
+
+func convertLazyBind(value interface{}) Bind {
+	if asBind, is := value.(map[string]interface{}); is {
+		return asBind
+	}
+	asMap, is := value.(map[interface{}]interface{})
+	if !is {
+		return nil
+	}
+	bind := make(Bind, len(asMap))
+	for k, v := range asMap {
+		bind[k.(string)] = v
+	}
+	return bind
+}
@@ -0,0 +1,82 @@
+package beeorm
+
+import "sync"
+
+var enginePool = sync.Pool{New: func() interface{} {
+	return &engineImplementation{}
+}}
+
+var flusherPool = sync.Pool{New: func() interface{} {
+	return &flusher{}
+}}
+
+var bindBuilderPool = sync.Pool{New: func() interface{} {
+	return &bindBuilder{}
+}}
+
+func getPooledEngine() *engineImplementation {
+	return enginePool.Get().(*engineImplementation)
+}
+
+// Release returns engine to the pool. Locks and delayed cache deletes do not use engine once scheduled,
+// but Release must not be called while other goroutines (consumers, Clone workers, handlers) still use it.
+func (e *engineImplementation) Release() {
+	for _, db := range e.dbs {
+		if db.inTransaction {
+			db.Rollback()
+		}
+	}
+	e.Close()
+	dbs := e.dbs
+	for code := range dbs {
+		delete(dbs, code)
+	}
+	localCache := e.localCache
+	for code := range localCache {
+		delete(localCache, code)
+	}
+	redis := e.redis
+	for code := range redis {
+		delete(redis, code)
+	}
+	*e = engineImplementation{dbs: dbs, localCache: localCache, redis: redis}
+	enginePool.Put(e)
+}
+
+func (f *flusher) Release() {
+	tracked := f.trackedEntities
+	if f.engine != nil {
+		f.Clear()
+	}
+	if f.serializer != nil {
+		putSerializer(f.serializer)
+	}
+	for i := range tracked {
+		tracked[i] = nil
+	}
+	f.stringBuilder.Reset()
+	*f = flusher{}
+	if tracked != nil {
+		f.trackedEntities = tracked[0:0]
+	}
+	flusherPool.Put(f)
+}
+
+func getBindBuilder() *bindBuilder {
+	return bindBuilderPool.Get().(*bindBuilder)
+}
+
+func releaseBindBuilder(b *bindBuilder) {
+	bind, current, sqlBind := b.bind, b.current, b.sqlBind
+	for key := range bind {
+		delete(bind, key)
+	}
+	for key := range current {
+		delete(current, key)
+	}
+	for key := range sqlBind {
+		delete(sqlBind, key)
+	}
+	*b = bindBuilder{bind: bind, current: current, sqlBind: sqlBind}
+	bindBuilderPool.Put(b)
+}
@@ -0,0 +1,43 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnginePool(t *testing.T) {
+	registry := &validatedRegistry{}
+	engine := registry.CreateEngine().(*engineImplementation)
+	engine.SetLogMetaData("user", 12)
+	engine.EnableRequestCache()
+	engine.localCache = map[string]*LocalCache{"default": {}}
+	closed := false
+	engine.OnClose(func(_ Engine) {
+		closed = true
+	})
+	clone := engine.Clone().(*engineImplementation)
+	assert.Equal(t, engine.logMetaData, clone.logMetaData)
+	assert.True(t, clone.hasRequestCache)
+	assert.Nil(t, clone.localCache["default"])
+
+	engine.Release()
+	assert.True(t, closed)
+	assert.Nil(t, engine.registry)
+	assert.Nil(t, engine.logMetaData)
+	assert.False(t, engine.hasRequestCache)
+	assert.False(t, engine.closed)
+	assert.NotNil(t, engine.localCache)
+	assert.Len(t, engine.localCache, 0)
+	clone.Release()
+
+	f := registry.CreateEngine().NewFlusher().(*flusher)
+	f.getSerializer().SerializeUInteger(1)
+	f.trackedEntities = []Entity{&ORM{}}
+	f.trackedEntitiesCounter = 1
+	f.Release()
+	assert.Nil(t, f.engine)
+	assert.Nil(t, f.serializer)
+	assert.Equal(t, 0, f.trackedEntitiesCounter)
+	assert.Len(t, f.trackedEntities, 0)
+}
@@ -0,0 +1,234 @@
+package beeorm
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+)
+
+var queryTimeoutMarkerPrefix = "/* beeorm-timeout:" + strconv.FormatInt(time.Now().UnixNano(), 36) + "-"
+var queryTimeoutCounter uint64
+
+var redisBlockingCommands = map[string]bool{"blpop": true, "brpop": true, "brpoplpush": true, "blmove": true, "blmpop": true,
+	"bzpopmin": true, "bzpopmax": true, "bzmpop": true, "xread": true, "xreadgroup": true, "wait": true}
+
+type QueryTimeoutError struct {
+	Source  string
+	Pool    string
+	Query   string
+	Timeout time.Duration
+	Killed  bool
+}
+
+func (err *QueryTimeoutError) Error() string {
+	return fmt.Sprintf("%s query exceeded timeout of %s in pool '%s'", err.Source, err.Timeout.String(), err.Pool)
+}
+
+type mySQLTimeout struct {
+	timeout   time.Duration
+	killQuery bool
+	legacy    bool
+	marker    string
+}
+
+type redisTimeoutKey struct{}
+
+type redisTimeoutCancelKey struct{}
+
+type redisTimeoutHook struct {
+	pool    string
+	timeout time.Duration
+}
+
+func (r *Registry) SetMySQLQueryTimeout(timeout time.Duration, killQuery bool, code ...string) {
+	dbCode := "default"
+	if len(code) > 0 {
+		dbCode = code[0]
+	}
+	if r.mysqlTimeouts == nil {
+		r.mysqlTimeouts = make(map[string]*mySQLTimeout)
+	}
+	r.mysqlTimeouts[dbCode] = &mySQLTimeout{timeout: timeout, killQuery: killQuery}
+}
+
+func (r *Registry) SetRedisTimeout(timeout time.Duration, code ...string) {
+	dbCode := "default"
+	if len(code) > 0 {
+		dbCode = code[0]
+	}
+	if r.redisTimeouts == nil {
+		r.redisTimeouts = make(map[string]time.Duration)
+	}
+	r.redisTimeouts[dbCode] = timeout
+}
+
+func initPoolTimeouts(r *Registry, registry *validatedRegistry) error {
+	for code, timeout := range r.mysqlTimeouts {
+		if _, has := registry.mySQLServers[code]; !has {
+			return fmt.Errorf("mysql pool '%s' for query timeout not found", code)
+		}
+		if timeout.timeout <= 0 {
+			return fmt.Errorf("invalid query timeout %s for mysql pool '%s'", timeout.timeout, code)
+		}
+	}
+	for code, timeout := range r.redisTimeouts {
+		if _, has := registry.redisServers[code]; !has {
+			return fmt.Errorf("redis pool '%s' for timeout not found", code)
+		}
+		if timeout <= 0 {
+			return fmt.Errorf("invalid timeout %s for redis pool '%s'", timeout, code)
+		}
+	}
+	registry.mysqlTimeouts = r.mysqlTimeouts
+	for code, pool := range registry.redisServers {
+		config, is := pool.(*redisCacheConfig)
+		if !is {
+			continue
+		}
+		if config.timeoutHook == nil {
+			config.timeoutHook = &redisTimeoutHook{pool: code}
+			config.client.AddHook(config.timeoutHook)
+		}
+		config.timeoutHook.timeout = r.redisTimeouts[code]
+	}
+	return nil
+}
+
+func (db *DB) WithTimeout(timeout time.Duration) *DB {
+	clone := *db
+	clone.timeout = timeout
+	return &clone
+}
+
+func (db *DB) getQueryTimeout() *mySQLTimeout {
+	code := db.config.GetCode()
+	poolTimeout := db.engine.registry.mysqlTimeouts[code]
+	if db.timeout > 0 {
+		return &mySQLTimeout{timeout: db.timeout, killQuery: poolTimeout != nil && poolTimeout.killQuery}
+	}
+	if poolTimeout != nil {
+		return &mySQLTimeout{timeout: poolTimeout.timeout, killQuery: poolTimeout.killQuery}
+	}
+	if db.engine.queryTimeLimit > 0 {
+		return &mySQLTimeout{timeout: time.Duration(db.engine.queryTimeLimit) * time.Second, legacy: true}
+	}
+	return nil
+}
+
+func (t *mySQLTimeout) markQuery(query string) string {
+	if !t.killQuery {
+		return query
+	}
+	t.marker = queryTimeoutMarkerPrefix + strconv.FormatUint(atomic.AddUint64(&queryTimeoutCounter, 1), 10) + " */ "
+	return t.marker + query
+}
+
+func (db *DB) timeoutError(limit *mySQLTimeout, query string) error {
+	err := &QueryTimeoutError{Source: "mysql", Pool: db.config.GetCode(), Query: query, Timeout: limit.timeout}
+	if limit.killQuery {
+		err.Killed = db.killQuery(limit.marker)
+	}
+	return err
+}
+
+func (db *DB) killQuery(marker string) bool {
+	client := db.config.getClient()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rows, err := client.QueryContext(ctx, "SELECT `ID` FROM `information_schema`.`PROCESSLIST` WHERE `INFO` LIKE ?", marker+"%")
+	if err != nil {
+		return false
+	}
+	ids := make([]uint64, 0)
+	for rows.Next() {
+		var id uint64
+		if rows.Scan(&id) == nil {
+			ids = append(ids, id)
+		}
+	}
+	_ = rows.Close()
+	killed := false
+	for _, id := range ids {
+		_, err = client.ExecContext(ctx, "KILL QUERY "+strconv.FormatUint(id, 10))
+		if err == nil {
+			killed = true
+		}
+	}
+	return killed
+}
+
+func (r *RedisCache) WithTimeout(timeout time.Duration) *RedisCache {
+	return &RedisCache{engine: r.engine, client: r.client, config: r.config, timeout: timeout}
+}
+
+func (r *RedisCache) context() context.Context {
+	if r.timeout > 0 {
+		return context.WithValue(context.Background(), redisTimeoutKey{}, r.timeout)
+	}
+	return context.Background()
+}
+
+func (h *redisTimeoutHook) withTimeout(ctx context.Context, blocking bool) context.Context {
+	timeout, has := ctx.Value(redisTimeoutKey{}).(time.Duration)
+	if !has {
+		if blocking || h.timeout <= 0 {
+			return ctx
+		}
+		timeout = h.timeout
+	}
+	if _, hasDeadline := ctx.Deadline(); hasDeadline {
+		return ctx
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return context.WithValue(ctx, redisTimeoutCancelKey{}, cancel)
+}
+
+func (h *redisTimeoutHook) finish(ctx context.Context, err error, query string) error {
+	cancel, has := ctx.Value(redisTimeoutCancelKey{}).(context.CancelFunc)
+	if !has {
+		return err
+	}
+	defer cancel()
+	if err != nil && err != redis.Nil && ctx.Err() == context.DeadlineExceeded {
+		timeout, has := ctx.Value(redisTimeoutKey{}).(time.Duration)
+		if !has {
+			timeout = h.timeout
+		}
+		return &QueryTimeoutError{Source: "redis", Pool: h.pool, Query: query, Timeout: timeout}
+	}
+	return err
+}
+
+func (h *redisTimeoutHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return h.withTimeout(ctx, redisBlockingCommands[cmd.Name()]), nil
+}
+
+func (h *redisTimeoutHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	return h.finish(ctx, cmd.Err(), cmd.Name())
+}
+
+func (h *redisTimeoutHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	blocking := false
+	for _, cmd := range cmds {
+		if redisBlockingCommands[cmd.Name()] {
+			blocking = true
+			break
+		}
+	}
+	return h.withTimeout(ctx, blocking), nil
+}
+
+func (h *redisTimeoutHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmd.Err() != nil && cmd.Err() != redis.Nil {
+			err = cmd.Err()
+			break
+		}
+	}
+	return h.finish(ctx, err, "PIPELINE")
+}
@@ -0,0 +1,117 @@
+package beeorm
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+type poolTimeoutEntity struct {
+	ORM  `orm:"redisCache"`
+	ID   uint
+	Name string
+}
+
+func TestPoolTimeouts(t *testing.T) {
+	var entity *poolTimeoutEntity
+	registry := &Registry{}
+	registry.SetMySQLQueryTimeout(time.Second, true)
+	registry.SetRedisTimeout(time.Second)
+	registry.RegisterRedisScript("sleep", `local start = redis.call('TIME')
+while true do
+	local now = redis.call('TIME')
+	if (now[1] - start[1]) * 1000000 + now[2] - start[2] > 50000 then
+		return 1
+	end
+end`)
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	engine.Flush(&poolTimeoutEntity{Name: "a"})
+
+	db := engine.GetMysql()
+	var name string
+	assert.True(t, db.QueryRow(NewWhere("SELECT `Name` FROM `poolTimeoutEntity` WHERE `ID` = 1"), &name))
+	assert.Equal(t, "a", name)
+	rows, def := db.Query("SELECT `Name` FROM `poolTimeoutEntity`")
+	assert.True(t, rows.Next())
+	def()
+
+	assert.PanicsWithError(t, "mysql query exceeded timeout of 1s in pool 'default'", func() {
+		db.Exec("SELECT SLEEP(5)")
+	})
+	func() {
+		defer func() {
+			err := recover().(*QueryTimeoutError)
+			assert.Equal(t, "mysql", err.Source)
+			assert.Equal(t, "SELECT SLEEP(3)", err.Query)
+			assert.Equal(t, 500*time.Millisecond, err.Timeout)
+			assert.True(t, err.Killed)
+		}()
+		db.WithTimeout(500 * time.Millisecond).QueryRow(NewWhere("SELECT SLEEP(3)"))
+	}()
+	assert.PanicsWithError(t, "mysql query exceeded timeout of 1s in pool 'default'", func() {
+		db.Query("SELECT SLEEP(3)")
+	})
+
+	r := engine.GetRedis()
+	r.Set("a", "b", 10)
+	value, has := r.Get("a")
+	assert.True(t, has)
+	assert.Equal(t, "b", value)
+	assert.PanicsWithError(t, "redis query exceeded timeout of 1ms in pool 'default'", func() {
+		r.WithTimeout(time.Millisecond).RunScript("sleep", nil)
+	})
+}
+
+func TestPoolTimeoutsInvalidPool(t *testing.T) {
+	registry := &Registry{}
+	registry.SetMySQLQueryTimeout(time.Second, false, "missing")
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "mysql pool 'missing' for query timeout not found")
+
+	registry = &Registry{}
+	registry.SetRedisTimeout(time.Second, "missing")
+	_, err = registry.Validate()
+	assert.EqualError(t, err, "redis pool 'missing' for timeout not found")
+}
+
+func TestRedisTimeoutHook(t *testing.T) {
+	hook := &redisTimeoutHook{pool: "default", timeout: time.Second}
+	ctx, err := hook.BeforeProcess(context.Background(), redis.NewStringCmd(context.Background(), "get", "a"))
+	assert.NoError(t, err)
+	deadline, has := ctx.Deadline()
+	assert.True(t, has)
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+	assert.NoError(t, hook.AfterProcess(ctx, redis.NewStringCmd(ctx, "get", "a")))
+
+	ctx, _ = hook.BeforeProcess(context.Background(), redis.NewStringSliceCmd(context.Background(), "blpop", "a", 0))
+	_, has = ctx.Deadline()
+	assert.False(t, has)
+
+	ctx, _ = hook.BeforeProcess(context.WithValue(context.Background(), redisTimeoutKey{}, time.Millisecond), redis.NewStringCmd(context.Background(), "get", "a"))
+	<-ctx.Done()
+	cmd := redis.NewStringCmd(ctx, "get", "a")
+	cmd.SetErr(ctx.Err())
+	err = hook.AfterProcess(ctx, cmd)
+	assert.EqualError(t, err, "redis query exceeded timeout of 1ms in pool 'default'")
+	assert.Equal(t, "get", err.(*QueryTimeoutError).Query)
+
+	hook.timeout = 0
+	ctx, _ = hook.BeforeProcess(context.Background(), redis.NewStringCmd(context.Background(), "get", "a"))
+	_, has = ctx.Deadline()
+	assert.False(t, has)
+}
+
+func TestMySQLTimeoutMarker(t *testing.T) {
+	limit := &mySQLTimeout{timeout: time.Second}
+	assert.Equal(t, "SELECT 1", limit.markQuery("SELECT 1"))
+	limit.killQuery = true
+	marked := limit.markQuery("SELECT 1")
+	assert.True(t, strings.HasPrefix(marked, queryTimeoutMarkerPrefix))
+	assert.True(t, strings.HasSuffix(marked, " */ SELECT 1"))
+	assert.Equal(t, limit.marker+"SELECT 1", marked)
+	assert.NotEqual(t, marked, limit.markQuery("SELECT 1"))
+}
@@ -0,0 +1,77 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+func (r *Registry) RegisterProjection(projection Entity, source Entity) {
+	if r.projections == nil {
+		r.projections = make(map[reflect.Type]reflect.Type)
+	}
+	projectionType := reflect.TypeOf(projection)
+	if projectionType.Kind() == reflect.Ptr {
+		projectionType = projectionType.Elem()
+	}
+	sourceType := reflect.TypeOf(source)
+	if sourceType.Kind() == reflect.Ptr {
+		sourceType = sourceType.Elem()
+	}
+	r.projections[projectionType] = sourceType
+}
+
+func initProjections(r *Registry, registry *validatedRegistry) error {
+	for projectionType, sourceType := range r.projections {
+		source := registry.tableSchemas[sourceType]
+		if source == nil || source.projectionOf != nil {
+			return fmt.Errorf("projection '%s' source entity '%s' is not registered", projectionType.String(), sourceType.String())
+		}
+		if _, has := registry.tableSchemas[projectionType]; has {
+			return fmt.Errorf("projection '%s' is registered as entity", projectionType.String())
+		}
+		schema := &tableSchema{}
+		err := schema.init(r, projectionType)
+		if err != nil {
+			return err
+		}
+		if len(schema.cachedIndexesAll) > 0 {
+			return fmt.Errorf("projection '%s' can't define cached queries", projectionType.String())
+		}
+		if _, has := schema.tags["ORM"]["table"]; !has {
+			schema.tableName = source.tableName
+			if _, has = schema.tags["ORM"]["mysql"]; !has {
+				schema.mysqlPoolName = source.mysqlPoolName
+			}
+			for _, column := range schema.columnNames {
+				if _, has = source.columnMapping[column]; !has {
+					return fmt.Errorf("projection '%s' field %s not found in %s", projectionType.String(), column, sourceType.String())
+				}
+			}
+		}
+		schema.projectionOf = source
+		source.projections = append(source.projections, schema)
+		registry.tableSchemas[projectionType] = schema
+	}
+	return nil
+}
+
+func (f *flusher) invalidateProjections(schema *tableSchema, id uint64) {
+	for _, projection := range schema.projections {
+		localCache, hasLocalCache := projection.GetLocalCache(f.engine)
+		if !hasLocalCache && f.engine.hasRequestCache {
+			hasLocalCache = true
+			localCache = f.engine.GetLocalCache(requestCacheKey)
+		}
+		redisCache, hasRedis := projection.GetRedisCache(f.engine)
+		if !hasLocalCache && !hasRedis {
+			continue
+		}
+		cacheKey := projection.getCacheKey(f.engine, id)
+		if hasLocalCache {
+			f.addLocalCacheDeletes(localCache.config.GetCode(), cacheKey)
+		}
+		if hasRedis {
+			f.getRedisFlusher().Del(redisCache.config.GetCode(), cacheKey)
+		}
+	}
+}
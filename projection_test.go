@@ -0,0 +1,68 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type projectionSourceEntity struct {
+	ORM         `orm:"redisCache"`
+	ID          uint
+	Name        string
+	Description string
+	Age         int
+}
+
+type projectionEntity struct {
+	ORM  `orm:"localCache"`
+	ID   uint
+	Name string
+}
+
+type projectionInvalidEntity struct {
+	ORM
+	ID      uint
+	Invalid string
+}
+
+func TestProjection(t *testing.T) {
+	var source *projectionSourceEntity
+	var projection *projectionEntity
+	registry := &Registry{}
+	registry.RegisterProjection(projection, source)
+	engine := prepareTables(t, registry, 5, 6, "", source)
+	engine.Flush(&projectionSourceEntity{Name: "a", Description: "long a", Age: 10},
+		&projectionSourceEntity{Name: "b", Description: "long b", Age: 20})
+
+	loaded := &projectionEntity{}
+	assert.True(t, engine.LoadByID(1, loaded))
+	assert.Equal(t, "a", loaded.Name)
+	assert.False(t, engine.LoadByID(3, loaded))
+
+	var rows []*projectionEntity
+	engine.Search(NewWhere("`Age` > ? ORDER BY `ID`", 5), nil, &rows)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "b", rows[1].Name)
+
+	sourceEntity := &projectionSourceEntity{}
+	engine.LoadByID(1, sourceEntity)
+	sourceEntity.Name = "c"
+	engine.Flush(sourceEntity, &projectionSourceEntity{Name: "d"})
+	assert.True(t, engine.LoadByID(1, loaded))
+	assert.Equal(t, "c", loaded.Name)
+	assert.True(t, engine.LoadByID(3, loaded))
+	assert.Equal(t, "d", loaded.Name)
+
+	loaded.Name = "e"
+	assert.PanicsWithError(t, "projection 'beeorm.projectionEntity' is read-only", func() {
+		engine.Flush(loaded)
+	})
+
+	registry = &Registry{}
+	registry.RegisterProjection(&projectionInvalidEntity{}, source)
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(source)
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "projection 'beeorm.projectionInvalidEntity' field Invalid not found in beeorm.projectionSourceEntity")
+}
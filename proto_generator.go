@@ -0,0 +1,318 @@
+package beeorm
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type ProtoGeneratorResult struct {
+	Proto      string
+	Converters string
+	Lock       []byte
+}
+
+type protoLock map[string]map[string]int
+
+type protoGenerator struct {
+	registry     *validatedRegistry
+	lock         protoLock
+	messages     []string
+	toProto      *strings.Builder
+	fromProto    *strings.Builder
+	hasTimestamp bool
+	hasJSON      bool
+}
+
+func GenerateProto(registry ValidatedRegistry, protoPackage, goPackage, entityPackage string, lock []byte) (*ProtoGeneratorResult, error) {
+	g := &protoGenerator{registry: registry.(*validatedRegistry), lock: make(protoLock),
+		toProto: &strings.Builder{}, fromProto: &strings.Builder{}}
+	if len(lock) > 0 {
+		err := json.Unmarshal(lock, &g.lock)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proto lock file: %w", err)
+		}
+	}
+	names := make([]string, 0)
+	for name := range g.registry.entities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t := g.registry.entities[name]
+		schema := getTableSchema(g.registry, t)
+		messageName := protoMessageName(t.Name())
+		g.toProto.WriteString("\nfunc " + t.Name() + "ToProto(e *" + t.Name() + ") *pb." + messageName + " {\n")
+		g.toProto.WriteString("if e == nil {\nreturn nil\n}\nm := &pb." + messageName + "{}\n")
+		g.fromProto.WriteString("\nfunc ProtoTo" + messageName + "(m *pb." + messageName + ") *" + t.Name() + " {\n")
+		g.fromProto.WriteString("if m == nil {\nreturn nil\n}\ne := &" + t.Name() + "{}\n")
+		g.writeMessage(schema, t, "", messageName, "e", "m")
+		g.toProto.WriteString("return m\n}\n")
+		g.fromProto.WriteString("return e\n}\n")
+	}
+	proto := &strings.Builder{}
+	proto.WriteString("syntax = \"proto3\";\n\npackage " + protoPackage + ";\n\noption go_package = \"" + goPackage + "\";\n")
+	if g.hasTimestamp {
+		proto.WriteString("\nimport \"google/protobuf/timestamp.proto\";\n")
+	}
+	for _, message := range g.messages {
+		proto.WriteString(message)
+	}
+
+	converters := &strings.Builder{}
+	converters.WriteString("// Code generated by beeorm. DO NOT EDIT.\n\npackage " + entityPackage + "\n\nimport (\n")
+	if g.hasJSON {
+		converters.WriteString("\"encoding/json\"\n")
+	}
+	converters.WriteString("\npb \"" + goPackage + "\"\n")
+	if g.hasTimestamp {
+		converters.WriteString("\"google.golang.org/protobuf/types/known/timestamppb\"\n")
+	}
+	converters.WriteString(")\n")
+	converters.WriteString(g.toProto.String())
+	converters.WriteString(g.fromProto.String())
+	formatted, err := format.Source([]byte(converters.String()))
+	if err != nil {
+		return nil, err
+	}
+	newLock, _ := json.MarshalIndent(g.lock, "", "  ")
+	return &ProtoGeneratorResult{Proto: proto.String(), Converters: string(formatted), Lock: newLock}, nil
+}
+
+func (g *protoGenerator) writeMessage(schema *tableSchema, t reflect.Type, prefix, messageName, entityPath, protoPath string) {
+	numbers, has := g.lock[messageName]
+	if !has {
+		numbers = make(map[string]int)
+		g.lock[messageName] = numbers
+	}
+	nextNumber := 1
+	for _, number := range numbers {
+		if number >= nextNumber {
+			nextNumber = number + 1
+		}
+	}
+	position := len(g.messages)
+	g.messages = append(g.messages, "")
+	used := make(map[string]bool)
+	message := &strings.Builder{}
+	message.WriteString("\nmessage " + messageName + " {\n")
+	lines := &strings.Builder{}
+	for _, field := range g.messageFields(schema, t, prefix) {
+		protoType, nestedType := g.protoType(field)
+		number, has := numbers[field.Name]
+		if !has {
+			number = nextNumber
+			nextNumber++
+			numbers[field.Name] = number
+		}
+		used[field.Name] = true
+		if nestedType != nil {
+			subName := messageName + "_" + field.Name
+			protoType = subName
+			g.toProto.WriteString(protoPath + "." + field.Name + " = &pb." + subName + "{}\n")
+			g.fromProto.WriteString("if " + protoPath + "." + field.Name + " != nil {\n")
+			g.writeMessage(schema, nestedType, field.Name, subName, entityPath+"."+field.Name, protoPath+"."+field.Name)
+			g.fromProto.WriteString("}\n")
+		} else {
+			g.writeConverter(field, entityPath, protoPath)
+		}
+		lines.WriteString("  " + protoType + " " + field.Name + " = " + strconv.Itoa(number) + ";\n")
+	}
+	reserved := make([]int, 0)
+	for name, number := range numbers {
+		if !used[name] {
+			reserved = append(reserved, number)
+		}
+	}
+	sort.Ints(reserved)
+	if len(reserved) > 0 {
+		numbersText := make([]string, len(reserved))
+		for i, number := range reserved {
+			numbersText[i] = strconv.Itoa(number)
+		}
+		message.WriteString("  reserved " + strings.Join(numbersText, ", ") + ";\n")
+	}
+	message.WriteString(lines.String())
+	message.WriteString("}\n")
+	g.messages[position] = message.String()
+}
+
+func (g *protoGenerator) messageFields(schema *tableSchema, t reflect.Type, prefix string) []reflect.StructField {
+	fields := make([]reflect.StructField, 0)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Name() == "ORM" {
+			continue
+		}
+		_, ignored := schema.tags[prefix+field.Name]["ignore"]
+		if ignored || field.Type == reflect.TypeOf(&CachedQuery{}) {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, g.messageFields(schema, field.Type, "")...)
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func (g *protoGenerator) protoType(field reflect.StructField) (string, reflect.Type) {
+	t := field.Type
+	switch t.Kind() {
+	case reflect.Ptr:
+		if t.Elem().Kind() == reflect.Struct {
+			_, isEntity := g.registry.tableSchemas[t.Elem()]
+			if isEntity {
+				return "uint64", nil
+			}
+			if t.Elem().String() == "time.Time" {
+				g.hasTimestamp = true
+				return "google.protobuf.Timestamp", nil
+			}
+			g.hasJSON = true
+			return "bytes", nil
+		}
+		scalar := protoScalar(t.Elem())
+		if scalar != "" {
+			return "optional " + scalar, nil
+		}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Ptr {
+			_, isEntity := g.registry.tableSchemas[t.Elem().Elem()]
+			if isEntity {
+				return "repeated uint64", nil
+			}
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "bytes", nil
+		}
+		if t.Elem().Kind() == reflect.String {
+			return "repeated string", nil
+		}
+	case reflect.Struct:
+		if t.String() == "time.Time" {
+			g.hasTimestamp = true
+			return "google.protobuf.Timestamp", nil
+		}
+		return "", t
+	default:
+		scalar := protoScalar(t)
+		if scalar != "" {
+			return scalar, nil
+		}
+	}
+	g.hasJSON = true
+	return "bytes", nil
+}
+
+func (g *protoGenerator) writeConverter(field reflect.StructField, entityPath, protoPath string) {
+	name := field.Name
+	e := entityPath + "." + name
+	m := protoPath + "." + name
+	t := field.Type
+	switch t.Kind() {
+	case reflect.Ptr:
+		if t.Elem().Kind() == reflect.Struct {
+			schema, isEntity := g.registry.tableSchemas[t.Elem()]
+			if isEntity {
+				idField, _ := schema.t.FieldByName("ID")
+				idType := idField.Type.String()
+				g.toProto.WriteString("if " + e + " != nil {\n" + m + " = " + e + ".GetID()\n}\n")
+				g.fromProto.WriteString("if " + m + " != 0 {\n" + e + " = &" + t.Elem().Name() + "{ID: " + idType + "(" + m + ")}\n}\n")
+				return
+			}
+			if t.Elem().String() == "time.Time" {
+				g.toProto.WriteString("if " + e + " != nil {\n" + m + " = timestamppb.New(*" + e + ")\n}\n")
+				g.fromProto.WriteString("if " + m + " != nil {\nv := " + m + ".AsTime()\n" + e + " = &v\n}\n")
+				return
+			}
+			g.writeJSONConverter(e, m)
+			return
+		}
+		scalar := protoScalar(t.Elem())
+		if scalar == "" {
+			g.writeJSONConverter(e, m)
+			return
+		}
+		g.toProto.WriteString("if " + e + " != nil {\nv := " + protoGoType(scalar) + "(*" + e + ")\n" + m + " = &v\n}\n")
+		g.fromProto.WriteString("if " + m + " != nil {\nv := " + t.Elem().String() + "(*" + m + ")\n" + e + " = &v\n}\n")
+		return
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Ptr {
+			schema, isEntity := g.registry.tableSchemas[t.Elem().Elem()]
+			if isEntity {
+				idField, _ := schema.t.FieldByName("ID")
+				idType := idField.Type.String()
+				g.toProto.WriteString("for _, v := range " + e + " {\n" + m + " = append(" + m + ", v.GetID())\n}\n")
+				g.fromProto.WriteString("for _, v := range " + m + " {\n" + e + " = append(" + e + ", &" +
+					t.Elem().Elem().Name() + "{ID: " + idType + "(v)})\n}\n")
+				return
+			}
+		}
+		if t.Elem().Kind() == reflect.Uint8 || t.Elem().Kind() == reflect.String {
+			g.toProto.WriteString(m + " = " + e + "\n")
+			g.fromProto.WriteString(e + " = " + m + "\n")
+			return
+		}
+	case reflect.Struct:
+		if t.String() == "time.Time" {
+			g.toProto.WriteString(m + " = timestamppb.New(" + e + ")\n")
+			g.fromProto.WriteString("if " + m + " != nil {\n" + e + " = " + m + ".AsTime()\n}\n")
+			return
+		}
+	default:
+		scalar := protoScalar(t)
+		if scalar != "" {
+			g.toProto.WriteString(m + " = " + protoGoType(scalar) + "(" + e + ")\n")
+			g.fromProto.WriteString(e + " = " + t.String() + "(" + m + ")\n")
+			return
+		}
+	}
+	g.writeJSONConverter(e, m)
+}
+
+func (g *protoGenerator) writeJSONConverter(e, m string) {
+	g.toProto.WriteString(m + ", _ = json.Marshal(" + e + ")\n")
+	g.fromProto.WriteString("if len(" + m + ") > 0 {\n_ = json.Unmarshal(" + m + ", &" + e + ")\n}\n")
+}
+
+func protoMessageName(name string) string {
+	return strings.ToUpper(name[0:1]) + name[1:]
+}
+
+func protoScalar(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint64:
+		return "uint64"
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "uint32"
+	case reflect.Int, reflect.Int64:
+		return "int64"
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return "int32"
+	case reflect.Float32:
+		return "float"
+	case reflect.Float64:
+		return "double"
+	case reflect.Bool:
+		return "bool"
+	case reflect.String:
+		return "string"
+	}
+	return ""
+}
+
+func protoGoType(scalar string) string {
+	switch scalar {
+	case "float":
+		return "float32"
+	case "double":
+		return "float64"
+	}
+	return scalar
+}
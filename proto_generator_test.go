@@ -0,0 +1,61 @@
+package beeorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type protoEntity struct {
+	ORM
+	ID         uint
+	Name       string
+	Age        *uint8
+	Born       time.Time
+	Address    protoAddress
+	Meta       map[string]string
+	Reference  *protoEntityReference
+	References []*protoEntityReference
+}
+
+type protoAddress struct {
+	City string
+}
+
+type protoEntityReference struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestGenerateProto(t *testing.T) {
+	var entity *protoEntity
+	var reference *protoEntityReference
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", entity, reference)
+
+	result, err := GenerateProto(engine.GetRegistry(), "entities", "example.com/app/pb", "entities", nil)
+	assert.NoError(t, err)
+	assert.Contains(t, result.Proto, "option go_package = \"example.com/app/pb\";\n")
+	assert.Contains(t, result.Proto, "import \"google/protobuf/timestamp.proto\";\n")
+	assert.Contains(t, result.Proto, "message ProtoEntity {\n  uint64 ID = 1;\n  string Name = 2;\n  optional uint32 Age = 3;\n"+
+		"  google.protobuf.Timestamp Born = 4;\n  ProtoEntity_Address Address = 5;\n  bytes Meta = 6;\n  uint64 Reference = 7;\n"+
+		"  repeated uint64 References = 8;\n}\n")
+	assert.Contains(t, result.Proto, "message ProtoEntity_Address {\n  string City = 1;\n}\n")
+	assert.Contains(t, result.Converters, "func protoEntityToProto(e *protoEntity) *pb.ProtoEntity {\n")
+	assert.Contains(t, result.Converters, "func ProtoToProtoEntity(m *pb.ProtoEntity) *protoEntity {\n")
+	assert.Contains(t, result.Converters, "\tm.Address.City = string(e.Address.City)\n")
+	assert.Contains(t, result.Converters, "\t\te.Reference = &protoEntityReference{ID: uint(m.Reference)}\n")
+	assert.Contains(t, result.Converters, "\tm.Meta, _ = json.Marshal(e.Meta)\n")
+	assert.Contains(t, string(result.Lock), "\"ProtoEntityReference\": {\n    \"ID\": 1,\n    \"Name\": 2\n  }")
+
+	result, err = GenerateProto(engine.GetRegistry(), "entities", "example.com/app/pb", "entities",
+		[]byte(`{"ProtoEntityReference": {"Name": 1, "Old": 2, "ID": 3}}`))
+	assert.NoError(t, err)
+	assert.Contains(t, result.Proto, "message ProtoEntityReference {\n  reserved 2;\n  uint64 ID = 3;\n  string Name = 1;\n}\n")
+	assert.Contains(t, string(result.Lock), "\"Old\": 2")
+
+	_, err = GenerateProto(engine.GetRegistry(), "entities", "example.com/app/pb", "entities", []byte("invalid"))
+	assert.EqualError(t, err, "invalid proto lock file: invalid character 'i' looking for beginning of value")
+}
@@ -0,0 +1,108 @@
+package beeorm
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	googleuuid "github.com/google/uuid"
+)
+
+const rateLimiterKeyPrefix = "_rate_limit:"
+
+var rateLimiterSlidingWindowScript = newRedisScript("beeorm_rate_limit_window", `
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], window)
+	return {1, limit - count - 1, 0}
+end
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+local retry = window
+if oldest[2] then
+	retry = tonumber(oldest[2]) + window - now
+end
+return {0, 0, retry}
+`)
+
+var rateLimiterTokenBucketScript = newRedisScript("beeorm_rate_limit_bucket", `
+local now = tonumber(ARGV[1])
+local capacity = tonumber(ARGV[2])
+local rate = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil then
+	tokens = capacity
+	ts = now
+end
+if now > ts then
+	tokens = math.min(capacity, tokens + (now - ts) * rate / 1000)
+	ts = now
+end
+local allowed = 0
+local retry = 0
+if tokens >= cost then
+	tokens = tokens - cost
+	allowed = 1
+else
+	retry = math.ceil((cost - tokens) * 1000 / rate)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', ts)
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity * 1000 / rate) + 1000)
+return {allowed, math.floor(tokens), retry}
+`)
+
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+type RateLimiter struct {
+	engine *engineImplementation
+	r      *RedisCache
+}
+
+func (e *engineImplementation) GetRateLimiter(code ...string) *RateLimiter {
+	return &RateLimiter{engine: e, r: e.GetRedis(code...)}
+}
+
+func (l *RateLimiter) SlidingWindow(key string, limit int, window time.Duration) RateLimitResult {
+	if limit <= 0 {
+		return RateLimitResult{RetryAfter: window}
+	}
+	now := l.engine.now().UnixNano() / int64(time.Millisecond)
+	member := strconv.FormatInt(now, 10) + ":" + googleuuid.New().String()
+	res := l.r.runScript(rateLimiterSlidingWindowScript, []string{l.r.addNamespacePrefix(rateLimiterKeyPrefix + key)},
+		now, window.Milliseconds(), limit, member)
+	return l.toResult(res)
+}
+
+func (l *RateLimiter) TokenBucket(key string, capacity int, refillPerSecond float64, cost int) RateLimitResult {
+	if cost > capacity || refillPerSecond <= 0 {
+		return RateLimitResult{RetryAfter: time.Duration(math.MaxInt64)}
+	}
+	now := l.engine.now().UnixNano() / int64(time.Millisecond)
+	res := l.r.runScript(rateLimiterTokenBucketScript, []string{l.r.addNamespacePrefix(rateLimiterKeyPrefix + key)},
+		now, capacity, strconv.FormatFloat(refillPerSecond, 'f', -1, 64), cost)
+	return l.toResult(res)
+}
+
+func (l *RateLimiter) Reset(key string) {
+	l.r.Del(rateLimiterKeyPrefix + key)
+}
+
+func (l *RateLimiter) toResult(res interface{}) RateLimitResult {
+	values := res.([]interface{})
+	return RateLimitResult{
+		Allowed:    values[0].(int64) == 1,
+		Remaining:  int(values[1].(int64)),
+		RetryAfter: time.Duration(values[2].(int64)) * time.Millisecond,
+	}
+}
@@ -0,0 +1,67 @@
+package beeorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterSlidingWindow(t *testing.T) {
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "")
+	clock := &cronTestClock{now: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)}
+	engine.SetClock(clock)
+	limiter := engine.GetRateLimiter()
+
+	for i := 0; i < 3; i++ {
+		result := limiter.SlidingWindow("api", 3, time.Minute)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2-i, result.Remaining)
+		clock.now = clock.now.Add(time.Second * 10)
+	}
+	result := limiter.SlidingWindow("api", 3, time.Minute)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, time.Second*30, result.RetryAfter)
+
+	clock.now = clock.now.Add(time.Second * 30)
+	result = limiter.SlidingWindow("api", 3, time.Minute)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	limiter.Reset("api")
+	result = limiter.SlidingWindow("api", 3, time.Minute)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+	assert.False(t, limiter.SlidingWindow("other", 0, time.Minute).Allowed)
+}
+
+func TestRateLimiterTokenBucket(t *testing.T) {
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "")
+	clock := &cronTestClock{now: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)}
+	engine.SetClock(clock)
+	limiter := engine.GetRateLimiter()
+
+	result := limiter.TokenBucket("upload", 10, 2, 4)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 6, result.Remaining)
+	result = limiter.TokenBucket("upload", 10, 2, 6)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	result = limiter.TokenBucket("upload", 10, 2, 1)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Millisecond*500, result.RetryAfter)
+
+	clock.now = clock.now.Add(time.Second * 2)
+	result = limiter.TokenBucket("upload", 10, 2, 1)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 3, result.Remaining)
+
+	clock.now = clock.now.Add(time.Hour)
+	result = limiter.TokenBucket("upload", 10, 2, 1)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 9, result.Remaining)
+	assert.False(t, limiter.TokenBucket("upload", 10, 2, 11).Allowed)
+}
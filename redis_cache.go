@@ -7,28 +7,27 @@ import (
 	"strings"
 	"time"
 
-	"github.com/shamaton/msgpack"
-
 	"github.com/go-redis/redis/v9"
 )
 
 type RedisCache struct {
 	engine *engineImplementation
 	client *redis.Client
-	locker *Locker
-	config RedisPoolConfig
+	locker  *Locker
+	config  RedisPoolConfig
+	timeout time.Duration
 }
 
 func (r *RedisCache) GetSet(key string, ttlSeconds int, provider func() interface{}) interface{} {
 	val, has := r.Get(key)
 	if !has {
 		userVal := provider()
-		encoded, _ := msgpack.Marshal(userVal)
+		encoded := r.engine.registry.encodePayload(userVal, "")
 		r.Set(key, string(encoded), ttlSeconds)
 		return userVal
 	}
 	var data interface{}
-	_ = msgpack.Unmarshal([]byte(val), &data)
+	_ = r.engine.registry.decodePayload([]byte(val), &data)
 	return data
 }
 
@@ -38,7 +37,7 @@ func (r *RedisCache) PipeLine() *RedisPipeLine {
 
 func (r *RedisCache) Info(section ...string) string {
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.Info(context.Background(), section...).Result()
+	val, err := r.client.Info(r.context(), section...).Result()
 	checkError(err)
 	if r.engine.hasRedisLogger {
 		message := "INFO"
@@ -57,7 +56,7 @@ func (r *RedisCache) GetPoolConfig() RedisPoolConfig {
 func (r *RedisCache) Get(key string) (value string, has bool) {
 	start := getNow(r.engine.hasRedisLogger)
 	key = r.addNamespacePrefix(key)
-	val, err := r.client.Get(context.Background(), key).Result()
+	val, err := r.client.Get(r.context(), key).Result()
 	if err != nil {
 		if err == redis.Nil {
 			err = nil
@@ -76,7 +75,7 @@ func (r *RedisCache) Get(key string) (value string, has bool) {
 
 func (r *RedisCache) Eval(script string, keys []string, args ...interface{}) interface{} {
 	start := getNow(r.engine.hasRedisLogger)
-	res, err := r.client.Eval(context.Background(), script, keys, args...).Result()
+	res, err := r.client.Eval(r.context(), script, keys, args...).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("EVAL "+script+" %v %v", keys, args)
 		r.fillLogFields("EVAL", message, start, false, err)
@@ -87,7 +86,7 @@ func (r *RedisCache) Eval(script string, keys []string, args ...interface{}) int
 
 func (r *RedisCache) EvalSha(sha1 string, keys []string, args ...interface{}) (res interface{}, exists bool) {
 	start := getNow(r.engine.hasRedisLogger)
-	res, err := r.client.EvalSha(context.Background(), sha1, keys, args...).Result()
+	res, err := r.client.EvalSha(r.context(), sha1, keys, args...).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("EVALSHA "+sha1+" %v %v", keys, args)
 		r.fillLogFields("EVALSHA", message, start, false, err)
@@ -101,7 +100,7 @@ func (r *RedisCache) EvalSha(sha1 string, keys []string, args ...interface{}) (r
 
 func (r *RedisCache) ScriptExists(sha1 string) bool {
 	start := getNow(r.engine.hasRedisLogger)
-	res, err := r.client.ScriptExists(context.Background(), sha1).Result()
+	res, err := r.client.ScriptExists(r.context(), sha1).Result()
 	if r.engine.hasRedisLogger {
 		r.fillLogFields("SCRIPTEXISTS", "SCRIPTEXISTS "+sha1, start, false, err)
 	}
@@ -111,7 +110,7 @@ func (r *RedisCache) ScriptExists(sha1 string) bool {
 
 func (r *RedisCache) ScriptLoad(script string) string {
 	start := getNow(r.engine.hasRedisLogger)
-	res, err := r.client.ScriptLoad(context.Background(), script).Result()
+	res, err := r.client.ScriptLoad(r.context(), script).Result()
 	if r.engine.hasRedisLogger {
 		r.fillLogFields("SCRIPTLOAD", "SCRIPTLOAD "+script, start, false, err)
 	}
@@ -122,7 +121,7 @@ func (r *RedisCache) ScriptLoad(script string) string {
 func (r *RedisCache) Set(key string, value interface{}, ttlSeconds int) {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	_, err := r.client.Set(context.Background(), key, value, time.Duration(ttlSeconds)*time.Second).Result()
+	_, err := r.client.Set(r.context(), key, value, time.Duration(ttlSeconds)*time.Second).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("SET %s %v %d", key, value, ttlSeconds)
 		r.fillLogFields("SET", message, start, false, err)
@@ -133,7 +132,7 @@ func (r *RedisCache) Set(key string, value interface{}, ttlSeconds int) {
 func (r *RedisCache) SetNX(key string, value interface{}, ttlSeconds int) bool {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	isSet, err := r.client.SetNX(context.Background(), key, value, time.Duration(ttlSeconds)*time.Second).Result()
+	isSet, err := r.client.SetNX(r.context(), key, value, time.Duration(ttlSeconds)*time.Second).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("SET NX %s %v %d", key, value, ttlSeconds)
 		r.fillLogFields("SETNX", message, start, false, err)
@@ -145,7 +144,7 @@ func (r *RedisCache) SetNX(key string, value interface{}, ttlSeconds int) bool {
 func (r *RedisCache) LPush(key string, values ...interface{}) int64 {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.LPush(context.Background(), key, values...).Result()
+	val, err := r.client.LPush(r.context(), key, values...).Result()
 	if r.engine.hasRedisLogger {
 		message := "LPUSH " + key
 		for _, v := range values {
@@ -160,7 +159,7 @@ func (r *RedisCache) LPush(key string, values ...interface{}) int64 {
 func (r *RedisCache) RPush(key string, values ...interface{}) int64 {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.RPush(context.Background(), key, values...).Result()
+	val, err := r.client.RPush(r.context(), key, values...).Result()
 	if r.engine.hasRedisLogger {
 		message := "RPUSH " + key
 		for _, v := range values {
@@ -175,7 +174,7 @@ func (r *RedisCache) RPush(key string, values ...interface{}) int64 {
 func (r *RedisCache) LLen(key string) int64 {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.LLen(context.Background(), key).Result()
+	val, err := r.client.LLen(r.context(), key).Result()
 	if r.engine.hasRedisLogger {
 		r.fillLogFields("LLEN", "LLEN", start, false, err)
 	}
@@ -190,7 +189,7 @@ func (r *RedisCache) Exists(keys ...string) int64 {
 		}
 	}
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.Exists(context.Background(), keys...).Result()
+	val, err := r.client.Exists(r.context(), keys...).Result()
 	if r.engine.hasRedisLogger {
 		r.fillLogFields("EXISTS", "EXISTS "+strings.Join(keys, " "), start, false, err)
 	}
@@ -201,7 +200,7 @@ func (r *RedisCache) Exists(keys ...string) int64 {
 func (r *RedisCache) Type(key string) string {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.Type(context.Background(), key).Result()
+	val, err := r.client.Type(r.context(), key).Result()
 	if r.engine.hasRedisLogger {
 		r.fillLogFields("TYPE", "TYPE "+key, start, false, err)
 	}
@@ -212,7 +211,7 @@ func (r *RedisCache) Type(key string) string {
 func (r *RedisCache) LRange(key string, start, stop int64) []string {
 	key = r.addNamespacePrefix(key)
 	s := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.LRange(context.Background(), key, start, stop).Result()
+	val, err := r.client.LRange(r.context(), key, start, stop).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("LRANGE %d %d", start, stop)
 		r.fillLogFields("LRANGE", message, s, false, err)
@@ -224,7 +223,7 @@ func (r *RedisCache) LRange(key string, start, stop int64) []string {
 func (r *RedisCache) LSet(key string, index int64, value interface{}) {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	_, err := r.client.LSet(context.Background(), key, index, value).Result()
+	_, err := r.client.LSet(r.context(), key, index, value).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("LSET %d %v", index, value)
 		r.fillLogFields("LSET", message, start, false, err)
@@ -235,7 +234,7 @@ func (r *RedisCache) LSet(key string, index int64, value interface{}) {
 func (r *RedisCache) RPop(key string) (value string, found bool) {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.RPop(context.Background(), key).Result()
+	val, err := r.client.RPop(r.context(), key).Result()
 	if err != nil {
 		if err == redis.Nil {
 			err = nil
@@ -255,7 +254,7 @@ func (r *RedisCache) RPop(key string) (value string, found bool) {
 func (r *RedisCache) LRem(key string, count int64, value interface{}) {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	_, err := r.client.LRem(context.Background(), key, count, value).Result()
+	_, err := r.client.LRem(r.context(), key, count, value).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("LREM %d %v", count, value)
 		r.fillLogFields("LREM", message, start, false, err)
@@ -266,7 +265,7 @@ func (r *RedisCache) LRem(key string, count int64, value interface{}) {
 func (r *RedisCache) Ltrim(key string, start, stop int64) {
 	key = r.addNamespacePrefix(key)
 	s := getNow(r.engine.hasRedisLogger)
-	_, err := r.client.LTrim(context.Background(), key, start, stop).Result()
+	_, err := r.client.LTrim(r.context(), key, start, stop).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("LTRIM %d %d", start, stop)
 		r.fillLogFields("LTRIM", message, s, false, err)
@@ -277,7 +276,7 @@ func (r *RedisCache) Ltrim(key string, start, stop int64) {
 func (r *RedisCache) HSet(key string, values ...interface{}) {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	_, err := r.client.HSet(context.Background(), key, values...).Result()
+	_, err := r.client.HSet(r.context(), key, values...).Result()
 	if r.engine.hasRedisLogger {
 		message := "HSET " + key + " "
 		for _, v := range values {
@@ -291,7 +290,7 @@ func (r *RedisCache) HSet(key string, values ...interface{}) {
 func (r *RedisCache) HSetNx(key, field string, value interface{}) bool {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	res, err := r.client.HSetNX(context.Background(), key, field, value).Result()
+	res, err := r.client.HSetNX(r.context(), key, field, value).Result()
 	if r.engine.hasRedisLogger {
 		message := "HSETNX " + key + " " + field + " " + fmt.Sprintf(" %v", value)
 		r.fillLogFields("HSETNX", message, start, false, err)
@@ -303,7 +302,7 @@ func (r *RedisCache) HSetNx(key, field string, value interface{}) bool {
 func (r *RedisCache) HDel(key string, fields ...string) {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	_, err := r.client.HDel(context.Background(), key, fields...).Result()
+	_, err := r.client.HDel(r.context(), key, fields...).Result()
 	if r.engine.hasRedisLogger {
 		message := "HDEL " + key + " " + strings.Join(fields, " ")
 		r.fillLogFields("HDEL", message, start, false, err)
@@ -314,7 +313,7 @@ func (r *RedisCache) HDel(key string, fields ...string) {
 func (r *RedisCache) HMGet(key string, fields ...string) map[string]interface{} {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.HMGet(context.Background(), key, fields...).Result()
+	val, err := r.client.HMGet(r.context(), key, fields...).Result()
 	results := make(map[string]interface{}, len(fields))
 	misses := 0
 	for index, v := range val {
@@ -333,7 +332,7 @@ func (r *RedisCache) HMGet(key string, fields ...string) map[string]interface{}
 func (r *RedisCache) HGetAll(key string) map[string]string {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.HGetAll(context.Background(), key).Result()
+	val, err := r.client.HGetAll(r.context(), key).Result()
 	if r.engine.hasRedisLogger {
 		r.fillLogFields("HGETALL", "HGETALL "+key, start, false, err)
 	}
@@ -345,7 +344,7 @@ func (r *RedisCache) HGet(key, field string) (value string, has bool) {
 	key = r.addNamespacePrefix(key)
 	misses := false
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.HGet(context.Background(), key, field).Result()
+	val, err := r.client.HGet(r.context(), key, field).Result()
 	if err == redis.Nil {
 		err = nil
 		misses = true
@@ -360,7 +359,7 @@ func (r *RedisCache) HGet(key, field string) (value string, has bool) {
 func (r *RedisCache) HLen(key string) int64 {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.HLen(context.Background(), key).Result()
+	val, err := r.client.HLen(r.context(), key).Result()
 	if r.engine.hasRedisLogger {
 		r.fillLogFields("HLEN", "HLEN "+key, start, false, err)
 	}
@@ -371,7 +370,7 @@ func (r *RedisCache) HLen(key string) int64 {
 func (r *RedisCache) HIncrBy(key, field string, incr int64) int64 {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.HIncrBy(context.Background(), key, field, incr).Result()
+	val, err := r.client.HIncrBy(r.context(), key, field, incr).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("HINCRBY %s %s %d", key, field, incr)
 		r.fillLogFields("HINCRBY", message, start, false, err)
@@ -383,7 +382,7 @@ func (r *RedisCache) HIncrBy(key, field string, incr int64) int64 {
 func (r *RedisCache) IncrBy(key string, incr int64) int64 {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.IncrBy(context.Background(), key, incr).Result()
+	val, err := r.client.IncrBy(r.context(), key, incr).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("INCRBY %s %d", key, incr)
 		r.fillLogFields("INCRBY", message, start, false, err)
@@ -395,7 +394,7 @@ func (r *RedisCache) IncrBy(key string, incr int64) int64 {
 func (r *RedisCache) Incr(key string) int64 {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.Incr(context.Background(), key).Result()
+	val, err := r.client.Incr(r.context(), key).Result()
 	if r.engine.hasRedisLogger {
 		r.fillLogFields("INCR", "INCR "+key, start, false, err)
 	}
@@ -407,7 +406,7 @@ func (r *RedisCache) IncrWithExpire(key string, expire time.Duration) int64 {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
 	p := r.client.Pipeline()
-	ctx := context.Background()
+	ctx := r.context()
 	res := p.Incr(ctx, key)
 	p.Expire(ctx, key, expire)
 	_, err := p.Exec(ctx)
@@ -423,7 +422,7 @@ func (r *RedisCache) IncrWithExpire(key string, expire time.Duration) int64 {
 func (r *RedisCache) Expire(key string, expiration time.Duration) bool {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.Expire(context.Background(), key, expiration).Result()
+	val, err := r.client.Expire(r.context(), key, expiration).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("EXPIRE %s %s", key, expiration.String())
 		r.fillLogFields("EXPIRE", message, start, false, err)
@@ -435,7 +434,7 @@ func (r *RedisCache) Expire(key string, expiration time.Duration) bool {
 func (r *RedisCache) ZAdd(key string, members ...redis.Z) int64 {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.ZAdd(context.Background(), key, members...).Result()
+	val, err := r.client.ZAdd(r.context(), key, members...).Result()
 	if r.engine.hasRedisLogger {
 		message := "ZADD " + key
 		for _, v := range members {
@@ -447,10 +446,22 @@ func (r *RedisCache) ZAdd(key string, members ...redis.Z) int64 {
 	return val
 }
 
+func (r *RedisCache) ZIncrBy(key string, increment float64, member string) float64 {
+	key = r.addNamespacePrefix(key)
+	start := getNow(r.engine.hasRedisLogger)
+	val, err := r.client.ZIncrBy(r.context(), key, increment, member).Result()
+	if r.engine.hasRedisLogger {
+		message := fmt.Sprintf("ZINCRBY %s %f %s", key, increment, member)
+		r.fillLogFields("ZINCRBY", message, start, false, err)
+	}
+	checkError(err)
+	return val
+}
+
 func (r *RedisCache) ZRevRange(key string, start, stop int64) []string {
 	key = r.addNamespacePrefix(key)
 	startTime := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.ZRevRange(context.Background(), key, start, stop).Result()
+	val, err := r.client.ZRevRange(r.context(), key, start, stop).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("ZREVRANGE %s %d %d", key, start, stop)
 		r.fillLogFields("ZREVRANGE", message, startTime, false, err)
@@ -462,7 +473,7 @@ func (r *RedisCache) ZRevRange(key string, start, stop int64) []string {
 func (r *RedisCache) ZRevRangeWithScores(key string, start, stop int64) []redis.Z {
 	key = r.addNamespacePrefix(key)
 	startTime := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.ZRevRangeWithScores(context.Background(), key, start, stop).Result()
+	val, err := r.client.ZRevRangeWithScores(r.context(), key, start, stop).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("ZREVRANGESCORE %s %d %d", key, start, stop)
 		r.fillLogFields("ZREVRANGESCORE", message, startTime, false, err)
@@ -474,7 +485,7 @@ func (r *RedisCache) ZRevRangeWithScores(key string, start, stop int64) []redis.
 func (r *RedisCache) ZRangeWithScores(key string, start, stop int64) []redis.Z {
 	key = r.addNamespacePrefix(key)
 	startTime := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.ZRangeWithScores(context.Background(), key, start, stop).Result()
+	val, err := r.client.ZRangeWithScores(r.context(), key, start, stop).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("ZRANGESCORE %s %d %d", key, start, stop)
 		r.fillLogFields("ZRANGESCORE", message, startTime, false, err)
@@ -486,7 +497,7 @@ func (r *RedisCache) ZRangeWithScores(key string, start, stop int64) []redis.Z {
 func (r *RedisCache) ZRemRangeByRank(key string, start, stop int64) int64 {
 	key = r.addNamespacePrefix(key)
 	startTime := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.ZRemRangeByRank(context.Background(), key, start, stop).Result()
+	val, err := r.client.ZRemRangeByRank(r.context(), key, start, stop).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("ZREMRANGEBYRANK %s %d %d", key, start, stop)
 		r.fillLogFields("ZREMRANGEBYRANK", message, startTime, false, err)
@@ -499,7 +510,7 @@ func (r *RedisCache) ZRemRangeByRank(key string, start, stop int64) int64 {
 func (r *RedisCache) ZRangeArgsWithScores(args redis.ZRangeArgs) []redis.Z {
 	key := r.addNamespacePrefix(args.Key)
 	startTime := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.ZRangeArgsWithScores(context.Background(), args).Result()
+	val, err := r.client.ZRangeArgsWithScores(r.context(), args).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("ZRANGE %s %+v WITHSCORE", key, args)
 		r.fillLogFields("ZRANGE", message, startTime, false, err)
@@ -511,7 +522,7 @@ func (r *RedisCache) ZRangeArgsWithScores(args redis.ZRangeArgs) []redis.Z {
 func (r *RedisCache) ZRangeArgs(args redis.ZRangeArgs) []string {
 	key := r.addNamespacePrefix(args.Key)
 	startTime := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.ZRangeArgs(context.Background(), args).Result()
+	val, err := r.client.ZRangeArgs(r.context(), args).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("ZRANGE %s %+v", key, args)
 		r.fillLogFields("ZRANGE", message, startTime, false, err)
@@ -523,7 +534,7 @@ func (r *RedisCache) ZRangeArgs(args redis.ZRangeArgs) []string {
 func (r *RedisCache) ZCard(key string) int64 {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.ZCard(context.Background(), key).Result()
+	val, err := r.client.ZCard(r.context(), key).Result()
 	if r.engine.hasRedisLogger {
 		r.fillLogFields("ZCARD", "ZCARD "+key, start, false, err)
 	}
@@ -534,7 +545,7 @@ func (r *RedisCache) ZCard(key string) int64 {
 func (r *RedisCache) ZCount(key string, min, max string) int64 {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.ZCount(context.Background(), key, min, max).Result()
+	val, err := r.client.ZCount(r.context(), key, min, max).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("ZCOUNT %s %s %s", key, min, max)
 		r.fillLogFields("ZCOUNT", message, start, false, err)
@@ -546,7 +557,7 @@ func (r *RedisCache) ZCount(key string, min, max string) int64 {
 func (r *RedisCache) ZScore(key, member string) float64 {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.ZScore(context.Background(), key, member).Result()
+	val, err := r.client.ZScore(r.context(), key, member).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("ZSCORE %s %s", key, member)
 		r.fillLogFields("ZSCORE", message, start, false, err)
@@ -562,7 +573,7 @@ func (r *RedisCache) MSet(pairs ...interface{}) {
 		}
 	}
 	start := getNow(r.engine.hasRedisLogger)
-	_, err := r.client.MSet(context.Background(), pairs...).Result()
+	_, err := r.client.MSet(r.context(), pairs...).Result()
 	if r.engine.hasRedisLogger {
 		message := "MSET"
 		for _, v := range pairs {
@@ -580,7 +591,7 @@ func (r *RedisCache) MGet(keys ...string) []interface{} {
 		}
 	}
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.MGet(context.Background(), keys...).Result()
+	val, err := r.client.MGet(r.context(), keys...).Result()
 	results := make([]interface{}, len(keys))
 	misses := 0
 	for i, v := range val {
@@ -596,10 +607,38 @@ func (r *RedisCache) MGet(keys ...string) []interface{} {
 	return results
 }
 
+func (r *RedisCache) PFAdd(key string, members ...interface{}) int64 {
+	key = r.addNamespacePrefix(key)
+	start := getNow(r.engine.hasRedisLogger)
+	val, err := r.client.PFAdd(r.context(), key, members...).Result()
+	if r.engine.hasRedisLogger {
+		message := "PFADD " + key
+		for _, v := range members {
+			message += fmt.Sprintf(" %v", v)
+		}
+		r.fillLogFields("PFADD", message, start, false, err)
+	}
+	checkError(err)
+	return val
+}
+
+func (r *RedisCache) PFCount(keys ...string) int64 {
+	for i, key := range keys {
+		keys[i] = r.addNamespacePrefix(key)
+	}
+	start := getNow(r.engine.hasRedisLogger)
+	val, err := r.client.PFCount(r.context(), keys...).Result()
+	if r.engine.hasRedisLogger {
+		r.fillLogFields("PFCOUNT", "PFCOUNT "+strings.Join(keys, " "), start, false, err)
+	}
+	checkError(err)
+	return val
+}
+
 func (r *RedisCache) SAdd(key string, members ...interface{}) int64 {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.SAdd(context.Background(), key, members...).Result()
+	val, err := r.client.SAdd(r.context(), key, members...).Result()
 	if r.engine.hasRedisLogger {
 		message := "SADD " + key
 		for _, v := range members {
@@ -614,7 +653,7 @@ func (r *RedisCache) SAdd(key string, members ...interface{}) int64 {
 func (r *RedisCache) SCard(key string) int64 {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.SCard(context.Background(), key).Result()
+	val, err := r.client.SCard(r.context(), key).Result()
 	if r.engine.hasRedisLogger {
 		r.fillLogFields("SCARD", "SCARD "+key, start, false, err)
 	}
@@ -625,7 +664,7 @@ func (r *RedisCache) SCard(key string) int64 {
 func (r *RedisCache) SPop(key string) (string, bool) {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.SPop(context.Background(), key).Result()
+	val, err := r.client.SPop(r.context(), key).Result()
 	found := true
 	if err == redis.Nil {
 		err = nil
@@ -641,7 +680,7 @@ func (r *RedisCache) SPop(key string) (string, bool) {
 func (r *RedisCache) SPopN(key string, max int64) []string {
 	key = r.addNamespacePrefix(key)
 	start := getNow(r.engine.hasRedisLogger)
-	val, err := r.client.SPopN(context.Background(), key, max).Result()
+	val, err := r.client.SPopN(r.context(), key, max).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("SPOPN %s %d", key, max)
 		r.fillLogFields("SPOPN", message, start, false, err)
@@ -657,7 +696,7 @@ func (r *RedisCache) Del(keys ...string) {
 		}
 	}
 	start := getNow(r.engine.hasRedisLogger)
-	_, err := r.client.Del(context.Background(), keys...).Result()
+	_, err := r.client.Del(r.context(), keys...).Result()
 	if r.engine.hasRedisLogger {
 		r.fillLogFields("DEL", "DEL "+strings.Join(keys, " "), start, false, err)
 	}
@@ -668,7 +707,7 @@ func (r *RedisCache) XTrim(stream string, maxLen int64) (deleted int64) {
 	stream = r.addNamespacePrefix(stream)
 	start := getNow(r.engine.hasRedisLogger)
 	var err error
-	deleted, err = r.client.XTrimMaxLen(context.Background(), stream, maxLen).Result()
+	deleted, err = r.client.XTrimMaxLen(r.context(), stream, maxLen).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("XTREAM %s %d", stream, maxLen)
 		r.fillLogFields("XTREAM", message, start, false, err)
@@ -680,7 +719,7 @@ func (r *RedisCache) XTrim(stream string, maxLen int64) (deleted int64) {
 func (r *RedisCache) XRange(stream, start, stop string, count int64) []redis.XMessage {
 	stream = r.addNamespacePrefix(stream)
 	s := getNow(r.engine.hasRedisLogger)
-	deleted, err := r.client.XRangeN(context.Background(), stream, start, stop, count).Result()
+	deleted, err := r.client.XRangeN(r.context(), stream, start, stop, count).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("XRANGE %s %s %s %d", stream, start, stop, count)
 		r.fillLogFields("XTREAM", message, s, false, err)
@@ -692,7 +731,7 @@ func (r *RedisCache) XRange(stream, start, stop string, count int64) []redis.XMe
 func (r *RedisCache) XRevRange(stream, start, stop string, count int64) []redis.XMessage {
 	stream = r.addNamespacePrefix(stream)
 	s := getNow(r.engine.hasRedisLogger)
-	deleted, err := r.client.XRevRangeN(context.Background(), stream, start, stop, count).Result()
+	deleted, err := r.client.XRevRangeN(r.context(), stream, start, stop, count).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("XREVRANGE %s %s %s %d", stream, start, stop, count)
 		r.fillLogFields("XREVRANGE", message, s, false, err)
@@ -704,7 +743,7 @@ func (r *RedisCache) XRevRange(stream, start, stop string, count int64) []redis.
 func (r *RedisCache) XInfoStream(stream string) *redis.XInfoStream {
 	stream = r.addNamespacePrefix(stream)
 	start := getNow(r.engine.hasRedisLogger)
-	info, err := r.client.XInfoStream(context.Background(), stream).Result()
+	info, err := r.client.XInfoStream(r.context(), stream).Result()
 	if r.engine.hasRedisLogger {
 		r.fillLogFields("XINFOSTREAM", "XINFOSTREAM "+stream, start, false, err)
 	}
@@ -715,7 +754,7 @@ func (r *RedisCache) XInfoStream(stream string) *redis.XInfoStream {
 func (r *RedisCache) XInfoGroups(stream string) []redis.XInfoGroup {
 	stream = r.addNamespacePrefix(stream)
 	start := getNow(r.engine.hasRedisLogger)
-	info, err := r.client.XInfoGroups(context.Background(), stream).Result()
+	info, err := r.client.XInfoGroups(r.context(), stream).Result()
 	if err == redis.Nil {
 		err = nil
 	}
@@ -737,11 +776,23 @@ func (r *RedisCache) XInfoGroups(stream string) []redis.XInfoGroup {
 	return info
 }
 
+func (r *RedisCache) XInfoConsumers(stream, group string) []redis.XInfoConsumer {
+	stream = r.addNamespacePrefix(stream)
+	group = r.addNamespacePrefix(group)
+	start := getNow(r.engine.hasRedisLogger)
+	info, err := r.client.XInfoConsumers(r.context(), stream, group).Result()
+	if r.engine.hasRedisLogger {
+		r.fillLogFields("XINFOCONSUMERS", "XINFOCONSUMERS "+stream+" "+group, start, false, err)
+	}
+	checkError(err)
+	return info
+}
+
 func (r *RedisCache) XGroupCreate(stream, group, start string) (key string, exists bool) {
 	stream = r.addNamespacePrefix(stream)
 	group = r.addNamespacePrefix(group)
 	s := getNow(r.engine.hasRedisLogger)
-	res, err := r.client.XGroupCreate(context.Background(), stream, group, start).Result()
+	res, err := r.client.XGroupCreate(r.context(), stream, group, start).Result()
 	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
 		if r.engine.hasRedisLogger {
 			message := fmt.Sprintf("XGROUPCREATE %s %s %s", stream, group, start)
@@ -757,11 +808,23 @@ func (r *RedisCache) XGroupCreate(stream, group, start string) (key string, exis
 	return res, false
 }
 
+func (r *RedisCache) XGroupSetID(stream, group, id string) {
+	stream = r.addNamespacePrefix(stream)
+	group = r.addNamespacePrefix(group)
+	s := getNow(r.engine.hasRedisLogger)
+	_, err := r.client.XGroupSetID(r.context(), stream, group, id).Result()
+	if r.engine.hasRedisLogger {
+		message := fmt.Sprintf("XGROUPSETID %s %s %s", stream, group, id)
+		r.fillLogFields("XGROUPSETID", message, s, false, err)
+	}
+	checkError(err)
+}
+
 func (r *RedisCache) XGroupCreateMkStream(stream, group, start string) (key string, exists bool) {
 	stream = r.addNamespacePrefix(stream)
 	group = r.addNamespacePrefix(group)
 	s := getNow(r.engine.hasRedisLogger)
-	res, err := r.client.XGroupCreateMkStream(context.Background(), stream, group, start).Result()
+	res, err := r.client.XGroupCreateMkStream(r.context(), stream, group, start).Result()
 	created := false
 	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
 		created = true
@@ -780,7 +843,7 @@ func (r *RedisCache) XGroupDestroy(stream, group string) int64 {
 	stream = r.addNamespacePrefix(stream)
 	group = r.addNamespacePrefix(group)
 	start := getNow(r.engine.hasRedisLogger)
-	res, err := r.client.XGroupDestroy(context.Background(), stream, group).Result()
+	res, err := r.client.XGroupDestroy(r.context(), stream, group).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("XGROUPCDESTROY %s %s", stream, group)
 		r.fillLogFields("XGROUPCDESTROY", message, start, false, err)
@@ -796,7 +859,7 @@ func (r *RedisCache) XRead(a *redis.XReadArgs) []redis.XStream {
 		}
 	}
 	start := getNow(r.engine.hasRedisLogger)
-	info, err := r.client.XRead(context.Background(), a).Result()
+	info, err := r.client.XRead(r.context(), a).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("XREAD %s COUNT %d BLOCK %d", strings.Join(a.Streams, " "), a.Count, a.Block)
 		r.fillLogFields("XREAD", message, start, false, err)
@@ -808,7 +871,7 @@ func (r *RedisCache) XRead(a *redis.XReadArgs) []redis.XStream {
 func (r *RedisCache) XDel(stream string, ids ...string) int64 {
 	stream = r.addNamespacePrefix(stream)
 	start := getNow(r.engine.hasRedisLogger)
-	deleted, err := r.client.XDel(context.Background(), stream, ids...).Result()
+	deleted, err := r.client.XDel(r.context(), stream, ids...).Result()
 	if r.engine.hasRedisLogger {
 		r.fillLogFields("XDEL", "XDEL "+stream+" "+strings.Join(ids, " "), start, false, err)
 	}
@@ -820,7 +883,7 @@ func (r *RedisCache) XGroupDelConsumer(stream, group, consumer string) int64 {
 	stream = r.addNamespacePrefix(stream)
 	group = r.addNamespacePrefix(group)
 	start := getNow(r.engine.hasRedisLogger)
-	deleted, err := r.client.XGroupDelConsumer(context.Background(), stream, group, consumer).Result()
+	deleted, err := r.client.XGroupDelConsumer(r.context(), stream, group, consumer).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("XGROUPDELCONSUMER %s %s %s", stream, group, consumer)
 		r.fillLogFields("XGROUPDELCONSUMER", message, start, false, err)
@@ -869,7 +932,7 @@ func (r *RedisCache) XPending(stream, group string) *redis.XPending {
 	stream = r.addNamespacePrefix(stream)
 	group = r.addNamespacePrefix(group)
 	start := getNow(r.engine.hasRedisLogger)
-	res, err := r.client.XPending(context.Background(), stream, group).Result()
+	res, err := r.client.XPending(r.context(), stream, group).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("XPENDING %s %s", stream, group)
 		r.fillLogFields("XPENDING", message, start, false, err)
@@ -889,7 +952,7 @@ func (r *RedisCache) XPendingExt(a *redis.XPendingExtArgs) []redis.XPendingExt {
 	}
 
 	start := getNow(r.engine.hasRedisLogger)
-	res, err := r.client.XPendingExt(context.Background(), a).Result()
+	res, err := r.client.XPendingExt(r.context(), a).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("XPENDINGEXT %s %s %s", a.Stream, a.Group, a.Consumer)
 		message += fmt.Sprintf(" START %s END %s COUNT %d IDLE %s", a.Start, a.End, a.Count, a.Idle.String())
@@ -903,7 +966,7 @@ func (r *RedisCache) xAdd(stream string, values interface{}) (id string) {
 	stream = r.addNamespacePrefix(stream)
 	a := &redis.XAddArgs{Stream: stream, ID: "*", Values: values}
 	start := getNow(r.engine.hasRedisLogger)
-	id, err := r.client.XAdd(context.Background(), a).Result()
+	id, err := r.client.XAdd(r.context(), a).Result()
 	if r.engine.hasRedisLogger {
 		message := "XADD " + stream + " " + strings.Join(values.([]string), " ")
 		r.fillLogFields("XADD", message, start, false, err)
@@ -915,7 +978,7 @@ func (r *RedisCache) xAdd(stream string, values interface{}) (id string) {
 func (r *RedisCache) XLen(stream string) int64 {
 	stream = r.addNamespacePrefix(stream)
 	start := getNow(r.engine.hasRedisLogger)
-	l, err := r.client.XLen(context.Background(), stream).Result()
+	l, err := r.client.XLen(r.context(), stream).Result()
 	if r.engine.hasRedisLogger {
 		r.fillLogFields("XLEN", "XLEN "+stream, start, false, err)
 	}
@@ -929,7 +992,7 @@ func (r *RedisCache) XClaim(a *redis.XClaimArgs) []redis.XMessage {
 		a.Group = r.addNamespacePrefix(a.Group)
 	}
 	start := getNow(r.engine.hasRedisLogger)
-	res, err := r.client.XClaim(context.Background(), a).Result()
+	res, err := r.client.XClaim(r.context(), a).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("XCLAIM %s %s %s", a.Stream, a.Group, a.Consumer)
 		message += fmt.Sprintf(" MINIDLE %s MESSAGES ", a.MinIdle.String()) + strings.Join(a.Messages, " ")
@@ -945,7 +1008,7 @@ func (r *RedisCache) XClaimJustID(a *redis.XClaimArgs) []string {
 		a.Group = r.addNamespacePrefix(a.Group)
 	}
 	start := getNow(r.engine.hasRedisLogger)
-	res, err := r.client.XClaimJustID(context.Background(), a).Result()
+	res, err := r.client.XClaimJustID(r.context(), a).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("XCLAIMJUSTID %s %s %s", a.Stream, a.Group, a.Consumer)
 
@@ -960,7 +1023,7 @@ func (r *RedisCache) XAck(stream, group string, ids ...string) int64 {
 	stream = r.addNamespacePrefix(stream)
 	group = r.addNamespacePrefix(group)
 	start := getNow(r.engine.hasRedisLogger)
-	res, err := r.client.XAck(context.Background(), stream, group, ids...).Result()
+	res, err := r.client.XAck(r.context(), stream, group, ids...).Result()
 	if r.engine.hasRedisLogger {
 		message := fmt.Sprintf("XACK %s %s %s", stream, group, strings.Join(ids, " "))
 		r.fillLogFields("XACK", message, start, false, err)
@@ -971,7 +1034,7 @@ func (r *RedisCache) XAck(stream, group string, ids ...string) int64 {
 
 func (r *RedisCache) FlushAll() {
 	start := getNow(r.engine.hasRedisLogger)
-	_, err := r.client.FlushAll(context.Background()).Result()
+	_, err := r.client.FlushAll(r.context()).Result()
 	if r.engine.hasRedisLogger {
 		r.fillLogFields("FLUSHALL", "FLUSHALL", start, false, err)
 	}
@@ -982,14 +1045,14 @@ func (r *RedisCache) FlushDB() {
 	start := getNow(r.engine.hasRedisLogger)
 	if r.config.HasNamespace() {
 		script := "for _,k in ipairs(redis.call('keys','" + r.config.GetNamespace() + ":*')) do redis.call('del',k) end return 1"
-		_, err := r.client.Eval(context.Background(), script, nil).Result()
+		_, err := r.client.Eval(r.context(), script, nil).Result()
 		if r.engine.hasRedisLogger {
 			r.fillLogFields("FLUSHDB EVAL", "EVAL REMOVE KEYS WITH PREFIX "+r.config.GetNamespace(), start, false, err)
 		}
 		checkError(err)
 		return
 	}
-	_, err := r.client.FlushDB(context.Background()).Result()
+	_, err := r.client.FlushDB(r.context()).Result()
 	if r.engine.hasRedisLogger {
 		r.fillLogFields("FLUSHDB", "FLUSHDB", start, false, err)
 	}
@@ -997,6 +1060,9 @@ func (r *RedisCache) FlushDB() {
 }
 
 func (r *RedisCache) fillLogFields(operation, query string, start *time.Time, cacheMiss bool, err error) {
+	if !r.engine.registry.runtime.sampleLog(err) {
+		return
+	}
 	fillLogFields(r.engine.queryLoggersRedis, r.config.GetCode(), sourceRedis, operation, query, start, cacheMiss, err)
 }
 
@@ -1,5 +1,7 @@
 package beeorm
 
+import "time"
+
 const (
 	commandDelete = iota
 	commandXAdd   = iota
@@ -12,13 +14,26 @@ type redisFlusherCommands struct {
 	usePool bool
 	deletes []string
 	hSets   map[string][]interface{}
-	sets    map[string]interface{}
+	sets    map[string]redisFlusherValue
 	events  map[string][][]string
 }
 
+type redisFlusherValue struct {
+	value      interface{}
+	ttlSeconds int
+}
+
 type redisFlusher struct {
-	engine    *engineImplementation
-	pipelines map[string]*redisFlusherCommands
+	engine          *engineImplementation
+	pipelines       map[string]*redisFlusherCommands
+	variants        map[string][]string
+	jetStreamEvents []jetStreamFlusherEvent
+}
+
+type jetStreamFlusherEvent struct {
+	client JetStreamClient
+	stream string
+	values []string
 }
 
 func (f *redisFlusher) Del(redisPool string, keys ...string) {
@@ -38,22 +53,43 @@ func (f *redisFlusher) Del(redisPool string, keys ...string) {
 	commands.deletes = append(commands.deletes, keys...)
 }
 
-func (f *redisFlusher) Set(redisPool string, key string, value interface{}) {
+func (f *redisFlusher) DelCacheKeyVariants(redisPool string, variantsKeys ...string) {
+	if len(variantsKeys) == 0 {
+		return
+	}
+	if f.variants == nil {
+		f.variants = make(map[string][]string)
+	}
+	f.variants[redisPool] = append(f.variants[redisPool], variantsKeys...)
+}
+
+func (f *redisFlusher) Set(redisPool string, key string, value interface{}, ttlSeconds int) {
 	if f.pipelines == nil {
 		f.pipelines = make(map[string]*redisFlusherCommands)
 	}
+	set := redisFlusherValue{value: value, ttlSeconds: ttlSeconds}
 	commands, has := f.pipelines[redisPool]
 	if !has {
-		commands = &redisFlusherCommands{sets: map[string]interface{}{key: value}, diffs: map[int]bool{commandSet: true}}
+		commands = &redisFlusherCommands{sets: map[string]redisFlusherValue{key: set}, diffs: map[int]bool{commandSet: true}}
 		f.pipelines[redisPool] = commands
 		return
 	}
 	commands.diffs[commandSet] = true
-	commands.sets[key] = value
+	if commands.sets == nil {
+		commands.sets = map[string]redisFlusherValue{key: set}
+		return
+	}
+	commands.sets[key] = set
 }
 
 func (f *redisFlusher) Publish(stream string, body interface{}, meta ...string) {
-	eventRaw := createEventSlice(body, meta)
+	jetStream, isJetStream := getJetStreamForStream(f.engine, stream)
+	if isJetStream {
+		f.jetStreamEvents = append(f.jetStreamEvents, jetStreamFlusherEvent{client: jetStream, stream: stream,
+			values: createEventSlice(f.engine, stream, body, meta)})
+		return
+	}
+	eventRaw := createEventSlice(f.engine, stream, body, meta)
 	if f.pipelines == nil {
 		f.pipelines = make(map[string]*redisFlusherCommands)
 	}
@@ -97,6 +133,18 @@ func (f *redisFlusher) HSet(redisPool, key string, values ...interface{}) {
 }
 
 func (f *redisFlusher) Flush() {
+	f.flushPipelines()
+	for poolCode, variantsKeys := range f.variants {
+		deleteCacheKeyVariants(f.engine.GetRedis(poolCode), variantsKeys...)
+	}
+	f.variants = nil
+	for _, e := range f.jetStreamEvents {
+		jetStreamPublish(e.client, e.stream, e.values)
+	}
+	f.jetStreamEvents = nil
+}
+
+func (f *redisFlusher) flushPipelines() {
 	if len(f.pipelines) <= 1 {
 		for poolCode, commands := range f.pipelines {
 			usePool := commands.usePool || len(commands.diffs) > 1 || len(commands.events) > 1 ||
@@ -115,7 +163,7 @@ func (f *redisFlusher) Flush() {
 					}
 				}
 				for key, value := range commands.sets {
-					p.Set(key, value, 0)
+					p.Set(key, value.value, time.Duration(value.ttlSeconds)*time.Second)
 				}
 				p.Exec()
 			} else {
@@ -135,7 +183,7 @@ func (f *redisFlusher) Flush() {
 				}
 				if commands.sets != nil {
 					for key, value := range commands.sets {
-						r.Set(key, value, 0)
+						r.Set(key, value.value, value.ttlSeconds)
 					}
 				}
 			}
@@ -157,7 +205,7 @@ func (f *redisFlusher) Flush() {
 				has = true
 			}
 			for key, value := range commands.sets {
-				p.Set(key, value, 0)
+				p.Set(key, value.value, time.Duration(value.ttlSeconds)*time.Second)
 				has = true
 			}
 			if has {
@@ -175,7 +223,7 @@ func (f *redisFlusher) Flush() {
 			}
 			if commands.sets != nil {
 				for key, value := range commands.sets {
-					r.Set(key, value, 0)
+					r.Set(key, value.value, value.ttlSeconds)
 				}
 			}
 		}
@@ -88,6 +88,18 @@ func (rp *RedisPipeLine) HDel(key string, values ...string) {
 	rp.pipeLine.HDel(context.Background(), key, values...)
 }
 
+func (rp *RedisPipeLine) SAdd(key string, members ...interface{}) {
+	key = rp.r.addNamespacePrefix(key)
+	rp.commands++
+	if rp.r.engine.hasRedisLogger {
+		rp.log = append(rp.log, "SADD", key)
+		for _, v := range members {
+			rp.log = append(rp.log, fmt.Sprintf("%v", v))
+		}
+	}
+	rp.pipeLine.SAdd(context.Background(), key, members...)
+}
+
 func (rp *RedisPipeLine) XAdd(stream string, values []string) *PipeLineString {
 	stream = rp.r.addNamespacePrefix(stream)
 	rp.commands++
@@ -0,0 +1,154 @@
+package beeorm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func (r *RedisCache) PFMerge(destination string, sources ...string) {
+	destination = r.addNamespacePrefix(destination)
+	for i, key := range sources {
+		sources[i] = r.addNamespacePrefix(key)
+	}
+	start := getNow(r.engine.hasRedisLogger)
+	_, err := r.client.PFMerge(r.context(), destination, sources...).Result()
+	if r.engine.hasRedisLogger {
+		r.fillLogFields("PFMERGE", "PFMERGE "+destination+" "+strings.Join(sources, " "), start, false, err)
+	}
+	checkError(err)
+}
+
+func (r *RedisCache) BFReserve(key string, errorRate float64, capacity int64) {
+	r.doCommand("BF.RESERVE", r.addNamespacePrefix(key), errorRate, capacity)
+}
+
+func (r *RedisCache) BFAdd(key string, item interface{}) bool {
+	return r.doCommand("BF.ADD", r.addNamespacePrefix(key), item).(int64) == 1
+}
+
+func (r *RedisCache) BFMAdd(key string, items ...interface{}) []bool {
+	return redisBoolSlice(r.doCommand("BF.MADD", append([]interface{}{r.addNamespacePrefix(key)}, items...)...))
+}
+
+func (r *RedisCache) BFExists(key string, item interface{}) bool {
+	return r.doCommand("BF.EXISTS", r.addNamespacePrefix(key), item).(int64) == 1
+}
+
+func (r *RedisCache) BFMExists(key string, items ...interface{}) []bool {
+	return redisBoolSlice(r.doCommand("BF.MEXISTS", append([]interface{}{r.addNamespacePrefix(key)}, items...)...))
+}
+
+func (r *RedisCache) CFReserve(key string, capacity int64) {
+	r.doCommand("CF.RESERVE", r.addNamespacePrefix(key), capacity)
+}
+
+func (r *RedisCache) CFAdd(key string, item interface{}) bool {
+	return r.doCommand("CF.ADD", r.addNamespacePrefix(key), item).(int64) == 1
+}
+
+func (r *RedisCache) CFAddNX(key string, item interface{}) bool {
+	return r.doCommand("CF.ADDNX", r.addNamespacePrefix(key), item).(int64) == 1
+}
+
+func (r *RedisCache) CFExists(key string, item interface{}) bool {
+	return r.doCommand("CF.EXISTS", r.addNamespacePrefix(key), item).(int64) == 1
+}
+
+func (r *RedisCache) CFDel(key string, item interface{}) bool {
+	return r.doCommand("CF.DEL", r.addNamespacePrefix(key), item).(int64) == 1
+}
+
+func (r *RedisCache) CFCount(key string, item interface{}) int64 {
+	return r.doCommand("CF.COUNT", r.addNamespacePrefix(key), item).(int64)
+}
+
+func (r *RedisCache) CMSInitByDim(key string, width, depth int64) {
+	r.doCommand("CMS.INITBYDIM", r.addNamespacePrefix(key), width, depth)
+}
+
+func (r *RedisCache) CMSInitByProb(key string, errorRate, probability float64) {
+	r.doCommand("CMS.INITBYPROB", r.addNamespacePrefix(key), errorRate, probability)
+}
+
+func (r *RedisCache) CMSIncrBy(key string, increments map[string]int64) map[string]int64 {
+	items := make([]string, 0, len(increments))
+	for item := range increments {
+		items = append(items, item)
+	}
+	sort.Strings(items)
+	args := []interface{}{r.addNamespacePrefix(key)}
+	for _, item := range items {
+		args = append(args, item, increments[item])
+	}
+	counts := redisInt64Slice(r.doCommand("CMS.INCRBY", args...))
+	result := make(map[string]int64, len(items))
+	for i, item := range items {
+		result[item] = counts[i]
+	}
+	return result
+}
+
+func (r *RedisCache) CMSQuery(key string, items ...interface{}) []int64 {
+	return redisInt64Slice(r.doCommand("CMS.QUERY", append([]interface{}{r.addNamespacePrefix(key)}, items...)...))
+}
+
+func (r *RedisCache) TopKReserve(key string, k, width, depth int64, decay float64) {
+	r.doCommand("TOPK.RESERVE", r.addNamespacePrefix(key), k, width, depth, decay)
+}
+
+func (r *RedisCache) TopKAdd(key string, items ...interface{}) (expelled []string) {
+	res := r.doCommand("TOPK.ADD", append([]interface{}{r.addNamespacePrefix(key)}, items...)...)
+	expelled = make([]string, 0)
+	for _, value := range res.([]interface{}) {
+		if value != nil {
+			expelled = append(expelled, value.(string))
+		}
+	}
+	return expelled
+}
+
+func (r *RedisCache) TopKQuery(key string, items ...interface{}) []bool {
+	return redisBoolSlice(r.doCommand("TOPK.QUERY", append([]interface{}{r.addNamespacePrefix(key)}, items...)...))
+}
+
+func (r *RedisCache) TopKList(key string) []string {
+	res := r.doCommand("TOPK.LIST", r.addNamespacePrefix(key)).([]interface{})
+	list := make([]string, len(res))
+	for i, value := range res {
+		list[i] = value.(string)
+	}
+	return list
+}
+
+func (r *RedisCache) doCommand(operation string, args ...interface{}) interface{} {
+	start := getNow(r.engine.hasRedisLogger)
+	val, err := r.client.Do(r.context(), append([]interface{}{operation}, args...)...).Result()
+	if r.engine.hasRedisLogger {
+		message := operation
+		for _, v := range args {
+			message += fmt.Sprintf(" %v", v)
+		}
+		r.fillLogFields(operation, message, start, false, err)
+	}
+	checkError(err)
+	return val
+}
+
+func redisBoolSlice(res interface{}) []bool {
+	values := res.([]interface{})
+	result := make([]bool, len(values))
+	for i, value := range values {
+		result[i] = value == int64(1)
+	}
+	return result
+}
+
+func redisInt64Slice(res interface{}) []int64 {
+	values := res.([]interface{})
+	result := make([]int64, len(values))
+	for i, value := range values {
+		result[i], _ = value.(int64)
+	}
+	return result
+}
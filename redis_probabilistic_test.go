@@ -0,0 +1,58 @@
+package beeorm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisHyperLogLog(t *testing.T) {
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "")
+	r := engine.GetRedis()
+
+	r.PFAdd("visits:a", "u1", "u2", "u3")
+	r.PFAdd("visits:b", "u3", "u4")
+	r.PFMerge("visits", "visits:a", "visits:b")
+	assert.Equal(t, int64(4), r.PFCount("visits"))
+}
+
+func TestRedisProbabilistic(t *testing.T) {
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "")
+	r := engine.GetRedis()
+	err := r.client.Do(context.Background(), "BF.INFO", "missing").Err()
+	if err != nil && strings.Contains(err.Error(), "unknown command") {
+		t.Skip("RedisBloom module is not loaded")
+	}
+
+	r.BFReserve("emails", 0.001, 1000)
+	assert.True(t, r.BFAdd("emails", "a@example.com"))
+	assert.False(t, r.BFAdd("emails", "a@example.com"))
+	assert.Equal(t, []bool{true, false}, r.BFMAdd("emails", "b@example.com", "a@example.com"))
+	assert.True(t, r.BFExists("emails", "b@example.com"))
+	assert.False(t, r.BFExists("emails", "c@example.com"))
+	assert.Equal(t, []bool{true, false}, r.BFMExists("emails", "a@example.com", "c@example.com"))
+
+	r.CFReserve("sessions", 1000)
+	assert.True(t, r.CFAdd("sessions", "s1"))
+	assert.True(t, r.CFAdd("sessions", "s1"))
+	assert.False(t, r.CFAddNX("sessions", "s1"))
+	assert.Equal(t, int64(2), r.CFCount("sessions", "s1"))
+	assert.True(t, r.CFDel("sessions", "s1"))
+	assert.True(t, r.CFExists("sessions", "s1"))
+	assert.True(t, r.CFDel("sessions", "s1"))
+	assert.False(t, r.CFExists("sessions", "s1"))
+
+	r.CMSInitByDim("hits", 2000, 5)
+	assert.Equal(t, map[string]int64{"home": 3, "about": 1}, r.CMSIncrBy("hits", map[string]int64{"home": 3, "about": 1}))
+	r.CMSIncrBy("hits", map[string]int64{"home": 2})
+	assert.Equal(t, []int64{5, 1, 0}, r.CMSQuery("hits", "home", "about", "contact"))
+
+	r.TopKReserve("products", 2, 50, 4, 0.9)
+	assert.Len(t, r.TopKAdd("products", "p1", "p1", "p1", "p2", "p2"), 0)
+	assert.Equal(t, []bool{true, true, false}, r.TopKQuery("products", "p1", "p2", "p3"))
+	assert.Equal(t, []string{"p1", "p2"}, r.TopKList("products"))
+}
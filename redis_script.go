@@ -0,0 +1,102 @@
+package beeorm
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/go-redis/redis/v9"
+)
+
+type redisScript struct {
+	name   string
+	source string
+	sha1   string
+}
+
+func newRedisScript(name, source string) *redisScript {
+	/* #nosec */
+	sum := sha1.Sum([]byte(source))
+	return &redisScript{name: name, source: source, sha1: hex.EncodeToString(sum[:])}
+}
+
+func (r *Registry) RegisterRedisScript(name, source string) {
+	if r.redisScripts == nil {
+		r.redisScripts = make(map[string]*redisScript)
+	}
+	if _, has := r.redisScripts[name]; has {
+		panic(fmt.Errorf("redis script '%s' already registered", name))
+	}
+	r.redisScripts[name] = newRedisScript(name, source)
+}
+
+func (r *RedisCache) RunScript(name string, keys []string, args ...interface{}) interface{} {
+	script, has := r.engine.registry.registry.redisScripts[name]
+	if !has {
+		panic(fmt.Errorf("unregistered redis script '%s'", name))
+	}
+	return r.runScript(script, keys, args...)
+}
+
+func (r *RedisCache) RunScriptInt(name string, keys []string, args ...interface{}) int64 {
+	res := r.RunScript(name, keys, args...)
+	asInt, is := res.(int64)
+	if !is && res != nil {
+		panic(fmt.Errorf("redis script '%s' returned %T instead of integer", name, res))
+	}
+	return asInt
+}
+
+func (r *RedisCache) RunScriptString(name string, keys []string, args ...interface{}) (value string, has bool) {
+	res := r.RunScript(name, keys, args...)
+	if res == nil {
+		return "", false
+	}
+	asString, is := res.(string)
+	if !is {
+		panic(fmt.Errorf("redis script '%s' returned %T instead of string", name, res))
+	}
+	return asString, true
+}
+
+func (r *RedisCache) RunScriptStrings(name string, keys []string, args ...interface{}) []string {
+	res := r.RunScript(name, keys, args...)
+	if res == nil {
+		return nil
+	}
+	values, is := res.([]interface{})
+	if !is {
+		panic(fmt.Errorf("redis script '%s' returned %T instead of array", name, res))
+	}
+	result := make([]string, len(values))
+	for i, value := range values {
+		if value != nil {
+			result[i] = fmt.Sprintf("%v", value)
+		}
+	}
+	return result
+}
+
+func (r *RedisCache) runScript(script *redisScript, keys []string, args ...interface{}) interface{} {
+	start := getNow(r.engine.hasRedisLogger)
+	res, err := r.client.EvalSha(r.context(), script.sha1, keys, args...).Result()
+	operation := "EVALSHA"
+	if err != nil && strings.HasPrefix(err.Error(), "NOSCRIPT") {
+		if r.engine.hasRedisLogger {
+			r.fillLogFields(operation, fmt.Sprintf("EVALSHA %s %v %v", script.name, keys, args), start, true, nil)
+		}
+		start = getNow(r.engine.hasRedisLogger)
+		res, err = r.client.Eval(r.context(), script.source, keys, args...).Result()
+		operation = "EVAL"
+	}
+	if err == redis.Nil {
+		res = nil
+		err = nil
+	}
+	if r.engine.hasRedisLogger {
+		r.fillLogFields(operation, fmt.Sprintf("%s %s %v %v", operation, script.name, keys, args), start, false, err)
+	}
+	checkError(err)
+	return res
+}
@@ -0,0 +1,48 @@
+package beeorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisScripts(t *testing.T) {
+	registry := &Registry{}
+	registry.RegisterRedisScript("incr_by", "return redis.call('INCRBY', KEYS[1], ARGV[1])")
+	registry.RegisterRedisScript("get", "return redis.call('GET', KEYS[1])")
+	registry.RegisterRedisScript("pair", "return {ARGV[1], tonumber(ARGV[2])}")
+	assert.PanicsWithError(t, "redis script 'get' already registered", func() {
+		registry.RegisterRedisScript("get", "return 1")
+	})
+	engine := prepareTables(t, registry, 5, 6, "")
+	r := engine.GetRedis()
+	r.client.ScriptFlush(context.Background())
+	testLogger := &testLogHandler{}
+	engine.RegisterQueryLogger(testLogger, false, true, false)
+
+	assert.Equal(t, int64(3), r.RunScriptInt("incr_by", []string{"counter"}, 3))
+	assert.Len(t, testLogger.Logs, 2)
+	assert.Equal(t, "EVALSHA", testLogger.Logs[0]["operation"])
+	assert.Equal(t, true, testLogger.Logs[0]["miss"])
+	assert.Equal(t, "EVAL", testLogger.Logs[1]["operation"])
+
+	testLogger.clear()
+	assert.Equal(t, int64(5), r.RunScriptInt("incr_by", []string{"counter"}, 2))
+	assert.Len(t, testLogger.Logs, 1)
+	assert.Equal(t, "EVALSHA", testLogger.Logs[0]["operation"])
+
+	value, has := r.RunScriptString("get", []string{"counter"})
+	assert.True(t, has)
+	assert.Equal(t, "5", value)
+	_, has = r.RunScriptString("get", []string{"missing"})
+	assert.False(t, has)
+	assert.Equal(t, []string{"a", "2"}, r.RunScriptStrings("pair", nil, "a", 2))
+
+	assert.PanicsWithError(t, "unregistered redis script 'invalid'", func() {
+		r.RunScript("invalid", nil)
+	})
+	assert.PanicsWithError(t, "redis script 'pair' returned []interface {} instead of integer", func() {
+		r.RunScriptInt("pair", nil, "a", 2)
+	})
+}
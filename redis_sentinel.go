@@ -0,0 +1,122 @@
+package beeorm
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+)
+
+const redisSentinelRetryTime = time.Second
+
+type RedisFailoverHandler func(pool, masterName, oldMaster, newMaster string)
+
+type redisSentinel struct {
+	registry  *Registry
+	pool      string
+	options   *redis.FailoverOptions
+	dialer    func(ctx context.Context, network, addr string) (net.Conn, error)
+	mutex     sync.Mutex
+	master    string
+	changed   chan struct{}
+	watchOnce sync.Once
+}
+
+func newRedisSentinel(registry *Registry, pool string, options *redis.FailoverOptions) *redisSentinel {
+	sentinel := &redisSentinel{registry: registry, pool: pool, options: options, dialer: options.Dialer, changed: make(chan struct{})}
+	options.Dialer = sentinel.dial
+	return sentinel
+}
+
+func (s *redisSentinel) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	if !s.options.ReplicaOnly {
+		s.switchMaster(addr)
+	}
+	if s.dialer != nil {
+		return s.dialer(ctx, network, addr)
+	}
+	netDialer := &net.Dialer{Timeout: s.options.DialTimeout, KeepAlive: 5 * time.Minute}
+	if s.options.TLSConfig == nil {
+		return netDialer.DialContext(ctx, network, addr)
+	}
+	return tls.DialWithDialer(netDialer, network, addr, s.options.TLSConfig)
+}
+
+func (s *redisSentinel) switchMaster(addr string) {
+	s.mutex.Lock()
+	old := s.master
+	if old == addr {
+		s.mutex.Unlock()
+		return
+	}
+	s.master = addr
+	if old != "" {
+		close(s.changed)
+		s.changed = make(chan struct{})
+	}
+	s.mutex.Unlock()
+	if old == "" {
+		return
+	}
+	for _, handler := range s.registry.redisFailoverHandlers {
+		handler(s.pool, s.options.MasterName, old, addr)
+	}
+}
+
+func (s *redisSentinel) getMaster() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.master
+}
+
+func (s *redisSentinel) failoverChannel() <-chan struct{} {
+	s.watchOnce.Do(func() {
+		go s.watch()
+	})
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.changed
+}
+
+func (s *redisSentinel) watch() {
+	ctx := context.Background()
+	for {
+		for _, addr := range s.options.SentinelAddrs {
+			s.listen(ctx, addr)
+		}
+		time.Sleep(redisSentinelRetryTime)
+	}
+}
+
+func (s *redisSentinel) listen(ctx context.Context, addr string) {
+	client := redis.NewSentinelClient(&redis.Options{
+		Addr:        addr,
+		Username:    s.options.SentinelUsername,
+		Password:    s.options.SentinelPassword,
+		DialTimeout: s.options.DialTimeout,
+		TLSConfig:   s.options.TLSConfig,
+	})
+	defer func() {
+		_ = client.Close()
+	}()
+	master, err := client.GetMasterAddrByName(ctx, s.options.MasterName).Result()
+	if err != nil || len(master) != 2 {
+		return
+	}
+	s.switchMaster(net.JoinHostPort(master[0], master[1]))
+	pubSub := client.Subscribe(ctx, "+switch-master")
+	defer func() {
+		_ = pubSub.Close()
+	}()
+	for message := range pubSub.Channel() {
+		parts := strings.Split(message.Payload, " ")
+		if len(parts) != 5 || parts[0] != s.options.MasterName {
+			continue
+		}
+		s.switchMaster(net.JoinHostPort(parts[3], parts[4]))
+	}
+}
@@ -0,0 +1,39 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisSentinelFailover(t *testing.T) {
+	registry := &Registry{}
+	events := make([][]string, 0)
+	registry.RegisterRedisFailoverHandler(func(pool, masterName, oldMaster, newMaster string) {
+		events = append(events, []string{pool, masterName, oldMaster, newMaster})
+	})
+	registry.RegisterRedisSentinel("mymaster", "", 0, []string{"localhost:26379"}, "sentinel")
+	registry.RegisterRedis("localhost:6382", "", 0, "simple")
+
+	simple := registry.redisPools["simple"]
+	assert.Equal(t, "localhost:6382", simple.GetMasterAddress())
+	assert.Nil(t, simple.getFailoverChannel())
+
+	config := registry.redisPools["sentinel"].(*redisCacheConfig)
+	assert.NotNil(t, config.sentinel)
+	config.sentinel.switchMaster("10.0.0.1:6379")
+	assert.Equal(t, "10.0.0.1:6379", config.GetMasterAddress())
+	assert.Len(t, events, 0)
+	changed := config.sentinel.changed
+	config.sentinel.switchMaster("10.0.0.1:6379")
+	assert.Len(t, events, 0)
+	config.sentinel.switchMaster("10.0.0.2:6379")
+	assert.Equal(t, "10.0.0.2:6379", config.GetMasterAddress())
+	assert.Equal(t, [][]string{{"sentinel", "mymaster", "10.0.0.1:6379", "10.0.0.2:6379"}}, events)
+	select {
+	case <-changed:
+	default:
+		assert.Fail(t, "failover channel not closed")
+	}
+	assert.NotEqual(t, changed, config.sentinel.changed)
+}
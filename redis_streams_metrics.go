@@ -0,0 +1,140 @@
+package beeorm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type StreamsMetricsCollector struct {
+	engine   Engine
+	interval time.Duration
+	streams  []string
+	stats    []*RedisStreamStatistics
+	onError  func(err error)
+	mutex    sync.RWMutex
+}
+
+func NewStreamsMetricsCollector(engine Engine, interval time.Duration, streams ...string) *StreamsMetricsCollector {
+	return &StreamsMetricsCollector{engine: engine, interval: interval, streams: streams}
+}
+
+func (c *StreamsMetricsCollector) SetErrorHandler(handler func(err error)) {
+	c.onError = handler
+}
+
+func (c *StreamsMetricsCollector) Run(ctx context.Context) {
+	c.collectInBackground()
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.collectInBackground()
+		}
+	}
+}
+
+func (c *StreamsMetricsCollector) collectInBackground() {
+	err := c.Collect()
+	if err != nil && c.onError != nil {
+		c.onError(err)
+	}
+}
+
+// Collect refreshes gauges. On error previously collected values are kept.
+func (c *StreamsMetricsCollector) Collect() (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			asErr, is := rec.(error)
+			if !is {
+				asErr = fmt.Errorf("%v", rec)
+			}
+			err = asErr
+		}
+	}()
+	stats := c.engine.GetEventBroker().GetStreamsStatistics(c.streams...)
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Stream < stats[j].Stream
+	})
+	for _, stat := range stats {
+		sort.Slice(stat.Groups, func(i, j int) bool {
+			return stat.Groups[i].Group < stat.Groups[j].Group
+		})
+	}
+	c.mutex.Lock()
+	c.stats = stats
+	c.mutex.Unlock()
+	return nil
+}
+
+func (c *StreamsMetricsCollector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	writeStreamsMetrics(w, c.stats)
+}
+
+func writeStreamsMetrics(w io.Writer, stats []*RedisStreamStatistics) {
+	b := &strings.Builder{}
+	writeStreamsMetricsHeader(b, "beeorm_stream_length", "Number of events in stream.")
+	for _, stat := range stats {
+		writeStreamsMetric(b, "beeorm_stream_length", stat, "", strconv.FormatUint(stat.Len, 10))
+	}
+	writeStreamsMetricsHeader(b, "beeorm_stream_oldest_event_seconds", "Age of the oldest pending event in stream.")
+	for _, stat := range stats {
+		writeStreamsMetric(b, "beeorm_stream_oldest_event_seconds", stat, "", strconv.Itoa(stat.OldestEventSeconds))
+	}
+	writeStreamsMetricsHeader(b, "beeorm_stream_group_pending", "Number of pending events in consumer group.")
+	for _, stat := range stats {
+		for _, group := range stat.Groups {
+			writeStreamsMetric(b, "beeorm_stream_group_pending", stat, group.Group, strconv.FormatUint(group.Pending, 10))
+		}
+	}
+	writeStreamsMetricsHeader(b, "beeorm_stream_group_lag", "Number of events not delivered to consumer group.")
+	for _, stat := range stats {
+		for _, group := range stat.Groups {
+			writeStreamsMetric(b, "beeorm_stream_group_lag", stat, group.Group, strconv.FormatInt(group.Lag, 10))
+		}
+	}
+	writeStreamsMetricsHeader(b, "beeorm_stream_group_delivery_rate", "Events delivered to consumer group per second.")
+	for _, stat := range stats {
+		for _, group := range stat.Groups {
+			writeStreamsMetric(b, "beeorm_stream_group_delivery_rate", stat, group.Group, strconv.FormatFloat(group.DeliveryRate, 'f', -1, 64))
+		}
+	}
+	writeStreamsMetricsHeader(b, "beeorm_stream_group_consumers", "Number of consumers with pending events in consumer group.")
+	for _, stat := range stats {
+		for _, group := range stat.Groups {
+			writeStreamsMetric(b, "beeorm_stream_group_consumers", stat, group.Group, strconv.Itoa(len(group.Consumers)))
+		}
+	}
+	_, _ = io.WriteString(w, b.String())
+}
+
+func writeStreamsMetricsHeader(b *strings.Builder, name, help string) {
+	b.WriteString("# HELP " + name + " " + help + "\n")
+	b.WriteString("# TYPE " + name + " gauge\n")
+}
+
+func writeStreamsMetric(b *strings.Builder, name string, stat *RedisStreamStatistics, group, value string) {
+	b.WriteString(name + "{stream=\"" + escapeMetricLabel(stat.Stream) + "\",pool=\"" + escapeMetricLabel(stat.RedisPool) + "\"")
+	if group != "" {
+		b.WriteString(",group=\"" + escapeMetricLabel(group) + "\"")
+	}
+	b.WriteString("} " + value + "\n")
+}
+
+func escapeMetricLabel(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, "\"", "\\\"")
+	return strings.ReplaceAll(value, "\n", "\\n")
+}
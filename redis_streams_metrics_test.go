@@ -0,0 +1,66 @@
+package beeorm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamsMetricsCollector(t *testing.T) {
+	client := &testJetStreamClient{messages: make(map[string][]*testJetStreamMessage), delivered: make(map[string]int),
+		pending: make(map[string]map[uint64]bool)}
+	registry := &Registry{}
+	registry.RegisterRedis("localhost:6382", "", 15)
+	registry.RegisterJetStream(client, "nats")
+	registry.RegisterJetStreamStream("jet-stream", "nats", []string{"test-group"})
+	validatedRegistry, err := registry.Validate()
+	assert.NoError(t, err)
+	engine := validatedRegistry.CreateEngine()
+	engine.GetEventBroker().Publish("jet-stream", "a")
+	engine.GetEventBroker().Publish("jet-stream", "b")
+	consumer := engine.GetEventBroker().Consumer("test-group")
+	consumer.DisableBlockMode()
+	assert.PanicsWithValue(t, "stop", func() {
+		consumer.Consume(context.Background(), 1, func(events []Event) {
+			panic("stop")
+		})
+	})
+
+	collector := NewStreamsMetricsCollector(engine, 0, "jet-stream")
+	assert.NoError(t, collector.Collect())
+	recorder := httptest.NewRecorder()
+	collector.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Result().Body)
+	assert.Equal(t, "text/plain; version=0.0.4", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, string(body), "# TYPE beeorm_stream_length gauge\n")
+	assert.Contains(t, string(body), "beeorm_stream_length{stream=\"jet-stream\",pool=\"nats\"} 2\n")
+	assert.Contains(t, string(body), "beeorm_stream_group_pending{stream=\"jet-stream\",pool=\"nats\",group=\"test-group\"} 1\n")
+	assert.Contains(t, string(body), "beeorm_stream_group_lag{stream=\"jet-stream\",pool=\"nats\",group=\"test-group\"} 1\n")
+	assert.Contains(t, string(body), "beeorm_stream_group_consumers{stream=\"jet-stream\",pool=\"nats\",group=\"test-group\"} 0\n")
+
+	client.infoError = errors.New("connection lost")
+	assert.EqualError(t, collector.Collect(), "connection lost")
+	recorder = httptest.NewRecorder()
+	collector.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ = io.ReadAll(recorder.Result().Body)
+	assert.Contains(t, string(body), "beeorm_stream_length{stream=\"jet-stream\",pool=\"nats\"} 2\n")
+
+	collector = NewStreamsMetricsCollector(engine, time.Millisecond, "jet-stream")
+	errs := make(chan error, 2)
+	collector.SetErrorHandler(func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	go collector.Run(ctx)
+	assert.EqualError(t, <-errs, "connection lost")
+	assert.EqualError(t, <-errs, "connection lost")
+	cancel()
+}
@@ -4,9 +4,12 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
+const streamDeliveryRateWindow = time.Minute
+
 type RedisStreamStatistics struct {
 	Stream             string
 	RedisPool          string
@@ -23,12 +26,20 @@ type RedisStreamGroupStatistics struct {
 	LastDeliveredDuration time.Duration
 	LowerID               string
 	LowerDuration         time.Duration
+	EntriesRead           int64
+	DeliveryRate          float64
 	Consumers             []*RedisStreamConsumerStatistics
 }
 
 type RedisStreamConsumerStatistics struct {
 	Name    string
 	Pending uint64
+	Idle    time.Duration
+}
+
+type streamDeliverySample struct {
+	time        time.Time
+	entriesRead int64
 }
 
 func (eb *eventBroker) GetStreamStatistics(stream string) *RedisStreamStatistics {
@@ -56,7 +67,6 @@ func (eb *eventBroker) GetStreamsStatistics(stream ...string) []*RedisStreamStat
 	now := time.Now()
 	results := make([]*RedisStreamStatistics, 0)
 	for redisPool, channels := range eb.engine.GetRegistry().GetRedisStreams() {
-		r := eb.engine.GetRedis(redisPool)
 		for streamName := range channels {
 			validName := len(stream) == 0
 			if !validName {
@@ -70,6 +80,7 @@ func (eb *eventBroker) GetStreamsStatistics(stream ...string) []*RedisStreamStat
 			if !validName {
 				continue
 			}
+			r := eb.engine.GetRedis(redisPool)
 			stat := &RedisStreamStatistics{Stream: streamName, RedisPool: redisPool}
 			results = append(results, stat)
 			stat.Groups = make([]*RedisStreamGroupStatistics, 0)
@@ -79,6 +90,8 @@ func (eb *eventBroker) GetStreamsStatistics(stream ...string) []*RedisStreamStat
 				groupStats := &RedisStreamGroupStatistics{Group: group.Name, Pending: uint64(group.Pending)}
 				groupStats.LastDeliveredID = group.LastDeliveredID
 				groupStats.Lag = group.Lag
+				groupStats.EntriesRead = group.EntriesRead
+				groupStats.DeliveryRate = eb.engine.registry.deliveryRates.getDeliveryRate(streamName, group.Name, group.EntriesRead, now)
 				groupStats.LastDeliveredDuration, _ = idToSince(group.LastDeliveredID, now)
 				groupStats.Consumers = make([]*RedisStreamConsumerStatistics, 0)
 
@@ -94,8 +107,12 @@ func (eb *eventBroker) GetStreamsStatistics(stream ...string) []*RedisStreamStat
 							minPending = int(since.Seconds())
 						}
 					}
+					idle := make(map[string]time.Duration)
+					for _, consumer := range r.XInfoConsumers(streamName, group.Name) {
+						idle[consumer.Name] = consumer.Idle
+					}
 					for name, pending := range pending.Consumers {
-						consumer := &RedisStreamConsumerStatistics{Name: name, Pending: uint64(pending)}
+						consumer := &RedisStreamConsumerStatistics{Name: name, Pending: uint64(pending), Idle: idle[name]}
 						groupStats.Consumers = append(groupStats.Consumers, consumer)
 					}
 				}
@@ -103,7 +120,35 @@ func (eb *eventBroker) GetStreamsStatistics(stream ...string) []*RedisStreamStat
 			}
 		}
 	}
-	return results
+	return append(results, eb.getJetStreamStatistics(stream...)...)
+}
+
+// streamDeliveryRates keeps delivery samples in validated registry, so every engine sees the same sliding window
+type streamDeliveryRates struct {
+	mutex   sync.Mutex
+	samples map[string][]streamDeliverySample
+}
+
+func (r *streamDeliveryRates) getDeliveryRate(stream, group string, entriesRead int64, now time.Time) float64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.samples == nil {
+		r.samples = make(map[string][]streamDeliverySample)
+	}
+	key := stream + ":" + group
+	samples := append(r.samples[key], streamDeliverySample{time: now, entriesRead: entriesRead})
+	i := 0
+	for i < len(samples)-2 && now.Sub(samples[i+1].time) >= streamDeliveryRateWindow {
+		i++
+	}
+	samples = samples[i:]
+	r.samples[key] = samples
+	first := samples[0]
+	seconds := now.Sub(first.time).Seconds()
+	if seconds <= 0 || entriesRead < first.entriesRead {
+		return 0
+	}
+	return float64(entriesRead-first.entriesRead) / seconds
 }
 
 func idToSince(id string, now time.Time) (time.Duration, time.Time) {
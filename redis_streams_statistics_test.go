@@ -73,6 +73,8 @@ func TestRedisStreamsStatus(t *testing.T) {
 	assert.Equal(t, "test-group", statsSingle.Groups[0].Group)
 	assert.Equal(t, uint64(0), statsSingle.Groups[0].Pending)
 	assert.Len(t, statsSingle.Groups[0].Consumers, 0)
+	assert.Equal(t, int64(10001), statsSingle.Groups[0].EntriesRead)
+	assert.Greater(t, statsSingle.Groups[0].DeliveryRate, float64(0))
 
 	flusher.Publish("test-stream", testEvent{"a"})
 	flusher.Flush()
@@ -87,8 +89,29 @@ func TestRedisStreamsStatus(t *testing.T) {
 	for _, stream := range stats {
 		if stream.Stream == "test-stream" {
 			assert.Equal(t, uint64(1), stream.Groups[0].Pending)
+			assert.Len(t, stream.Groups[0].Consumers, 1)
+			assert.Equal(t, "consumer-1", stream.Groups[0].Consumers[0].Name)
+			assert.Greater(t, stream.Groups[0].Consumers[0].Idle, time.Duration(0))
 			valid = true
 		}
 	}
 	assert.True(t, valid)
 }
+
+func TestRedisStreamsDeliveryRate(t *testing.T) {
+	rates := &streamDeliveryRates{}
+	now := time.Now()
+	assert.Equal(t, float64(0), rates.getDeliveryRate("a", "b", 10, now))
+	assert.Equal(t, float64(10), rates.getDeliveryRate("a", "b", 110, now.Add(time.Second*10)))
+	assert.Equal(t, float64(5), rates.getDeliveryRate("a", "b", 210, now.Add(time.Second*40)))
+	assert.Equal(t, 4.4, rates.getDeliveryRate("a", "b", 230, now.Add(time.Second*50)))
+	assert.Equal(t, float64(2), rates.getDeliveryRate("a", "b", 240, now.Add(time.Second*75)))
+	assert.Len(t, rates.samples["a:b"], 4)
+	assert.Equal(t, float64(0), rates.getDeliveryRate("a", "c", 10, now))
+
+	registry := &validatedRegistry{}
+	first := &engineImplementation{registry: registry}
+	second := &engineImplementation{registry: registry}
+	assert.Equal(t, float64(0), first.registry.deliveryRates.getDeliveryRate("a", "b", 10, now))
+	assert.Equal(t, float64(10), second.registry.deliveryRates.getDeliveryRate("a", "b", 110, now.Add(time.Second*10)))
+}
@@ -0,0 +1,80 @@
+package beeorm
+
+import "time"
+
+type referenceBatchEntry struct {
+	binary []byte
+	loaded time.Time
+}
+
+func (e *engineImplementation) EnableReferenceBatching(window time.Duration) {
+	e.referenceBatchWindow = window
+	if e.referenceBatch == nil {
+		e.referenceBatch = make(map[*tableSchema]map[string]*referenceBatchEntry)
+	}
+}
+
+func (e *engineImplementation) DisableReferenceBatching() {
+	e.referenceBatchWindow = 0
+	e.referenceBatch = nil
+}
+
+func (e *engineImplementation) ClearReferenceBatch() {
+	if e.referenceBatch != nil {
+		e.referenceBatch = make(map[*tableSchema]map[string]*referenceBatchEntry)
+	}
+}
+
+func (e *engineImplementation) useReferenceBatch(serializer *serializer, dbMap map[string]map[*tableSchema]map[string][]Entity,
+	localMap map[string]map[string][]Entity, redisMap map[string]map[string][]Entity) map[string]Entity {
+	if e.referenceBatch == nil {
+		return nil
+	}
+	now := e.now()
+	toBatch := make(map[string]Entity)
+	for _, schemas := range dbMap {
+		for schema, keys := range schemas {
+			batch := e.referenceBatch[schema]
+			for key, refs := range keys {
+				entry, has := batch[key]
+				if !has || now.Sub(entry.loaded) >= e.referenceBatchWindow {
+					toBatch[key] = refs[0]
+					continue
+				}
+				if entry.binary != nil {
+					for _, r := range refs {
+						fillFromBinary(serializer, e.registry, entry.binary, r)
+					}
+				}
+				fillRef(key, localMap, redisMap, dbMap)
+			}
+		}
+	}
+	return toBatch
+}
+
+func (e *engineImplementation) addToReferenceBatch(entities map[string]Entity) {
+	if len(entities) == 0 || e.referenceBatch == nil {
+		return
+	}
+	now := e.now()
+	for key, entity := range entities {
+		orm := entity.getORM()
+		batch, has := e.referenceBatch[orm.tableSchema]
+		if !has {
+			batch = make(map[string]*referenceBatchEntry)
+			e.referenceBatch[orm.tableSchema] = batch
+		}
+		entry := &referenceBatchEntry{loaded: now}
+		if orm.loaded {
+			entry.binary = orm.copyBinary()
+		}
+		batch[key] = entry
+	}
+}
+
+func (e *engineImplementation) invalidateReferenceBatch(schema *tableSchema) {
+	if e.referenceBatch != nil {
+		delete(e.referenceBatch, schema)
+	}
+}
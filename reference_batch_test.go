@@ -0,0 +1,70 @@
+package beeorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type referenceBatchEntity struct {
+	ORM       `orm:"redisCache"`
+	ID        uint
+	Name      string
+	Reference *referenceBatchReference
+}
+
+type referenceBatchReference struct {
+	ORM  `orm:"redisCache"`
+	ID   uint
+	Name string
+}
+
+func TestReferenceBatching(t *testing.T) {
+	var entity *referenceBatchEntity
+	var reference *referenceBatchReference
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity, reference)
+	clock := &cronTestClock{now: time.Now()}
+	engine.SetClock(clock)
+	engine.Flush(&referenceBatchEntity{Name: "a", Reference: &referenceBatchReference{Name: "r1"}},
+		&referenceBatchEntity{Name: "b", Reference: &referenceBatchReference{ID: 1}},
+		&referenceBatchEntity{Name: "c", Reference: &referenceBatchReference{ID: 7}})
+
+	engine.EnableReferenceBatching(time.Second)
+	var rows []*referenceBatchEntity
+	engine.LoadByIDs([]uint64{1, 2, 3}, &rows, "Reference")
+	assert.Equal(t, "r1", rows[0].Reference.Name)
+	assert.Equal(t, "r1", rows[1].Reference.Name)
+	assert.False(t, rows[2].Reference.IsLoaded())
+
+	logger := &testLogHandler{}
+	engine.RegisterQueryLogger(logger, true, true, false)
+	engine.LoadByIDs([]uint64{2, 3}, &rows, "Reference")
+	assert.Len(t, logger.Logs, 1)
+	assert.Equal(t, "r1", rows[0].Reference.Name)
+	assert.False(t, rows[1].Reference.IsLoaded())
+
+	logger.clear()
+	clock.now = clock.now.Add(time.Second)
+	engine.LoadByIDs([]uint64{2}, &rows, "Reference")
+	assert.Len(t, logger.Logs, 2)
+	assert.Equal(t, "r1", rows[0].Reference.Name)
+
+	rows[0].Reference.Name = "r2"
+	engine.Flush(rows[0].Reference)
+	logger.clear()
+	engine.LoadByIDs([]uint64{1}, &rows, "Reference")
+	assert.Len(t, logger.Logs, 4)
+	assert.Equal(t, "r2", rows[0].Reference.Name)
+
+	logger.clear()
+	engine.ClearReferenceBatch()
+	engine.LoadByIDs([]uint64{1}, &rows, "Reference")
+	assert.Len(t, logger.Logs, 2)
+
+	logger.clear()
+	engine.DisableReferenceBatching()
+	engine.LoadByIDs([]uint64{1}, &rows, "Reference")
+	engine.LoadByIDs([]uint64{1}, &rows, "Reference")
+	assert.Len(t, logger.Logs, 4)
+}
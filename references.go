@@ -0,0 +1,167 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const referencesWildcard = "*"
+const referencesRecursiveWildcard = "**"
+const referencesPresetPrefix = "@"
+
+type referencesPreset struct {
+	entityType reflect.Type
+	references []string
+}
+
+type expandedReferences struct {
+	names []string
+	next  map[string][]string
+}
+
+// RegisterReferences registers named references that can be used as "@name" in any references argument.
+// Reference paths are separated with "/", "*" loads all one-to-one references of the current level and
+// "**" loads them recursively, stopping at entities that were already visited in the same path.
+func (r *Registry) RegisterReferences(name string, entity Entity, references ...string) {
+	if r.referencesPresets == nil {
+		r.referencesPresets = make(map[string]*referencesPreset)
+	}
+	t := reflect.TypeOf(entity)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	r.referencesPresets[name] = &referencesPreset{entityType: t, references: references}
+}
+
+func validateReferencesPresets(registry *validatedRegistry) error {
+	for name, preset := range registry.registry.referencesPresets {
+		schema := registry.tableSchemas[preset.entityType]
+		if schema == nil {
+			return fmt.Errorf("references '%s' entity '%s' is not registered", name, preset.entityType.String())
+		}
+		for _, reference := range preset.references {
+			if strings.Contains(reference, referencesPresetPrefix) {
+				return fmt.Errorf("references '%s' can't include other references %s", name, reference)
+			}
+		}
+		err := validateReferences(registry, schema, preset.references, nil)
+		if err != nil {
+			return fmt.Errorf("references '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
+func validateReferences(registry *validatedRegistry, schema *tableSchema, references []string, path []*tableSchema) error {
+	expanded, err := expandReferences(registry, schema, references, path)
+	if err != nil {
+		return err
+	}
+	path = append(path[:len(path):len(path)], schema)
+	for _, name := range expanded.names {
+		next := expanded.next[name]
+		if len(next) > 0 {
+			err = validateReferences(registry, getReferenceSchema(registry, schema, name), next, path)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func expandReferences(registry *validatedRegistry, schema *tableSchema, references []string, path []*tableSchema) (*expandedReferences, error) {
+	expanded := &expandedReferences{next: make(map[string][]string)}
+	add := func(name string, next string) {
+		current, has := expanded.next[name]
+		if !has {
+			expanded.names = append(expanded.names, name)
+		}
+		if next == "" {
+			expanded.next[name] = current
+			return
+		}
+		for _, existing := range current {
+			if existing == next {
+				return
+			}
+		}
+		expanded.next[name] = append(current, next)
+	}
+	for _, reference := range references {
+		refName := reference
+		next := ""
+		pos := strings.Index(refName, "/")
+		if pos > 0 {
+			next = refName[pos+1:]
+			refName = refName[0:pos]
+		}
+		switch {
+		case refName == referencesWildcard:
+			for _, name := range schema.refOne {
+				add(name, next)
+			}
+		case refName == referencesRecursiveWildcard:
+			if next != "" {
+				return nil, fmt.Errorf("reference %s in %s is not valid", reference, schema.tableName)
+			}
+			for _, name := range schema.refOne {
+				refSchema := getReferenceSchema(registry, schema, name)
+				cycle := refSchema == schema
+				for _, parent := range path {
+					if parent == refSchema {
+						cycle = true
+						break
+					}
+				}
+				if cycle {
+					add(name, "")
+				} else {
+					add(name, referencesRecursiveWildcard)
+				}
+			}
+		case strings.HasPrefix(refName, referencesPresetPrefix):
+			preset, has := registry.registry.referencesPresets[refName[1:]]
+			if !has {
+				return nil, fmt.Errorf("references '%s' not found", refName[1:])
+			}
+			if preset.entityType != schema.t {
+				return nil, fmt.Errorf("references '%s' are registered for %s", refName[1:], preset.entityType.String())
+			}
+			if next != "" {
+				return nil, fmt.Errorf("reference %s in %s is not valid", reference, schema.tableName)
+			}
+			presetExpanded, err := expandReferences(registry, schema, preset.references, path)
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range presetExpanded.names {
+				add(name, "")
+				for _, presetNext := range presetExpanded.next[name] {
+					add(name, presetNext)
+				}
+			}
+		default:
+			tags, has := schema.tags[refName]
+			if !has {
+				return nil, fmt.Errorf("reference %s in %s is not valid", reference, schema.tableName)
+			}
+			_, hasRef := tags["ref"]
+			_, hasRefs := tags["refs"]
+			if !hasRef && !hasRefs {
+				return nil, fmt.Errorf("reference tag %s is not valid", reference)
+			}
+			add(refName, next)
+		}
+	}
+	return expanded, nil
+}
+
+func getReferenceSchema(registry *validatedRegistry, schema *tableSchema, name string) *tableSchema {
+	refType, has := schema.tags[name]["ref"]
+	if !has {
+		refType = schema.tags[name]["refs"]
+	}
+	return registry.tableSchemas[registry.entities[refType]]
+}
@@ -0,0 +1,115 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type referencesCountry struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+type referencesAddress struct {
+	ORM
+	ID      uint
+	City    string
+	Country *referencesCountry
+}
+
+type referencesUser struct {
+	ORM
+	ID      uint
+	Name    string
+	Address *referencesAddress
+	Friend  *referencesUser
+}
+
+type referencesOrder struct {
+	ORM
+	ID     uint
+	Name   string
+	User   *referencesUser
+	Owner  *referencesUser
+	Others []*referencesUser
+}
+
+func TestReferencesWildcards(t *testing.T) {
+	var country *referencesCountry
+	var address *referencesAddress
+	var user *referencesUser
+	var order *referencesOrder
+	registry := &Registry{}
+	registry.RegisterReferences("details", order, "User/Address/Country", "Owner/*")
+	engine := prepareTables(t, registry, 5, 6, "", country, address, user, order)
+
+	country = &referencesCountry{Name: "Poland"}
+	address = &referencesAddress{City: "Warsaw", Country: country}
+	friend := &referencesUser{Name: "Friend", Address: address}
+	user = &referencesUser{Name: "Tom", Address: address, Friend: friend}
+	owner := &referencesUser{Name: "Owner", Address: &referencesAddress{City: "Berlin", Country: country}}
+	engine.Flush(country, address, friend, user, owner)
+	friend.Friend = user
+	engine.Flush(friend)
+	engine.Flush(&referencesOrder{Name: "order", User: user, Owner: owner, Others: []*referencesUser{friend}})
+
+	order = &referencesOrder{}
+	engine.LoadByID(1, order, "User/Address/Country")
+	assert.Equal(t, "Tom", order.User.Name)
+	assert.Equal(t, "Warsaw", order.User.Address.City)
+	assert.Equal(t, "Poland", order.User.Address.Country.Name)
+	assert.False(t, order.Owner.IsLoaded())
+
+	order = &referencesOrder{}
+	engine.LoadByID(1, order, "User/*")
+	assert.True(t, order.User.Address.IsLoaded())
+	assert.True(t, order.User.Friend.IsLoaded())
+	assert.False(t, order.User.Address.Country.IsLoaded())
+	assert.False(t, order.Owner.IsLoaded())
+
+	order = &referencesOrder{}
+	engine.LoadByID(1, order, "*", "Others")
+	assert.True(t, order.User.IsLoaded())
+	assert.True(t, order.Owner.IsLoaded())
+	assert.True(t, order.Others[0].IsLoaded())
+	assert.False(t, order.User.Address.IsLoaded())
+
+	order = &referencesOrder{}
+	engine.LoadByID(1, order, "**")
+	assert.Equal(t, "Poland", order.User.Address.Country.Name)
+	assert.Equal(t, "Poland", order.Owner.Address.Country.Name)
+	assert.Equal(t, "Friend", order.User.Friend.Name)
+	assert.False(t, order.User.Friend.Friend.IsLoaded())
+
+	order = &referencesOrder{}
+	engine.LoadByID(1, order, "@details")
+	assert.Equal(t, "Poland", order.User.Address.Country.Name)
+	assert.Equal(t, "Berlin", order.Owner.Address.City)
+	assert.False(t, order.Owner.Address.Country.IsLoaded())
+
+	dbLogger := &testLogHandler{}
+	engine.RegisterQueryLogger(dbLogger, true, false, false)
+	order = &referencesOrder{}
+	engine.LoadByID(1, order, "User/Address", "Owner/Address")
+	assert.Len(t, dbLogger.Logs, 3)
+	assert.Equal(t, "Berlin", order.Owner.Address.City)
+
+	assert.PanicsWithError(t, "reference Invalid in referencesUser is not valid", func() {
+		engine.LoadByID(1, &referencesOrder{}, "User/Invalid")
+	})
+	assert.PanicsWithError(t, "references 'missing' not found", func() {
+		engine.LoadByID(1, &referencesOrder{}, "@missing")
+	})
+	assert.PanicsWithError(t, "references 'details' are registered for beeorm.referencesOrder", func() {
+		engine.LoadByID(1, &referencesUser{}, "@details")
+	})
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(country, address, user, order)
+	registry.RegisterReferences("invalid", order, "User/Address/Invalid")
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "references 'invalid': reference Invalid in referencesAddress is not valid")
+}
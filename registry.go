@@ -18,15 +18,44 @@ import (
 )
 
 type Registry struct {
-	mysqlPools        map[string]MySQLPoolConfig
-	localCachePools   map[string]LocalCachePoolConfig
-	redisPools        map[string]RedisPoolConfig
-	entities          map[string]reflect.Type
-	enums             map[string]Enum
-	defaultEncoding   string
-	defaultCollate    string
-	redisStreamGroups map[string]map[string]map[string]bool
-	redisStreamPools  map[string]string
+	mysqlPools              map[string]MySQLPoolConfig
+	localCachePools         map[string]LocalCachePoolConfig
+	redisPools              map[string]RedisPoolConfig
+	entities                map[string]reflect.Type
+	enums                   map[string]Enum
+	defaultEncoding         string
+	defaultCollate          string
+	redisStreamGroups       map[string]map[string]map[string]bool
+	redisStreamPools        map[string]string
+	plugins                 []Plugin
+	jetStreamPools          map[string]JetStreamPoolConfig
+	jetStreamGroups         map[string]map[string]map[string]bool
+	jetStreamStreamPools    map[string]string
+	enforcePagination       bool
+	timestampsLocation      *time.Location
+	redisFailoverHandlers   []RedisFailoverHandler
+	validators              []EntityValidator
+	cacheCompressor         CacheCompressor
+	cacheCompressionMinSize int
+	jsonStringIDs           bool
+	objectStores            map[string]ObjectStore
+	strictEnums             bool
+	idGenerators            map[string]IDGenerator
+	intEnums                map[reflect.Type]*intEnum
+	columnNaming            ColumnNamingStrategy
+	referencesPresets       map[string]*referencesPreset
+	projections             map[reflect.Type]reflect.Type
+	indexers                map[string]*indexerDefinition
+	crudSubscribers         map[string]*crudSubscriberDefinition
+	webhooks                map[string]*WebhookEndpoint
+	cronJobs                map[string]*cronJob
+	redisScripts            map[string]*redisScript
+	payloadCodecs           map[string]PayloadCodec
+	payloadCodec            string
+	mysqlTimeouts           map[string]*mySQLTimeout
+	redisTimeouts           map[string]time.Duration
+	cacheDoubleDeleteDelay  time.Duration
+	cacheDoubleDeleteError  func(err error)
 }
 
 func NewRegistry() *Registry {
@@ -74,6 +103,10 @@ func (r *Registry) Validate() (validated ValidatedRegistry, err error) {
 		var waitTimeout int
 		err = db.QueryRow("SHOW VARIABLES LIKE 'wait_timeout'").Scan(&skip, &waitTimeout)
 		checkError(err)
+		var maxAllowedPacket int
+		err = db.QueryRow("SHOW VARIABLES LIKE 'max_allowed_packet'").Scan(&skip, &maxAllowedPacket)
+		checkError(err)
+		v.(*mySQLPoolConfig).maxAllowedPacket = maxAllowedPacket
 		maxConnections = int(math.Max(math.Floor(float64(maxConnections)*0.5), 1))
 		maxLimit := v.getMaxConnections()
 		if maxLimit == 0 {
@@ -125,6 +158,26 @@ func (r *Registry) Validate() (validated ValidatedRegistry, err error) {
 			hasLog = true
 		}
 	}
+	err = initProjections(r, registry)
+	if err != nil {
+		return nil, err
+	}
+	err = initIndexers(r, registry)
+	if err != nil {
+		return nil, err
+	}
+	err = initCrudSubscribers(r, registry)
+	if err != nil {
+		return nil, err
+	}
+	err = initPayloadCodecs(r, registry)
+	if err != nil {
+		return nil, err
+	}
+	err = initPoolTimeouts(r, registry)
+	if err != nil {
+		return nil, err
+	}
 	_, has := r.redisStreamPools[LazyChannelName]
 	if !has {
 		r.RegisterRedisStream(LazyChannelName, "default", []string{BackgroundConsumerGroupName})
@@ -143,6 +196,30 @@ func (r *Registry) Validate() (validated ValidatedRegistry, err error) {
 	}
 	registry.redisStreamGroups = r.redisStreamGroups
 	registry.redisStreamPools = r.redisStreamPools
+	registry.jetStreamServers = make(map[string]JetStreamPoolConfig)
+	for k, v := range r.jetStreamPools {
+		registry.jetStreamServers[k] = v
+	}
+	for name, pool := range r.jetStreamStreamPools {
+		_, has := registry.jetStreamServers[pool]
+		if !has {
+			return nil, fmt.Errorf("jet stream pool '%s' for stream '%s' not found", pool, name)
+		}
+	}
+	registry.jetStreamGroups = r.jetStreamGroups
+	registry.jetStreamStreamPools = r.jetStreamStreamPools
+	registry.plugins = r.plugins
+	for _, plugin := range r.plugins {
+		_, is := plugin.(PluginInterfaceEntityFlushed)
+		if is {
+			registry.hasFlushedPlugin = true
+		}
+		_, is = plugin.(PluginInterfaceEntityFlushedInTransaction)
+		if is {
+			registry.hasFlushedPlugin = true
+			registry.hasFlushedInTransactionPlugin = true
+		}
+	}
 	registry.defaultQueryLogger = &defaultLogLogger{maxPoolLen: maxPoolLen, logger: log.New(os.Stderr, "", 0)}
 	engine := registry.CreateEngine()
 	for _, schema := range registry.tableSchemas {
@@ -150,8 +227,16 @@ func (r *Registry) Validate() (validated ValidatedRegistry, err error) {
 		if err != nil {
 			return nil, errors.Wrapf(err, "invalid entity struct '%s'", schema.t.String())
 		}
+		schema.schemaFields, err = buildSchemaFields(schema, engine.(*engineImplementation))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid entity struct '%s'", schema.t.String())
+		}
 		schema.registry = registry
 	}
+	err = validateReferencesPresets(registry)
+	if err != nil {
+		return nil, err
+	}
 	return registry, nil
 }
 
@@ -159,6 +244,14 @@ func (r *Registry) SetDefaultEncoding(encoding string) {
 	r.defaultEncoding = encoding
 }
 
+func (r *Registry) EnforcePagination() {
+	r.enforcePagination = true
+}
+
+func (r *Registry) SetTimestampsLocation(location *time.Location) {
+	r.timestampsLocation = location
+}
+
 func (r *Registry) SetDefaultCollate(collate string) {
 	r.defaultCollate = collate
 }
@@ -248,8 +341,7 @@ func (r *Registry) RegisterRedisSentinelWithCredentials(masterName, namespace, u
 		Username:        user,
 		Password:        password,
 	}
-	client := redis.NewFailoverClient(options)
-	r.registerRedis(client, code, fmt.Sprintf("%v", sentinels), namespace, db)
+	r.registerRedisSentinel(options, code, namespace, db)
 }
 
 func (r *Registry) RegisterRedisSentinelWithOptions(namespace string, opts redis.FailoverOptions, db int, sentinels []string, code ...string) {
@@ -258,8 +350,11 @@ func (r *Registry) RegisterRedisSentinelWithOptions(namespace string, opts redis
 	if opts.ConnMaxIdleTime == 0 {
 		opts.ConnMaxIdleTime = time.Minute * 2
 	}
-	client := redis.NewFailoverClient(&opts)
-	r.registerRedis(client, code, fmt.Sprintf("%v", sentinels), namespace, db)
+	r.registerRedisSentinel(&opts, code, namespace, db)
+}
+
+func (r *Registry) RegisterRedisFailoverHandler(handler RedisFailoverHandler) {
+	r.redisFailoverHandlers = append(r.redisFailoverHandlers, handler)
 }
 
 func (r *Registry) RegisterRedisStream(name string, redisPool string, groups []string) {
@@ -268,6 +363,9 @@ func (r *Registry) RegisterRedisStream(name string, redisPool string, groups []s
 		r.redisStreamPools = make(map[string]string)
 	}
 	_, has := r.redisStreamPools[name]
+	if !has {
+		_, has = r.jetStreamStreamPools[name]
+	}
 	if has {
 		panic(fmt.Errorf("stream with name %s already exists", name))
 	}
@@ -299,21 +397,29 @@ func (r *Registry) registerSQLPool(dataSourceName string, code ...string) {
 	parts := strings.Split(dataSourceName, "/")
 	dbName := strings.Split(parts[len(parts)-1], "?")[0]
 
-	pos := strings.Index(dataSourceName, "limit_connections=")
-	if pos > 0 {
-		val := dataSourceName[pos+18:]
-		val = strings.Split(val, "&")[0]
-		db.maxConnections, _ = strconv.Atoi(val)
-		dataSourceName = strings.Replace(dataSourceName, "limit_connections="+val, "", -1)
-		dataSourceName = strings.Trim(dataSourceName, "?&")
-		dataSourceName = strings.Replace(dataSourceName, "?&", "?", -1)
-		db.dataSourceName = dataSourceName
-	}
+	db.maxConnections, dataSourceName = extractDataSourceParameter(dataSourceName, "limit_connections")
+	db.maxInSize, dataSourceName = extractDataSourceParameter(dataSourceName, "max_in_size")
+	db.dataSourceName = dataSourceName
 	db.databaseName = dbName
 	r.mysqlPools[dbCode] = db
 }
 
-func (r *Registry) registerRedis(client *redis.Client, code []string, address, namespace string, db int) {
+func extractDataSourceParameter(dataSourceName, name string) (int, string) {
+	pos := strings.Index(dataSourceName, name+"=")
+	if pos <= 0 {
+		return 0, dataSourceName
+	}
+	val := dataSourceName[pos+len(name)+1:]
+	val = strings.Split(val, "&")[0]
+	asInt, _ := strconv.Atoi(val)
+	dataSourceName = strings.Replace(dataSourceName, name+"="+val, "", -1)
+	dataSourceName = strings.Trim(dataSourceName, "?&")
+	dataSourceName = strings.Replace(dataSourceName, "?&", "?", -1)
+	dataSourceName = strings.Replace(dataSourceName, "&&", "&", -1)
+	return asInt, dataSourceName
+}
+
+func (r *Registry) registerRedis(client *redis.Client, code []string, address, namespace string, db int) *redisCacheConfig {
 	dbCode := "default"
 	if len(code) > 0 {
 		dbCode = code[0]
@@ -324,6 +430,17 @@ func (r *Registry) registerRedis(client *redis.Client, code []string, address, n
 		r.redisPools = make(map[string]RedisPoolConfig)
 	}
 	r.redisPools[dbCode] = redisCache
+	return redisCache
+}
+
+func (r *Registry) registerRedisSentinel(options *redis.FailoverOptions, code []string, namespace string, db int) {
+	dbCode := "default"
+	if len(code) > 0 {
+		dbCode = code[0]
+	}
+	sentinel := newRedisSentinel(r, dbCode, options)
+	client := redis.NewFailoverClient(options)
+	r.registerRedis(client, code, fmt.Sprintf("%v", options.SentinelAddrs), namespace, db).sentinel = sentinel
 }
 
 type RedisPoolConfig interface {
@@ -332,7 +449,9 @@ type RedisPoolConfig interface {
 	GetAddress() string
 	GetNamespace() string
 	HasNamespace() bool
+	GetMasterAddress() string
 	getClient() *redis.Client
+	getFailoverChannel() <-chan struct{}
 }
 
 type redisCacheConfig struct {
@@ -342,6 +461,8 @@ type redisCacheConfig struct {
 	address      string
 	namespace    string
 	hasNamespace bool
+	sentinel     *redisSentinel
+	timeoutHook  *redisTimeoutHook
 }
 
 func (p *redisCacheConfig) GetCode() string {
@@ -364,6 +485,20 @@ func (p *redisCacheConfig) HasNamespace() bool {
 	return p.hasNamespace
 }
 
+func (p *redisCacheConfig) GetMasterAddress() string {
+	if p.sentinel == nil {
+		return p.address
+	}
+	return p.sentinel.getMaster()
+}
+
 func (p *redisCacheConfig) getClient() *redis.Client {
 	return p.client
 }
+
+func (p *redisCacheConfig) getFailoverChannel() <-chan struct{} {
+	if p.sentinel == nil {
+		return nil
+	}
+	return p.sentinel.failoverChannel()
+}
@@ -0,0 +1,63 @@
+package beeorm
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const rewriteReferencesBatchSize = 1000
+
+func rewriteReferences(engine *engineImplementation, entity Entity, fromID, toID uint64) int {
+	schema := initIfNeeded(engine.registry, entity).tableSchema
+	total := 0
+	for t, columns := range schema.GetUsage(engine.registry) {
+		refSchema := getTableSchema(engine.registry, t)
+		for _, column := range columns {
+			total += rewriteReferenceColumn(engine, schema, refSchema, column, fromID, toID)
+		}
+	}
+	return total
+}
+
+func rewriteReferenceColumn(engine *engineImplementation, schema, refSchema *tableSchema, column string, fromID, toID uint64) int {
+	where := NewWhere("`"+refSchema.getColumnName(column)+"` = ?", fromID)
+	where.ShowFakeDeleted()
+	_, isField := refSchema.t.FieldByName(column)
+	total := 0
+	for {
+		ids, _ := searchIDs(engine, where, NewPager(1, rewriteReferencesBatchSize), false, refSchema.t)
+		if len(ids) == 0 {
+			return total
+		}
+		if isField {
+			rows := reflect.New(reflect.SliceOf(reflect.PtrTo(refSchema.t)))
+			engine.LoadByIDs(ids, rows.Interface())
+			flusher := engine.NewFlusher()
+			for i := 0; i < rows.Elem().Len(); i++ {
+				row := rows.Elem().Index(i)
+				if row.IsNil() {
+					continue
+				}
+				reference := schema.NewEntity()
+				reference.getORM().idElem.SetUint(toID)
+				row.Elem().FieldByName(column).Set(reflect.ValueOf(reference))
+				flusher.Track(row.Interface().(Entity))
+			}
+			flusher.Flush()
+		} else {
+			asStrings := make([]string, len(ids))
+			for i, id := range ids {
+				asStrings[i] = strconv.FormatUint(id, 10)
+			}
+			/* #nosec */
+			refSchema.GetMysql(engine).Exec("UPDATE `"+refSchema.tableName+"` SET `"+refSchema.getColumnName(column)+"` = ? WHERE `ID` IN ("+
+				strings.Join(asStrings, ",")+")", toID)
+			clearByIDs(engine, refSchema.NewEntity(), ids...)
+		}
+		total += len(ids)
+		if len(ids) < rewriteReferencesBatchSize {
+			return total
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rewriteReferencesUser struct {
+	ORM  `orm:"localCache"`
+	ID   uint
+	Name string
+}
+
+type rewriteReferencesAddress struct {
+	City  string
+	Owner *rewriteReferencesUser
+}
+
+type rewriteReferencesOrder struct {
+	ORM     `orm:"localCache;redisCache"`
+	ID      uint
+	User    *rewriteReferencesUser `orm:"index=User"`
+	Editor  *rewriteReferencesUser
+	Address rewriteReferencesAddress
+	ByUser  *CachedQuery `query:":User = ?"`
+}
+
+func TestRewriteReferences(t *testing.T) {
+	var user *rewriteReferencesUser
+	var order *rewriteReferencesOrder
+	engine := prepareTables(t, &Registry{}, 5, 6, "", user, order)
+
+	from := &rewriteReferencesUser{Name: "from"}
+	to := &rewriteReferencesUser{Name: "to"}
+	other := &rewriteReferencesUser{Name: "other"}
+	engine.Flush(from, to, other)
+	flusher := engine.NewFlusher()
+	flusher.Track(&rewriteReferencesOrder{User: from, Editor: other, Address: rewriteReferencesAddress{Owner: from}})
+	flusher.Track(&rewriteReferencesOrder{User: from, Editor: from})
+	flusher.Track(&rewriteReferencesOrder{User: other, Editor: other})
+	flusher.Flush()
+
+	var orders []*rewriteReferencesOrder
+	assert.Equal(t, 2, engine.CachedSearch(&orders, "ByUser", nil, from.ID))
+	assert.Equal(t, 0, engine.CachedSearch(&orders, "ByUser", nil, to.ID))
+	order = &rewriteReferencesOrder{}
+	engine.LoadByID(1, order)
+
+	assert.Equal(t, 4, engine.RewriteReferences(from, uint64(from.ID), uint64(to.ID)))
+	assert.Equal(t, 0, engine.CachedSearch(&orders, "ByUser", nil, from.ID))
+	assert.Equal(t, 2, engine.CachedSearch(&orders, "ByUser", nil, to.ID))
+	order = &rewriteReferencesOrder{}
+	assert.True(t, engine.LoadByID(1, order))
+	assert.Equal(t, to.ID, order.User.ID)
+	assert.Equal(t, other.ID, order.Editor.ID)
+	assert.Equal(t, to.ID, order.Address.Owner.ID)
+	order = &rewriteReferencesOrder{}
+	assert.True(t, engine.LoadByID(2, order))
+	assert.Equal(t, to.ID, order.Editor.ID)
+	order = &rewriteReferencesOrder{}
+	assert.True(t, engine.LoadByID(3, order))
+	assert.Equal(t, other.ID, order.User.ID)
+
+	assert.Equal(t, 0, engine.RewriteReferences(from, uint64(from.ID), uint64(to.ID)))
+}
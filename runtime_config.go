@@ -0,0 +1,90 @@
+package beeorm
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+type RuntimeConfig struct {
+	MySQLPools         map[string]*MySQLRuntimeConfig
+	QueryTimeLimit     *int
+	QueryLogSampleRate *float64
+	RedisCacheNilTTL   *time.Duration
+}
+
+type MySQLRuntimeConfig struct {
+	MaxOpenConnections int
+	MaxIdleConnections int
+	ConnMaxLifetime    time.Duration
+}
+
+type runtimeConfig struct {
+	queryTimeLimit   int32
+	logSkipRate      uint64
+	redisCacheNilTTL int64
+}
+
+func (r *validatedRegistry) ApplyRuntimeConfig(config RuntimeConfig) error {
+	for code, poolConfig := range config.MySQLPools {
+		if _, has := r.mySQLServers[code]; !has {
+			return fmt.Errorf("unregistered mysql pool '%s'", code)
+		}
+		if poolConfig.MaxOpenConnections < 0 || poolConfig.MaxIdleConnections < 0 || poolConfig.ConnMaxLifetime < 0 {
+			return fmt.Errorf("invalid runtime config for mysql pool '%s'", code)
+		}
+	}
+	if config.QueryTimeLimit != nil && (*config.QueryTimeLimit < 0 || *config.QueryTimeLimit > math.MaxUint16) {
+		return fmt.Errorf("invalid query time limit %d", *config.QueryTimeLimit)
+	}
+	if config.QueryLogSampleRate != nil && (*config.QueryLogSampleRate < 0 || *config.QueryLogSampleRate > 1) {
+		return fmt.Errorf("invalid query log sample rate %v", *config.QueryLogSampleRate)
+	}
+	if config.RedisCacheNilTTL != nil && *config.RedisCacheNilTTL < time.Second {
+		return fmt.Errorf("invalid redis cache nil TTL %s", config.RedisCacheNilTTL.String())
+	}
+	for code, poolConfig := range config.MySQLPools {
+		client := r.mySQLServers[code].getClient()
+		if client == nil {
+			continue
+		}
+		if poolConfig.MaxOpenConnections > 0 {
+			client.SetMaxOpenConns(poolConfig.MaxOpenConnections)
+		}
+		if poolConfig.MaxIdleConnections > 0 {
+			client.SetMaxIdleConns(poolConfig.MaxIdleConnections)
+		}
+		if poolConfig.ConnMaxLifetime > 0 {
+			client.SetConnMaxLifetime(poolConfig.ConnMaxLifetime)
+		}
+	}
+	if config.QueryTimeLimit != nil {
+		atomic.StoreInt32(&r.runtime.queryTimeLimit, int32(*config.QueryTimeLimit))
+	}
+	if config.QueryLogSampleRate != nil {
+		atomic.StoreUint64(&r.runtime.logSkipRate, math.Float64bits(1-*config.QueryLogSampleRate))
+	}
+	if config.RedisCacheNilTTL != nil {
+		atomic.StoreInt64(&r.runtime.redisCacheNilTTL, int64(config.RedisCacheNilTTL.Seconds()))
+	}
+	return nil
+}
+
+func (c *runtimeConfig) getQueryTimeLimit() uint16 {
+	return uint16(atomic.LoadInt32(&c.queryTimeLimit))
+}
+
+func (c *runtimeConfig) sampleLog(err error) bool {
+	skipRate := math.Float64frombits(atomic.LoadUint64(&c.logSkipRate))
+	return err != nil || skipRate == 0 || rand.Float64() >= skipRate
+}
+
+func (c *runtimeConfig) getRedisCacheNilTTL() int {
+	ttl := atomic.LoadInt64(&c.redisCacheNilTTL)
+	if ttl == 0 {
+		return 60
+	}
+	return int(ttl)
+}
@@ -0,0 +1,59 @@
+package beeorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type runtimeConfigEntity struct {
+	ORM  `orm:"redisCache"`
+	ID   uint
+	Name string
+}
+
+func TestApplyRuntimeConfig(t *testing.T) {
+	var entity *runtimeConfigEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	registry := engine.GetRegistry()
+
+	invalidTimeLimit := -1
+	assert.EqualError(t, registry.ApplyRuntimeConfig(RuntimeConfig{QueryTimeLimit: &invalidTimeLimit}), "invalid query time limit -1")
+	invalidRate := 1.5
+	assert.EqualError(t, registry.ApplyRuntimeConfig(RuntimeConfig{QueryLogSampleRate: &invalidRate}), "invalid query log sample rate 1.5")
+	invalidTTL := time.Millisecond
+	assert.EqualError(t, registry.ApplyRuntimeConfig(RuntimeConfig{RedisCacheNilTTL: &invalidTTL}), "invalid redis cache nil TTL 1ms")
+	assert.EqualError(t, registry.ApplyRuntimeConfig(RuntimeConfig{MySQLPools: map[string]*MySQLRuntimeConfig{"missing": {}}}),
+		"unregistered mysql pool 'missing'")
+
+	timeLimit := 7
+	rate := 0.0
+	nilTTL := time.Second * 30
+	err := registry.ApplyRuntimeConfig(RuntimeConfig{
+		MySQLPools:         map[string]*MySQLRuntimeConfig{"default": {MaxOpenConnections: 3, MaxIdleConnections: 1, ConnMaxLifetime: time.Minute}},
+		QueryTimeLimit:     &timeLimit,
+		QueryLogSampleRate: &rate,
+		RedisCacheNilTTL:   &nilTTL,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, engine.GetMysql().GetPoolConfig().getClient().Stats().MaxOpenConnections)
+
+	engine = registry.CreateEngine().(*engineImplementation)
+	assert.Equal(t, uint16(7), engine.queryTimeLimit)
+	dbLogger := &testLogHandler{}
+	engine.RegisterQueryLogger(dbLogger, true, true, false)
+	engine.GetMysql().Exec("SELECT 1")
+	assert.Len(t, dbLogger.Logs, 0)
+	assert.False(t, engine.LoadByID(100, &runtimeConfigEntity{}))
+	schema := registry.GetTableSchemaForEntity(entity).(*tableSchema)
+	ttl := engine.GetRedis().client.TTL(context.Background(), schema.getCacheKey(engine, 100)).Val()
+	assert.LessOrEqual(t, ttl, time.Second*30)
+	assert.Greater(t, ttl, time.Second*20)
+
+	rate = 1
+	assert.NoError(t, registry.ApplyRuntimeConfig(RuntimeConfig{QueryLogSampleRate: &rate}))
+	engine.GetMysql().Exec("SELECT 1")
+	assert.Len(t, dbLogger.Logs, 1)
+}
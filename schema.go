@@ -23,10 +23,12 @@ type indexDB struct {
 	KeyName   string
 	Seq       int
 	Column    string
+	IndexType string
 }
 
 type index struct {
 	Unique  bool
+	Spatial bool
 	Columns map[int]string
 }
 
@@ -49,6 +51,27 @@ func (a Alter) Exec() {
 	a.engine.GetMysql(a.Pool).Exec(a.SQL)
 }
 
+func getTableAlters(engine *engineImplementation, poolName, tableName, createSQL string) []Alter {
+	pool := engine.GetMysql(poolName)
+	var tableDef string
+	hasTable := pool.QueryRow(NewWhere(fmt.Sprintf("SHOW TABLES LIKE '%s'", tableName)), &tableDef)
+	if !hasTable {
+		return []Alter{{SQL: createSQL, Safe: true, Pool: poolName, engine: engine}}
+	}
+	var skip, createTableDB string
+	pool.QueryRow(NewWhere(fmt.Sprintf("SHOW CREATE TABLE `%s`", tableName)), &skip, &createTableDB)
+	createTableDB = strings.Replace(createTableDB, "CREATE TABLE ", fmt.Sprintf("CREATE TABLE `%s`.", pool.GetPoolConfig().GetDatabase()), 1) + ";"
+	re := regexp.MustCompile(" AUTO_INCREMENT=[0-9]+ ")
+	createTableDB = re.ReplaceAllString(createTableDB, " ")
+	if createSQL == createTableDB {
+		return nil
+	}
+	isEmpty := isTableEmptyInPool(engine, poolName, tableName)
+	dropTableSQL := fmt.Sprintf("DROP TABLE `%s`.`%s`;", pool.GetPoolConfig().GetDatabase(), tableName)
+	return []Alter{{SQL: dropTableSQL, Safe: isEmpty, Pool: poolName, engine: engine},
+		{SQL: createSQL, Safe: true, Pool: poolName, engine: engine}}
+}
+
 func getAlters(engine *engineImplementation) (alters []Alter) {
 	tablesInDB := make(map[string]map[string]bool)
 	tablesInEntities := make(map[string]map[string]bool)
@@ -73,8 +96,6 @@ func getAlters(engine *engineImplementation) (alters []Alter) {
 			has, newAlters := tableSchema.GetSchemaChanges(engine)
 			if tableSchema.hasLog {
 				logPool := engine.GetMysql(tableSchema.logPoolName)
-				var tableDef string
-				hasLogTable := logPool.QueryRow(NewWhere(fmt.Sprintf("SHOW TABLES LIKE '%s'", tableSchema.logTableName)), &tableDef)
 				var logTableSchema string
 				if logPool.GetPoolConfig().GetVersion() == 5 {
 					logTableSchema = fmt.Sprintf("CREATE TABLE `%s`.`%s` (\n  `id` bigint(11) unsigned NOT NULL AUTO_INCREMENT,\n  "+
@@ -88,21 +109,7 @@ func getAlters(engine *engineImplementation) (alters []Alter) {
 						logPool.GetPoolConfig().GetDatabase(), tableSchema.logTableName, engine.registry.registry.defaultCollate)
 				}
 
-				if !hasLogTable {
-					alters = append(alters, Alter{SQL: logTableSchema, Safe: true, Pool: tableSchema.logPoolName, engine: engine})
-				} else {
-					var skip, createTableDB string
-					logPool.QueryRow(NewWhere(fmt.Sprintf("SHOW CREATE TABLE `%s`", tableSchema.logTableName)), &skip, &createTableDB)
-					createTableDB = strings.Replace(createTableDB, "CREATE TABLE ", fmt.Sprintf("CREATE TABLE `%s`.", logPool.GetPoolConfig().GetDatabase()), 1) + ";"
-					re := regexp.MustCompile(" AUTO_INCREMENT=[0-9]+ ")
-					createTableDB = re.ReplaceAllString(createTableDB, " ")
-					if logTableSchema != createTableDB {
-						isEmpty := isTableEmptyInPool(engine, tableSchema.logPoolName, tableSchema.logTableName)
-						dropTableSQL := fmt.Sprintf("DROP TABLE `%s`.`%s`;", logPool.GetPoolConfig().GetDatabase(), tableSchema.logTableName)
-						alters = append(alters, Alter{SQL: dropTableSQL, Safe: isEmpty, Pool: tableSchema.logPoolName, engine: engine})
-						alters = append(alters, Alter{SQL: logTableSchema, Safe: true, Pool: tableSchema.logPoolName, engine: engine})
-					}
-				}
+				alters = append(alters, getTableAlters(engine, tableSchema.logPoolName, tableSchema.logTableName, logTableSchema)...)
 				tablesInEntities[tableSchema.logPoolName][tableSchema.logTableName] = true
 			}
 			if !has {
@@ -111,6 +118,15 @@ func getAlters(engine *engineImplementation) (alters []Alter) {
 			alters = append(alters, newAlters...)
 		}
 	}
+	for _, plugin := range engine.registry.plugins {
+		tablesPlugin, is := plugin.(PluginInterfaceSchemaTables)
+		if is {
+			for _, table := range tablesPlugin.PluginInterfaceSchemaTables(engine) {
+				alters = append(alters, getTableAlters(engine, table.Pool, table.Name, table.CreateSQL)...)
+				tablesInEntities[table.Pool][table.Name] = true
+			}
+		}
+	}
 
 	for poolName, tables := range tablesInDB {
 		for tableName := range tables {
@@ -212,11 +228,7 @@ func getSchemaChanges(engine *engineImplementation, tableSchema *tableSchema) (h
 	}
 
 	createTableSQL += "  PRIMARY KEY (`ID`)\n"
-	collate := ""
-	if pool.GetPoolConfig().GetVersion() == 8 {
-		collate += " COLLATE=" + engine.registry.registry.defaultEncoding + "_" + engine.registry.registry.defaultCollate
-	}
-	createTableSQL += fmt.Sprintf(") ENGINE=InnoDB DEFAULT CHARSET=%s%s;", engine.registry.registry.defaultEncoding, collate)
+	createTableSQL += fmt.Sprintf(") %s;", tableSchema.buildTableOptions(pool.GetPoolConfig().GetVersion(), engine.registry.registry))
 
 	var skip string
 	hasTable := pool.QueryRow(NewWhere(fmt.Sprintf("SHOW TABLES LIKE '%s'", tableSchema.tableName)), &skip)
@@ -243,13 +255,9 @@ func getSchemaChanges(engine *engineImplementation, tableSchema *tableSchema) (h
 	lines := strings.Split(createTableDB, "\n")
 	for x := 1; x < len(lines); x++ {
 		if lines[x][2] != 96 {
-			for _, field := range strings.Split(lines[x], " ") {
-				if strings.HasPrefix(field, "CHARSET=") {
-					if field[8:] != engine.registry.registry.defaultEncoding {
-						hasAlters = true
-						hasAlterEngineCharset = true
-					}
-				}
+			if strings.HasPrefix(lines[x], ")") && tableSchema.tableOptionsChanged(lines[x]) {
+				hasAlters = true
+				hasAlterEngineCharset = true
 			}
 			continue
 		}
@@ -259,6 +267,8 @@ func getSchemaChanges(engine *engineImplementation, tableSchema *tableSchema) (h
 		tableDBColumns = append(tableDBColumns, [2]string{columnName, line})
 	}
 
+	matchGeneratedColumns(columns, tableDBColumns)
+
 	var rows []indexDB
 	/* #nosec */
 	results, def := pool.Query(fmt.Sprintf("SHOW INDEXES FROM `%s`", tableSchema.tableName))
@@ -266,9 +276,9 @@ func getSchemaChanges(engine *engineImplementation, tableSchema *tableSchema) (h
 	for results.Next() {
 		var row indexDB
 		if pool.GetPoolConfig().GetVersion() == 5 {
-			results.Scan(&row.Skip, &row.NonUnique, &row.KeyName, &row.Seq, &row.Column, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip)
+			results.Scan(&row.Skip, &row.NonUnique, &row.KeyName, &row.Seq, &row.Column, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.IndexType, &row.Skip, &row.Skip)
 		} else {
-			results.Scan(&row.Skip, &row.NonUnique, &row.KeyName, &row.Seq, &row.Column, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip)
+			results.Scan(&row.Skip, &row.NonUnique, &row.KeyName, &row.Seq, &row.Column, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.Skip, &row.IndexType, &row.Skip, &row.Skip, &row.Skip, &row.Skip)
 		}
 		rows = append(rows, row)
 	}
@@ -277,7 +287,7 @@ func getSchemaChanges(engine *engineImplementation, tableSchema *tableSchema) (h
 	for _, value := range rows {
 		current, has := indexesDB[value.KeyName]
 		if !has {
-			current = &index{Unique: value.NonUnique == 0, Columns: map[int]string{value.Seq: value.Column}}
+			current = &index{Unique: value.NonUnique == 0, Spatial: value.IndexType == "SPATIAL", Columns: map[int]string{value.Seq: value.Column}}
 			indexesDB[value.KeyName] = current
 		} else {
 			current.Columns[value.Seq] = value.Column
@@ -486,11 +496,7 @@ OUTER:
 		}
 		alters = append(alters, Alter{SQL: alterSQL, Safe: safe, Pool: tableSchema.mysqlPoolName, engine: engine})
 	} else if hasAlterEngineCharset {
-		collate := ""
-		if pool.GetPoolConfig().GetVersion() == 8 {
-			collate += " COLLATE=" + engine.registry.registry.defaultEncoding + "_" + engine.registry.registry.defaultCollate
-		}
-		alterSQL += fmt.Sprintf(" ENGINE=InnoDB DEFAULT CHARSET=%s%s;", engine.registry.registry.defaultEncoding, collate)
+		alterSQL += fmt.Sprintf(" %s;", tableSchema.buildTableOptions(pool.GetPoolConfig().GetVersion(), engine.registry.registry))
 		alters = append(alters, Alter{SQL: alterSQL, Safe: true, Pool: tableSchema.mysqlPoolName, engine: engine})
 	}
 	if hasAlterRemoveForeignKey {
@@ -581,9 +587,9 @@ func checkColumn(engine *engineImplementation, schema *tableSchema, field *refle
 	addDefaultNullIfNullable := true
 	defaultValue := "nil"
 	var typeAsString = field.Type.String()
-	columnName := prefix + field.Name
+	columnName := schema.getColumnName(prefix + field.Name)
 
-	attributes := schema.tags[columnName]
+	attributes := schema.tags[prefix+field.Name]
 	version := schema.GetMysql(engine).GetPoolConfig().GetVersion()
 
 	_, has := attributes["ignore"]
@@ -602,9 +608,9 @@ func checkColumn(engine *engineImplementation, schema *tableSchema, field *refle
 				_, hasSkipFK := attributes["skip_FK"]
 				if !hasSkipFK {
 					pool := refOneSchema.GetMysql(engine)
-					foreignKey := &foreignIndex{Column: prefix + field.Name, Table: refOneSchema.tableName,
+					foreignKey := &foreignIndex{Column: columnName, Table: refOneSchema.tableName,
 						ParentDatabase: pool.GetPoolConfig().GetDatabase(), OnDelete: "RESTRICT"}
-					name := fmt.Sprintf("%s:%s:%s", pool.GetPoolConfig().GetDatabase(), schema.tableName, prefix+field.Name)
+					name := fmt.Sprintf("%s:%s:%s", pool.GetPoolConfig().GetDatabase(), schema.tableName, columnName)
 					foreignKeys[name] = foreignKey
 				}
 			}
@@ -624,10 +630,10 @@ func checkColumn(engine *engineImplementation, schema *tableSchema, field *refle
 				}
 				current, has := indexes[indexColumn[0]]
 				if !has {
-					current = &index{Unique: unique, Columns: map[int]string{location: prefix + field.Name}}
+					current = &index{Unique: unique, Columns: map[int]string{location: columnName}}
 					indexes[indexColumn[0]] = current
 				} else {
-					current.Columns[location] = prefix + field.Name
+					current.Columns[location] = columnName
 				}
 			}
 		}
@@ -702,6 +708,16 @@ func checkColumn(engine *engineImplementation, schema *tableSchema, field *refle
 		if err != nil {
 			return nil, err
 		}
+	case "*string":
+		enum, hasEnum := attributes["enum"]
+		if !hasEnum {
+			definition = "json"
+			break
+		}
+		definition, addNotNullIfNotSet, addDefaultNullIfNullable, defaultValue, err = handleSetEnum(version, engine.registry, "enum", enum, true)
+		if err != nil {
+			return nil, err
+		}
 	case "float32":
 		definition, addNotNullIfNotSet, defaultValue = handleFloat("float", attributes, false)
 	case "float64":
@@ -720,7 +736,23 @@ func checkColumn(engine *engineImplementation, schema *tableSchema, field *refle
 		return nil, nil
 	default:
 		kind := field.Type.Kind().String()
-		if kind == "struct" {
+		if enum, nullable := getIntEnum(engine.registry.registry, field.Type); enum != nil {
+			definition, err = enum.definition(version, engine.registry.registry, attributes)
+			if err != nil {
+				return nil, err
+			}
+			addNotNullIfNotSet = !nullable
+			addDefaultNullIfNullable = nullable
+			defaultValue = "nil"
+		} else if nullable, isCustom := getCustomColumnType(field.Type); isCustom {
+			definition, err = customColumnDefinition(version, *field, attributes)
+			if err != nil {
+				return nil, err
+			}
+			addNotNullIfNotSet = !nullable
+			addDefaultNullIfNullable = nullable
+			defaultValue = "nil"
+		} else if kind == "struct" {
 			subFieldPrefix := prefix
 			//if !field.Anonymous {
 			//	subFieldPrefix += field.Name
@@ -741,6 +773,23 @@ func checkColumn(engine *engineImplementation, schema *tableSchema, field *refle
 			definition = "json"
 		}
 	}
+	customDefault, hasCustomDefault := attributes["default"]
+	if hasCustomDefault {
+		if typeAsString == "bool" || typeAsString == "*bool" {
+			asBool, _ := strconv.ParseBool(customDefault)
+			customDefault = "0"
+			if asBool {
+				customDefault = "1"
+			}
+		}
+		defaultValue = "'" + strings.ReplaceAll(customDefault, "'", "''") + "'"
+	}
+	_, isGenerated := attributes["generated"]
+	if isGenerated {
+		definition = getGeneratedColumnDefinition(definition, attributes)
+		defaultValue = "nil"
+		addDefaultNullIfNullable = false
+	}
 	isNotNull := false
 	if addNotNullIfNotSet || isRequired {
 		definition += " NOT NULL"
@@ -751,7 +800,30 @@ func checkColumn(engine *engineImplementation, schema *tableSchema, field *refle
 	} else if !isNotNull && addDefaultNullIfNullable {
 		definition += " DEFAULT NULL"
 	}
-	return [][2]string{{columnName, fmt.Sprintf("`%s` %s", columnName, definition)}}, nil
+	definition += getColumnComment(attributes)
+	spatialIndex, hasSpatialIndex := attributes["spatial"]
+	if hasSpatialIndex {
+		if !isNotNull {
+			return nil, fmt.Errorf("spatial index requires not null field %s", columnName)
+		}
+		if spatialIndex == "true" {
+			spatialIndex = columnName
+		}
+		indexes[spatialIndex] = &index{Spatial: true, Columns: map[int]string{1: columnName}}
+	}
+	columns := [][2]string{{columnName, fmt.Sprintf("`%s` %s", columnName, definition)}}
+	jsonIndex, hasJSONIndex := attributes["jsonIndex"]
+	if hasJSONIndex {
+		if !strings.HasPrefix(definition, "json") {
+			return nil, fmt.Errorf("jsonIndex requires json column in field %s", columnName)
+		}
+		jsonColumns, err := getJSONIndexColumns(version, columnName, jsonIndex, indexes)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, jsonColumns...)
+	}
+	return columns, nil
 }
 
 func handleInt(version int, typeAsString string, attributes map[string]string, nullable bool) (string, bool, string) {
@@ -805,7 +877,26 @@ func handleString(version int, registry *validatedRegistry, attributes map[strin
 	if haSet {
 		return handleSetEnum(version, registry, "set", set, nullable)
 	}
+	_, isUUID := attributes["uuid"]
+	if isUUID || attributes["uuidStorage"] == "char" {
+		defaultValue := "nil"
+		if !nullable {
+			defaultValue = "''"
+		}
+		return "char(36) CHARACTER SET ascii COLLATE ascii_bin", !nullable, true, defaultValue, nil
+	}
 	length, hasLength := attributes["length"]
+	varbinary, hasVarbinary := attributes["varbinary"]
+	textStorage := getStringTextStorage(attributes)
+	storages := 0
+	for _, has := range []bool{hasLength && length != "max", hasVarbinary, textStorage != ""} {
+		if has {
+			storages++
+		}
+	}
+	if storages > 1 {
+		return "", false, false, "", fmt.Errorf("conflicting string storage tags")
+	}
 	if !hasLength {
 		length = "255"
 	}
@@ -814,12 +905,14 @@ func handleString(version int, registry *validatedRegistry, attributes map[strin
 	if !nullable {
 		defaultValue = "''"
 	}
-	if length == "max" {
-		definition = "mediumtext"
-		if version == 8 {
-			encoding := registry.registry.defaultEncoding
-			definition += " CHARACTER SET " + encoding + " COLLATE " + encoding + "_" + registry.registry.defaultCollate
+	if hasVarbinary {
+		i, err := strconv.Atoi(varbinary)
+		if err != nil || i < 1 || i > 65535 {
+			return "", false, false, "", fmt.Errorf("invalid varbinary length: %s", varbinary)
 		}
+		definition = fmt.Sprintf("varbinary(%d)", i)
+	} else if textStorage != "" {
+		definition = textStorage + getColumnCharset(version, registry, attributes)
 		addDefaultNullIfNullable = false
 		defaultValue = "nil"
 	} else {
@@ -827,12 +920,7 @@ func handleString(version int, registry *validatedRegistry, attributes map[strin
 		if err != nil || i > 65535 {
 			return "", false, false, "", fmt.Errorf("invalid max string: %s", length)
 		}
-		if version == 5 {
-			definition = fmt.Sprintf("varchar(%s)", strconv.Itoa(i))
-		} else {
-			definition = fmt.Sprintf("varchar(%s) CHARACTER SET %s COLLATE %s_"+registry.registry.defaultCollate, strconv.Itoa(i),
-				registry.registry.defaultEncoding, registry.registry.defaultEncoding)
-		}
+		definition = fmt.Sprintf("varchar(%s)", strconv.Itoa(i)) + getColumnCharset(version, registry, attributes)
 	}
 	return definition, !nullable, addDefaultNullIfNullable, defaultValue, nil
 }
@@ -957,7 +1045,7 @@ func checkStruct(tableSchema *tableSchema, engine *engineImplementation, t refle
 			for k, v := range tableSchema.uniqueIndicesGlobal {
 				current := &index{Unique: true, Columns: map[int]string{}}
 				for i, l := range v {
-					current.Columns[i+1] = l
+					current.Columns[i+1] = tableSchema.getColumnName(l)
 				}
 				indexes[k] = current
 			}
@@ -995,6 +1083,20 @@ func buildCreateIndexSQL(keyName string, definition *index) string {
 	indexType := "INDEX"
 	if definition.Unique {
 		indexType = "UNIQUE " + indexType
+	} else if definition.Spatial {
+		indexType = "SPATIAL " + indexType
 	}
 	return fmt.Sprintf("ADD %s `%s` (%s)", indexType, keyName, strings.Join(indexColumns, ","))
 }
+
+func getStringTextStorage(attributes map[string]string) string {
+	if attributes["length"] == "max" {
+		return "mediumtext"
+	}
+	for _, storage := range []string{"tinytext", "text", "mediumtext", "longtext"} {
+		if attributes[storage] == "true" {
+			return storage
+		}
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package beeorm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type SchemaDrift struct {
+	Pool      string
+	Table     string
+	Operation string
+	SQL       string
+	Safe      bool
+}
+
+type SchemaDriftReport struct {
+	CheckedAt time.Time
+	Drifts    []SchemaDrift
+}
+
+func (r *SchemaDriftReport) HasDrift() bool {
+	return len(r.Drifts) > 0
+}
+
+func (e *engineImplementation) CheckSchemaDrift() *SchemaDriftReport {
+	report := &SchemaDriftReport{CheckedAt: time.Now().UTC(), Drifts: make([]SchemaDrift, 0)}
+	for _, alter := range getAlters(e) {
+		item := newAlterPlanItem(alter)
+		report.Drifts = append(report.Drifts, SchemaDrift{Pool: alter.Pool, Table: item.Table, Operation: item.Operation,
+			SQL: alter.SQL, Safe: alter.Safe})
+	}
+	return report
+}
+
+type SchemaDriftChecker struct {
+	engine   Engine
+	interval time.Duration
+	stream   string
+	report   *SchemaDriftReport
+	mutex    sync.RWMutex
+}
+
+func NewSchemaDriftChecker(engine Engine, interval time.Duration) *SchemaDriftChecker {
+	return &SchemaDriftChecker{engine: engine, interval: interval}
+}
+
+func (c *SchemaDriftChecker) SetStream(stream string) {
+	c.stream = stream
+}
+
+func (c *SchemaDriftChecker) Run(ctx context.Context) {
+	c.Check()
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Check()
+		}
+	}
+}
+
+func (c *SchemaDriftChecker) Check() *SchemaDriftReport {
+	report := c.engine.CheckSchemaDrift()
+	c.mutex.Lock()
+	c.report = report
+	c.mutex.Unlock()
+	if report.HasDrift() && c.stream != "" {
+		c.engine.GetEventBroker().Publish(c.stream, report)
+	}
+	return report
+}
+
+func (c *SchemaDriftChecker) GetLastReport() *SchemaDriftReport {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.report
+}
+
+func (c *SchemaDriftChecker) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	report := c.GetLastReport()
+	w.Header().Set("Content-Type", "application/json")
+	if report == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"status":"unknown"}`))
+		return
+	}
+	status := "ok"
+	if report.HasDrift() {
+		status = "drift"
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	body, _ := json.Marshal(map[string]interface{}{"status": status, "checked_at": report.CheckedAt, "drifts": report.Drifts})
+	_, _ = w.Write(body)
+}
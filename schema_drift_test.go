@@ -0,0 +1,67 @@
+package beeorm
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type schemaDriftEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestSchemaDrift(t *testing.T) {
+	var entity *schemaDriftEntity
+	registry := &Registry{}
+	registry.RegisterRedisStream("schema-drift", "default", []string{"schema-drift-group"})
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	engine.GetRedis().FlushDB()
+
+	report := engine.CheckSchemaDrift()
+	assert.False(t, report.HasDrift())
+	assert.False(t, report.CheckedAt.IsZero())
+
+	checker := NewSchemaDriftChecker(engine, 0)
+	checker.SetStream("schema-drift")
+	recorder := httptest.NewRecorder()
+	checker.ServeHTTP(recorder, httptest.NewRequest("GET", "/health/schema", nil))
+	assert.Equal(t, 503, recorder.Code)
+	checker.Check()
+	recorder = httptest.NewRecorder()
+	checker.ServeHTTP(recorder, httptest.NewRequest("GET", "/health/schema", nil))
+	assert.Equal(t, 200, recorder.Code)
+	body, _ := io.ReadAll(recorder.Result().Body)
+	assert.Contains(t, string(body), `"status":"ok"`)
+	assert.Equal(t, int64(0), engine.GetRedis().XLen("schema-drift"))
+
+	engine.GetMysql().Exec("ALTER TABLE `schemaDriftEntity` ADD COLUMN `Hotfix` int(11) DEFAULT NULL")
+	report = checker.Check()
+	assert.True(t, report.HasDrift())
+	assert.Len(t, report.Drifts, 1)
+	assert.Equal(t, "default", report.Drifts[0].Pool)
+	assert.Equal(t, "schemaDriftEntity", report.Drifts[0].Table)
+	assert.Equal(t, AlterOperationAlter, report.Drifts[0].Operation)
+	assert.Equal(t, "ALTER TABLE `test`.`schemaDriftEntity`\n    DROP COLUMN `Hotfix`;", report.Drifts[0].SQL)
+	assert.Same(t, report, checker.GetLastReport())
+	assert.Equal(t, int64(1), engine.GetRedis().XLen("schema-drift"))
+	recorder = httptest.NewRecorder()
+	checker.ServeHTTP(recorder, httptest.NewRequest("GET", "/health/schema", nil))
+	assert.Equal(t, 503, recorder.Code)
+	body, _ = io.ReadAll(recorder.Result().Body)
+	assert.Contains(t, string(body), `"status":"drift"`)
+
+	consumer := engine.GetEventBroker().Consumer("schema-drift-group")
+	consumer.DisableBlockMode()
+	consumer.Consume(context.Background(), 10, func(events []Event) {
+		assert.Len(t, events, 1)
+		received := &SchemaDriftReport{}
+		events[0].Unserialize(received)
+		assert.Len(t, received.Drifts, 1)
+		assert.Equal(t, "schemaDriftEntity", received.Drifts[0].Table)
+	})
+}
@@ -0,0 +1,152 @@
+package beeorm
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var schemaFieldTypeModifiers = []string{" CHARACTER SET ", " COLLATE ", " NOT NULL", " DEFAULT ", " GENERATED ALWAYS AS (", " COMMENT '"}
+
+type SchemaField struct {
+	Name          string
+	Column        string
+	GoType        reflect.Type
+	SQLType       string
+	Nullable      bool
+	EnumValues    []string
+	Indexes       []SchemaFieldIndex
+	Reference     reflect.Type
+	ReferenceMany bool
+	Tags          map[string]string
+}
+
+type SchemaFieldIndex struct {
+	Name     string
+	Unique   bool
+	Spatial  bool
+	Position int
+}
+
+func (tableSchema *tableSchema) Fields() []SchemaField {
+	fields := make([]SchemaField, len(tableSchema.schemaFields))
+	copy(fields, tableSchema.schemaFields)
+	return fields
+}
+
+func buildSchemaFields(schema *tableSchema, engine *engineImplementation) ([]SchemaField, error) {
+	indexes := make(map[string]*index)
+	columns, err := checkStruct(schema, engine, schema.t, indexes, make(map[string]*foreignIndex), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	definitions := make(map[string]string, len(columns))
+	for _, column := range columns {
+		definitions[column[0]] = strings.TrimPrefix(column[1], "`"+column[0]+"` ")
+	}
+	indexNames := make([]string, 0, len(indexes))
+	for name := range indexes {
+		indexNames = append(indexNames, name)
+	}
+	sort.Strings(indexNames)
+	columnIndexes := make(map[string][]SchemaFieldIndex)
+	for _, name := range indexNames {
+		definition := indexes[name]
+		for position, column := range definition.Columns {
+			columnIndexes[column] = append(columnIndexes[column], SchemaFieldIndex{Name: name, Unique: definition.Unique,
+				Spatial: definition.Spatial, Position: position})
+		}
+	}
+	fields := make([]SchemaField, 0, len(columns))
+	return appendSchemaFields(fields, schema, engine.registry, schema.t, definitions, columnIndexes, nil, ""), nil
+}
+
+func appendSchemaFields(fields []SchemaField, schema *tableSchema, registry *validatedRegistry, t reflect.Type,
+	definitions map[string]string, columnIndexes map[string][]SchemaFieldIndex, subField *reflect.StructField, prefix string) []SchemaField {
+	if subField != nil && !subField.Anonymous {
+		prefix += subField.Name
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if i == 0 && subField == nil {
+			continue
+		}
+		field := t.Field(i)
+		name := prefix + field.Name
+		attributes := schema.tags[name]
+		if _, ignored := attributes["ignore"]; ignored {
+			continue
+		}
+		if isSchemaSubStruct(registry, field.Type) {
+			fields = appendSchemaFields(fields, schema, registry, field.Type, definitions, columnIndexes, &field, prefix)
+			continue
+		}
+		column := schema.getColumnName(name)
+		definition, has := definitions[column]
+		if !has {
+			continue
+		}
+		sqlType, nullable := splitColumnDefinition(definition)
+		schemaField := SchemaField{Name: name, Column: column, GoType: field.Type, SQLType: sqlType, Nullable: nullable,
+			EnumValues: getSchemaFieldEnumValues(registry, field.Type, attributes), Indexes: columnIndexes[column]}
+		if field.Type.Kind() == reflect.Ptr && getTableSchema(registry, field.Type.Elem()) != nil {
+			schemaField.Reference = field.Type.Elem()
+		} else if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Ptr &&
+			getTableSchema(registry, field.Type.Elem().Elem()) != nil {
+			schemaField.Reference = field.Type.Elem().Elem()
+			schemaField.ReferenceMany = true
+		}
+		if len(attributes) > 0 {
+			schemaField.Tags = make(map[string]string, len(attributes))
+			for key, value := range attributes {
+				schemaField.Tags[key] = value
+			}
+		}
+		fields = append(fields, schemaField)
+	}
+	return fields
+}
+
+func isSchemaSubStruct(registry *validatedRegistry, t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.String() == "time.Time" {
+		return false
+	}
+	if enum, _ := getIntEnum(registry.registry, t); enum != nil {
+		return false
+	}
+	_, isCustom := getCustomColumnType(t)
+	return !isCustom
+}
+
+func splitColumnDefinition(definition string) (sqlType string, nullable bool) {
+	end := len(definition)
+	for _, modifier := range schemaFieldTypeModifiers {
+		position := strings.Index(definition, modifier)
+		if position >= 0 && position < end {
+			end = position
+		}
+	}
+	comment := strings.Index(definition, " COMMENT '")
+	if comment < 0 {
+		comment = len(definition)
+	}
+	return definition[:end], !strings.Contains(definition[:comment], " NOT NULL")
+}
+
+func getSchemaFieldEnumValues(registry *validatedRegistry, t reflect.Type, attributes map[string]string) []string {
+	for _, key := range []string{"enum", "set"} {
+		name, has := attributes[key]
+		if has && registry.enums[name] != nil {
+			values := registry.enums[name].GetFields()
+			return append(make([]string, 0, len(values)), values...)
+		}
+	}
+	enum, _ := getIntEnum(registry.registry, t)
+	if enum == nil {
+		return nil
+	}
+	values := make([]string, len(enum.keys))
+	for i, key := range enum.keys {
+		values[i] = enum.names[key]
+	}
+	return values
+}
@@ -0,0 +1,94 @@
+package beeorm
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type schemaFieldsEntity struct {
+	ORM
+	ID         uint
+	Name       string `orm:"unique=Name;required"`
+	Age        *uint8 `orm:"index=AgeColor"`
+	Color      string `orm:"enum=beeorm.schemaFieldsColor;index=AgeColor:2"`
+	Status     testIntEnumStatus
+	Address    schemaFieldsAddress
+	Reference  *schemaFieldsReference
+	References []*schemaFieldsReference
+	ByName     *CachedQuery `queryOne:":Name = ?"`
+	Ignored    string       `orm:"ignore"`
+}
+
+type schemaFieldsAddress struct {
+	City string `orm:"length=100"`
+}
+
+type schemaFieldsReference struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestSchemaFields(t *testing.T) {
+	var entity *schemaFieldsEntity
+	var reference *schemaFieldsReference
+	registry := &Registry{}
+	registry.RegisterEnum("beeorm.schemaFieldsColor", []string{"red", "blue"})
+	registry.RegisterIntEnum(map[testIntEnumStatus]string{testIntEnumStatusActive: "active", testIntEnumStatusInactive: "inactive"})
+	engine := prepareTables(t, registry, 5, 6, "", entity, reference)
+
+	fields := engine.GetRegistry().GetTableSchemaForEntity(entity).Fields()
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	assert.Equal(t, []string{"ID", "Name", "Age", "Color", "Status", "AddressCity", "Reference", "References"}, names)
+
+	assert.Equal(t, "ID", fields[0].Column)
+	assert.Equal(t, reflect.TypeOf(uint(0)), fields[0].GoType)
+	assert.Equal(t, "int(10) unsigned", fields[0].SQLType)
+	assert.False(t, fields[0].Nullable)
+
+	assert.Equal(t, "varchar(255)", fields[1].SQLType)
+	assert.False(t, fields[1].Nullable)
+	assert.Equal(t, []SchemaFieldIndex{{Name: "Name", Unique: true, Position: 1}}, fields[1].Indexes)
+	assert.Equal(t, "true", fields[1].Tags["required"])
+
+	assert.Equal(t, "tinyint(3) unsigned", fields[2].SQLType)
+	assert.True(t, fields[2].Nullable)
+	assert.Equal(t, []SchemaFieldIndex{{Name: "AgeColor", Position: 1}}, fields[2].Indexes)
+
+	assert.Equal(t, "enum('red','blue')", fields[3].SQLType)
+	assert.Equal(t, []string{"red", "blue"}, fields[3].EnumValues)
+	assert.Equal(t, []SchemaFieldIndex{{Name: "AgeColor", Position: 2}}, fields[3].Indexes)
+
+	assert.Equal(t, []string{"active", "inactive"}, fields[4].EnumValues)
+
+	assert.Equal(t, "varchar(100)", fields[5].SQLType)
+	assert.Equal(t, reflect.TypeOf(""), fields[5].GoType)
+
+	assert.Equal(t, reflect.TypeOf(schemaFieldsReference{}), fields[6].Reference)
+	assert.False(t, fields[6].ReferenceMany)
+	assert.True(t, fields[6].Nullable)
+	assert.Len(t, fields[6].Indexes, 1)
+	assert.Equal(t, reflect.TypeOf(schemaFieldsReference{}), fields[7].Reference)
+	assert.True(t, fields[7].ReferenceMany)
+	assert.Equal(t, "json", fields[7].SQLType)
+
+	fields[0].Name = "changed"
+	assert.Equal(t, "ID", engine.GetRegistry().GetTableSchemaForEntity(entity).Fields()[0].Name)
+}
+
+func TestSplitColumnDefinition(t *testing.T) {
+	sqlType, nullable := splitColumnDefinition("varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT ''")
+	assert.Equal(t, "varchar(255)", sqlType)
+	assert.False(t, nullable)
+	sqlType, nullable = splitColumnDefinition("int unsigned DEFAULT NULL COMMENT 'NOT NULL'")
+	assert.Equal(t, "int unsigned", sqlType)
+	assert.True(t, nullable)
+	sqlType, nullable = splitColumnDefinition("json")
+	assert.Equal(t, "json", sqlType)
+	assert.True(t, nullable)
+}
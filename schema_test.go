@@ -41,6 +41,24 @@ type schemaInvalidMaxStringEntity struct {
 	Name string `orm:"length=invalid"`
 }
 
+type schemaStringStorageEntity struct {
+	ORM
+	ID         uint
+	Long       string `orm:"length=2000"`
+	Tiny       string `orm:"tinytext"`
+	Text       string `orm:"text;required"`
+	Medium     string `orm:"mediumtext"`
+	LongText   string `orm:"longtext"`
+	Binary     string `orm:"varbinary=64;required"`
+	BinaryNull string `orm:"varbinary=32"`
+}
+
+type schemaStringStorageConflictEntity struct {
+	ORM
+	ID   uint
+	Name string `orm:"length=100;text"`
+}
+
 type schemaToDropEntity struct {
 	ORM `orm:"log"`
 	ID  uint
@@ -394,3 +412,41 @@ func testSchema(t *testing.T, version int) {
 	_, err = registry.Validate()
 	assert.EqualError(t, err, "missing index for cached query 'IndexName' in beeorm.invalidSchema9")
 }
+
+func TestSchemaStringStorage(t *testing.T) {
+	engine := prepareTables(t, &Registry{}, 5, 6, "", &schemaStringStorageEntity{})
+	schema := engine.GetRegistry().GetTableSchemaForEntity(&schemaStringStorageEntity{})
+	assert.Len(t, engine.GetAlters(), 0)
+	schema.DropTable(engine)
+	has, alters := schema.GetSchemaChanges(engine)
+	assert.True(t, has)
+	assert.Len(t, alters, 1)
+	assert.Equal(t, "CREATE TABLE `test`.`schemaStringStorageEntity` (\n  `ID` int(10) unsigned NOT NULL AUTO_INCREMENT,\n  "+
+		"`Long` varchar(2000) DEFAULT NULL,\n  `Tiny` tinytext,\n  `Text` text NOT NULL,\n  `Medium` mediumtext,\n  `LongText` longtext,\n  "+
+		"`Binary` varbinary(64) NOT NULL DEFAULT '',\n  `BinaryNull` varbinary(32) DEFAULT NULL,\n  PRIMARY KEY (`ID`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;", alters[0].SQL)
+	schema.UpdateSchema(engine)
+	assert.Len(t, engine.GetAlters(), 0)
+
+	entity := &schemaStringStorageEntity{Text: "text", Binary: "\x00\x01binary"}
+	engine.Flush(entity)
+	entity = &schemaStringStorageEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "\x00\x01binary", entity.Binary)
+
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&schemaStringStorageConflictEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "invalid entity struct 'beeorm.schemaStringStorageConflictEntity': conflicting string storage tags")
+
+	type invalidVarbinary struct {
+		ORM
+		ID   uint
+		Name string `orm:"varbinary=invalid"`
+	}
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&invalidVarbinary{})
+	_, err = registry.Validate()
+	assert.EqualError(t, err, "invalid entity struct 'beeorm.invalidVarbinary': invalid varbinary length: invalid")
+}
@@ -2,6 +2,7 @@ package beeorm
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -119,6 +120,11 @@ func prepareScanForFields(fields *tableFields, start int, pointers []interface{}
 		pointers[start] = &v
 		start++
 	}
+	for range fields.customs {
+		v := sql.NullString{}
+		pointers[start] = &v
+		start++
+	}
 	for _, subFields := range fields.structsFields {
 		start = prepareScanForFields(subFields, start, pointers)
 	}
@@ -131,6 +137,8 @@ func searchRow(serializer *serializer, engine *engineImplementation, where *Wher
 	whereQuery := where.String()
 	if !where.showFakeDeleted && schema.hasFakeDelete {
 		whereQuery = "`FakeDelete` = 0 AND " + whereQuery
+	} else if !where.showFakeDeleted && schema.hasSoftDelete {
+		whereQuery = "`" + schema.getColumnName(schema.softDeleteColumn) + "` IS NULL AND " + whereQuery
 	}
 	/* #nosec */
 	query := "SELECT " + schema.fieldsQuery + " FROM `" + schema.tableName + "` WHERE " + whereQuery + " LIMIT 1"
@@ -146,6 +154,7 @@ func searchRow(serializer *serializer, engine *engineImplementation, where *Wher
 	def()
 	id := *pointers[schema.idIndex].(*uint64)
 	fillFromDBRow(serializer, id, engine.registry, pointers, entity)
+	engine.entityLoaded(entity)
 	if len(references) > 0 {
 		warmUpReferences(serializer, engine, schema, entity.getORM().value, references, false)
 	}
@@ -154,6 +163,9 @@ func searchRow(serializer *serializer, engine *engineImplementation, where *Wher
 
 func search(serializer *serializer, engine *engineImplementation, where *Where, pager *Pager, withCount, checkIsSlice bool, entities reflect.Value, references ...string) (totalRows int) {
 	if pager == nil {
+		if engine.pagerRequired {
+			panic(errors.New("pager is required"))
+		}
 		pager = NewPager(1, 50000)
 	}
 	entities.SetLen(0)
@@ -166,6 +178,9 @@ func search(serializer *serializer, engine *engineImplementation, where *Where,
 	if !where.showFakeDeleted && schema.hasFakeDelete {
 		whereQuery = "`FakeDelete` = 0 AND " + whereQuery
 		where = NewWhere(whereQuery, where.parameters)
+	} else if !where.showFakeDeleted && schema.hasSoftDelete {
+		whereQuery = "`" + schema.getColumnName(schema.softDeleteColumn) + "` IS NULL AND " + whereQuery
+		where = NewWhere(whereQuery, where.parameters)
 	}
 	/* #nosec */
 	query := "SELECT " + schema.fieldsQuery + " FROM `" + schema.tableName + "` WHERE " + whereQuery + " " + pager.String()
@@ -181,11 +196,23 @@ func search(serializer *serializer, engine *engineImplementation, where *Where,
 		results.Scan(pointers...)
 		value := reflect.New(entityType)
 		id := *pointers[schema.idIndex].(*uint64)
-		fillFromDBRow(serializer, id, engine.registry, pointers, value.Interface().(Entity))
+		mapped, isMapped := engine.getFromIdentityMap(schema, id)
+		if isMapped {
+			value = mapped.getORM().value
+		} else {
+			fillFromDBRow(serializer, id, engine.registry, pointers, value.Interface().(Entity))
+			engine.entityLoaded(value.Interface().(Entity))
+		}
 		val = reflect.Append(val, value)
 		i++
+		if engine.queryResultLimit > 0 {
+			engine.checkQueryResultLimit(schema, i, false)
+		}
 	}
 	def()
+	if engine.queryResultLimit > 0 {
+		engine.checkQueryResultLimit(schema, i, true)
+	}
 	totalRows = getTotalRows(engine, withCount, pager, where, schema, i)
 	if len(references) > 0 && i > 0 {
 		warmUpReferences(serializer, engine, schema, val, references, true)
@@ -200,6 +227,9 @@ func searchOne(serializer *serializer, engine *engineImplementation, where *Wher
 
 func searchIDs(engine *engineImplementation, where *Where, pager *Pager, withCount bool, entityType reflect.Type) (ids []uint64, total int) {
 	if pager == nil {
+		if engine.pagerRequired {
+			panic(errors.New("pager is required"))
+		}
 		pager = NewPager(1, 50000)
 	}
 	schema := getTableSchema(engine.registry, entityType)
@@ -208,6 +238,10 @@ func searchIDs(engine *engineImplementation, where *Where, pager *Pager, withCou
 		/* #nosec */
 		whereQuery = "`FakeDelete` = 0 AND " + whereQuery
 		where = NewWhere(whereQuery, where.parameters)
+	} else if !where.showFakeDeleted && schema.hasSoftDelete {
+		/* #nosec */
+		whereQuery = "`" + schema.getColumnName(schema.softDeleteColumn) + "` IS NULL AND " + whereQuery
+		where = NewWhere(whereQuery, where.parameters)
 	}
 	/* #nosec */
 	query := "SELECT `ID` FROM `" + schema.tableName + "` WHERE " + whereQuery + " " + pager.String()
@@ -219,8 +253,14 @@ func searchIDs(engine *engineImplementation, where *Where, pager *Pager, withCou
 		var row uint64
 		results.Scan(&row)
 		result = append(result, row)
+		if engine.queryResultLimit > 0 {
+			engine.checkQueryResultLimit(schema, len(result), false)
+		}
 	}
 	def()
+	if engine.queryResultLimit > 0 {
+		engine.checkQueryResultLimit(schema, len(result), true)
+	}
 	totalRows := getTotalRows(engine, withCount, pager, where, schema, len(result))
 	return result, totalRows
 }
@@ -84,3 +84,70 @@ func TestSearch(t *testing.T) {
 		engine.Search(NewWhere("ID > 0"), nil, &rows)
 	})
 }
+
+func TestSearchQueryResultLimit(t *testing.T) {
+	var entity *searchEntity
+	var reference *searchEntityReference
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity, reference)
+
+	flusher := engine.NewFlusher()
+	for i := 1; i <= 10; i++ {
+		flusher.Track(&searchEntity{Name: fmt.Sprintf("name %d", i)})
+	}
+	flusher.Flush()
+
+	var rows []*searchEntity
+	engine.SetQueryResultLimit(5)
+	engine.Search(NewWhere("ID > 5"), nil, &rows)
+	assert.Len(t, rows, 5)
+	assert.PanicsWithError(t, "query result for 'beeorm.searchEntity' exceeded limit of 5 rows", func() {
+		engine.Search(NewWhere("ID > 0"), nil, &rows)
+	})
+	assert.PanicsWithError(t, "query result for 'beeorm.searchEntity' exceeded limit of 5 rows", func() {
+		engine.SearchIDs(NewWhere("ID > 0"), nil, entity)
+	})
+	assert.PanicsWithError(t, "query result for 'beeorm.searchEntity' exceeded limit of 5 rows", func() {
+		engine.LoadByIDs([]uint64{1, 2, 3, 4, 5, 6}, &rows)
+	})
+
+	exceeded := 0
+	engine.SetQueryResultLimit(5, func(_ Engine, schema TableSchema, rows int) {
+		assert.Equal(t, "beeorm.searchEntity", schema.GetType().String())
+		exceeded = rows
+	})
+	engine.Search(NewWhere("ID > 0"), nil, &rows)
+	assert.Len(t, rows, 10)
+	assert.Equal(t, 10, exceeded)
+	exceeded = 0
+	engine.LoadByIDs([]uint64{1, 2, 3, 4, 5, 6}, &rows)
+	assert.Equal(t, 6, exceeded)
+
+	engine.SetQueryResultLimit(0)
+	exceeded = 0
+	engine.Search(NewWhere("ID > 0"), nil, &rows)
+	assert.Len(t, rows, 10)
+	assert.Equal(t, 0, exceeded)
+}
+
+func TestSearchPagerEnforcement(t *testing.T) {
+	var entity *searchEntity
+	var reference *searchEntityReference
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity, reference)
+	engine.Flush(&searchEntity{Name: "a"})
+
+	var rows []*searchEntity
+	engine.Search(NewWhere("ID > 0"), nil, &rows)
+	assert.Len(t, rows, 1)
+	engine.EnablePagerEnforcement()
+	assert.PanicsWithError(t, "pager is required", func() {
+		engine.Search(NewWhere("ID > 0"), nil, &rows)
+	})
+	assert.PanicsWithError(t, "pager is required", func() {
+		engine.SearchIDs(NewWhere("ID > 0"), nil, entity)
+	})
+	engine.Search(NewWhere("ID > 0"), NewPager(1, 10), &rows)
+	assert.Len(t, rows, 1)
+	assert.PanicsWithError(t, "pager is required", func() {
+		engine.Clone().Search(NewWhere("ID > 0"), nil, &rows)
+	})
+}
@@ -5,9 +5,16 @@ import (
 	"encoding/binary"
 	"math"
 	"reflect"
+	"sync"
 	"unsafe"
 )
 
+const serializerPoolMaxSize = 64 * 1024
+
+var serializerPool = sync.Pool{New: func() interface{} {
+	return newSerializer(nil)
+}}
+
 type serializer struct {
 	scratch [binary.MaxVarintLen64]byte
 	buffer  *bytes.Buffer
@@ -17,6 +24,18 @@ func newSerializer(buf []uint8) *serializer {
 	return &serializer{buffer: bytes.NewBuffer(buf)}
 }
 
+func getSerializer() *serializer {
+	return serializerPool.Get().(*serializer)
+}
+
+func putSerializer(s *serializer) {
+	if s.buffer.Cap() > serializerPoolMaxSize {
+		return
+	}
+	s.buffer.Reset()
+	serializerPool.Put(s)
+}
+
 func (s *serializer) Read() []byte {
 	b := make([]byte, s.buffer.Len())
 	copy(b, s.buffer.Bytes())
@@ -100,7 +119,7 @@ func (s *serializer) DeserializeString() string {
 	if l == 0 {
 		return ""
 	}
-	return string(s.DeserializeFixed(int(l)))
+	return string(s.buffer.Next(int(l)))
 }
 
 func (s *serializer) DeserializeBytes() []byte {
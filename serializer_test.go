@@ -0,0 +1,34 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSerializerPool(t *testing.T) {
+	s := getSerializer()
+	s.SerializeUInteger(12)
+	s.SerializeString("hello")
+	s.SerializeBytes([]byte("world"))
+	binary := s.Read()
+	putSerializer(s)
+
+	s = getSerializer()
+	defer putSerializer(s)
+	allocs := testing.AllocsPerRun(100, func() {
+		s.Reset(binary)
+		s.DeserializeUInteger()
+		_ = s.DeserializeString()
+	})
+	assert.Equal(t, 1.0, allocs)
+	s.Reset(binary)
+	s.DeserializeUInteger()
+	assert.Equal(t, "hello", s.DeserializeString())
+	value := s.DeserializeBytes()
+	s.Reset([]byte("other"))
+	assert.Equal(t, "world", string(value))
+
+	large := newSerializer(make([]byte, 0, serializerPoolMaxSize+1))
+	putSerializer(large)
+}
@@ -0,0 +1,59 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+const softDeletePurgeBatchSize = 1000
+
+func restoreDeleted(engine *engineImplementation, entities ...Entity) {
+	flusher := engine.NewFlusher()
+	for _, entity := range entities {
+		orm := initIfNeeded(engine.registry, entity)
+		schema := orm.tableSchema
+		if schema.hasSoftDelete {
+			field := orm.elem.FieldByName(schema.softDeleteColumn)
+			field.Set(reflect.Zero(field.Type()))
+		} else if schema.hasFakeDelete {
+			orm.elem.FieldByName("FakeDelete").SetBool(false)
+		} else {
+			panic(fmt.Errorf("entity '%s' has no soft delete", schema.t.String()))
+		}
+		orm.fakeDelete = false
+		orm.delete = false
+		flusher.Track(entity)
+	}
+	flusher.Flush()
+}
+
+func purgeSoftDeleted(engine *engineImplementation, entity Entity, retention time.Duration) int {
+	schema := initIfNeeded(engine.registry, entity).tableSchema
+	if !schema.hasSoftDelete {
+		panic(fmt.Errorf("entity '%s' has no soft delete", schema.t.String()))
+	}
+	where := NewWhere("`"+schema.getColumnName(schema.softDeleteColumn)+"` <= ?", engine.now().Add(-retention).Format(timeFormat))
+	where.ShowFakeDeleted()
+	total := 0
+	for {
+		ids, _ := searchIDs(engine, where, NewPager(1, softDeletePurgeBatchSize), false, schema.t)
+		if len(ids) == 0 {
+			return total
+		}
+		rows := reflect.New(reflect.SliceOf(reflect.PtrTo(schema.t)))
+		engine.LoadByIDs(ids, rows.Interface())
+		flusher := engine.NewFlusher()
+		for i := 0; i < rows.Elem().Len(); i++ {
+			row := rows.Elem().Index(i)
+			if !row.IsNil() {
+				flusher.ForceDelete(row.Interface().(Entity))
+			}
+		}
+		flusher.Flush()
+		total += len(ids)
+		if len(ids) < softDeletePurgeBatchSize {
+			return total
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package beeorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type softDeleteEntity struct {
+	ORM       `orm:"localCache;redisCache"`
+	ID        uint
+	Name      string       `orm:"index=Name"`
+	DeletedAt *time.Time   `orm:"softDelete"`
+	ByName    *CachedQuery `query:":Name = ?"`
+	All       *CachedQuery `query:""`
+}
+
+type softDeleteInvalidEntity struct {
+	ORM
+	ID        uint
+	DeletedAt time.Time `orm:"softDelete"`
+}
+
+func TestSoftDelete(t *testing.T) {
+	var entity *softDeleteEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+
+	flusher := engine.NewFlusher()
+	for _, name := range []string{"a", "a", "b"} {
+		flusher.Track(&softDeleteEntity{Name: name})
+	}
+	flusher.Flush()
+
+	var rows []*softDeleteEntity
+	assert.Equal(t, 2, engine.CachedSearch(&rows, "ByName", nil, "a"))
+	assert.Equal(t, 3, engine.CachedSearch(&rows, "All", nil))
+
+	entity = &softDeleteEntity{}
+	engine.LoadByID(1, entity)
+	engine.Delete(entity)
+	assert.NotNil(t, entity.DeletedAt)
+	entity = &softDeleteEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.NotNil(t, entity.DeletedAt)
+
+	engine.Search(NewWhere("1"), nil, &rows)
+	assert.Len(t, rows, 2)
+	engine.Search(NewWhere("1").ShowFakeDeleted(), nil, &rows)
+	assert.Len(t, rows, 3)
+	assert.Len(t, engine.SearchIDs(NewWhere("1"), nil, entity), 2)
+	assert.False(t, engine.SearchOne(NewWhere("ID = 1"), entity))
+	assert.Equal(t, 1, engine.CachedSearch(&rows, "ByName", nil, "a"))
+	assert.Equal(t, uint(2), rows[0].ID)
+	assert.Equal(t, 2, engine.CachedSearch(&rows, "All", nil))
+
+	entity = &softDeleteEntity{}
+	engine.LoadByID(1, entity)
+	engine.Restore(entity)
+	assert.Nil(t, entity.DeletedAt)
+	engine.Search(NewWhere("1"), nil, &rows)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, 2, engine.CachedSearch(&rows, "ByName", nil, "a"))
+	assert.Equal(t, 3, engine.CachedSearch(&rows, "All", nil))
+
+	engine.Delete(rows[0], rows[1])
+	engine.GetMysql().Exec("UPDATE `softDeleteEntity` SET `DeletedAt` = ? WHERE `ID` = 1", time.Now().Add(-time.Hour*48).Format(timeFormat))
+	engine.ClearCacheByIDs(entity, 1)
+	assert.Equal(t, 1, engine.PurgeSoftDeleted(entity, time.Hour*24))
+	assert.False(t, engine.LoadByID(1, entity))
+	assert.True(t, engine.LoadByID(2, entity))
+	assert.Equal(t, 0, engine.PurgeSoftDeleted(entity, time.Hour*24))
+	assert.Equal(t, 1, engine.PurgeSoftDeleted(entity, 0))
+	assert.False(t, engine.LoadByID(2, entity))
+
+	assert.PanicsWithError(t, "entity 'beeorm.flushEntityReference' has no soft delete", func() {
+		engine.Restore(&flushEntityReference{})
+	})
+
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&softDeleteInvalidEntity{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "soft delete field DeletedAt in beeorm.softDeleteInvalidEntity must be *time.Time")
+}
@@ -0,0 +1,41 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const TablePrefixPluginCode = "beeorm/table_prefix"
+
+type PluginInterfaceTableName interface {
+	PluginInterfaceTableName(entityType reflect.Type, tableName string) string
+}
+
+type TablePrefixPlugin struct {
+	prefix string
+}
+
+func NewTablePrefixPlugin(prefix string) *TablePrefixPlugin {
+	return &TablePrefixPlugin{prefix: prefix}
+}
+
+func (p *TablePrefixPlugin) GetCode() string {
+	return TablePrefixPluginCode
+}
+
+func (p *TablePrefixPlugin) PluginInterfaceTableName(_ reflect.Type, tableName string) string {
+	return p.prefix + tableName
+}
+
+func initTableName(tableSchema *tableSchema, registry *Registry, entityType reflect.Type) error {
+	for _, plugin := range registry.plugins {
+		namePlugin, is := plugin.(PluginInterfaceTableName)
+		if is {
+			tableSchema.tableName = namePlugin.PluginInterfaceTableName(entityType, tableSchema.tableName)
+		}
+	}
+	if tableSchema.tableName == "" {
+		return fmt.Errorf("empty table name for entity %s", entityType.String())
+	}
+	return nil
+}
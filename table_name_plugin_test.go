@@ -0,0 +1,62 @@
+package beeorm
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tableNamePluginEntity struct {
+	ORM  `orm:"localCache;redisCache"`
+	ID   uint
+	Name string
+}
+
+type tableNamePluginEntityCustom struct {
+	ORM  `orm:"table=people"`
+	ID   uint
+	Name string
+}
+
+type pluralTableNamePlugin struct{}
+
+func (p *pluralTableNamePlugin) GetCode() string {
+	return "test/plural"
+}
+
+func (p *pluralTableNamePlugin) PluginInterfaceTableName(_ reflect.Type, tableName string) string {
+	return tableName + "s"
+}
+
+func TestTableNamePlugin(t *testing.T) {
+	var entity *tableNamePluginEntity
+	var entityCustom *tableNamePluginEntityCustom
+	registry := &Registry{}
+	registry.RegisterPlugin(&pluralTableNamePlugin{})
+	registry.RegisterPlugin(NewTablePrefixPlugin("dev_"))
+	engine := prepareTables(t, registry, 5, 6, "", entity, entityCustom)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	assert.Equal(t, "dev_tableNamePluginEntitys", schema.GetTableName())
+	assert.Equal(t, "dev_peoples", engine.GetRegistry().GetTableSchemaForEntity(entityCustom).GetTableName())
+	assert.Equal(t, TablePrefixPluginCode, engine.GetRegistry().GetPlugin(TablePrefixPluginCode).GetCode())
+	schema.DropTable(engine)
+	has, alters := schema.GetSchemaChanges(engine)
+	assert.True(t, has)
+	assert.Equal(t, "CREATE TABLE `test`.`dev_tableNamePluginEntitys` (\n  `ID` int(10) unsigned NOT NULL AUTO_INCREMENT,\n  "+
+		"`Name` varchar(255) DEFAULT NULL,\n  PRIMARY KEY (`ID`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;", alters[0].SQL)
+	schema.UpdateSchema(engine)
+
+	engine.Flush(&tableNamePluginEntity{Name: "a"})
+	var name string
+	assert.True(t, engine.GetMysql().QueryRow(NewWhere("SELECT `Name` FROM `dev_tableNamePluginEntitys` WHERE `ID` = 1"), &name))
+	assert.Equal(t, "a", name)
+	entity = &tableNamePluginEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "a", entity.Name)
+
+	registry = &Registry{}
+	registry.RegisterPlugin(&pluralTableNamePlugin{})
+	otherEngine := prepareTables(t, registry, 5, 6, "", entity)
+	assert.NotEqual(t, schema.(*tableSchema).cachePrefix, otherEngine.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema).cachePrefix)
+}
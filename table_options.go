@@ -0,0 +1,82 @@
+package beeorm
+
+import (
+	"regexp"
+	"strings"
+)
+
+var tableCommentRegexp = regexp.MustCompile(`COMMENT='((?:[^'\\]|\\.|'')*)'`)
+
+func initTableOptions(tableSchema *tableSchema, registry *Registry) {
+	tableSchema.charset = tableSchema.getTag("charset", registry.defaultEncoding, registry.defaultEncoding)
+	tableSchema.collate = tableSchema.getTag("collate", "", "")
+	tableSchema.comment = tableSchema.getTag("comment", "", "")
+	if tableSchema.collate != "" && tableSchema.getTag("charset", "", "") == "" {
+		tableSchema.charset = strings.Split(tableSchema.collate, "_")[0]
+	}
+}
+
+func (tableSchema *tableSchema) buildTableOptions(version int, registry *Registry) string {
+	options := "ENGINE=InnoDB DEFAULT CHARSET=" + tableSchema.charset
+	if tableSchema.collate != "" {
+		options += " COLLATE=" + tableSchema.collate
+	} else if version == 8 && tableSchema.charset == registry.defaultEncoding {
+		options += " COLLATE=" + registry.defaultEncoding + "_" + registry.defaultCollate
+	}
+	if tableSchema.comment != "" {
+		options += " COMMENT='" + escapeSchemaComment(tableSchema.comment) + "'"
+	}
+	return options
+}
+
+func (tableSchema *tableSchema) tableOptionsChanged(line string) bool {
+	for _, field := range strings.Split(line, " ") {
+		if strings.HasPrefix(field, "CHARSET=") && field[8:] != tableSchema.charset {
+			return true
+		}
+		if tableSchema.collate != "" && strings.HasPrefix(field, "COLLATE=") && field[8:] != tableSchema.collate {
+			return true
+		}
+	}
+	comment := ""
+	matches := tableCommentRegexp.FindStringSubmatch(line)
+	if len(matches) == 2 {
+		comment = matches[1]
+	}
+	return comment != escapeSchemaComment(tableSchema.comment)
+}
+
+func getColumnCharset(version int, registry *validatedRegistry, attributes map[string]string) string {
+	charset := attributes["charset"]
+	collate := attributes["collate"]
+	encoding := registry.registry.defaultEncoding
+	if charset == "" && collate == "" {
+		if version == 8 {
+			return " CHARACTER SET " + encoding + " COLLATE " + encoding + "_" + registry.registry.defaultCollate
+		}
+		return ""
+	}
+	if charset == "" {
+		charset = strings.Split(collate, "_")[0]
+	}
+	if collate == "" && version == 8 && charset == encoding {
+		collate = encoding + "_" + registry.registry.defaultCollate
+	}
+	definition := " CHARACTER SET " + charset
+	if collate != "" {
+		definition += " COLLATE " + collate
+	}
+	return definition
+}
+
+func getColumnComment(attributes map[string]string) string {
+	comment := attributes["comment"]
+	if comment == "" || comment == "true" {
+		return ""
+	}
+	return " COMMENT '" + escapeSchemaComment(comment) + "'"
+}
+
+func escapeSchemaComment(comment string) string {
+	return strings.NewReplacer("\\", "\\\\", "'", "''", "\n", "\\n", "\r", "\\r", "\x00", "\\0", "\x1a", "\\Z").Replace(comment)
+}
@@ -0,0 +1,66 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tableOptionsEntity struct {
+	ORM   `orm:"charset=latin1;collate=latin1_bin;comment=Customer's addresses"`
+	ID    uint
+	Name  string `orm:"comment=Full name"`
+	Code  string `orm:"length=10;charset=ascii;collate=ascii_bin"`
+	Notes string `orm:"text;charset=utf8mb4;collate=utf8mb4_unicode_ci"`
+	Age   uint8  `orm:"comment=Age in years"`
+}
+
+func TestTableOptionsChanged(t *testing.T) {
+	schema := &tableSchema{charset: "latin1", collate: "latin1_bin", comment: "Customer's addresses"}
+	assert.False(t, schema.tableOptionsChanged(") ENGINE=InnoDB DEFAULT CHARSET=latin1 COLLATE=latin1_bin COMMENT='Customer''s addresses'"))
+	assert.True(t, schema.tableOptionsChanged(") ENGINE=InnoDB DEFAULT CHARSET=latin1 COLLATE=latin1_bin"))
+	assert.True(t, schema.tableOptionsChanged(") ENGINE=InnoDB DEFAULT CHARSET=latin1 COLLATE=latin1_swedish_ci COMMENT='Customer''s addresses'"))
+	assert.True(t, schema.tableOptionsChanged(") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=latin1_bin COMMENT='Customer''s addresses'"))
+	schema = &tableSchema{charset: "utf8mb4"}
+	assert.False(t, schema.tableOptionsChanged(") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci"))
+	assert.True(t, schema.tableOptionsChanged(") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='old'"))
+	assert.Equal(t, "it''s \\\\ \\n", escapeSchemaComment("it's \\ \n"))
+}
+
+func TestTableOptions(t *testing.T) {
+	testTableOptions(t, 5)
+	testTableOptions(t, 8)
+}
+
+func testTableOptions(t *testing.T, version int) {
+	var entity *tableOptionsEntity
+	registry := &Registry{}
+	engine := prepareTables(t, registry, version, 6, "", entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	schema.DropTable(engine)
+	has, alters := schema.GetSchemaChanges(engine)
+	assert.True(t, has)
+	if version == 5 {
+		assert.Equal(t, "CREATE TABLE `test`.`tableOptionsEntity` (\n  `ID` int(10) unsigned NOT NULL AUTO_INCREMENT,\n  "+
+			"`Name` varchar(255) DEFAULT NULL COMMENT 'Full name',\n  `Code` varchar(10) CHARACTER SET ascii COLLATE ascii_bin DEFAULT NULL,\n  "+
+			"`Notes` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,\n  `Age` tinyint(3) unsigned NOT NULL DEFAULT '0' COMMENT 'Age in years',\n  "+
+			"PRIMARY KEY (`ID`)\n) ENGINE=InnoDB DEFAULT CHARSET=latin1 COLLATE=latin1_bin COMMENT='Customer''s addresses';", alters[0].SQL)
+	} else {
+		assert.Equal(t, "CREATE TABLE `test`.`tableOptionsEntity` (\n  `ID` int unsigned NOT NULL AUTO_INCREMENT,\n  "+
+			"`Name` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT 'Full name',\n  "+
+			"`Code` varchar(10) CHARACTER SET ascii COLLATE ascii_bin DEFAULT NULL,\n  "+
+			"`Notes` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,\n  `Age` tinyint unsigned NOT NULL DEFAULT '0' COMMENT 'Age in years',\n  "+
+			"PRIMARY KEY (`ID`)\n) ENGINE=InnoDB DEFAULT CHARSET=latin1 COLLATE=latin1_bin COMMENT='Customer''s addresses';", alters[0].SQL)
+	}
+	schema.UpdateSchema(engine)
+	has, _ = schema.GetSchemaChanges(engine)
+	assert.False(t, has)
+
+	engine.GetMysql().Exec("ALTER TABLE `tableOptionsEntity` COMMENT='old'")
+	has, alters = schema.GetSchemaChanges(engine)
+	assert.True(t, has)
+	assert.Equal(t, "ALTER TABLE `test`.`tableOptionsEntity`\n ENGINE=InnoDB DEFAULT CHARSET=latin1 COLLATE=latin1_bin COMMENT='Customer''s addresses';", alters[0].SQL)
+	schema.UpdateSchema(engine)
+	has, _ = schema.GetSchemaChanges(engine)
+	assert.False(t, has)
+}
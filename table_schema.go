@@ -16,12 +16,21 @@ import (
 
 type CachedQuery struct{}
 
+type CachedQueryOverflow string
+
+const (
+	CachedQueryOverflowSQL      CachedQueryOverflow = "sql"
+	CachedQueryOverflowTruncate CachedQueryOverflow = "truncate"
+)
+
 type cachedQueryDefinition struct {
 	Max           int
 	Query         string
 	TrackedFields []string
 	QueryFields   []string
 	OrderFields   []string
+	OrderDesc     []bool
+	Overflow      CachedQueryOverflow
 }
 
 type Enum interface {
@@ -84,6 +93,7 @@ type EntityLog struct {
 type TableSchema interface {
 	GetTableName() string
 	GetType() reflect.Type
+	GetOwner() string
 	NewEntity() Entity
 	DropTable(engine Engine)
 	TruncateTable(engine Engine)
@@ -98,6 +108,7 @@ type TableSchema interface {
 	GetSchemaChanges(engine Engine) (has bool, alters []Alter)
 	GetUsage(registry ValidatedRegistry) map[reflect.Type][]string
 	GetEntityLogs(engine Engine, entityID uint64, pager *Pager, where *Where) []EntityLog
+	Fields() []SchemaField
 }
 
 type tableSchema struct {
@@ -105,6 +116,7 @@ type tableSchema struct {
 	mysqlPoolName           string
 	t                       reflect.Type
 	fields                  *tableFields
+	schemaFields            []SchemaField
 	registry                *validatedRegistry
 	fieldsQuery             string
 	tags                    map[string]map[string]string
@@ -122,16 +134,55 @@ type tableSchema struct {
 	hasLocalCache           bool
 	redisCacheName          string
 	hasRedisCache           bool
+	cacheMode               cacheMode
+	cacheTTL                int
+	doubleDeleteDelay       time.Duration
 	searchCacheName         string
 	cachePrefix             string
+	cacheVersions           map[string]*cacheVersion
+	projectionOf            *tableSchema
+	projections             []*tableSchema
+	indexerStreams          []string
+	crudSubscribers         []*crudSubscriber
 	structureHash           uint64
 	hasFakeDelete           bool
 	hasSearchableFakeDelete bool
+	hasSoftDelete           bool
+	softDeleteColumn        string
+	createdAtColumn         string
+	updatedAtColumn         string
+	validations             []fieldValidation
+	defaults                map[string]reflect.Value
+	piiFields               map[string]string
+	maskedColumns           map[string]bool
+	cacheKeyProviders       []CacheKeyProvider
+	translatedColumns       []string
+	i18nTableName           string
+	i18nFallbacks           []string
+	treeParentColumn        string
+	treePathColumn          string
+	fileColumns             map[string]string
+	uuidGenerators          map[string]string
 	hasLog                  bool
 	logPoolName             string //name of redis
 	logTableName            string
 	skipLogs                []string
+	hasAudit                bool
+	auditPoolName           string
+	auditTableName          string
+	hasTemporal             bool
+	temporalPoolName        string
+	temporalTableName       string
+	owner                   string
 	hasUUID                 bool
+	idGenerator             IDGenerator
+	generatedColumns        []string
+	hasBinder               bool
+	charset                 string
+	collate                 string
+	comment                 string
+	columnNaming            ColumnNamingStrategy
+	hasColumnNames          bool
 	mapBindToScanPointer    mapBindToScanPointer
 	mapPointerToValue       mapPointerToValue
 }
@@ -174,6 +225,8 @@ type tableFields struct {
 	refsTypes               []reflect.Type
 	refsMany                []int
 	refsManyTypes           []reflect.Type
+	customs                 []int
+	customsOptions          []customColumnOptions
 }
 
 func getTableSchema(registry *validatedRegistry, entityType reflect.Type) *tableSchema {
@@ -184,6 +237,10 @@ func (tableSchema *tableSchema) GetTableName() string {
 	return tableSchema.tableName
 }
 
+func (tableSchema *tableSchema) GetOwner() string {
+	return tableSchema.owner
+}
+
 func (tableSchema *tableSchema) GetType() reflect.Type {
 	return tableSchema.t
 }
@@ -350,6 +407,15 @@ func (tableSchema *tableSchema) init(registry *Registry, entityType reflect.Type
 		return fmt.Errorf("mysql pool '%s' not found", tableSchema.mysqlPoolName)
 	}
 	tableSchema.tableName = tableSchema.getTag("table", entityType.Name(), entityType.Name())
+	err := initTableName(tableSchema, registry, entityType)
+	if err != nil {
+		return err
+	}
+	initTableOptions(tableSchema, registry)
+	err = initColumnNames(tableSchema, registry, entityType)
+	if err != nil {
+		return err
+	}
 	localCache := tableSchema.getTag("localCache", "default", "")
 	redisCache := tableSchema.getTag("redisCache", "default", "")
 	if localCache != "" {
@@ -378,6 +444,90 @@ func (tableSchema *tableSchema) init(registry *Registry, entityType reflect.Type
 		searchable := tableSchema.tags["FakeDelete"] != nil && tableSchema.tags["FakeDelete"]["searchable"] == "true"
 		tableSchema.hasSearchableFakeDelete = searchable
 	}
+	for field, tags := range tableSchema.tags {
+		if tags["softDelete"] != "true" {
+			continue
+		}
+		softDeleteField, is := entityType.FieldByName(field)
+		if !is || softDeleteField.Type.String() != "*time.Time" {
+			return fmt.Errorf("soft delete field %s in %s must be *time.Time", field, entityType.String())
+		}
+		if tableSchema.hasFakeDelete {
+			return fmt.Errorf("entity %s cannot use FakeDelete and soft delete together", entityType.String())
+		}
+		tableSchema.hasSoftDelete = true
+		tableSchema.softDeleteColumn = field
+	}
+	for field, tags := range tableSchema.tags {
+		for _, tag := range []string{"createdAt", "updatedAt"} {
+			if tags[tag] != "true" {
+				continue
+			}
+			timestampField, is := entityType.FieldByName(field)
+			if !is || (timestampField.Type.String() != "time.Time" && timestampField.Type.String() != "*time.Time") {
+				return fmt.Errorf("%s field %s in %s must be time.Time", tag, field, entityType.String())
+			}
+			if tag == "createdAt" {
+				tableSchema.createdAtColumn = field
+			} else {
+				tableSchema.updatedAtColumn = field
+			}
+		}
+	}
+	err = initValidations(tableSchema, registry, entityType)
+	if err != nil {
+		return err
+	}
+	err = initPIIFields(tableSchema, entityType)
+	if err != nil {
+		return err
+	}
+	initCacheKeyProviders(tableSchema, registry, entityType)
+	err = initTranslatedFields(tableSchema, entityType)
+	if err != nil {
+		return err
+	}
+	err = initTree(tableSchema, entityType)
+	if err != nil {
+		return err
+	}
+	err = initFileFields(tableSchema, registry, entityType)
+	if err != nil {
+		return err
+	}
+	err = initUUIDFields(tableSchema, entityType)
+	if err != nil {
+		return err
+	}
+	err = initGeneratedColumns(tableSchema, entityType)
+	if err != nil {
+		return err
+	}
+	for field, tags := range tableSchema.tags {
+		defaultValue, has := tags["default"]
+		if !has {
+			continue
+		}
+		structField, is := entityType.FieldByName(field)
+		if !is {
+			continue
+		}
+		if tags["length"] == "max" {
+			return fmt.Errorf("default value not allowed for field %s in %s with length=max", field, entityType.String())
+		}
+		textStorage := getStringTextStorage(tags)
+		if textStorage != "" {
+			return fmt.Errorf("default value not allowed for field %s in %s with %s", field, entityType.String(), textStorage)
+		}
+		value, err := parseDefaultValue(structField.Type, defaultValue)
+		if err != nil {
+			return fmt.Errorf("invalid default '%s' for field %s in %s", defaultValue, field, entityType.String())
+		}
+		if tableSchema.defaults == nil {
+			tableSchema.defaults = make(map[string]reflect.Value)
+		}
+		tableSchema.defaults[field] = value
+	}
 	for key, values := range tableSchema.tags {
 		isOne := false
 		query, has := values["query"]
@@ -385,11 +535,35 @@ func (tableSchema *tableSchema) init(registry *Registry, entityType reflect.Type
 			query, has = values["queryOne"]
 			isOne = true
 		}
+		queryMax := 0
+		overflow := CachedQueryOverflow("")
+		if has {
+			options := strings.Split(query, ";")
+			query = options[0]
+			for _, option := range options[1:] {
+				if strings.HasPrefix(option, "max=") {
+					max, err := strconv.Atoi(option[4:])
+					if err != nil || max <= 0 {
+						return fmt.Errorf("invalid max '%s' in cached query '%s' in %s", option[4:], key, entityType.String())
+					}
+					queryMax = max
+				} else if strings.HasPrefix(option, "overflow=") {
+					overflow = CachedQueryOverflow(option[9:])
+					if overflow != CachedQueryOverflowSQL && overflow != CachedQueryOverflowTruncate {
+						return fmt.Errorf("invalid overflow '%s' in cached query '%s' in %s", option[9:], key, entityType.String())
+					}
+				}
+			}
+			if !isOne && queryMax == 0 && registry.enforcePagination {
+				return fmt.Errorf("cached query '%s' in %s must define max", key, entityType.String())
+			}
+		}
 		queryOrigin := query
 		fields := make([]string, 0)
 		fieldsTracked := make([]string, 0)
 		fieldsQuery := make([]string, 0)
 		fieldsOrder := make([]string, 0)
+		orderDesc := make([]bool, 0)
 		if has {
 			re := regexp.MustCompile(":([A-Za-z\\d])+")
 			variables := re.FindAllString(query, -1)
@@ -405,19 +579,26 @@ func (tableSchema *tableSchema) init(registry *Registry, entityType reflect.Type
 				if !has {
 					fields = append(fields, fieldName)
 				}
-				query = strings.Replace(query, variable, fmt.Sprintf("`%s`", fieldName), 1)
+				query = strings.Replace(query, variable, fmt.Sprintf("`%s`", tableSchema.getColumnName(fieldName)), 1)
 			}
 			if tableSchema.hasFakeDelete && len(variables) > 0 {
 				fields = append(fields, "FakeDelete")
 			}
+			if tableSchema.hasSoftDelete && len(variables) > 0 {
+				fields = append(fields, tableSchema.softDeleteColumn)
+			}
 			if query == "" {
 				if tableSchema.hasFakeDelete {
 					query = "`FakeDelete` = 0 ORDER BY `ID`"
+				} else if tableSchema.hasSoftDelete {
+					query = "`" + tableSchema.getColumnName(tableSchema.softDeleteColumn) + "` IS NULL ORDER BY `ID`"
 				} else {
 					query = "1 ORDER BY `ID`"
 				}
 			} else if tableSchema.hasFakeDelete {
 				query = "`FakeDelete` = 0 AND " + query
+			} else if tableSchema.hasSoftDelete {
+				query = "`" + tableSchema.getColumnName(tableSchema.softDeleteColumn) + "` IS NULL AND " + query
 			}
 			queryLower := strings.ToLower(queryOrigin)
 			posOrderBy := strings.Index(queryLower, "order by")
@@ -431,19 +612,29 @@ func (tableSchema *tableSchema) init(registry *Registry, entityType reflect.Type
 				}
 			}
 			if posOrderBy > -1 {
-				variables = re.FindAllString(queryOrigin[posOrderBy:], -1)
-				for _, variable := range variables {
-					fieldName := variable[1:]
-					fieldsOrder = append(fieldsOrder, fieldName)
+				for _, part := range strings.Split(queryOrigin[posOrderBy+8:], ",") {
+					variable := re.FindString(part)
+					if variable == "" {
+						continue
+					}
+					fieldsOrder = append(fieldsOrder, variable[1:])
+					desc := strings.HasSuffix(strings.ToLower(strings.TrimSpace(part)), " desc")
+					if len(orderDesc) > 0 && orderDesc[0] != desc {
+						return fmt.Errorf("cached query '%s' in %s can't mix ASC and DESC order", key, entityType.String())
+					}
+					orderDesc = append(orderDesc, desc)
 				}
 			}
 
 			if !isOne {
-				def := &cachedQueryDefinition{50000, query, fieldsTracked, fieldsQuery, fieldsOrder}
+				if queryMax == 0 {
+					queryMax = 50000
+				}
+				def := &cachedQueryDefinition{queryMax, query, fieldsTracked, fieldsQuery, fieldsOrder, orderDesc, overflow}
 				cachedQueries[key] = def
 				cachedQueriesAll[key] = def
 			} else {
-				def := &cachedQueryDefinition{1, query, fieldsTracked, fieldsQuery, fieldsOrder}
+				def := &cachedQueryDefinition{1, query, fieldsTracked, fieldsQuery, fieldsOrder, orderDesc, overflow}
 				cachedQueriesOne[key] = def
 				cachedQueriesAll[key] = def
 			}
@@ -458,8 +649,28 @@ func (tableSchema *tableSchema) init(registry *Registry, entityType reflect.Type
 		}
 	}
 	logPoolName := tableSchema.getTag("log", tableSchema.mysqlPoolName, "")
+	auditPoolName := tableSchema.getTag("audit", tableSchema.mysqlPoolName, "")
+	if auditPoolName != "" {
+		_, has = registry.mysqlPools[auditPoolName]
+		if !has {
+			return fmt.Errorf("audit mysql pool '%s' not found", auditPoolName)
+		}
+	}
+	temporalPoolName := tableSchema.getTag("temporal", tableSchema.mysqlPoolName, "")
+	if temporalPoolName != "" {
+		_, has = registry.mysqlPools[temporalPoolName]
+		if !has {
+			return fmt.Errorf("temporal mysql pool '%s' not found", temporalPoolName)
+		}
+	}
 	hasUUID := tableSchema.getTag("uuid", "true", "false") == "true"
-	if hasUUID {
+	idGenerator, err := getIDGenerator(registry, tableSchema, entityType)
+	if err != nil {
+		return err
+	}
+	if idGenerator != nil {
+		hasUUID = true
+	} else if hasUUID {
 		idField, is := entityType.FieldByName("ID")
 		if is && idField.Type.String() != "uint64" {
 			return fmt.Errorf("entity %s with uuid enabled must be unit64", entityType.String())
@@ -470,6 +681,7 @@ func (tableSchema *tableSchema) init(registry *Registry, entityType reflect.Type
 	uniqueIndicesSimpleGlobal := make(map[string][]string)
 	indices := make(map[string]map[int]string)
 	skipLogs := make([]string, 0)
+	maskedColumns := make(map[string]bool)
 	uniqueGlobal := tableSchema.getTag("unique", "", "")
 	if uniqueGlobal != "" {
 		parts := strings.Split(uniqueGlobal, "|")
@@ -524,6 +736,9 @@ func (tableSchema *tableSchema) init(registry *Registry, entityType reflect.Type
 		if has {
 			skipLogs = append(skipLogs, k)
 		}
+		if v["mask"] == "true" {
+			maskedColumns[k] = true
+		}
 	}
 	for _, ref := range oneRefs {
 		has := false
@@ -546,7 +761,7 @@ func (tableSchema *tableSchema) init(registry *Registry, entityType reflect.Type
 		}
 	}
 	tableSchema.fields = tableSchema.buildTableFields(entityType, registry, 1, "", tableSchema.tags)
-	tableSchema.columnNames, tableSchema.fieldsQuery = tableSchema.fields.buildColumnNames("")
+	tableSchema.columnNames, tableSchema.fieldsQuery = tableSchema.fields.buildColumnNames("", tableSchema.getColumnName)
 	columnMapping := make(map[string]int)
 	for i, name := range tableSchema.columnNames {
 		columnMapping[name] = i
@@ -558,6 +773,7 @@ func (tableSchema *tableSchema) init(registry *Registry, entityType reflect.Type
 	_, _ = h.Write([]byte(cachePrefix))
 
 	tableSchema.structureHash = uint64(h.Sum32())
+	initBinder(tableSchema, entityType)
 	tableSchema.columnMapping = columnMapping
 	tableSchema.cachedIndexes = cachedQueries
 	tableSchema.cachedIndexesOne = cachedQueriesOne
@@ -569,13 +785,31 @@ func (tableSchema *tableSchema) init(registry *Registry, entityType reflect.Type
 	tableSchema.refOne = oneRefs
 	tableSchema.refMany = manyRefs
 	tableSchema.cachePrefix = cachePrefix
+	initCacheVersions(tableSchema)
+	err = initCacheMode(tableSchema)
+	if err != nil {
+		return err
+	}
+	err = initDoubleDelete(tableSchema, registry)
+	if err != nil {
+		return err
+	}
 	tableSchema.uniqueIndices = uniqueIndicesSimple
 	tableSchema.uniqueIndicesGlobal = uniqueIndicesSimpleGlobal
 	tableSchema.hasLog = logPoolName != ""
 	tableSchema.hasUUID = hasUUID
+	tableSchema.idGenerator = idGenerator
 	tableSchema.logPoolName = logPoolName
 	tableSchema.logTableName = fmt.Sprintf("_log_%s_%s", tableSchema.mysqlPoolName, tableSchema.tableName)
 	tableSchema.skipLogs = skipLogs
+	tableSchema.maskedColumns = maskedColumns
+	tableSchema.hasAudit = auditPoolName != ""
+	tableSchema.auditPoolName = auditPoolName
+	tableSchema.auditTableName = "_audit_" + tableSchema.tableName
+	tableSchema.hasTemporal = temporalPoolName != ""
+	tableSchema.temporalPoolName = temporalPoolName
+	tableSchema.temporalTableName = "_history_" + tableSchema.tableName
+	tableSchema.owner = tableSchema.getTag("owner", "", "")
 
 	return tableSchema.validateIndexes(uniqueIndices, indices)
 }
@@ -731,6 +965,12 @@ func (tableSchema *tableSchema) buildTableFields(t reflect.Type, registry *Regis
 			tableSchema.buildIntPointerField(attributes)
 		case "string":
 			tableSchema.buildStringField(attributes, registry)
+		case "*string":
+			if _, hasEnum := tags["enum"]; hasEnum {
+				tableSchema.buildStringField(attributes, registry)
+			} else {
+				tableSchema.buildPointerField(attributes)
+			}
 		case "[]string":
 			tableSchema.buildStringSliceField(attributes, registry)
 		case "[]uint8":
@@ -753,7 +993,14 @@ func (tableSchema *tableSchema) buildTableFields(t reflect.Type, registry *Regis
 			tableSchema.buildTimeField(attributes)
 		default:
 			k := f.Type.Kind().String()
-			if k == "struct" {
+			if enum, _ := getIntEnum(registry, f.Type); enum != nil {
+				fields.customs = append(fields.customs, i)
+				fields.customsOptions = append(fields.customsOptions, customColumnOptions{scale: -1, intEnum: enum,
+					enumStorage: tags["enumStorage"] == "enum"})
+			} else if _, isCustom := getCustomColumnType(f.Type); isCustom {
+				fields.customs = append(fields.customs, i)
+				fields.customsOptions = append(fields.customsOptions, getCustomColumnOptions(f.Type, tags))
+			} else if k == "struct" {
 				tableSchema.buildStructField(attributes, registry, schemaTags)
 			} else if k == "ptr" {
 				tableSchema.buildPointerField(attributes)
@@ -1072,17 +1319,21 @@ func extractTag(registry *Registry, field reflect.StructField) map[string]map[st
 		length := len(args)
 		var attributes = make(map[string]string, length)
 		for j := 0; j < length; j++ {
-			arg := strings.Split(args[j], "=")
+			arg := strings.SplitN(args[j], "=", 2)
 			if len(arg) == 1 {
 				attributes[arg[0]] = "true"
 			} else {
 				attributes[arg[0]] = arg[1]
 			}
 		}
+		if attributes["softDelete"] == "true" || attributes["createdAt"] == "true" || attributes["updatedAt"] == "true" {
+			attributes["time"] = "true"
+		}
 		return map[string]map[string]string{field.Name: attributes}
 	} else if field.Type.Kind().String() == "struct" {
 		t := field.Type.String()
-		if t != "beeorm.ORM" && t != "time.Time" {
+		_, isCustom := getCustomColumnType(field.Type)
+		if t != "beeorm.ORM" && t != "time.Time" && !isCustom {
 			prefix := ""
 			if !field.Anonymous {
 				prefix = field.Name
@@ -1093,10 +1344,6 @@ func extractTag(registry *Registry, field reflect.StructField) map[string]map[st
 	return make(map[string]map[string]string)
 }
 
-func (tableSchema *tableSchema) getCacheKey(id uint64) string {
-	return tableSchema.cachePrefix + ":" + strconv.FormatUint(id, 10)
-}
-
 func (tableSchema *tableSchema) NewEntity() Entity {
 	val := reflect.New(tableSchema.t)
 	e := val.Interface().(Entity)
@@ -1109,7 +1356,7 @@ func (tableSchema *tableSchema) NewEntity() Entity {
 	return e
 }
 
-func (fields *tableFields) buildColumnNames(subFieldPrefix string) ([]string, string) {
+func (fields *tableFields) buildColumnNames(subFieldPrefix string, columnName func(field string) string) ([]string, string) {
 	fieldsQuery := ""
 	columns := make([]string, 0)
 	ids := fields.refs
@@ -1138,13 +1385,14 @@ func (fields *tableFields) buildColumnNames(subFieldPrefix string) ([]string, st
 	timesNullableEnd := len(ids)
 	ids = append(ids, fields.jsons...)
 	ids = append(ids, fields.refsMany...)
+	ids = append(ids, fields.customs...)
 	for k, i := range ids {
 		name := subFieldPrefix + fields.fields[i].Name
 		columns = append(columns, name)
 		if (k >= timesStart && k < timesEnd) || (k >= timesNullableStart && k < timesNullableEnd) {
-			fieldsQuery += ",TO_SECONDS(`" + name + "`)"
+			fieldsQuery += ",TO_SECONDS(`" + columnName(name) + "`)"
 		} else {
-			fieldsQuery += ",`" + name + "`"
+			fieldsQuery += ",`" + columnName(name) + "`"
 		}
 	}
 	for i, subFields := range fields.structsFields {
@@ -1153,7 +1401,7 @@ func (fields *tableFields) buildColumnNames(subFieldPrefix string) ([]string, st
 		if !field.Anonymous {
 			prefixName += field.Name
 		}
-		subColumns, subQuery := subFields.buildColumnNames(prefixName)
+		subColumns, subQuery := subFields.buildColumnNames(prefixName, columnName)
 		columns = append(columns, subColumns...)
 		fieldsQuery += "," + subQuery
 	}
@@ -1233,3 +1481,47 @@ var scanStringPointer = func() interface{} {
 var pointerStringScan = func(val interface{}) interface{} {
 	return *val.(*string)
 }
+
+func parseDefaultValue(t reflect.Type, value string) (reflect.Value, error) {
+	if t.Kind() == reflect.Ptr {
+		elem, err := parseDefaultValue(t.Elem(), value)
+		if err != nil {
+			return elem, err
+		}
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	}
+	result := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		result.SetString(value)
+	case reflect.Bool:
+		asBool, err := strconv.ParseBool(value)
+		if err != nil {
+			return result, err
+		}
+		result.SetBool(asBool)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		asInt, err := strconv.ParseInt(value, 10, t.Bits())
+		if err != nil {
+			return result, err
+		}
+		result.SetInt(asInt)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		asUint, err := strconv.ParseUint(value, 10, t.Bits())
+		if err != nil {
+			return result, err
+		}
+		result.SetUint(asUint)
+	case reflect.Float32, reflect.Float64:
+		asFloat, err := strconv.ParseFloat(value, t.Bits())
+		if err != nil {
+			return result, err
+		}
+		result.SetFloat(asFloat)
+	default:
+		return result, fmt.Errorf("unsupported type %s", t.String())
+	}
+	return result, nil
+}
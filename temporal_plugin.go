@@ -0,0 +1,107 @@
+package beeorm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const TemporalPluginCode = "beeorm/temporal"
+
+const temporalTimeFormat = "2006-01-02 15:04:05.000000"
+
+type TemporalPlugin struct{}
+
+func NewTemporalPlugin() *TemporalPlugin {
+	return &TemporalPlugin{}
+}
+
+func (p *TemporalPlugin) GetCode() string {
+	return TemporalPluginCode
+}
+
+func (p *TemporalPlugin) PluginInterfaceSchemaTables(engine Engine) []PluginTable {
+	tables := make([]PluginTable, 0)
+	registry := engine.(*engineImplementation).registry
+	for _, t := range registry.entities {
+		schema := getTableSchema(registry, t)
+		if !schema.hasTemporal {
+			continue
+		}
+		poolConfig := engine.GetMysql(schema.temporalPoolName).GetPoolConfig()
+		var createSQL string
+		if poolConfig.GetVersion() == 5 {
+			createSQL = fmt.Sprintf("CREATE TABLE `%s`.`%s` (\n  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,\n  "+
+				"`entity_id` bigint(20) unsigned NOT NULL,\n  `valid_from` datetime(6) NOT NULL,\n  `valid_to` datetime(6) DEFAULT NULL,\n  "+
+				"`data` json DEFAULT NULL,\n  PRIMARY KEY (`id`),\n  KEY `entity_id` (`entity_id`,`valid_from`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+				poolConfig.GetDatabase(), schema.temporalTableName)
+		} else {
+			createSQL = fmt.Sprintf("CREATE TABLE `%s`.`%s` (\n  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n  "+
+				"`entity_id` bigint unsigned NOT NULL,\n  `valid_from` datetime(6) NOT NULL,\n  `valid_to` datetime(6) DEFAULT NULL,\n  "+
+				"`data` json DEFAULT NULL,\n  PRIMARY KEY (`id`),\n  KEY `entity_id` (`entity_id`,`valid_from`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_%s;",
+				poolConfig.GetDatabase(), schema.temporalTableName, registry.registry.defaultCollate)
+		}
+		tables = append(tables, PluginTable{Pool: schema.temporalPoolName, Name: schema.temporalTableName, CreateSQL: createSQL})
+	}
+	return tables
+}
+
+func (p *TemporalPlugin) PluginInterfaceEntityFlushedInTransaction(engine Engine, event *EntityFlushedEvent) {
+	schema := event.TableSchema.(*tableSchema)
+	if !schema.hasTemporal || event.Lazy || event.ID == 0 {
+		return
+	}
+	db := engine.GetMysql(schema.temporalPoolName)
+	now := engine.(*engineImplementation).now().Format(temporalTimeFormat)
+	if event.Type != FlushTypeInsert {
+		db.Exec("UPDATE `"+schema.temporalTableName+"` SET `valid_to` = ? WHERE `entity_id` = ? AND `valid_to` IS NULL", now, event.ID)
+	}
+	if event.Type == FlushTypeDelete {
+		return
+	}
+	state := Bind{}
+	for column, value := range event.Before {
+		state[column] = value
+	}
+	for column, value := range event.Changes {
+		state[column] = value
+	}
+	delete(state, "ID")
+	data, _ := json.Marshal(state)
+	db.Exec("INSERT INTO `"+schema.temporalTableName+"`(`entity_id`, `valid_from`, `data`) VALUES(?, ?, ?)", event.ID, now, string(data))
+}
+
+func loadByIDAsOf(serializer *serializer, engine *engineImplementation, id uint64, asOf time.Time, entity Entity, references ...string) bool {
+	orm := initIfNeeded(engine.registry, entity)
+	schema := orm.tableSchema
+	if !schema.hasTemporal {
+		panic(fmt.Errorf("entity '%s' is not temporal", schema.t.String()))
+	}
+	columns := make([]string, len(schema.columnNames))
+	for i, column := range schema.columnNames {
+		if column == "ID" {
+			columns[i] = "`entity_id` AS `ID`"
+			continue
+		}
+		path := "JSON_EXTRACT(`data`, '$.\"" + column + "\"')"
+		columns[i] = "CASE WHEN " + path + " IS NULL OR JSON_TYPE(" + path + ") = 'NULL' THEN NULL ELSE JSON_UNQUOTE(" +
+			path + ") END AS `" + schema.getColumnName(column) + "`"
+	}
+	/* #nosec */
+	query := "SELECT " + schema.fieldsQuery + " FROM (SELECT " + strings.Join(columns, ",") + " FROM `" + schema.temporalTableName +
+		"` WHERE `entity_id` = " + strconv.FormatUint(id, 10) + " AND `valid_from` <= ? AND (`valid_to` IS NULL OR `valid_to` > ?)" +
+		" ORDER BY `id` DESC LIMIT 1) AS `history`"
+	asOfValue := asOf.Local().Format(temporalTimeFormat)
+	pointers := prepareScan(schema)
+	found := engine.GetMysql(schema.temporalPoolName).QueryRow(NewWhere(query, asOfValue, asOfValue), pointers...)
+	if !found {
+		return false
+	}
+	fillFromDBRow(serializer, id, engine.registry, pointers, entity)
+	if len(references) > 0 {
+		warmUpReferences(serializer, engine, schema, orm.value, references, false)
+	}
+	return true
+}
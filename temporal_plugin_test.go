@@ -0,0 +1,107 @@
+package beeorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type temporalEntity struct {
+	ORM       `orm:"temporal;localCache"`
+	ID        uint
+	Name      string
+	Age       *uint
+	Active    bool
+	Born      *time.Time `orm:"time"`
+	Reference *temporalEntityReference
+}
+
+type temporalEntityReference struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestTemporalPlugin(t *testing.T) {
+	var entity *temporalEntity
+	var reference *temporalEntityReference
+	registry := &Registry{}
+	registry.RegisterPlugin(NewTemporalPlugin())
+	engine := prepareTables(t, registry, 5, 6, "", entity, reference)
+	assert.Len(t, engine.GetAlters(), 0)
+	engine.GetMysql().Exec("TRUNCATE TABLE `_history_temporalEntity`")
+
+	beforeInsert := time.Now()
+	time.Sleep(time.Millisecond * 5)
+	born := time.Date(2020, 1, 2, 3, 4, 5, 0, time.Local)
+	entity = &temporalEntity{Name: "a", Born: &born, Reference: &temporalEntityReference{Name: "r"}}
+	engine.Flush(entity)
+	time.Sleep(time.Millisecond * 5)
+	afterInsert := time.Now()
+	time.Sleep(time.Millisecond * 5)
+	age := uint(18)
+	entity.Name = "b"
+	entity.Age = &age
+	entity.Active = true
+	engine.Flush(entity)
+	time.Sleep(time.Millisecond * 5)
+	afterUpdate := time.Now()
+	time.Sleep(time.Millisecond * 5)
+	engine.Delete(entity)
+
+	entity = &temporalEntity{}
+	assert.False(t, engine.LoadByIDAsOf(1, beforeInsert, entity))
+	assert.True(t, engine.LoadByIDAsOf(1, afterInsert, entity, "Reference"))
+	assert.Equal(t, uint(1), entity.ID)
+	assert.Equal(t, "a", entity.Name)
+	assert.Nil(t, entity.Age)
+	assert.False(t, entity.Active)
+	assert.Equal(t, born.Unix(), entity.Born.Unix())
+	assert.Equal(t, "r", entity.Reference.Name)
+
+	entity = &temporalEntity{}
+	assert.True(t, engine.LoadByIDAsOf(1, afterUpdate, entity))
+	assert.Equal(t, "b", entity.Name)
+	assert.Equal(t, uint(18), *entity.Age)
+	assert.True(t, entity.Active)
+	assert.False(t, engine.LoadByIDAsOf(1, time.Now(), entity))
+	assert.False(t, engine.LoadByID(1, entity))
+
+	assert.PanicsWithError(t, "entity 'beeorm.temporalEntityReference' is not temporal", func() {
+		engine.LoadByIDAsOf(1, time.Now(), &temporalEntityReference{})
+	})
+}
+
+func TestTemporalPluginClockAndLazy(t *testing.T) {
+	var entity *temporalEntity
+	var reference *temporalEntityReference
+	registry := &Registry{}
+	registry.RegisterPlugin(NewTemporalPlugin())
+	engine := prepareTables(t, registry, 5, 6, "", entity, reference)
+	engine.GetMysql().Exec("TRUNCATE TABLE `_history_temporalEntity`")
+	clock := &testClock{now: time.Date(2022, 1, 1, 12, 0, 0, 0, time.Local)}
+	engine.SetClock(clock)
+
+	entity = &temporalEntity{Name: "a"}
+	engine.Flush(entity)
+	clock.now = clock.now.Add(time.Hour)
+	entity.Name = "b"
+	engine.FlushLazy(entity)
+
+	entity = &temporalEntity{}
+	assert.False(t, engine.LoadByIDAsOf(1, clock.now.Add(-time.Hour-time.Second), entity))
+	assert.True(t, engine.LoadByIDAsOf(1, clock.now, entity))
+	assert.Equal(t, "a", entity.Name)
+
+	receiver := NewBackgroundConsumer(engine)
+	receiver.DisableBlockMode()
+	receiver.blockTime = time.Millisecond
+	receiver.Digest(context.Background())
+	entity = &temporalEntity{}
+	assert.True(t, engine.LoadByIDAsOf(1, time.Now(), entity))
+	assert.Equal(t, "b", entity.Name)
+	assert.True(t, engine.LoadByIDAsOf(1, time.Date(2022, 1, 1, 12, 30, 0, 0, time.Local), entity))
+	assert.Equal(t, "a", entity.Name)
+}
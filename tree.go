@@ -0,0 +1,145 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type treeMove struct {
+	schema    *tableSchema
+	oldPrefix string
+	newPrefix string
+}
+
+func initTree(tableSchema *tableSchema, entityType reflect.Type) error {
+	tableSchema.treeParentColumn = ""
+	tableSchema.treePathColumn = ""
+	for i := 0; i < entityType.NumField(); i++ {
+		field := entityType.Field(i)
+		tags := tableSchema.tags[field.Name]
+		if tags["tree"] == "true" {
+			if field.Type != reflect.PtrTo(entityType) {
+				return fmt.Errorf("tree field %s in %s must reference %s", field.Name, entityType.String(), entityType.String())
+			}
+			tableSchema.treeParentColumn = field.Name
+		}
+		if tags["treePath"] == "true" {
+			if field.Type.Kind() != reflect.String {
+				return fmt.Errorf("tree path field %s in %s must be string", field.Name, entityType.String())
+			}
+			tableSchema.treePathColumn = field.Name
+		}
+	}
+	if tableSchema.treeParentColumn == "" && tableSchema.treePathColumn == "" {
+		return nil
+	}
+	if tableSchema.treeParentColumn == "" || tableSchema.treePathColumn == "" {
+		return fmt.Errorf("tree entity %s requires both tree and treePath fields", entityType.String())
+	}
+	_, hasIndex := tableSchema.tags[tableSchema.treePathColumn]["index"]
+	if !hasIndex {
+		tableSchema.tags[tableSchema.treePathColumn]["index"] = tableSchema.treePathColumn
+	}
+	return nil
+}
+
+func getTreeSchema(engine *engineImplementation, entity Entity) *tableSchema {
+	schema := initIfNeeded(engine.registry, entity).tableSchema
+	if schema.treeParentColumn == "" {
+		panic(fmt.Errorf("entity '%s' is not a tree", schema.t.String()))
+	}
+	return schema
+}
+
+func getTreeAncestors(engine *engineImplementation, entity Entity, entities interface{}) {
+	schema := getTreeSchema(engine, entity)
+	path := entity.getORM().elem.FieldByName(schema.treePathColumn).String()
+	ids := make([]uint64, 0)
+	for _, part := range strings.Split(path, "/") {
+		if part == "" {
+			continue
+		}
+		id, _ := strconv.ParseUint(part, 10, 64)
+		ids = append(ids, id)
+	}
+	engine.LoadByIDs(ids, entities)
+}
+
+func getTreeDescendants(engine *engineImplementation, entity Entity, entities interface{}, pager *Pager) {
+	schema := getTreeSchema(engine, entity)
+	orm := entity.getORM()
+	prefix := orm.elem.FieldByName(schema.treePathColumn).String() + strconv.FormatUint(orm.GetID(), 10) + "/"
+	column := schema.getColumnName(schema.treePathColumn)
+	where := NewWhere("`"+column+"` LIKE ? ORDER BY `"+column+"`, `ID`", prefix+"%")
+	if pager == nil {
+		pager = NewPager(1, 50000)
+	}
+	engine.Search(where, pager, entities)
+}
+
+func moveTreeNode(engine *engineImplementation, entity Entity, parent Entity) {
+	schema := getTreeSchema(engine, entity)
+	field := entity.getORM().elem.FieldByName(schema.treeParentColumn)
+	if parent == nil {
+		field.Set(reflect.Zero(field.Type()))
+	} else {
+		field.Set(reflect.ValueOf(parent))
+	}
+	engine.Flush(entity)
+}
+
+func (f *flusher) fillTreePath(orm *ORM) {
+	schema := orm.tableSchema
+	path := "/"
+	parentField := orm.elem.FieldByName(schema.treeParentColumn)
+	if !parentField.IsNil() {
+		parent := parentField.Interface().(Entity)
+		if !parent.IsLoaded() {
+			f.engine.Load(parent)
+		}
+		parentORM := parent.getORM()
+		path = parentORM.elem.FieldByName(schema.treePathColumn).String() + strconv.FormatUint(parent.GetID(), 10) + "/"
+		id := orm.GetID()
+		if id > 0 && strings.Contains(path, "/"+strconv.FormatUint(id, 10)+"/") {
+			panic(fmt.Errorf("entity '%s' with ID %d can't be moved into its own subtree", schema.t.String(), id))
+		}
+	}
+	orm.elem.FieldByName(schema.treePathColumn).SetString(path)
+}
+
+func (f *flusher) addTreeMove(orm *ORM, bindBuilder *bindBuilder) {
+	newPath, has := bindBuilder.bind[orm.tableSchema.treePathColumn]
+	if !has || !orm.inDB || orm.delete {
+		return
+	}
+	oldPath, _ := bindBuilder.current[orm.tableSchema.treePathColumn].(string)
+	id := strconv.FormatUint(orm.GetID(), 10) + "/"
+	f.treeMoves = append(f.treeMoves, treeMove{schema: orm.tableSchema, oldPrefix: oldPath + id, newPrefix: newPath.(string) + id})
+}
+
+func (f *flusher) flushTreeMoves() {
+	for _, move := range f.treeMoves {
+		column := move.schema.getColumnName(move.schema.treePathColumn)
+		db := move.schema.GetMysql(f.engine)
+		where := NewWhere("`"+column+"` LIKE ?", move.oldPrefix+"%")
+		where.ShowFakeDeleted()
+		ids := make([]uint64, 0)
+		for page := 1; ; page++ {
+			pageIDs, _ := searchIDs(f.engine, where, NewPager(page, 50000), false, move.schema.t)
+			ids = append(ids, pageIDs...)
+			if len(pageIDs) < 50000 {
+				break
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		/* #nosec */
+		db.Exec("UPDATE `"+move.schema.tableName+"` SET `"+column+"` = CONCAT(?, SUBSTRING(`"+column+"`, ?)) WHERE `"+column+"` LIKE ?",
+			move.newPrefix, len(move.oldPrefix)+1, move.oldPrefix+"%")
+		clearByIDs(f.engine, move.schema.NewEntity(), ids...)
+	}
+	f.treeMoves = nil
+}
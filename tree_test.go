@@ -0,0 +1,95 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type treeEntity struct {
+	ORM    `orm:"redisCache"`
+	ID     uint
+	Name   string
+	Parent *treeEntity `orm:"tree"`
+	Path   string      `orm:"treePath"`
+}
+
+type treeEntityInvalid struct {
+	ORM
+	ID     uint
+	Parent *treeEntityInvalid `orm:"tree"`
+}
+
+func TestTree(t *testing.T) {
+	var entity *treeEntity
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	assert.Len(t, engine.GetAlters(), 0)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity).(*tableSchema)
+	assert.Equal(t, "Parent", schema.treeParentColumn)
+	assert.Equal(t, "Path", schema.treePathColumn)
+
+	root := &treeEntity{Name: "root"}
+	a := &treeEntity{Name: "a", Parent: root}
+	b := &treeEntity{Name: "b", Parent: a}
+	c := &treeEntity{Name: "c", Parent: b}
+	other := &treeEntity{Name: "other"}
+	engine.Flush(c, other)
+	assert.Equal(t, "/", root.Path)
+	assert.Equal(t, "/1/", a.Path)
+	assert.Equal(t, "/1/2/", b.Path)
+	assert.Equal(t, "/1/2/3/", c.Path)
+
+	var rows []*treeEntity
+	engine.GetTreeAncestors(c, &rows)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, "root", rows[0].Name)
+	assert.Equal(t, "b", rows[2].Name)
+
+	rows = nil
+	engine.GetTreeDescendants(root, &rows, nil)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, "a", rows[0].Name)
+	assert.Equal(t, "c", rows[2].Name)
+
+	loaded := &treeEntity{}
+	assert.True(t, engine.LoadByID(4, loaded))
+	assert.Equal(t, "/1/2/3/", loaded.Path)
+
+	a = &treeEntity{}
+	engine.LoadByID(2, a)
+	engine.MoveTreeNode(a, &treeEntity{ID: 5})
+	assert.Equal(t, "/5/", a.Path)
+	loaded = &treeEntity{}
+	assert.True(t, engine.LoadByID(4, loaded))
+	assert.Equal(t, "/5/2/3/", loaded.Path)
+	rows = nil
+	engine.GetTreeDescendants(root, &rows, nil)
+	assert.Len(t, rows, 0)
+	other = &treeEntity{}
+	engine.LoadByID(5, other)
+	engine.GetTreeDescendants(other, &rows, nil)
+	assert.Len(t, rows, 3)
+
+	assert.PanicsWithError(t, "entity 'beeorm.treeEntity' with ID 5 can't be moved into its own subtree", func() {
+		engine.MoveTreeNode(other, loaded)
+	})
+	other = &treeEntity{}
+	engine.LoadByID(5, other)
+
+	engine.MoveTreeNode(loaded, nil)
+	assert.Equal(t, "/", loaded.Path)
+	rows = nil
+	engine.GetTreeDescendants(other, &rows, nil)
+	assert.Len(t, rows, 2)
+
+	assert.PanicsWithError(t, "entity 'beeorm.flushEntity' is not a tree", func() {
+		engine.GetTreeAncestors(&flushEntity{}, &rows)
+	})
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&treeEntityInvalid{})
+	_, err := registry.Validate()
+	assert.EqualError(t, err, "tree entity beeorm.treeEntityInvalid requires both tree and treePath fields")
+}
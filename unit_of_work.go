@@ -0,0 +1,73 @@
+package beeorm
+
+import "fmt"
+
+func (e *engineImplementation) EnableUnitOfWork() {
+	e.unitOfWork = true
+}
+
+func (e *engineImplementation) DisableUnitOfWork() {
+	e.unitOfWork = false
+	e.unitOfWorkEntities = nil
+	e.unitOfWorkTracked = nil
+}
+
+func (e *engineImplementation) Track(entity ...Entity) {
+	if !e.unitOfWork {
+		panic(fmt.Errorf("unit of work is not enabled"))
+	}
+	for _, entity := range entity {
+		initIfNeeded(e.registry, entity)
+		e.trackInUnitOfWork(entity)
+	}
+}
+
+func (e *engineImplementation) Commit() {
+	if !e.unitOfWork {
+		panic(fmt.Errorf("unit of work is not enabled"))
+	}
+	flusher := e.NewFlusher()
+	tracked := 0
+	for _, entity := range e.unitOfWorkEntities {
+		orm := entity.getORM()
+		if orm.inDB && !orm.IsToDelete() && !orm.IsDirty() {
+			continue
+		}
+		flusher.Track(entity)
+		tracked++
+		if tracked == 10000 {
+			flusher.Flush()
+			tracked = 0
+		}
+	}
+	flusher.Flush()
+	entities := e.unitOfWorkEntities[:0]
+	for _, entity := range e.unitOfWorkEntities {
+		orm := entity.getORM()
+		if orm.inDB && !orm.IsToDelete() {
+			entities = append(entities, entity)
+		} else {
+			delete(e.unitOfWorkTracked, entity)
+		}
+	}
+	e.unitOfWorkEntities = entities
+}
+
+func (e *engineImplementation) entityLoaded(entity Entity) Entity {
+	entity = e.addToIdentityMap(entity)
+	if e.unitOfWork {
+		e.trackInUnitOfWork(entity)
+	}
+	return entity
+}
+
+func (e *engineImplementation) trackInUnitOfWork(entity Entity) {
+	if e.unitOfWorkTracked[entity] {
+		return
+	}
+	if e.unitOfWorkTracked == nil {
+		e.unitOfWorkTracked = make(map[Entity]bool)
+	}
+	e.unitOfWorkTracked[entity] = true
+	e.unitOfWorkEntities = append(e.unitOfWorkEntities, entity)
+}
@@ -0,0 +1,76 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type unitOfWorkEntity struct {
+	ORM       `orm:"localCache"`
+	ID        uint
+	Name      string
+	Reference *unitOfWorkReference
+}
+
+type unitOfWorkReference struct {
+	ORM  `orm:"localCache"`
+	ID   uint
+	Name string
+}
+
+func TestUnitOfWork(t *testing.T) {
+	var entity *unitOfWorkEntity
+	var reference *unitOfWorkReference
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", entity, reference)
+	engine.Flush(&unitOfWorkEntity{Name: "a"}, &unitOfWorkEntity{Name: "b"}, &unitOfWorkEntity{Name: "c"})
+
+	assert.PanicsWithError(t, "unit of work is not enabled", func() {
+		engine.Commit()
+	})
+
+	engine.EnableUnitOfWork()
+	first := &unitOfWorkEntity{}
+	engine.LoadByID(1, first)
+	var rows []*unitOfWorkEntity
+	engine.LoadByIDs([]uint64{2, 3}, &rows)
+	first.Name = "a2"
+	rows[0].Reference = &unitOfWorkReference{Name: "ref"}
+	added := &unitOfWorkEntity{Name: "d"}
+	engine.Track(added)
+
+	dbLogger := &testLogHandler{}
+	engine.RegisterQueryLogger(dbLogger, true, false, false)
+	engine.Commit()
+	assert.NotEmpty(t, dbLogger.Logs)
+	assert.Equal(t, uint64(4), added.GetID())
+	assert.Equal(t, uint64(1), rows[0].Reference.GetID())
+
+	engine.GetLocalCache().Clear()
+	loaded := &unitOfWorkEntity{}
+	engine.DisableUnitOfWork()
+	engine.LoadByID(1, loaded)
+	assert.Equal(t, "a2", loaded.Name)
+	engine.LoadByID(2, loaded, "Reference")
+	assert.Equal(t, "ref", loaded.Reference.Name)
+	engine.LoadByID(4, loaded)
+	assert.Equal(t, "d", loaded.Name)
+
+	engine.EnableUnitOfWork()
+	engine.Search(NewWhere("1"), nil, &rows)
+	dbLogger.clear()
+	engine.Commit()
+	assert.Len(t, dbLogger.Logs, 0)
+
+	rows[3].Name = "d2"
+	engine.Commit()
+	engine.GetLocalCache().Clear()
+	engine.LoadByID(4, loaded)
+	assert.Equal(t, "d2", loaded.Name)
+
+	engine.DisableUnitOfWork()
+	assert.PanicsWithError(t, "unit of work is not enabled", func() {
+		engine.Track(&unitOfWorkEntity{})
+	})
+}
@@ -0,0 +1,70 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+
+	googleuuid "github.com/google/uuid"
+)
+
+func initUUIDFields(tableSchema *tableSchema, entityType reflect.Type) error {
+	tableSchema.uuidGenerators = nil
+	for i := 1; i < entityType.NumField(); i++ {
+		field := entityType.Field(i)
+		tags := tableSchema.tags[field.Name]
+		version, hasVersion := tags["uuid"]
+		storage, hasStorage := tags["uuidStorage"]
+		if !hasVersion && !hasStorage {
+			continue
+		}
+		t := field.Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t != uuidType && field.Type.Kind() != reflect.String {
+			return fmt.Errorf("uuid field %s in %s must be uuid.UUID or string", field.Name, entityType.String())
+		}
+		if hasStorage && storage != "char" && (storage != "binary" || t != uuidType) {
+			return fmt.Errorf("invalid uuid storage '%s' for field %s in %s", storage, field.Name, entityType.String())
+		}
+		switch version {
+		case "", "true":
+		case "v4", "v7":
+			if tableSchema.uuidGenerators == nil {
+				tableSchema.uuidGenerators = make(map[string]string)
+			}
+			tableSchema.uuidGenerators[field.Name] = version
+		default:
+			return fmt.Errorf("invalid uuid version '%s' for field %s in %s", version, field.Name, entityType.String())
+		}
+	}
+	return nil
+}
+
+func (orm *ORM) fillUUIDs() bool {
+	changed := false
+	for field, version := range orm.tableSchema.uuidGenerators {
+		fieldValue := orm.elem.FieldByName(field)
+		if !fieldValue.IsZero() {
+			continue
+		}
+		var value googleuuid.UUID
+		var err error
+		if version == "v7" {
+			value, err = googleuuid.NewV7()
+		} else {
+			value, err = googleuuid.NewRandom()
+		}
+		checkError(err)
+		switch fieldValue.Kind() {
+		case reflect.String:
+			fieldValue.SetString(value.String())
+		case reflect.Ptr:
+			fieldValue.Set(reflect.ValueOf(&value))
+		default:
+			fieldValue.Set(reflect.ValueOf(value))
+		}
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,75 @@
+package beeorm
+
+import (
+	"testing"
+
+	googleuuid "github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type uuidFieldsEntity struct {
+	ORM       `orm:"localCache;redisCache"`
+	ID        uint
+	PublicID  googleuuid.UUID  `orm:"uuid=v7;unique=PublicID"`
+	Reference *googleuuid.UUID `orm:"uuidStorage=char"`
+	Token     string           `orm:"uuid=v4;required"`
+}
+
+type uuidFieldsInvalidEntity struct {
+	ORM
+	ID    uint
+	Token string `orm:"uuid=v9"`
+}
+
+func TestUUIDFields(t *testing.T) {
+	var entity *uuidFieldsEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	schema.DropTable(engine)
+	_, alters := schema.GetSchemaChanges(engine)
+	assert.Equal(t, "CREATE TABLE `test`.`uuidFieldsEntity` (\n  `ID` int(10) unsigned NOT NULL AUTO_INCREMENT,\n  "+
+		"`Token` char(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '',\n  `PublicID` binary(16) NOT NULL,\n  "+
+		"`Reference` char(36) CHARACTER SET ascii COLLATE ascii_bin DEFAULT NULL,\n  UNIQUE INDEX `PublicID` (`PublicID`),\n  "+
+		"PRIMARY KEY (`ID`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;", alters[0].SQL)
+	schema.UpdateSchema(engine)
+	assert.Len(t, engine.GetAlters(), 0)
+
+	entity = &uuidFieldsEntity{}
+	engine.Flush(entity)
+	assert.Equal(t, googleuuid.Version(7), entity.PublicID.Version())
+	assert.Nil(t, entity.Reference)
+	token, err := googleuuid.Parse(entity.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, googleuuid.Version(4), token.Version())
+	assert.False(t, entity.IsDirty())
+
+	fixed := googleuuid.MustParse("0b6f1b0c-7a11-4fd4-9b3c-2f1a0e5d7c11")
+	second := &uuidFieldsEntity{PublicID: fixed, Reference: &fixed, Token: "f47ac10b-58cc-4372-a567-0e02b2c3d479"}
+	engine.Flush(second)
+	assert.Equal(t, fixed, second.PublicID)
+	assert.Equal(t, "f47ac10b-58cc-4372-a567-0e02b2c3d479", second.Token)
+
+	engine.GetLocalCache().Clear()
+	engine.GetRedis().FlushDB()
+	loaded := &uuidFieldsEntity{}
+	assert.True(t, engine.LoadByID(2, loaded))
+	assert.Equal(t, fixed, loaded.PublicID)
+	assert.Equal(t, fixed, *loaded.Reference)
+	assert.False(t, loaded.IsDirty())
+	var raw string
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Reference` FROM `uuidFieldsEntity` WHERE `ID` = 2"), &raw)
+	assert.Equal(t, "0b6f1b0c-7a11-4fd4-9b3c-2f1a0e5d7c11", raw)
+
+	loaded = &uuidFieldsEntity{}
+	assert.True(t, engine.SearchOne(NewWhere("`PublicID` = ?", fixed[:]), loaded))
+	assert.Equal(t, uint(2), loaded.ID)
+	loaded = &uuidFieldsEntity{}
+	assert.True(t, engine.LoadByID(1, loaded))
+	assert.Equal(t, entity.PublicID, loaded.PublicID)
+
+	registry := &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&uuidFieldsInvalidEntity{})
+	_, err = registry.Validate()
+	assert.EqualError(t, err, "invalid uuid version 'v9' for field Token in beeorm.uuidFieldsInvalidEntity")
+}
@@ -3,6 +3,8 @@ package beeorm
 import (
 	"fmt"
 	"reflect"
+	"strings"
+	"sync"
 )
 
 type ValidatedRegistry interface {
@@ -17,26 +19,127 @@ type ValidatedRegistry interface {
 	GetLocalCachePools() map[string]LocalCachePoolConfig
 	GetRedisPools() map[string]RedisPoolConfig
 	GetEntities() map[string]reflect.Type
+	GetPlugin(code string) Plugin
+	GetJetStreamPools() map[string]JetStreamPoolConfig
+	CreateTestClone(suffix string) ValidatedRegistry
+	EncodeJSON(value interface{}) ([]byte, error)
+	DecodeJSON(data []byte, value interface{}) error
+	ApplyRuntimeConfig(config RuntimeConfig) error
+	GetLockerMetrics() map[string]LockerMetrics
 }
 
 type validatedRegistry struct {
-	registry           *Registry
-	tableSchemas       map[reflect.Type]*tableSchema
-	entities           map[string]reflect.Type
-	localCacheServers  map[string]LocalCachePoolConfig
-	mySQLServers       map[string]MySQLPoolConfig
-	redisServers       map[string]RedisPoolConfig
-	redisStreamGroups  map[string]map[string]map[string]bool
-	redisStreamPools   map[string]string
-	enums              map[string]Enum
-	timeOffset         int64
-	defaultQueryLogger *defaultLogLogger
+	registry                      *Registry
+	tableSchemas                  map[reflect.Type]*tableSchema
+	entities                      map[string]reflect.Type
+	localCacheServers             map[string]LocalCachePoolConfig
+	mySQLServers                  map[string]MySQLPoolConfig
+	redisServers                  map[string]RedisPoolConfig
+	redisStreamGroups             map[string]map[string]map[string]bool
+	redisStreamPools              map[string]string
+	enums                         map[string]Enum
+	timeOffset                    int64
+	defaultQueryLogger            *defaultLogLogger
+	plugins                       []Plugin
+	hasFlushedPlugin              bool
+	hasFlushedInTransactionPlugin bool
+	jetStreamServers              map[string]JetStreamPoolConfig
+	jetStreamGroups               map[string]map[string]map[string]bool
+	jetStreamStreamPools          map[string]string
+	writeFreeze                   writeFreeze
+	runtime                       runtimeConfig
+	payloadCodec                  PayloadCodec
+	payloadCodecs                 map[string]PayloadCodec
+	mysqlTimeouts                 map[string]*mySQLTimeout
+	lockerMetrics                 sync.Map
+	deliveryRates                 streamDeliveryRates
 }
 
 func (r *validatedRegistry) GetSourceRegistry() *Registry {
 	return r.registry
 }
 
+func (r *validatedRegistry) CreateTestClone(suffix string) ValidatedRegistry {
+	source := r.registry
+	registry := &Registry{defaultEncoding: source.defaultEncoding, defaultCollate: source.defaultCollate,
+		enforcePagination: source.enforcePagination, timestampsLocation: source.timestampsLocation,
+		redisFailoverHandlers: source.redisFailoverHandlers, validators: source.validators,
+		cacheCompressor: source.cacheCompressor, cacheCompressionMinSize: source.cacheCompressionMinSize,
+		jsonStringIDs: source.jsonStringIDs, objectStores: source.objectStores,
+		strictEnums: source.strictEnums, idGenerators: source.idGenerators,
+		columnNaming: source.columnNaming, referencesPresets: source.referencesPresets,
+		projections: source.projections, indexers: source.indexers,
+		crudSubscribers: source.crudSubscribers, webhooks: source.webhooks,
+		cronJobs: source.cronJobs, intEnums: source.intEnums, redisScripts: source.redisScripts,
+		payloadCodecs: source.payloadCodecs, payloadCodec: source.payloadCodec,
+		mysqlTimeouts: source.mysqlTimeouts, redisTimeouts: source.redisTimeouts,
+		cacheDoubleDeleteDelay: source.cacheDoubleDeleteDelay, cacheDoubleDeleteError: source.cacheDoubleDeleteError}
+	registry.mysqlPools = make(map[string]MySQLPoolConfig)
+	for code, pool := range r.mySQLServers {
+		config := pool.(*mySQLPoolConfig)
+		databaseName := config.databaseName + "_" + suffix
+		_, err := config.getClient().Exec("CREATE DATABASE IF NOT EXISTS `" + databaseName + "`")
+		checkError(err)
+		parts := strings.SplitN(config.dataSourceName, "?", 2)
+		parts[0] = strings.TrimSuffix(parts[0], config.databaseName) + databaseName
+		registry.mysqlPools[code] = &mySQLPoolConfig{code: code, dataSourceName: strings.Join(parts, "?"),
+			databaseName: databaseName, maxConnections: config.maxConnections, maxInSize: config.maxInSize}
+	}
+	registry.redisPools = make(map[string]RedisPoolConfig)
+	for code, pool := range r.redisServers {
+		config := pool.(*redisCacheConfig)
+		namespace := suffix
+		if config.namespace != "" {
+			namespace = config.namespace + "_" + suffix
+		}
+		registry.redisPools[code] = &redisCacheConfig{code: code, client: config.client, db: config.db,
+			address: config.address, namespace: namespace, hasNamespace: true, sentinel: config.sentinel}
+	}
+	registry.localCachePools = make(map[string]LocalCachePoolConfig)
+	for code, pool := range r.localCacheServers {
+		registry.localCachePools[code] = newLocalCacheConfig(code, pool.GetLimit())
+	}
+	registry.entities = make(map[string]reflect.Type)
+	for name, t := range r.entities {
+		registry.entities[name] = t
+	}
+	registry.enums = make(map[string]Enum)
+	for code, enum := range r.enums {
+		registry.enums[code] = enum
+	}
+	for pool, streams := range source.redisStreamGroups {
+		for stream, groups := range streams {
+			groupNames := make([]string, 0, len(groups))
+			for group := range groups {
+				groupNames = append(groupNames, group)
+			}
+			registry.RegisterRedisStream(stream, pool, groupNames)
+		}
+	}
+	for _, plugin := range source.plugins {
+		registry.RegisterPlugin(plugin)
+	}
+	for code, pool := range source.jetStreamPools {
+		registry.RegisterJetStream(pool.GetClient(), code)
+	}
+	for pool, streams := range source.jetStreamGroups {
+		for stream, groups := range streams {
+			groupNames := make([]string, 0, len(groups))
+			for group := range groups {
+				groupNames = append(groupNames, group)
+			}
+			registry.RegisterJetStreamStream(stream, pool, groupNames)
+		}
+	}
+	validated, err := registry.Validate()
+	checkError(err)
+	engine := validated.CreateEngine()
+	for _, alter := range engine.GetAlters() {
+		alter.Exec()
+	}
+	return validated
+}
+
 func (r *validatedRegistry) GetEntities() map[string]reflect.Type {
 	return r.entities
 }
@@ -83,7 +186,10 @@ func (r *validatedRegistry) GetRedisPools() map[string]RedisPoolConfig {
 }
 
 func (r *validatedRegistry) CreateEngine() Engine {
-	return &engineImplementation{registry: r}
+	e := getPooledEngine()
+	e.registry = r
+	e.queryTimeLimit = r.runtime.getQueryTimeLimit()
+	return e
 }
 
 func (r *validatedRegistry) GetTableSchema(entityName string) TableSchema {
@@ -85,3 +85,25 @@ func TestValidatedRegistry(t *testing.T) {
 		assert.Equal(t, "Ref", data[0])
 	}
 }
+
+func TestValidatedRegistryCreateTestClone(t *testing.T) {
+	var entity *validatedRegistryEntity
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+
+	clone := engine.GetRegistry().CreateTestClone("clone")
+	assert.Equal(t, "test_clone", clone.GetMySQLPools()["default"].GetDatabase())
+	assert.Equal(t, "root:root@tcp(localhost:3311)/test_clone?multiStatements=true", clone.GetMySQLPools()["default"].GetDataSourceURI())
+	assert.Equal(t, "clone", clone.GetRedisPools()["default"].GetNamespace())
+	assert.Len(t, clone.GetEntities(), 1)
+
+	cloneEngine := clone.CreateEngine()
+	assert.Len(t, cloneEngine.GetAlters(), 0)
+	cloneEngine.Flush(&validatedRegistryEntity{})
+	total := 0
+	found := cloneEngine.GetMysql().QueryRow(NewWhere("SELECT COUNT(*) FROM validatedRegistryEntity"), &total)
+	assert.True(t, found)
+	assert.Equal(t, 1, total)
+	engine.GetMysql().QueryRow(NewWhere("SELECT COUNT(*) FROM validatedRegistryEntity"), &total)
+	assert.Equal(t, 0, total)
+}
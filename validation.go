@@ -0,0 +1,198 @@
+package beeorm
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var emailRegexp = regexp.MustCompile(`^[a-zA-Z0-9.!#$%&'*+/=?^_{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)+$`)
+
+type EntityValidator func(entity Entity) []FieldValidationError
+
+type FieldValidationError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+type ValidationError struct {
+	Entity string
+	ID     uint64
+	Errors []FieldValidationError
+}
+
+func (err *ValidationError) Error() string {
+	messages := make([]string, len(err.Errors))
+	for i, fieldError := range err.Errors {
+		messages[i] = fieldError.Field + ": " + fieldError.Message
+	}
+	return fmt.Sprintf("validation failed for %s: %s", err.Entity, strings.Join(messages, ", "))
+}
+
+type fieldValidation struct {
+	field    string
+	index    int
+	notEmpty bool
+	email    bool
+	hasMin   bool
+	min      float64
+	hasMax   bool
+	max      float64
+	enum     Enum
+}
+
+func (r *Registry) RegisterValidator(validator EntityValidator) {
+	r.validators = append(r.validators, validator)
+}
+
+func (r *Registry) EnableStrictEnums() {
+	r.strictEnums = true
+}
+
+func initValidations(tableSchema *tableSchema, registry *Registry, entityType reflect.Type) error {
+	for field, tags := range tableSchema.tags {
+		_, hasMin := tags["min"]
+		_, hasMax := tags["max"]
+		notEmpty := tags["notEmpty"] == "true"
+		email := tags["email"] == "true"
+		var enum Enum
+		if registry.strictEnums {
+			enumCode, hasEnum := tags["enum"]
+			if !hasEnum {
+				enumCode, hasEnum = tags["set"]
+			}
+			if hasEnum {
+				enum = registry.enums[enumCode]
+			}
+		}
+		if !hasMin && !hasMax && !notEmpty && !email && enum == nil {
+			continue
+		}
+		structField, has := entityType.FieldByName(field)
+		if !has {
+			continue
+		}
+		validation := fieldValidation{field: field, index: structField.Index[0], notEmpty: notEmpty, email: email, hasMin: hasMin,
+			hasMax: hasMax, enum: enum}
+		if email && structField.Type.Kind() != reflect.String {
+			return fmt.Errorf("email validation for field %s in %s requires string", field, entityType.String())
+		}
+		for _, rule := range []string{"min", "max"} {
+			value, has := tags[rule]
+			if !has {
+				continue
+			}
+			asFloat, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s '%s' for field %s in %s", rule, value, field, entityType.String())
+			}
+			if rule == "min" {
+				validation.min = asFloat
+			} else {
+				validation.max = asFloat
+			}
+		}
+		tableSchema.validations = append(tableSchema.validations, validation)
+	}
+	sort.Slice(tableSchema.validations, func(i, j int) bool {
+		return tableSchema.validations[i].index < tableSchema.validations[j].index
+	})
+	return nil
+}
+
+func validateEntity(entity Entity) []FieldValidationError {
+	orm := entity.getORM()
+	errors := make([]FieldValidationError, 0)
+	for _, validation := range orm.tableSchema.validations {
+		errors = append(errors, validation.validate(orm.elem.FieldByName(validation.field))...)
+	}
+	for _, validator := range orm.tableSchema.registry.registry.validators {
+		errors = append(errors, validator(entity)...)
+	}
+	return errors
+}
+
+func (v fieldValidation) validate(field reflect.Value) []FieldValidationError {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			if v.notEmpty {
+				return []FieldValidationError{{Field: v.field, Rule: "notEmpty", Message: "value is required"}}
+			}
+			return nil
+		}
+		field = field.Elem()
+	}
+	if v.notEmpty && field.IsZero() {
+		return []FieldValidationError{{Field: v.field, Rule: "notEmpty", Message: "value is required"}}
+	}
+	errors := make([]FieldValidationError, 0)
+	if v.enum != nil {
+		values, isSlice := field.Interface().([]string)
+		if !isSlice && field.Kind() == reflect.String && field.String() != "" {
+			values = []string{field.String()}
+		}
+		for _, value := range values {
+			if !v.enum.Has(value) {
+				errors = append(errors, FieldValidationError{Field: v.field, Rule: "enum", Message: "invalid value '" + value + "'"})
+			}
+		}
+	}
+	var size float64
+	unit := ""
+	switch field.Kind() {
+	case reflect.String:
+		if field.String() == "" {
+			return nil
+		}
+		size = float64(utf8.RuneCountInString(field.String()))
+		unit = " characters"
+		if v.email && !emailRegexp.MatchString(field.String()) {
+			errors = append(errors, FieldValidationError{Field: v.field, Rule: "email", Message: "invalid email"})
+		}
+	case reflect.Slice, reflect.Map:
+		size = float64(field.Len())
+		unit = " elements"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = float64(field.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = float64(field.Uint())
+	case reflect.Float32, reflect.Float64:
+		size = field.Float()
+	default:
+		return errors
+	}
+	if v.hasMin && size < v.min {
+		errors = append(errors, FieldValidationError{Field: v.field, Rule: "min",
+			Message: "must be at least " + strconv.FormatFloat(v.min, 'f', -1, 64) + unit})
+	}
+	if v.hasMax && size > v.max {
+		errors = append(errors, FieldValidationError{Field: v.field, Rule: "max",
+			Message: "must be at most " + strconv.FormatFloat(v.max, 'f', -1, 64) + unit})
+	}
+	return errors
+}
+
+func (f *flusher) validateTrackedEntities() error {
+	for _, entity := range f.trackedEntities {
+		initIfNeeded(f.engine.registry, entity)
+		if entity.IsToDelete() {
+			continue
+		}
+		errors := validateEntity(entity)
+		if len(errors) > 0 {
+			f.Clear()
+			return &ValidationError{Entity: entity.getORM().tableSchema.t.String(), ID: entity.GetID(), Errors: errors}
+		}
+	}
+	return nil
+}
+
+func (b *bindBuilder) newEnumValueError(field, value string) *ValidationError {
+	return &ValidationError{Entity: b.orm.tableSchema.t.String(), ID: b.id,
+		Errors: []FieldValidationError{{Field: field, Rule: "enum", Message: "invalid value '" + value + "'"}}}
+}
@@ -0,0 +1,138 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type validationEntity struct {
+	ORM
+	ID    uint
+	Name  string   `orm:"notEmpty;min=3;max=10"`
+	Email string   `orm:"email"`
+	Age   *uint8   `orm:"min=18;max=99"`
+	Tags  []string `orm:"max=2"`
+}
+
+type validationEnumEntity struct {
+	ORM      `orm:"localCache"`
+	ID       uint
+	Status   string   `orm:"enum=beeorm.TestEnum;required"`
+	Optional *string  `orm:"enum=beeorm.TestEnum"`
+	Flags    []string `orm:"set=beeorm.TestSet"`
+}
+
+type validationInvalidEntity struct {
+	ORM
+	ID   uint
+	Name string `orm:"min=abc"`
+}
+
+func TestValidation(t *testing.T) {
+	var entity *validationEntity
+	registry := &Registry{}
+	registry.RegisterValidator(func(entity Entity) []FieldValidationError {
+		e, is := entity.(*validationEntity)
+		if is && e.Name == "forbidden" {
+			return []FieldValidationError{{Field: "Name", Rule: "custom", Message: "name is forbidden"}}
+		}
+		return nil
+	})
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+
+	age := uint8(10)
+	entity = &validationEntity{Name: "ab", Email: "invalid", Age: &age, Tags: []string{"a", "b", "c"}}
+	err := engine.FlushWithCheck(entity)
+	assert.EqualError(t, err, "validation failed for beeorm.validationEntity: Name: must be at least 3 characters, "+
+		"Email: invalid email, Age: must be at least 18, Tags: must be at most 2 elements")
+	validationErr, is := err.(*ValidationError)
+	assert.True(t, is)
+	assert.Equal(t, "beeorm.validationEntity", validationErr.Entity)
+	assert.Len(t, validationErr.Errors, 4)
+	assert.Equal(t, "min", validationErr.Errors[0].Rule)
+	assert.False(t, engine.LoadByID(1, &validationEntity{}))
+
+	err = engine.FlushWithFullCheck(&validationEntity{})
+	assert.EqualError(t, err, "validation failed for beeorm.validationEntity: Name: value is required")
+	err = engine.FlushWithCheck(&validationEntity{Name: "forbidden"})
+	assert.EqualError(t, err, "validation failed for beeorm.validationEntity: Name: name is forbidden")
+
+	age = 20
+	entity = &validationEntity{Name: "John", Email: "john@example.com", Age: &age}
+	assert.NoError(t, engine.FlushWithCheck(entity))
+	assert.True(t, engine.LoadByID(1, &validationEntity{}))
+
+	entity.Name = "a"
+	engine.Flush(entity)
+	entity.Email = "wrong"
+	assert.Error(t, engine.FlushWithCheck(entity))
+	engine.Delete(entity)
+	assert.False(t, engine.LoadByID(1, &validationEntity{}))
+
+	registry = &Registry{}
+	registry.RegisterMySQLPool("root:root@tcp(localhost:3311)/test")
+	registry.RegisterEntity(&validationInvalidEntity{})
+	_, err = registry.Validate()
+	assert.EqualError(t, err, "invalid min 'abc' for field Name in beeorm.validationInvalidEntity")
+}
+
+func TestStrictEnums(t *testing.T) {
+	var entity *validationEnumEntity
+	registry := &Registry{}
+	registry.RegisterEnum("beeorm.TestEnum", []string{"a", "b", "c"})
+	registry.RegisterEnum("beeorm.TestSet", []string{"a", "b", "c"})
+	registry.EnableStrictEnums()
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+	schema := engine.GetRegistry().GetTableSchemaForEntity(entity)
+	schema.DropTable(engine)
+	_, alters := schema.GetSchemaChanges(engine)
+	assert.Equal(t, "CREATE TABLE `test`.`validationEnumEntity` (\n  `ID` int(10) unsigned NOT NULL AUTO_INCREMENT,\n  "+
+		"`Status` enum('a','b','c') NOT NULL DEFAULT 'a',\n  `Optional` enum('a','b','c') DEFAULT NULL,\n  "+
+		"`Flags` set('a','b','c') DEFAULT NULL,\n  PRIMARY KEY (`ID`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;", alters[0].SQL)
+	schema.UpdateSchema(engine)
+
+	entity = &validationEnumEntity{Status: "b"}
+	engine.Flush(entity)
+	entity = &validationEnumEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Nil(t, entity.Optional)
+	var optional *string
+	engine.GetMysql().QueryRow(NewWhere("SELECT `Optional` FROM `validationEnumEntity` WHERE `ID` = 1"), &optional)
+	assert.Nil(t, optional)
+
+	value := "c"
+	entity.Optional = &value
+	engine.Flush(entity)
+	entity = &validationEnumEntity{}
+	engine.GetLocalCache().Clear()
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "c", *entity.Optional)
+	entity.Optional = nil
+	assert.True(t, entity.IsDirty())
+	engine.Flush(entity)
+	entity = &validationEnumEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Nil(t, entity.Optional)
+
+	invalid := "x"
+	entity.Status = "d"
+	entity.Optional = &invalid
+	entity.Flags = []string{"a", "z"}
+	err := engine.FlushWithCheck(entity)
+	assert.EqualError(t, err, "validation failed for beeorm.validationEnumEntity: Status: invalid value 'd', "+
+		"Optional: invalid value 'x', Flags: invalid value 'z'")
+	validationErr, is := err.(*ValidationError)
+	assert.True(t, is)
+	assert.Equal(t, "enum", validationErr.Errors[0].Rule)
+
+	entity.Optional = nil
+	entity.Status = "a"
+	assert.PanicsWithError(t, "validation failed for beeorm.validationEnumEntity: Flags: invalid value 'z'", func() {
+		engine.Flush(entity)
+	})
+	entity = &validationEnumEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "b", entity.Status)
+	assert.Nil(t, entity.Flags)
+}
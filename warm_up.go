@@ -0,0 +1,307 @@
+package beeorm
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const warmUpDefaultHotnessLimit = 1000
+
+type WarmUpPlan struct {
+	Entities      []WarmUpEntity
+	CachedQueries []WarmUpCachedQuery
+	Concurrency   int
+	Progress      WarmUpProgressHandler
+}
+
+type WarmUpEntity struct {
+	Entity      Entity
+	IDs         []uint64
+	HotnessPool string
+	Limit       int
+	References  []string
+}
+
+type WarmUpCachedQuery struct {
+	Entity    Entity
+	Name      string
+	Pager     *Pager
+	Arguments [][]interface{}
+}
+
+type WarmUpProgress struct {
+	Step   string
+	Done   int
+	Total  int
+	Loaded int
+}
+
+type WarmUpProgressHandler func(progress WarmUpProgress)
+
+type warmUpTask struct {
+	step string
+	run  func(engine Engine) int
+}
+
+func RecordWarmUpHotness(engine Engine, redisPool string, entity Entity, ids ...uint64) {
+	schema := initIfNeeded(engine.(*engineImplementation).registry, entity).tableSchema
+	redisCache := engine.GetRedis(redisPool)
+	key := warmUpHotnessKey(schema)
+	for _, id := range ids {
+		redisCache.ZIncrBy(key, 1, strconv.FormatUint(id, 10))
+	}
+}
+
+func warmUp(engine *engineImplementation, plan WarmUpPlan) {
+	tasks := make([]warmUpTask, 0)
+	for _, definition := range plan.Entities {
+		tasks = append(tasks, warmUpEntityTask(engine, definition))
+	}
+	for _, definition := range plan.CachedQueries {
+		tasks = append(tasks, warmUpCachedQueryTasks(engine, definition)...)
+	}
+	if len(tasks) == 0 {
+		return
+	}
+	concurrency := plan.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if concurrency > len(tasks) {
+		concurrency = len(tasks)
+	}
+	queue := make(chan warmUpTask, len(tasks))
+	for _, task := range tasks {
+		queue <- task
+	}
+	close(queue)
+	progress := WarmUpProgress{Total: len(tasks)}
+	var mutex sync.Mutex
+	var panicValue interface{}
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer func() {
+				if rec := recover(); rec != nil {
+					mutex.Lock()
+					panicValue = rec
+					mutex.Unlock()
+				}
+				wg.Done()
+			}()
+			workerEngine := engine.Clone()
+			for task := range queue {
+				loaded := task.run(workerEngine)
+				mutex.Lock()
+				progress.Step = task.step
+				progress.Done++
+				progress.Loaded += loaded
+				if plan.Progress != nil {
+					plan.Progress(progress)
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if panicValue != nil {
+		panic(panicValue)
+	}
+}
+
+func warmUpEntityTask(engine *engineImplementation, definition WarmUpEntity) warmUpTask {
+	schema := initIfNeeded(engine.registry, definition.Entity).tableSchema
+	return warmUpTask{step: schema.t.String(), run: func(engine Engine) int {
+		ids := definition.IDs
+		if definition.HotnessPool != "" {
+			limit := definition.Limit
+			if limit <= 0 {
+				limit = warmUpDefaultHotnessLimit
+			}
+			for _, member := range engine.GetRedis(definition.HotnessPool).ZRevRange(warmUpHotnessKey(schema), 0, int64(limit-1)) {
+				id, err := strconv.ParseUint(member, 10, 64)
+				if err == nil {
+					ids = append(ids, id)
+				}
+			}
+		}
+		if len(ids) == 0 {
+			return 0
+		}
+		rows := reflect.New(reflect.SliceOf(reflect.PtrTo(schema.t)))
+		engine.LoadByIDs(ids, rows.Interface(), definition.References...)
+		loaded := 0
+		for i := 0; i < rows.Elem().Len(); i++ {
+			if !rows.Elem().Index(i).IsNil() {
+				loaded++
+			}
+		}
+		return loaded
+	}}
+}
+
+func warmUpCachedQueryTasks(engine *engineImplementation, definition WarmUpCachedQuery) []warmUpTask {
+	schema := initIfNeeded(engine.registry, definition.Entity).tableSchema
+	pager := definition.Pager
+	if pager == nil {
+		pager = NewPager(1, 100)
+	}
+	arguments := definition.Arguments
+	if len(arguments) == 0 {
+		arguments = [][]interface{}{nil}
+	}
+	tasks := make([]warmUpTask, len(arguments))
+	for i, args := range arguments {
+		args := args
+		tasks[i] = warmUpTask{step: schema.t.String() + "." + definition.Name, run: func(engine Engine) int {
+			entity := reflect.New(schema.t).Interface().(Entity)
+			_, ids := engine.CachedSearchIDs(entity, definition.Name, pager, args...)
+			return len(ids)
+		}}
+	}
+	return tasks
+}
+
+func warmUpCachedQuery(engine *engineImplementation, entity Entity, indexName string, arguments ...interface{}) int {
+	schema := initIfNeeded(engine.registry, entity).tableSchema
+	definition, has := schema.cachedIndexesAll[indexName]
+	if !has {
+		panic(fmt.Errorf("index %s not found", indexName))
+	}
+	cacheKey := getCacheKeySearch(engine, schema, indexName, NewWhere(definition.Query, arguments...).GetParameters()...)
+	if localCache, hasLocalCache := schema.GetLocalCache(engine); hasLocalCache {
+		localCache.Remove(cacheKey)
+	}
+	if engine.hasRequestCache {
+		engine.GetLocalCache(requestCacheKey).Remove(cacheKey)
+	}
+	if redisCache, hasRedis := schema.GetRedisCache(engine); hasRedis {
+		redisCache.Del(cacheKey)
+	}
+	if _, isOne := schema.cachedIndexesOne[indexName]; isOne {
+		if cachedSearchOne(newSerializer(nil), engine, entity, indexName, false, arguments, nil) {
+			return 1
+		}
+		return 0
+	}
+	_, ids := cachedSearch(newSerializer(nil), engine, entity, indexName, NewPager(1, definition.Max), arguments, false, nil)
+	return len(ids)
+}
+
+func warmUpAllCachedQueries(engine *engineImplementation, entity Entity) int {
+	schema := initIfNeeded(engine.registry, entity).tableSchema
+	names := make([]string, 0, len(schema.cachedIndexesAll))
+	for indexName := range schema.cachedIndexesAll {
+		names = append(names, indexName)
+	}
+	sort.Strings(names)
+	warmed := 0
+	for _, indexName := range names {
+		fields := getCachedQueryArgumentFields(schema, schema.cachedIndexesAll[indexName])
+		if len(fields) == 0 {
+			warmUpCachedQuery(engine, entity, indexName)
+			warmed++
+			continue
+		}
+		if fields == nil {
+			continue
+		}
+		for _, arguments := range getCachedQueryArguments(engine, schema, fields) {
+			warmUpCachedQuery(engine, entity, indexName, arguments...)
+			warmed++
+		}
+	}
+	return warmed
+}
+
+func getCachedQueryArgumentFields(schema *tableSchema, definition *cachedQueryDefinition) []string {
+	fields := make([]string, 0, len(definition.QueryFields))
+	for _, field := range definition.QueryFields {
+		if (!schema.hasFakeDelete || field != "FakeDelete") && (!schema.hasSoftDelete || field != schema.softDeleteColumn) {
+			fields = append(fields, field)
+		}
+	}
+	query := definition.Query
+	if pos := strings.Index(strings.ToLower(query), "order by"); pos >= 0 {
+		query = query[0:pos]
+	}
+	if strings.Count(query, "?") != len(fields) || strings.Contains(strings.ToLower(query), "in ?") {
+		return nil
+	}
+	return fields
+}
+
+func getCachedQueryArguments(engine *engineImplementation, schema *tableSchema, fields []string) [][]interface{} {
+	columns := make([]string, len(fields))
+	for i, field := range fields {
+		columns[i] = "`" + schema.getColumnName(field) + "`"
+	}
+	where := "1"
+	if schema.hasFakeDelete {
+		where = "`FakeDelete` = 0"
+	} else if schema.hasSoftDelete {
+		where = "`" + schema.getColumnName(schema.softDeleteColumn) + "` IS NULL"
+	}
+	/* #nosec */
+	query := "SELECT DISTINCT " + strings.Join(columns, ",") + " FROM `" + schema.tableName + "` WHERE " + where
+	results, def := schema.GetMysql(engine).Query(query)
+	defer def()
+	arguments := make([][]interface{}, 0)
+main:
+	for results.Next() {
+		pointers := make([]interface{}, len(fields))
+		for i := range pointers {
+			pointers[i] = &sql.NullString{}
+		}
+		results.Scan(pointers...)
+		row := make([]interface{}, len(fields))
+		for i, pointer := range pointers {
+			value := pointer.(*sql.NullString)
+			if !value.Valid {
+				continue main
+			}
+			row[i] = convertCachedQueryArgument(schema.t, fields[i], value.String)
+		}
+		arguments = append(arguments, row)
+	}
+	return arguments
+}
+
+func convertCachedQueryArgument(t reflect.Type, field string, value string) interface{} {
+	structField, has := t.FieldByName(field)
+	if !has {
+		return value
+	}
+	fieldType := structField.Type
+	if fieldType.Kind() == reflect.Ptr {
+		if fieldType.Implements(entityInterfaceType) {
+			id, _ := strconv.ParseUint(value, 10, 64)
+			return id
+		}
+		fieldType = fieldType.Elem()
+	}
+	switch fieldType.Kind() {
+	case reflect.Bool:
+		return value == "1"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		converted, _ := strconv.ParseUint(value, 10, 64)
+		return converted
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		converted, _ := strconv.ParseInt(value, 10, 64)
+		return converted
+	case reflect.Float32, reflect.Float64:
+		converted, _ := strconv.ParseFloat(value, 64)
+		return converted
+	}
+	return value
+}
+
+func warmUpHotnessKey(schema *tableSchema) string {
+	return "_warm_up:" + schema.cachePrefix
+}
@@ -0,0 +1,87 @@
+package beeorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type warmUpEntity struct {
+	ORM   `orm:"localCache;redisCache"`
+	ID    uint
+	Name  string       `orm:"index=Name"`
+	Index *CachedQuery `query:":Name = ?"`
+}
+
+func TestWarmUp(t *testing.T) {
+	var entity *warmUpEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+
+	flusher := engine.NewFlusher()
+	for _, name := range []string{"a", "a", "b", "c", "d"} {
+		flusher.Track(&warmUpEntity{Name: name})
+	}
+	flusher.Flush()
+	engine.GetLocalCache().Clear()
+	engine.GetRedis().FlushDB()
+	RecordWarmUpHotness(engine, "default", entity, 3, 4, 4, 5, 5, 5)
+
+	progress := make([]WarmUpProgress, 0)
+	engine.WarmUp(WarmUpPlan{
+		Entities: []WarmUpEntity{
+			{Entity: entity, IDs: []uint64{1}},
+			{Entity: entity, HotnessPool: "default", Limit: 2},
+		},
+		CachedQueries: []WarmUpCachedQuery{{Entity: entity, Name: "Index", Arguments: [][]interface{}{{"a"}, {"b"}}}},
+		Concurrency:   2,
+		Progress: func(p WarmUpProgress) {
+			progress = append(progress, p)
+		},
+	})
+	assert.Len(t, progress, 4)
+	assert.Equal(t, 4, progress[3].Done)
+	assert.Equal(t, 4, progress[3].Total)
+	assert.Equal(t, 6, progress[3].Loaded)
+
+	testLogger := &testLogHandler{}
+	engine.RegisterQueryLogger(testLogger, true, false, false)
+	for _, id := range []uint64{1, 4, 5} {
+		assert.True(t, engine.LoadByID(id, &warmUpEntity{}))
+	}
+	var rows []*warmUpEntity
+	assert.Equal(t, 2, engine.CachedSearch(&rows, "Index", nil, "a"))
+	assert.Len(t, testLogger.Logs, 0)
+	assert.True(t, engine.LoadByID(3, &warmUpEntity{}))
+	assert.Len(t, testLogger.Logs, 1)
+
+	engine.WarmUp(WarmUpPlan{})
+}
+
+func TestWarmUpCachedQueries(t *testing.T) {
+	var entity *warmUpEntity
+	engine := prepareTables(t, &Registry{}, 5, 6, "", entity)
+
+	flusher := engine.NewFlusher()
+	for _, name := range []string{"a", "a", "b"} {
+		flusher.Track(&warmUpEntity{Name: name})
+	}
+	flusher.Flush()
+	engine.GetLocalCache().Clear()
+	engine.GetRedis().FlushDB()
+
+	assert.Equal(t, 2, engine.WarmUpCachedQuery(entity, "Index", "a"))
+	testLogger := &testLogHandler{}
+	engine.RegisterQueryLogger(testLogger, true, false, false)
+	var rows []*warmUpEntity
+	assert.Equal(t, 2, engine.CachedSearch(&rows, "Index", nil, "a"))
+	assert.Len(t, testLogger.Logs, 0)
+
+	engine.GetMysql().Exec("UPDATE `warmUpEntity` SET `Name` = 'a' WHERE `ID` = 3")
+	assert.Equal(t, 2, engine.CachedSearch(&rows, "Index", nil, "a"))
+	assert.Equal(t, 1, engine.WarmUpAllCachedQueries(entity))
+	assert.Equal(t, 3, engine.CachedSearch(&rows, "Index", nil, "a"))
+
+	assert.PanicsWithError(t, "index Invalid not found", func() {
+		engine.WarmUpCachedQuery(entity, "Invalid")
+	})
+}
@@ -0,0 +1,184 @@
+package beeorm
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"time"
+
+	googleuuid "github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const webhookSubscriberPrefix = "webhook-"
+
+const WebhookSignatureHeader = "X-Beeorm-Signature"
+
+var webhookDeliveryEntityType = reflect.TypeOf(WebhookDeliveryEntity{})
+
+type WebhookEndpoint struct {
+	Name          string
+	URL           string
+	Secret        string
+	RedisPool     string
+	MaxAttempts   int
+	Backoff       time.Duration
+	Subscriptions []CrudSubscription
+}
+
+type WebhookDeliveryEntity struct {
+	ORM        `orm:"table=_beeorm_webhook_deliveries"`
+	ID         uint64
+	Webhook    string `orm:"length=100;required;index=Webhook"`
+	EventID    string `orm:"length=50"`
+	Entity     string
+	EntityID   uint64
+	Payload    string `orm:"length=max"`
+	StatusCode uint16
+	Attempts   uint16
+	Success    bool
+	LastError  string    `orm:"length=max"`
+	CreatedAt  time.Time `orm:"time"`
+}
+
+type webhookPayload struct {
+	Entity  string               `json:"entity"`
+	ID      uint64               `json:"id"`
+	Type    string               `json:"type"`
+	Changes map[string]FieldDiff `json:"changes"`
+}
+
+type WebhookDispatcher struct {
+	eventConsumerBase
+	endpoint *WebhookEndpoint
+	client   *http.Client
+}
+
+func (r *Registry) RegisterWebhook(endpoint WebhookEndpoint) {
+	if r.webhooks == nil {
+		r.webhooks = make(map[string]*WebhookEndpoint)
+		r.RegisterEntity(&WebhookDeliveryEntity{})
+	}
+	if endpoint.RedisPool == "" {
+		endpoint.RedisPool = "default"
+	}
+	if endpoint.MaxAttempts <= 0 {
+		endpoint.MaxAttempts = 5
+	}
+	if endpoint.Backoff <= 0 {
+		endpoint.Backoff = time.Second
+	}
+	r.RegisterCrudSubscriber(webhookSubscriberPrefix+endpoint.Name, endpoint.RedisPool, endpoint.Subscriptions...)
+	r.webhooks[endpoint.Name] = &endpoint
+}
+
+func NewWebhookDispatcher(engine Engine, name string) *WebhookDispatcher {
+	e := engine.(*engineImplementation)
+	endpoint, has := e.registry.registry.webhooks[name]
+	if !has {
+		panic(fmt.Errorf("webhook '%s' is not registered", name))
+	}
+	d := &WebhookDispatcher{endpoint: endpoint, client: &http.Client{Timeout: time.Second * 10}}
+	d.engine = e
+	d.block = true
+	d.blockTime = time.Second * 30
+	return d
+}
+
+func (d *WebhookDispatcher) SetHTTPClient(client *http.Client) {
+	d.client = client
+}
+
+func (d *WebhookDispatcher) Digest(ctx context.Context) bool {
+	consumer := NewCrudConsumer(d.engine, webhookSubscriberPrefix+d.endpoint.Name)
+	consumer.eventConsumerBase = d.eventConsumerBase
+	return consumer.Digest(ctx, 100, func(events []*CrudEvent) {
+		deliveries := make([]Entity, len(events))
+		for i, event := range events {
+			deliveries[i] = d.deliver(ctx, event)
+		}
+		d.engine.Flush(deliveries...)
+	})
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, event *CrudEvent) *WebhookDeliveryEntity {
+	flushType := "insert"
+	if event.Type == FlushTypeUpdate {
+		flushType = "update"
+	} else if event.Type == FlushTypeDelete {
+		flushType = "delete"
+	}
+	body, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(&webhookPayload{Entity: event.Entity, ID: event.ID, Type: flushType,
+		Changes: event.Changes})
+	checkError(err)
+	delivery := &WebhookDeliveryEntity{Webhook: d.endpoint.Name, EventID: newWebhookEventID(), Entity: event.Entity, EntityID: event.ID,
+		Payload: string(body), CreatedAt: d.engine.now()}
+	backoff := d.endpoint.Backoff
+	for {
+		delivery.Attempts++
+		delivery.StatusCode, err = d.send(ctx, delivery.EventID, body)
+		if err == nil {
+			delivery.Success = true
+			delivery.LastError = ""
+			return delivery
+		}
+		delivery.LastError = err.Error()
+		if int(delivery.Attempts) >= d.endpoint.MaxAttempts || ctx.Err() != nil {
+			return delivery
+		}
+		select {
+		case <-ctx.Done():
+			return delivery
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, eventID string, body []byte) (uint16, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, d.endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Beeorm-Event-ID", eventID)
+	if d.endpoint.Secret != "" {
+		request.Header.Set(WebhookSignatureHeader, SignWebhookPayload(d.endpoint.Secret, body))
+	}
+	response, err := d.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+	if response.StatusCode >= 300 {
+		return uint16(response.StatusCode), fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+	return uint16(response.StatusCode), nil
+}
+
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookEventID() string {
+	return googleuuid.New().String()
+}
+
+func (e *engineImplementation) GetWebhookDeliveries(webhook string, pager *Pager) []*WebhookDeliveryEntity {
+	schema := getTableSchema(e.registry, webhookDeliveryEntityType)
+	if schema == nil {
+		panic(fmt.Errorf("webhooks are not registered"))
+	}
+	var deliveries []*WebhookDeliveryEntity
+	e.Search(NewWhere("`"+schema.getColumnName("Webhook")+"` = ? ORDER BY `ID` DESC", webhook), pager, &deliveries)
+	return deliveries
+}
@@ -0,0 +1,71 @@
+package beeorm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type webhookEntity struct {
+	ORM
+	ID     uint
+	Status string
+}
+
+func TestWebhookDispatcher(t *testing.T) {
+	calls := 0
+	signatures := make([]string, 0)
+	bodies := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		signatures = append(signatures, r.Header.Get(WebhookSignatureHeader))
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	var entity *webhookEntity
+	registry := &Registry{}
+	registry.RegisterWebhook(WebhookEndpoint{Name: "status", URL: server.URL, Secret: "secret", Backoff: time.Millisecond,
+		MaxAttempts: 2, Subscriptions: []CrudSubscription{{Entity: entity, Fields: []string{"Status"}}}})
+	engine := prepareTables(t, registry, 5, 6, "", entity, &WebhookDeliveryEntity{})
+
+	e := &webhookEntity{Status: "new"}
+	engine.Flush(e)
+	e.Status = "done"
+	engine.Flush(e)
+
+	dispatcher := NewWebhookDispatcher(engine, "status")
+	dispatcher.DisableBlockMode()
+	dispatcher.blockTime = time.Millisecond
+	dispatcher.Digest(context.Background())
+	assert.Equal(t, 3, calls)
+	assert.Len(t, bodies, 2)
+	assert.Equal(t, `{"entity":"beeorm.webhookEntity","id":1,"type":"update","changes":{"Status":{"Old":"new","New":"done"}}}`, bodies[1])
+	assert.Equal(t, SignWebhookPayload("secret", []byte(bodies[1])), signatures[1])
+
+	deliveries := engine.GetWebhookDeliveries("status", nil)
+	assert.Len(t, deliveries, 2)
+	assert.True(t, deliveries[0].Success)
+	assert.Equal(t, uint16(1), deliveries[0].Attempts)
+	assert.Equal(t, uint16(2), deliveries[1].Attempts)
+	assert.Equal(t, uint16(http.StatusOK), deliveries[1].StatusCode)
+	assert.Equal(t, uint64(1), deliveries[1].EntityID)
+
+	assert.PanicsWithError(t, "webhook 'invalid' is not registered", func() {
+		NewWebhookDispatcher(engine, "invalid")
+	})
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	assert.Equal(t, "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8", SignWebhookPayload("key", []byte("The quick brown fox jumps over the lazy dog")))
+}
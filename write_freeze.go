@@ -0,0 +1,70 @@
+package beeorm
+
+import (
+	"sync"
+	"time"
+)
+
+const writeFreezeCheckInterval = time.Second
+
+type WriteFreezeMode int
+
+const (
+	WriteFreezeReject WriteFreezeMode = iota
+	WriteFreezeLazy
+)
+
+type WriteFrozenError struct {
+	Until time.Time
+}
+
+func (err *WriteFrozenError) Error() string {
+	return "mysql writes are frozen until " + err.Until.Format(time.RFC3339)
+}
+
+type writeFreeze struct {
+	mutex sync.RWMutex
+	until time.Time
+	mode  WriteFreezeMode
+}
+
+func (w *writeFreeze) set(until time.Time, mode WriteFreezeMode) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.until = until
+	w.mode = mode
+}
+
+func (w *writeFreeze) get() (until time.Time, mode WriteFreezeMode, active bool) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.until, w.mode, time.Now().Before(w.until)
+}
+
+func (w *writeFreeze) wait() {
+	for {
+		until, _, active := w.get()
+		if !active {
+			return
+		}
+		sleep := time.Until(until)
+		if sleep > writeFreezeCheckInterval {
+			sleep = writeFreezeCheckInterval
+		}
+		time.Sleep(sleep)
+	}
+}
+
+func (f *flusher) applyWriteFreeze(lazy bool) bool {
+	if lazy {
+		return true
+	}
+	until, mode, active := f.engine.registry.writeFreeze.get()
+	if !active {
+		return false
+	}
+	if mode == WriteFreezeLazy {
+		return true
+	}
+	panic(&WriteFrozenError{Until: until})
+}
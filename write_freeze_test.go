@@ -0,0 +1,65 @@
+package beeorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type writeFreezeEntity struct {
+	ORM
+	ID   uint
+	Name string
+}
+
+func TestWriteFreeze(t *testing.T) {
+	var entity *writeFreezeEntity
+	registry := &Registry{}
+	engine := prepareTables(t, registry, 5, 6, "", entity)
+
+	_, _, active := engine.GetWriteFreeze()
+	assert.False(t, active)
+
+	until := time.Now().Add(time.Hour)
+	engine.SetWriteFreeze(until, WriteFreezeReject)
+	frozenUntil, mode, active := engine.GetWriteFreeze()
+	assert.True(t, active)
+	assert.Equal(t, WriteFreezeReject, mode)
+	assert.Equal(t, until, frozenUntil)
+	assert.True(t, engine.Clone().(*engineImplementation).registry == engine.registry)
+
+	entity = &writeFreezeEntity{Name: "a"}
+	assert.PanicsWithError(t, "mysql writes are frozen until "+until.Format(time.RFC3339), func() {
+		engine.Flush(entity)
+	})
+	err := engine.FlushWithCheck(entity)
+	assert.IsType(t, &WriteFrozenError{}, err)
+	assert.Equal(t, until, err.(*WriteFrozenError).Until)
+	err = engine.FlushWithFullCheck(entity)
+	assert.IsType(t, &WriteFrozenError{}, err)
+	assert.False(t, engine.LoadByID(1, &writeFreezeEntity{}))
+
+	engine.SetWriteFreeze(time.Now().Add(time.Hour), WriteFreezeLazy)
+	entity = &writeFreezeEntity{Name: "b"}
+	engine.Flush(entity)
+	assert.False(t, engine.LoadByID(1, &writeFreezeEntity{}))
+
+	engine.SetWriteFreeze(time.Now().Add(-time.Second), WriteFreezeReject)
+	_, _, active = engine.GetWriteFreeze()
+	assert.False(t, active)
+	receiver := NewBackgroundConsumer(engine)
+	receiver.DisableBlockMode()
+	receiver.blockTime = time.Millisecond
+	receiver.Digest(context.Background())
+	entity = &writeFreezeEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "b", entity.Name)
+
+	entity.Name = "c"
+	engine.Flush(entity)
+	entity = &writeFreezeEntity{}
+	assert.True(t, engine.LoadByID(1, entity))
+	assert.Equal(t, "c", entity.Name)
+}